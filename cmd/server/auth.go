@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/myideascope/otherside/internal/config"
+	"github.com/myideascope/otherside/internal/repository"
+	"github.com/myideascope/otherside/internal/service"
+)
+
+// tokenSecretFile holds the generated signing secret for bearer tokens and
+// share links under the data path when AUTH_TOKEN_SECRET is not set
+const tokenSecretFile = "auth_token.secret"
+
+// newAuthService creates the auth service with its signing secret
+func newAuthService(db *repository.DB, cfg *config.Config, secret []byte) *service.AuthService {
+	return service.NewAuthService(
+		repository.NewSQLiteAPIKeyRepository(db.DB),
+		repository.NewSQLiteInvestigatorRepository(db.DB),
+		repository.NewSQLiteDeviceRepository(db.DB),
+		secret,
+		time.Duration(cfg.Auth.TokenTTL)*time.Second,
+	)
+}
+
+// loadTokenSecret returns AUTH_TOKEN_SECRET, or a secret generated once and
+// kept under the data path so bearer tokens and share links survive restarts
+func loadTokenSecret(cfg *config.Config) ([]byte, error) {
+	if cfg.Auth.TokenSecret != "" {
+		return []byte(cfg.Auth.TokenSecret), nil
+	}
+
+	path := filepath.Join(cfg.Storage.DataPath, tokenSecretFile)
+	data, err := os.ReadFile(path)
+	if err == nil {
+		return []byte(strings.TrimSpace(string(data))), nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read token secret: %w", err)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate token secret: %w", err)
+	}
+	encoded := hex.EncodeToString(secret)
+
+	if err := os.MkdirAll(cfg.Storage.DataPath, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(encoded+"\n"), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write token secret: %w", err)
+	}
+	log.Printf("Generated token signing secret in %s", path)
+
+	return []byte(encoded), nil
+}
+
+// runCreateInvestigator creates an investigator so that the first API key
+// can be minted before anyone can call the API
+func runCreateInvestigator(db *repository.DB, name string, admin bool) {
+	participantService := service.NewParticipantService(
+		repository.NewSQLiteSessionRepository(db.DB),
+		repository.NewSQLiteInvestigatorRepository(db.DB),
+		repository.NewSQLiteDeviceRepository(db.DB),
+		repository.NewSQLiteSessionParticipantRepository(db.DB),
+	)
+
+	investigator, err := participantService.CreateInvestigator(context.Background(), service.CreateInvestigatorRequest{Name: name, IsAdmin: admin})
+	if err != nil {
+		log.Fatalf("Failed to create investigator: %v", err)
+	}
+	fmt.Printf("Created investigator %s (%s)\n", investigator.ID, investigator.Name)
+}
+
+// runCreateAPIKey mints an API key and prints it once
+func runCreateAPIKey(authService *service.AuthService, req service.CreateAPIKeyRequest) {
+	key, plaintext, err := authService.CreateAPIKey(context.Background(), req)
+	if err != nil {
+		log.Fatalf("Failed to create API key: %v", err)
+	}
+
+	fmt.Printf("Created API key %s (%s) for %s %s\n", key.ID, key.Name, key.PrincipalKind, key.PrincipalID)
+	fmt.Println("Store this key now, it cannot be shown again:")
+	fmt.Println(plaintext)
+}
+
+// runListAPIKeys prints all API keys without their secrets
+func runListAPIKeys(authService *service.AuthService) {
+	keys, err := authService.ListAPIKeys(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to list API keys: %v", err)
+	}
+
+	if len(keys) == 0 {
+		fmt.Println("No API keys")
+		return
+	}
+
+	for _, key := range keys {
+		status := "active"
+		if key.IsRevoked() {
+			status = "revoked " + key.RevokedAt.Format(time.RFC3339)
+		}
+		lastUsed := "never"
+		if key.LastUsedAt != nil {
+			lastUsed = key.LastUsedAt.Format(time.RFC3339)
+		}
+		fmt.Printf("%s  %-20s  %s %s  created %s  last used %s  %s\n",
+			key.ID, key.Name, key.PrincipalKind, key.PrincipalID,
+			key.CreatedAt.Format(time.RFC3339), lastUsed, status)
+	}
+}
+
+// runRevokeAPIKey revokes an API key and the bearer tokens issued with it
+func runRevokeAPIKey(authService *service.AuthService, id string) {
+	if err := authService.RevokeAPIKey(context.Background(), id); err != nil {
+		log.Fatalf("Failed to revoke API key: %v", err)
+	}
+	fmt.Printf("Revoked API key %s\n", id)
+}
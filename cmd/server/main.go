@@ -2,17 +2,25 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 	"time"
 
+	"github.com/gorilla/mux"
 	"github.com/myideascope/otherside/internal/config"
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/myideascope/otherside/internal/handler"
+	"github.com/myideascope/otherside/internal/ingest"
 	"github.com/myideascope/otherside/internal/repository"
 	"github.com/myideascope/otherside/internal/service"
+	"github.com/myideascope/otherside/pkg/audio"
 )
 
 func main() {
@@ -21,6 +29,14 @@ func main() {
 		migrate = flag.Bool("migrate", false, "Run database migrations and exit")
 		cleanup = flag.Bool("cleanup", false, "Run cleanup operations and exit")
 		status  = flag.Bool("status", false, "Show migration status and exit")
+
+		createInvestigator = flag.String("create-investigator", "", "Create an investigator with this name and exit")
+		investigatorAdmin  = flag.Bool("admin", false, "Make the investigator created with -create-investigator an admin")
+		createAPIKey       = flag.String("create-api-key", "", "Mint an API key with this name for -device or -investigator and exit")
+		keyDevice          = flag.String("device", "", "Device ID the new API key belongs to")
+		keyInvestigator    = flag.String("investigator", "", "Investigator ID the new API key belongs to")
+		listAPIKeys        = flag.Bool("list-api-keys", false, "List API keys and exit")
+		revokeAPIKey       = flag.String("revoke-api-key", "", "Revoke the API key with this ID and exit")
 	)
 	flag.Parse()
 
@@ -55,6 +71,32 @@ func main() {
 		return
 	}
 
+	if *createInvestigator != "" {
+		runCreateInvestigator(db, *createInvestigator, *investigatorAdmin)
+		return
+	}
+
+	if *createAPIKey != "" || *listAPIKeys || *revokeAPIKey != "" {
+		tokenSecret, err := loadTokenSecret(cfg)
+		if err != nil {
+			log.Fatalf("Failed to initialize authentication: %v", err)
+		}
+		authService := newAuthService(db, cfg, tokenSecret)
+		switch {
+		case *createAPIKey != "":
+			runCreateAPIKey(authService, service.CreateAPIKeyRequest{
+				Name:           *createAPIKey,
+				DeviceID:       *keyDevice,
+				InvestigatorID: *keyInvestigator,
+			})
+		case *revokeAPIKey != "":
+			runRevokeAPIKey(authService, *revokeAPIKey)
+		default:
+			runListAPIKeys(authService)
+		}
+		return
+	}
+
 	// Initialize application components
 	app, err := initializeApp(db, cfg)
 	if err != nil {
@@ -66,10 +108,9 @@ func main() {
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
 	// Start application
-	go func() {
-		log.Println("Starting OtherSide application...")
-		// TODO: Start HTTP server, WebSocket handlers, etc.
-	}()
+	if err := app.Start(); err != nil {
+		log.Fatalf("Failed to start application: %v", err)
+	}
 
 	// Wait for shutdown signal
 	<-sigChan
@@ -92,6 +133,14 @@ type Application struct {
 	fileManager    *repository.FileManager
 	cleanupManager *repository.CleanupManager
 	db             *repository.DB
+	httpServer     *http.Server
+	ingestListener *ingest.Listener
+	mqttBridge     *ingest.MQTTBridge
+	scheduler      *Scheduler
+	idempotency    *service.IdempotencyService
+	syncService    *service.SyncService
+	eventHub       *service.EventHub
+	webhookService *service.WebhookService
 }
 
 // initializeApp sets up all application components
@@ -104,7 +153,7 @@ func initializeApp(db *repository.DB, cfg *config.Config) (*Application, error)
 	app.fileManager = repository.NewFileManager(db.DB, cfg.Storage.DataPath)
 
 	// Initialize session manager
-	app.sessionManager = service.NewSessionStateManager(db.DB)
+	app.sessionManager = service.NewSessionStateManager(repository.NewSQLiteSessionRepository(db.DB))
 	if err := app.sessionManager.Initialize(context.Background()); err != nil {
 		return nil, fmt.Errorf("failed to initialize session manager: %w", err)
 	}
@@ -112,13 +161,308 @@ func initializeApp(db *repository.DB, cfg *config.Config) (*Application, error)
 	// Initialize cleanup manager
 	app.cleanupManager = repository.NewCleanupManager(db.DB, app.fileManager)
 
+	// Initialize repositories. Sessions are read and written through the
+	// session manager, so every service sees the same cached state.
+	var sessionRepo domain.SessionRepository = app.sessionManager
+	evpRepo := repository.NewSQLiteEVPRepository(db.DB)
+	voxRepo := repository.NewSQLiteVOXRepository(db.DB)
+	radarRepo := repository.NewSQLiteRadarRepository(db.DB)
+	slsRepo := repository.NewSQLiteSLSRepository(db.DB)
+	interactionRepo := repository.NewSQLiteInteractionRepository(db.DB)
+	fileRepo := repository.NewSQLiteFileRepository(db.DB, cfg.Storage.DataPath)
+	trackRepo := repository.NewSQLiteRadarTrackRepository(db.DB)
+	floorPlanRepo := repository.NewSQLiteFloorPlanRepository(db.DB)
+	placementRepo := repository.NewSQLiteDevicePlacementRepository(db.DB)
+	fusionRepo := repository.NewSQLiteFusionEventRepository(db.DB)
+	readingRepo := repository.NewSQLiteEnvironmentalReadingRepository(db.DB)
+	anomalyRepo := repository.NewSQLiteEnvironmentalAnomalyRepository(db.DB)
+	sensorRepo := repository.NewSQLiteSensorRegistrationRepository(db.DB)
+	investigatorRepo := repository.NewSQLiteInvestigatorRepository(db.DB)
+	deviceRepo := repository.NewSQLiteDeviceRepository(db.DB)
+	participantRepo := repository.NewSQLiteSessionParticipantRepository(db.DB)
+	teamRepo := repository.NewSQLiteTeamRepository(db.DB)
+	aclRepo := repository.NewSQLiteSessionACLRepository(db.DB)
+	exportRecordRepo := repository.NewSQLiteExportRecordRepository(db.DB)
+
+	// Initialize audio processing
+	audioProcessor := audio.NewProcessor(audio.ProcessorConfig{
+		SampleRate:     cfg.Audio.SampleRate,
+		BitDepth:       cfg.Audio.BitDepth,
+		NoiseThreshold: cfg.Audio.NoiseThreshold,
+	})
+	voxGenerator := audio.NewVOXGenerator(audio.VOXConfig{
+		DefaultLanguage:  "english",
+		PhoneticBankSize: 32,
+		TriggerThreshold: 0.3,
+	})
+
+	// Initialize services
+	accessService := service.NewAccessService(investigatorRepo, teamRepo, aclRepo, exportRecordRepo)
+	sessionService := service.NewSessionService(
+		sessionRepo, evpRepo, voxRepo, radarRepo, slsRepo, interactionRepo, fileRepo,
+		audioProcessor, voxGenerator,
+	)
+	sessionService.SetParticipantRepository(participantRepo)
+	sessionService.SetAccessService(accessService)
+	app.eventHub = service.NewEventHub(cfg.Stream.ReplayEvents)
+	sessionService.SetEventHub(app.eventHub)
+	app.syncService = service.NewSyncService(
+		sessionService, repository.NewSQLiteEventBatchRepository(db.DB), repository.NewSQLiteChangeRepository(db.DB),
+	)
+	app.syncService.SetRecordRepositories(service.SyncRecordRepositories{
+		Readings:     readingRepo,
+		Tracks:       trackRepo,
+		Fusion:       fusionRepo,
+		FloorPlans:   floorPlanRepo,
+		Placements:   placementRepo,
+		Participants: participantRepo,
+		ACLs:         aclRepo,
+	})
+	voxAnalysisService := service.NewVOXAnalysisService(sessionRepo, voxRepo, interactionRepo, voxGenerator)
+	exportService := service.NewExportService(sessionRepo, evpRepo, voxRepo, radarRepo, slsRepo, interactionRepo, fileRepo)
+	exportService.SetVOXAnalysisService(voxAnalysisService)
+	exportService.SetAccessService(accessService)
+	radarTrackingService := service.NewRadarTrackingService(sessionRepo, radarRepo, trackRepo)
+	heatmapService := service.NewHeatmapService(sessionRepo, radarRepo)
+	fusionService := service.NewFusionService(sessionRepo, evpRepo, radarRepo, slsRepo, fusionRepo)
+	timelineService := service.NewTimelineService(sessionRepo, evpRepo, voxRepo, radarRepo, slsRepo, interactionRepo, anomalyRepo)
+	floorPlanService := service.NewFloorPlanService(sessionRepo, floorPlanRepo, placementRepo, radarRepo, slsRepo, evpRepo, fileRepo)
+	environmentalService := service.NewEnvironmentalService(sessionRepo, readingRepo, anomalyRepo, sensorRepo, service.EnvironmentalAnomalyConfig{})
+	alertService := service.NewAlertService(
+		repository.NewSQLiteAlertRuleRepository(db.DB), repository.NewSQLiteAlertRepository(db.DB), sessionRepo,
+	)
+	alertService.SetAccessService(accessService)
+	alertService.SetEventHub(app.eventHub)
+	sessionService.SetAlertService(alertService)
+	environmentalService.SetAlertService(alertService)
+	environmentalService.SetAccessService(accessService)
+	app.webhookService = service.NewWebhookService(
+		repository.NewSQLiteWebhookSubscriptionRepository(db.DB), repository.NewSQLiteWebhookDeliveryRepository(db.DB),
+		service.WebhookDeliveryConfig{
+			Backoff:               time.Duration(cfg.Webhook.RetryBackoff) * time.Second,
+			MaxBackoff:            time.Duration(cfg.Webhook.MaxBackoff) * time.Second,
+			MaxAttempts:           cfg.Webhook.MaxAttempts,
+			EVPMinConfidence:      cfg.Webhook.EVPMinConfidence,
+			AllowPrivateAddresses: cfg.Webhook.AllowPrivateAddresses,
+		},
+	)
+	app.webhookService.SetAccessService(accessService)
+	app.sessionManager.SetWebhookService(app.webhookService)
+	sessionService.SetWebhookService(app.webhookService)
+	exportService.SetWebhookService(app.webhookService)
+	alertService.SetWebhookService(app.webhookService)
+	searchService := service.NewSearchService(repository.NewSQLiteSearchRepository(db.DB), sessionRepo)
+	searchService.SetAccessService(accessService)
+	lifecycleService := service.NewSessionLifecycleService(app.sessionManager, evpRepo, fileRepo)
+	lifecycleService.SetAccessService(accessService)
+	participantService := service.NewParticipantService(sessionRepo, investigatorRepo, deviceRepo, participantRepo)
+	participantService.SetAccessService(accessService)
+	tokenSecret, err := loadTokenSecret(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize authentication: %w", err)
+	}
+	authService := newAuthService(db, cfg, tokenSecret)
+	shareService := service.NewShareService(
+		repository.NewSQLiteShareLinkRepository(db.DB), sessionRepo, evpRepo, fileRepo, timelineService, tokenSecret,
+	)
+	shareService.SetAccessService(accessService)
+	app.idempotency = service.NewIdempotencyService(
+		repository.NewSQLiteIdempotencyRepository(db.DB), time.Duration(cfg.Sync.IdempotencyTTL)*time.Second,
+	)
+
+	// Initialize HTTP handlers
+	sessionHandler := handler.NewSessionHandler(sessionService)
+	router := mux.NewRouter()
+	sessionHandler.RegisterRoutes(router)
+	handler.NewAnalysisHandler(voxAnalysisService, radarTrackingService, heatmapService, fusionService).RegisterRoutes(router)
+	handler.NewExportHandler(exportService).RegisterRoutes(router)
+	handler.NewTimelineHandler(timelineService).RegisterRoutes(router)
+	handler.NewFloorPlanHandler(floorPlanService).RegisterRoutes(router)
+	handler.NewEnvironmentalHandler(environmentalService).RegisterRoutes(router)
+	handler.NewSessionLifecycleHandler(lifecycleService).RegisterRoutes(router)
+	handler.NewParticipantHandler(participantService).RegisterRoutes(router)
+	handler.NewShareHandler(shareService).RegisterRoutes(router)
+	handler.NewSyncHandler(app.syncService).RegisterRoutes(router)
+	handler.NewStreamHandler(sessionService, time.Duration(cfg.Stream.HeartbeatInterval)*time.Second).RegisterRoutes(router)
+	handler.NewAlertHandler(alertService).RegisterRoutes(router)
+	handler.NewWebhookHandler(app.webhookService).RegisterRoutes(router)
+	handler.NewSearchHandler(searchService).RegisterRoutes(router)
+	authHandler := handler.NewAuthHandler(authService)
+	authHandler.RegisterRoutes(router)
+	accessHandler := handler.NewAccessHandler(accessService)
+	accessHandler.RegisterRoutes(router)
+	router.Use(accessHandler.Middleware)
+	router.Use(handler.NewIdempotencyHandler(app.idempotency).Middleware)
+	router.PathPrefix("/").Handler(http.FileServer(http.Dir(filepath.Join("web", "static"))))
+
+	var apiHandler http.Handler = router
+	if cfg.Auth.Enabled {
+		apiHandler = authHandler.Middleware(router)
+	} else {
+		log.Println("API authentication is disabled")
+	}
+
+	app.httpServer = &http.Server{
+		Addr:         ":" + cfg.Server.Port,
+		Handler:      sessionHandler.CORSMiddleware(apiHandler),
+		ReadTimeout:  time.Duration(cfg.Server.ReadTimeout) * time.Second,
+		WriteTimeout: time.Duration(cfg.Server.WriteTimeout) * time.Second,
+	}
+
+	// Initialize the optional sensor feed listener
+	if cfg.Ingest.TCPAddr != "" || cfg.Ingest.UDPAddr != "" {
+		app.ingestListener = ingest.NewListener(ingest.Config{
+			TCPAddr: cfg.Ingest.TCPAddr,
+			UDPAddr: cfg.Ingest.UDPAddr,
+		}, environmentalService)
+	}
+
+	// Initialize the optional MQTT bridge
+	if cfg.MQTT.BrokerURL != "" {
+		routes, err := ingest.ParseMQTTRoutes(cfg.MQTT.Routes)
+		if err != nil {
+			return nil, err
+		}
+		app.mqttBridge, err = ingest.NewMQTTBridge(ingest.MQTTConfig{
+			BrokerURL: cfg.MQTT.BrokerURL,
+			ClientID:  cfg.MQTT.ClientID,
+			Username:  cfg.MQTT.Username,
+			Password:  cfg.MQTT.Password,
+			Routes:    routes,
+		}, environmentalService, environmentalService, sessionService, sessionService)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	app.scheduler = newBackgroundScheduler(app, cfg)
+
 	return app, nil
 }
 
+// newBackgroundScheduler schedules inactivity expiry, session state saves,
+// storage cleanup, webhook deliveries and purging of expired idempotency
+// keys, change feed tombstones and the webhook delivery log
+func newBackgroundScheduler(app *Application, cfg *config.Config) *Scheduler {
+	inactivityTimeout := time.Duration(cfg.Scheduler.InactivityTimeout) * time.Second
+	expiryInterval := time.Duration(cfg.Scheduler.ExpiryInterval) * time.Second
+	if inactivityTimeout <= 0 {
+		expiryInterval = 0
+	}
+
+	return NewScheduler(
+		ScheduledJob{
+			Name:     "session-expiry",
+			Interval: expiryInterval,
+			Run: func(ctx context.Context) error {
+				return app.sessionManager.CleanupExpiredSessions(ctx, inactivityTimeout)
+			},
+		},
+		ScheduledJob{
+			Name:     "session-save",
+			Interval: time.Duration(cfg.Scheduler.SaveInterval) * time.Second,
+			Run:      app.sessionManager.SaveSessionState,
+		},
+		ScheduledJob{
+			Name:     "storage-cleanup",
+			Interval: cleanupInterval(cfg),
+			Run: func(ctx context.Context) error {
+				stats, err := app.cleanupManager.RunCleanup(ctx, cleanupConfig(cfg))
+				if err != nil {
+					return err
+				}
+				log.Printf("Storage cleanup: %d sessions, %d files, %d bytes freed in %v",
+					stats.SessionsCleaned, stats.FilesCleaned, stats.BytesFreed, stats.Duration)
+				return nil
+			},
+		},
+		ScheduledJob{
+			Name:     "idempotency-purge",
+			Interval: time.Duration(cfg.Scheduler.IdempotencyPurge) * time.Second,
+			Run: func(ctx context.Context) error {
+				_, err := app.idempotency.PurgeExpired(ctx)
+				return err
+			},
+		},
+		ScheduledJob{
+			Name:     "tombstone-purge",
+			Interval: time.Duration(cfg.Scheduler.TombstonePurge) * time.Second,
+			Run: func(ctx context.Context) error {
+				_, err := app.syncService.PurgeTombstones(ctx, time.Duration(cfg.Sync.TombstoneRetention)*time.Second)
+				return err
+			},
+		},
+		ScheduledJob{
+			Name:     "webhook-delivery",
+			Interval: time.Duration(cfg.Scheduler.WebhookDelivery) * time.Second,
+			Run: func(ctx context.Context) error {
+				_, err := app.webhookService.DeliverDue(ctx)
+				return err
+			},
+			Wake: app.webhookService.Wake(),
+		},
+		ScheduledJob{
+			Name:     "webhook-delivery-purge",
+			Interval: time.Duration(cfg.Scheduler.WebhookPurge) * time.Second,
+			Run: func(ctx context.Context) error {
+				_, err := app.webhookService.PurgeDeliveries(ctx, time.Duration(cfg.Webhook.DeliveryRetention)*time.Second)
+				return err
+			},
+		},
+	)
+}
+
+// Start begins serving HTTP and, when configured, the sensor feed and MQTT bridge
+func (app *Application) Start() error {
+	if app.ingestListener != nil {
+		if err := app.ingestListener.Start(); err != nil {
+			return fmt.Errorf("failed to start sensor ingest listener: %w", err)
+		}
+	}
+	if app.mqttBridge != nil {
+		if err := app.mqttBridge.Start(); err != nil {
+			return fmt.Errorf("failed to start MQTT bridge: %w", err)
+		}
+	}
+
+	app.scheduler.Start()
+
+	go func() {
+		log.Printf("Starting OtherSide application on %s...", app.httpServer.Addr)
+		if err := app.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("HTTP server failed: %v", err)
+		}
+	}()
+
+	return nil
+}
+
 // Shutdown gracefully shuts down the application
 func (app *Application) Shutdown(ctx context.Context) error {
 	log.Println("Shutting down application components...")
 
+	// Stop accepting requests and sensor data. Open event streams never go
+	// idle, so they are ended first.
+	app.eventHub.Close()
+	if err := app.httpServer.Shutdown(ctx); err != nil {
+		log.Printf("Error shutting down HTTP server: %v", err)
+	}
+	if app.ingestListener != nil {
+		if err := app.ingestListener.Shutdown(ctx); err != nil {
+			log.Printf("Error shutting down sensor ingest listener: %v", err)
+		}
+	}
+	if app.mqttBridge != nil {
+		if err := app.mqttBridge.Shutdown(ctx); err != nil {
+			log.Printf("Error shutting down MQTT bridge: %v", err)
+		}
+	}
+
+	// Stop background jobs before the state they work on goes away
+	if err := app.scheduler.Shutdown(ctx); err != nil {
+		log.Printf("Error shutting down background scheduler: %v", err)
+	}
+
 	// Save session states
 	if err := app.sessionManager.Shutdown(ctx); err != nil {
 		log.Printf("Error shutting down session manager: %v", err)
@@ -132,14 +476,18 @@ func (app *Application) Shutdown(ctx context.Context) error {
 	return nil
 }
 
-// runCleanup performs cleanup operations
-func runCleanup(db *repository.DB, cfg *config.Config) {
-	ctx := context.Background()
-	fileManager := repository.NewFileManager(db.DB, cfg.Storage.DataPath)
-	cleanupManager := repository.NewCleanupManager(db.DB, fileManager)
+// cleanupInterval returns how often storage cleanup runs in the background,
+// zero unless it has been enabled since it deletes sessions and files
+func cleanupInterval(cfg *config.Config) time.Duration {
+	if !cfg.Scheduler.CleanupEnabled {
+		return 0
+	}
+	return time.Duration(cfg.Scheduler.CleanupInterval) * time.Second
+}
 
-	// Default cleanup configuration
-	cleanupConfig := repository.CleanupConfig{
+// cleanupConfig returns the cleanup configuration derived from the storage settings
+func cleanupConfig(cfg *config.Config) repository.CleanupConfig {
+	return repository.CleanupConfig{
 		MaxSessionAge:         time.Duration(cfg.Storage.RetentionDays) * 24 * time.Hour,
 		MaxFileSizeBytes:      int64(cfg.Storage.MaxSizeGB) * 1024 * 1024 * 1024 / 10, // 10% of max storage
 		MaxStorageSizeBytes:   int64(cfg.Storage.MaxSizeGB) * 1024 * 1024 * 1024,
@@ -147,6 +495,15 @@ func runCleanup(db *repository.DB, cfg *config.Config) {
 		EnableDatabaseCleanup: true,
 		EnableOrphanCleanup:   true,
 	}
+}
+
+// runCleanup performs cleanup operations
+func runCleanup(db *repository.DB, cfg *config.Config) {
+	ctx := context.Background()
+	fileManager := repository.NewFileManager(db.DB, cfg.Storage.DataPath)
+	cleanupManager := repository.NewCleanupManager(db.DB, fileManager)
+
+	cleanupConfig := cleanupConfig(cfg)
 
 	// Show cleanup plan first
 	fmt.Println("Cleanup Plan:")
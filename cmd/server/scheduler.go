@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// defaultJitter is the fraction of an interval added at random to each wait
+// so that jobs on several instances, or with equal intervals, drift apart
+const defaultJitter = 0.1
+
+// ScheduledJob is a task run repeatedly in the background. A value
+// received on Wake runs the job at once instead of at its next interval.
+type ScheduledJob struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+	Wake     <-chan struct{}
+}
+
+// Scheduler runs background jobs on their intervals. Each job has its own
+// goroutine and waits for its previous run to finish before scheduling the
+// next one, so a slow run delays the job instead of overlapping with it.
+type Scheduler struct {
+	jobs   []ScheduledJob
+	jitter float64
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewScheduler creates a scheduler for the given jobs. Jobs with a zero or
+// negative interval are disabled.
+func NewScheduler(jobs ...ScheduledJob) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	scheduler := &Scheduler{
+		jitter: defaultJitter,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	for _, job := range jobs {
+		if job.Interval <= 0 {
+			log.Printf("Background job %s is disabled", job.Name)
+			continue
+		}
+		scheduler.jobs = append(scheduler.jobs, job)
+	}
+
+	return scheduler
+}
+
+// Start begins running the jobs. The first run of each job happens one
+// interval after Start.
+func (s *Scheduler) Start() {
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(job)
+		log.Printf("Background job %s scheduled every %v", job.Name, job.Interval)
+	}
+}
+
+// Shutdown stops scheduling jobs, cancels the context of any run in
+// progress and waits for it to return or for ctx to be done
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// loop runs one job until the scheduler is shut down
+func (s *Scheduler) loop(job ScheduledJob) {
+	defer s.wg.Done()
+
+	timer := time.NewTimer(s.delay(job.Interval))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-timer.C:
+		case <-job.Wake:
+			timer.Stop()
+		}
+
+		start := time.Now()
+		if err := job.Run(s.ctx); err != nil && s.ctx.Err() == nil {
+			log.Printf("Background job %s failed after %v: %v", job.Name, time.Since(start), err)
+		}
+
+		timer.Reset(s.delay(job.Interval))
+	}
+}
+
+// delay returns the interval plus a random jitter of up to s.jitter of it
+func (s *Scheduler) delay(interval time.Duration) time.Duration {
+	spread := int64(float64(interval) * s.jitter)
+	if spread <= 0 {
+		return interval
+	}
+	return interval + time.Duration(rand.Int63n(spread+1))
+}
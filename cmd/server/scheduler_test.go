@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/myideascope/otherside/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_SlowJob_RunsRepeatedlyWithoutOverlap(t *testing.T) {
+	// Arrange
+	var runs, running, overlaps atomic.Int32
+	scheduler := NewScheduler(ScheduledJob{
+		Name:     "slow",
+		Interval: time.Millisecond,
+		Run: func(ctx context.Context) error {
+			if running.Add(1) > 1 {
+				overlaps.Add(1)
+			}
+			time.Sleep(10 * time.Millisecond)
+			running.Add(-1)
+			runs.Add(1)
+			return nil
+		},
+	})
+
+	// Act
+	scheduler.Start()
+	require.Eventually(t, func() bool { return runs.Load() >= 3 }, 5*time.Second, time.Millisecond)
+	err := scheduler.Shutdown(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Zero(t, overlaps.Load())
+}
+
+func TestScheduler_Shutdown_CancelsRunInProgress(t *testing.T) {
+	// Arrange
+	started := make(chan struct{})
+	var cancelled atomic.Bool
+	scheduler := NewScheduler(ScheduledJob{
+		Name:     "blocking",
+		Interval: time.Millisecond,
+		Run: func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			cancelled.Store(true)
+			return ctx.Err()
+		},
+	})
+	scheduler.Start()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Act
+	err := scheduler.Shutdown(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, cancelled.Load())
+}
+
+func TestScheduler_Shutdown_StuckJob_ReturnsContextError(t *testing.T) {
+	// Arrange
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{})
+	scheduler := NewScheduler(ScheduledJob{
+		Name:     "stuck",
+		Interval: time.Millisecond,
+		Run: func(ctx context.Context) error {
+			close(started)
+			<-release
+			return nil
+		},
+	})
+	scheduler.Start()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	// Act
+	err := scheduler.Shutdown(ctx)
+
+	// Assert
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestScheduler_Wake_RunsJobBeforeInterval(t *testing.T) {
+	// Arrange
+	wake := make(chan struct{}, 1)
+	var runs atomic.Int32
+	scheduler := NewScheduler(ScheduledJob{
+		Name:     "woken",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			runs.Add(1)
+			return nil
+		},
+		Wake: wake,
+	})
+	scheduler.Start()
+
+	// Act
+	wake <- struct{}{}
+	require.Eventually(t, func() bool { return runs.Load() == 1 }, 5*time.Second, time.Millisecond)
+	wake <- struct{}{}
+	require.Eventually(t, func() bool { return runs.Load() == 2 }, 5*time.Second, time.Millisecond)
+	err := scheduler.Shutdown(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), runs.Load())
+}
+
+func TestScheduler_ZeroInterval_JobDisabled(t *testing.T) {
+	// Arrange
+	var runs atomic.Int32
+	scheduler := NewScheduler(ScheduledJob{
+		Name: "disabled",
+		Run: func(ctx context.Context) error {
+			runs.Add(1)
+			return nil
+		},
+	})
+
+	// Act
+	scheduler.Start()
+	time.Sleep(20 * time.Millisecond)
+	err := scheduler.Shutdown(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Zero(t, runs.Load())
+	assert.Empty(t, scheduler.jobs)
+}
+
+func TestCleanupInterval_NotEnabled_Disabled(t *testing.T) {
+	// Arrange
+	cfg := &config.Config{Scheduler: config.SchedulerConfig{CleanupInterval: 60}}
+
+	// Act
+	disabled := cleanupInterval(cfg)
+	cfg.Scheduler.CleanupEnabled = true
+	enabled := cleanupInterval(cfg)
+
+	// Assert
+	assert.Zero(t, disabled)
+	assert.Equal(t, time.Minute, enabled)
+}
+
+func TestScheduler_Delay_AddsBoundedJitter(t *testing.T) {
+	scheduler := NewScheduler()
+
+	for i := 0; i < 100; i++ {
+		delay := scheduler.delay(time.Second)
+		assert.GreaterOrEqual(t, delay, time.Second)
+		assert.LessOrEqual(t, delay, time.Second+100*time.Millisecond)
+	}
+}
@@ -7,10 +7,17 @@ import (
 
 // Config holds all configuration for the application
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Audio    AudioConfig
-	Storage  StorageConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	Audio     AudioConfig
+	Storage   StorageConfig
+	Ingest    IngestConfig
+	MQTT      MQTTConfig
+	Scheduler SchedulerConfig
+	Auth      AuthConfig
+	Sync      SyncConfig
+	Stream    StreamConfig
+	Webhook   WebhookConfig
 }
 
 // ServerConfig holds server-related configuration
@@ -47,6 +54,83 @@ type StorageConfig struct {
 	RetentionDays int
 }
 
+// IngestConfig holds the sensor feed listener configuration. An empty
+// address disables that transport.
+type IngestConfig struct {
+	TCPAddr string
+	UDPAddr string
+}
+
+// MQTTConfig holds the MQTT bridge configuration. An empty broker URL
+// disables the bridge. Routes are "pattern=kind" pairs separated by
+// semicolons.
+type MQTTConfig struct {
+	BrokerURL string
+	ClientID  string
+	Username  string
+	Password  string
+	Routes    string
+}
+
+// SchedulerConfig holds the background job intervals, in seconds. A zero
+// interval disables that job. Storage cleanup deletes old sessions and
+// files, so it only runs on its interval when CleanupEnabled is set.
+type SchedulerConfig struct {
+	ExpiryInterval    int
+	InactivityTimeout int
+	SaveInterval      int
+	CleanupEnabled    bool
+	CleanupInterval   int
+	IdempotencyPurge  int
+	TombstonePurge    int
+	WebhookDelivery   int
+	WebhookPurge      int
+}
+
+// AuthConfig holds API authentication configuration. It is off unless
+// enabled, since the bundled web app sends no credentials. An empty token
+// secret is replaced by one generated and kept under the data path.
+type AuthConfig struct {
+	Enabled     bool
+	TokenSecret string
+	TokenTTL    int
+}
+
+// SyncConfig holds offline sync configuration, in seconds.
+// IdempotencyTTL is how long a stored response answers retries sent with
+// its Idempotency-Key, and TombstoneRetention how long the change feed
+// keeps deleted records.
+type SyncConfig struct {
+	IdempotencyTTL     int
+	TombstoneRetention int
+}
+
+// StreamConfig holds live event stream configuration. HeartbeatInterval
+// is in seconds; ReplayEvents is how many recent events of each session are
+// kept for clients resuming a dropped stream.
+type StreamConfig struct {
+	HeartbeatInterval int
+	ReplayEvents      int
+}
+
+// WebhookConfig holds outbound webhook configuration. RetryBackoff is the
+// wait in seconds after a first failed attempt, doubled with each further
+// failure up to MaxBackoff; DeliveryRetention is how long, in seconds, the
+// delivery log keeps finished deliveries. EVPMinConfidence is the detection
+// level from which an EVP is sent as evp.high_confidence.
+// AllowPrivateAddresses lets webhooks reach loopback, link-local and
+// private addresses. It is off by default so that subscriptions cannot
+// probe the server's own network; receivers on the LAN, such as a local
+// dashboard, need it on.
+type WebhookConfig struct {
+	RetryBackoff          int
+	MaxBackoff            int
+	MaxAttempts           int
+	DeliveryRetention     int
+	EVPMinConfidence      float64
+	AllowPrivateAddresses bool
+}
+
 // Load loads configuration from environment variables with defaults
 func Load() *Config {
 	return &Config{
@@ -76,6 +160,49 @@ func Load() *Config {
 			MaxSizeGB:     getEnvAsInt("MAX_SIZE_GB", 10),
 			RetentionDays: getEnvAsInt("RETENTION_DAYS", 30),
 		},
+		Ingest: IngestConfig{
+			TCPAddr: getEnv("INGEST_TCP_ADDR", ""),
+			UDPAddr: getEnv("INGEST_UDP_ADDR", ""),
+		},
+		MQTT: MQTTConfig{
+			BrokerURL: getEnv("MQTT_BROKER_URL", ""),
+			ClientID:  getEnv("MQTT_CLIENT_ID", "otherside"),
+			Username:  getEnv("MQTT_USERNAME", ""),
+			Password:  getEnv("MQTT_PASSWORD", ""),
+			Routes:    getEnv("MQTT_ROUTES", "otherside/{device}/environmental=environmental;otherside/{device}/radar=radar;otherside/{device}/vox=vox"),
+		},
+		Scheduler: SchedulerConfig{
+			ExpiryInterval:    getEnvAsInt("SESSION_EXPIRY_INTERVAL", 300),
+			InactivityTimeout: getEnvAsInt("SESSION_INACTIVITY_TIMEOUT", 4*60*60),
+			SaveInterval:      getEnvAsInt("SESSION_SAVE_INTERVAL", 60),
+			CleanupEnabled:    getEnvAsBool("CLEANUP_ENABLED", false),
+			CleanupInterval:   getEnvAsInt("CLEANUP_INTERVAL", 6*60*60),
+			IdempotencyPurge:  getEnvAsInt("IDEMPOTENCY_PURGE_INTERVAL", 60*60),
+			TombstonePurge:    getEnvAsInt("TOMBSTONE_PURGE_INTERVAL", 24*60*60),
+			WebhookDelivery:   getEnvAsInt("WEBHOOK_DELIVERY_INTERVAL", 5),
+			WebhookPurge:      getEnvAsInt("WEBHOOK_PURGE_INTERVAL", 24*60*60),
+		},
+		Auth: AuthConfig{
+			Enabled:     getEnvAsBool("AUTH_ENABLED", false),
+			TokenSecret: getEnv("AUTH_TOKEN_SECRET", ""),
+			TokenTTL:    getEnvAsInt("AUTH_TOKEN_TTL", 60*60),
+		},
+		Sync: SyncConfig{
+			IdempotencyTTL:     getEnvAsInt("IDEMPOTENCY_KEY_TTL", 24*60*60),
+			TombstoneRetention: getEnvAsInt("TOMBSTONE_RETENTION", 30*24*60*60),
+		},
+		Stream: StreamConfig{
+			HeartbeatInterval: getEnvAsInt("STREAM_HEARTBEAT_INTERVAL", 15),
+			ReplayEvents:      getEnvAsInt("STREAM_REPLAY_EVENTS", 256),
+		},
+		Webhook: WebhookConfig{
+			RetryBackoff:          getEnvAsInt("WEBHOOK_RETRY_BACKOFF", 10),
+			MaxBackoff:            getEnvAsInt("WEBHOOK_MAX_BACKOFF", 60*60),
+			MaxAttempts:           getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 8),
+			DeliveryRetention:     getEnvAsInt("WEBHOOK_DELIVERY_RETENTION", 30*24*60*60),
+			EVPMinConfidence:      getEnvAsFloat("WEBHOOK_EVP_MIN_CONFIDENCE", 0.8),
+			AllowPrivateAddresses: getEnvAsBool("WEBHOOK_ALLOW_PRIVATE_ADDRESSES", false),
+		},
 	}
 }
 
@@ -102,3 +229,11 @@ func getEnvAsFloat(name string, defaultVal float64) float64 {
 	}
 	return defaultVal
 }
+
+func getEnvAsBool(name string, defaultVal bool) bool {
+	valueStr := getEnv(name, "")
+	if value, err := strconv.ParseBool(valueStr); err == nil {
+		return value
+	}
+	return defaultVal
+}
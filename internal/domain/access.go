@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"time"
+)
+
+// Team is a group of investigators that can share sessions
+type Team struct {
+	ID        string    `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// TeamMember is an investigator's membership in a team
+type TeamMember struct {
+	TeamID         string    `json:"team_id" db:"team_id"`
+	InvestigatorID string    `json:"investigator_id" db:"investigator_id"`
+	JoinedAt       time.Time `json:"joined_at" db:"joined_at"`
+}
+
+// AccessLevel is what a caller may do with a session. Each level includes
+// the ones below it.
+type AccessLevel string
+
+const (
+	// AccessNone grants nothing
+	AccessNone AccessLevel = ""
+	// AccessGuest may read the session
+	AccessGuest AccessLevel = "guest"
+	// AccessTeam may also record events and edit the session
+	AccessTeam AccessLevel = "team"
+	// AccessOwner may also delete, archive and share the session
+	AccessOwner AccessLevel = "owner"
+)
+
+// rank orders access levels
+func (l AccessLevel) rank() int {
+	switch l {
+	case AccessGuest:
+		return 1
+	case AccessTeam:
+		return 2
+	case AccessOwner:
+		return 3
+	}
+	return 0
+}
+
+// Allows reports whether l includes the required level
+func (l AccessLevel) Allows(required AccessLevel) bool {
+	return l.rank() >= required.rank()
+}
+
+// SessionACL lists who may access a session. A session without an owner
+// is open at team level to its team. Open marks sessions that never had an
+// owner or team, such as those created by devices or before access control,
+// which are open to every investigator; a session that lost its team stays
+// closed.
+type SessionACL struct {
+	SessionID string    `json:"session_id" db:"session_id"`
+	OwnerID   string    `json:"owner_id,omitempty" db:"owner_id"`
+	TeamID    string    `json:"team_id,omitempty" db:"team_id"`
+	GuestIDs  []string  `json:"guest_ids"`
+	Open      bool      `json:"open"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ExportRecord remembers who created an export file and which sessions it holds
+type ExportRecord struct {
+	Filename   string    `json:"filename" db:"filename"`
+	CreatedBy  string    `json:"created_by,omitempty" db:"created_by"`
+	SessionIDs []string  `json:"session_ids" db:"session_ids"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"time"
+)
+
+// AlertRule raises an alert when every one of its conditions is met by
+// events of one session no more than WindowSeconds apart. A rule without a
+// session applies to every session its author can read. DebounceSeconds
+// holds back further alerts of the rule in the same session after one
+// fired.
+type AlertRule struct {
+	ID              string           `json:"id" db:"id"`
+	Name            string           `json:"name" db:"name"`
+	SessionID       string           `json:"session_id,omitempty" db:"session_id"`
+	Conditions      []AlertCondition `json:"conditions" db:"conditions"`
+	WindowSeconds   float64          `json:"window_seconds" db:"window_seconds"`
+	DebounceSeconds float64          `json:"debounce_seconds" db:"debounce_seconds"`
+	WebhookURL      string           `json:"webhook_url,omitempty" db:"webhook_url"`
+	Enabled         bool             `json:"enabled" db:"enabled"`
+	CreatedBy       string           `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt       time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// AlertCondition compares one field of an event type with a value, such as
+// radar strength > 0.7. For environmental readings the field is the metric.
+// Value is a number, or a string for the == and != operators.
+type AlertCondition struct {
+	Event string      `json:"event"`
+	Field string      `json:"field"`
+	Op    string      `json:"op"`
+	Value interface{} `json:"value"`
+}
+
+// Alert is a firing of an alert rule in a session
+type Alert struct {
+	ID          string       `json:"id" db:"id"`
+	RuleID      string       `json:"rule_id" db:"rule_id"`
+	RuleName    string       `json:"rule_name" db:"rule_name"`
+	SessionID   string       `json:"session_id" db:"session_id"`
+	Message     string       `json:"message" db:"message"`
+	Matches     []AlertMatch `json:"matches" db:"matches"`
+	TriggeredAt time.Time    `json:"triggered_at" db:"triggered_at"`
+}
+
+// AlertMatch is the event that met one condition of a fired rule
+type AlertMatch struct {
+	Event     string      `json:"event"`
+	EventID   string      `json:"event_id"`
+	Field     string      `json:"field"`
+	Value     interface{} `json:"value"`
+	Timestamp time.Time   `json:"timestamp"`
+}
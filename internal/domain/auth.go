@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// PrincipalKind is the kind of caller an API key or token belongs to
+type PrincipalKind string
+
+const (
+	PrincipalDevice       PrincipalKind = "device"
+	PrincipalInvestigator PrincipalKind = "investigator"
+)
+
+// APIKey is a long-lived credential for a device or investigator. The key
+// itself is only shown when it is minted; KeyHash is its SHA-256 hash.
+type APIKey struct {
+	ID            string        `json:"id" db:"id"`
+	Name          string        `json:"name" db:"name"`
+	KeyHash       string        `json:"-" db:"key_hash"`
+	PrincipalKind PrincipalKind `json:"principal_kind" db:"principal_kind"`
+	PrincipalID   string        `json:"principal_id" db:"principal_id"`
+	CreatedAt     time.Time     `json:"created_at" db:"created_at"`
+	LastUsedAt    *time.Time    `json:"last_used_at,omitempty" db:"last_used_at"`
+	RevokedAt     *time.Time    `json:"revoked_at,omitempty" db:"revoked_at"`
+}
+
+// IsRevoked reports whether the key has been revoked
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
+
+// AuthMethod is how a request proved its identity
+type AuthMethod string
+
+const (
+	AuthMethodAPIKey AuthMethod = "api_key"
+	AuthMethodBearer AuthMethod = "bearer"
+)
+
+// Identity is the authenticated caller of a request
+type Identity struct {
+	Kind   PrincipalKind `json:"kind"`
+	ID     string        `json:"id"`
+	KeyID  string        `json:"key_id"`
+	Method AuthMethod    `json:"method"`
+}
+
+type identityContextKey struct{}
+
+// ContextWithIdentity returns a copy of ctx carrying the caller's identity
+func ContextWithIdentity(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, identityContextKey{}, identity)
+}
+
+// IdentityFromContext returns the caller's identity, or nil when the
+// request was not authenticated
+func IdentityFromContext(ctx context.Context) *Identity {
+	identity, _ := ctx.Value(identityContextKey{}).(*Identity)
+	return identity
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrVersionConflict is returned by repositories for an update based on a
+// version of a record that is no longer the stored one
+var ErrVersionConflict = errors.New("version conflict")
+
+// VersionConflictError is returned for an update based on an outdated copy
+// of a record. Current holds the stored copy the caller should merge with.
+type VersionConflictError struct {
+	Entity  string
+	ID      string
+	Version int64
+	Current interface{}
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("version conflict: %s %s has changed and is now at version %d", e.Entity, e.ID, e.Version)
+}
+
+func (e *VersionConflictError) Unwrap() error {
+	return ErrVersionConflict
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"time"
+)
+
+// EnvironmentalMetric identifies a continuously logged environmental quantity
+type EnvironmentalMetric string
+
+const (
+	EnvironmentalMetricTemperature EnvironmentalMetric = "temperature"
+	EnvironmentalMetricHumidity    EnvironmentalMetric = "humidity"
+	EnvironmentalMetricPressure    EnvironmentalMetric = "pressure"
+	EnvironmentalMetricEMF         EnvironmentalMetric = "emf"
+	EnvironmentalMetricLight       EnvironmentalMetric = "light"
+	EnvironmentalMetricNoise       EnvironmentalMetric = "noise"
+)
+
+// EnvironmentalReading represents a single sensor sample during a session
+type EnvironmentalReading struct {
+	ID        string              `json:"id" db:"id"`
+	SessionID string              `json:"session_id" db:"session_id"`
+	DeviceID  string              `json:"device_id" db:"device_id"`
+	Timestamp time.Time           `json:"timestamp" db:"timestamp"`
+	Metric    EnvironmentalMetric `json:"metric" db:"metric"`
+	Value     float64             `json:"value" db:"value"`
+	CreatedAt time.Time           `json:"created_at" db:"created_at"`
+}
+
+// EnvironmentalAnomaly represents a reading that deviated from the recent
+// baseline of its device and metric
+type EnvironmentalAnomaly struct {
+	ID        string              `json:"id" db:"id"`
+	SessionID string              `json:"session_id" db:"session_id"`
+	ReadingID string              `json:"reading_id" db:"reading_id"`
+	DeviceID  string              `json:"device_id" db:"device_id"`
+	Metric    EnvironmentalMetric `json:"metric" db:"metric"`
+	Timestamp time.Time           `json:"timestamp" db:"timestamp"`
+	Value     float64             `json:"value" db:"value"`
+	Baseline  float64             `json:"baseline" db:"baseline"`
+	StdDev    float64             `json:"std_dev" db:"std_dev"`
+	Deviation float64             `json:"deviation" db:"deviation"`
+	CreatedAt time.Time           `json:"created_at" db:"created_at"`
+}
+
+// SensorRegistration routes readings pushed by a standalone sensor, which
+// does not know about sessions, to the session it is currently deployed in
+type SensorRegistration struct {
+	DeviceID     string    `json:"device_id" db:"device_id"`
+	SessionID    string    `json:"session_id" db:"session_id"`
+	Name         string    `json:"name,omitempty" db:"name"`
+	RegisteredAt time.Time `json:"registered_at" db:"registered_at"`
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"time"
+)
+
+// FloorPlan represents a building floor made of rooms, in metres
+type FloorPlan struct {
+	ID          string    `json:"id" db:"id"`
+	Name        string    `json:"name" db:"name"`
+	Description string    `json:"description" db:"description"`
+	ImagePath   string    `json:"image_path,omitempty" db:"image_path"`
+	Width       float64   `json:"width" db:"width"`
+	Height      float64   `json:"height" db:"height"`
+	Rooms       []Room    `json:"rooms"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Room represents a room outline on a floor plan. The polygon vertices are
+// building coordinates in metres.
+type Room struct {
+	ID          string        `json:"id" db:"id"`
+	FloorPlanID string        `json:"floor_plan_id" db:"floor_plan_id"`
+	Name        string        `json:"name" db:"name"`
+	Polygon     []Coordinates `json:"polygon" db:"polygon"`
+}
+
+// DevicePlacement positions an investigation device on a floor plan for a
+// session. Origin is the device location in building coordinates and Rotation
+// is the angle in radians from the building +X axis to the device +X axis,
+// counter-clockwise.
+type DevicePlacement struct {
+	ID          string      `json:"id" db:"id"`
+	SessionID   string      `json:"session_id" db:"session_id"`
+	FloorPlanID string      `json:"floor_plan_id" db:"floor_plan_id"`
+	DeviceID    string      `json:"device_id" db:"device_id"`
+	Origin      Coordinates `json:"origin" db:"origin"`
+	Rotation    float64     `json:"rotation" db:"rotation"`
+	PlacedAt    time.Time   `json:"placed_at" db:"placed_at"`
+	CreatedAt   time.Time   `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"time"
+)
+
+// FusionEvent represents events from several sensor modalities that fired
+// within one correlation window
+type FusionEvent struct {
+	ID           string              `json:"id" db:"id"`
+	SessionID    string              `json:"session_id" db:"session_id"`
+	Rule         string              `json:"rule" db:"rule"`
+	StartTime    time.Time           `json:"start_time" db:"start_time"`
+	EndTime      time.Time           `json:"end_time" db:"end_time"`
+	Contributors []FusionContributor `json:"contributors" db:"contributors"`
+	Confidence   float64             `json:"confidence" db:"confidence"`
+	CreatedAt    time.Time           `json:"created_at" db:"created_at"`
+}
+
+// FusionContributor references a stored event that contributed to a fusion
+// event. EventType is the modality, e.g. "evp", "radar", "sls" or "emf".
+type FusionContributor struct {
+	EventType  string    `json:"event_type" db:"event_type"`
+	EventID    string    `json:"event_id" db:"event_id"`
+	Timestamp  time.Time `json:"timestamp" db:"timestamp"`
+	Confidence float64   `json:"confidence" db:"confidence"`
+}
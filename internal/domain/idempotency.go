@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"time"
+)
+
+// IdempotencyRecord is the stored outcome of a request sent with an
+// Idempotency-Key header. Keys belong to the principal that sent them, and
+// a record without CompletedAt is a request still being handled.
+type IdempotencyRecord struct {
+	Principal    string     `json:"principal" db:"principal"`
+	Key          string     `json:"key" db:"idempotency_key"`
+	Method       string     `json:"method" db:"method"`
+	Path         string     `json:"path" db:"path"`
+	RequestHash  string     `json:"request_hash" db:"request_hash"`
+	StatusCode   int        `json:"status_code" db:"status_code"`
+	ContentType  string     `json:"content_type,omitempty" db:"content_type"`
+	ResponseBody []byte     `json:"-" db:"response_body"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty" db:"completed_at"`
+}
+
+// IsComplete reports whether the response to the request has been stored
+func (r *IdempotencyRecord) IsComplete() bool {
+	return r.CompletedAt != nil
+}
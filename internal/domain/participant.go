@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"time"
+)
+
+// Investigator is a person taking part in investigations. Admins manage
+// investigators and devices.
+type Investigator struct {
+	ID        string    `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	Email     string    `json:"email,omitempty" db:"email"`
+	IsAdmin   bool      `json:"is_admin" db:"is_admin"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// Device is a piece of investigation equipment that produces events. Its ID
+// is the device ID sensors report with.
+type Device struct {
+	ID           string    `json:"id" db:"id"`
+	Name         string    `json:"name" db:"name"`
+	Kind         string    `json:"kind,omitempty" db:"kind"`
+	SerialNumber string    `json:"serial_number,omitempty" db:"serial_number"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// ParticipantRole is what an investigator does in a session
+type ParticipantRole string
+
+const (
+	ParticipantRoleLead         ParticipantRole = "lead"
+	ParticipantRoleInvestigator ParticipantRole = "investigator"
+	ParticipantRoleObserver     ParticipantRole = "observer"
+)
+
+// IsValid reports whether r is a known participant role
+func (r ParticipantRole) IsValid() bool {
+	switch r {
+	case ParticipantRoleLead, ParticipantRoleInvestigator, ParticipantRoleObserver:
+		return true
+	}
+	return false
+}
+
+// CanRecordEvents reports whether events may be attributed to a participant
+// with this role. Observers only watch.
+func (r ParticipantRole) CanRecordEvents() bool {
+	return r == ParticipantRoleLead || r == ParticipantRoleInvestigator
+}
+
+// SessionParticipant is an investigator's membership in a session
+type SessionParticipant struct {
+	SessionID      string          `json:"session_id" db:"session_id"`
+	InvestigatorID string          `json:"investigator_id" db:"investigator_id"`
+	Role           ParticipantRole `json:"role" db:"role"`
+	JoinedAt       time.Time       `json:"joined_at" db:"joined_at"`
+}
@@ -14,6 +14,14 @@ type SessionRepository interface {
 	Update(ctx context.Context, session *Session) error
 	Delete(ctx context.Context, id string) error
 	GetByDateRange(ctx context.Context, start, end time.Time) ([]*Session, error)
+	// UpdateStatus moves a session from change.FromStatus to change.ToStatus
+	// and records the change. It fails with sql.ErrNoRows when the session is
+	// no longer in change.FromStatus.
+	UpdateStatus(ctx context.Context, session *Session, change *SessionStatusChange) error
+	GetStatusHistory(ctx context.Context, sessionID string) ([]*SessionStatusChange, error)
+	// Touch records activity on a session by setting its updated time to at.
+	// It fails with sql.ErrNoRows when the session does not exist.
+	Touch(ctx context.Context, id string, at time.Time) error
 }
 
 // EVPRepository defines the interface for EVP recording operations
@@ -49,6 +57,214 @@ type RadarRepository interface {
 	GetByStrengthRange(ctx context.Context, minStrength, maxStrength float64) ([]*RadarEvent, error)
 }
 
+// RadarTrackRepository defines the interface for radar track operations
+type RadarTrackRepository interface {
+	ReplaceBySessionID(ctx context.Context, sessionID string, tracks []*RadarTrack) error
+	GetByID(ctx context.Context, id string) (*RadarTrack, error)
+	GetBySessionID(ctx context.Context, sessionID string) ([]*RadarTrack, error)
+	DeleteBySessionID(ctx context.Context, sessionID string) error
+}
+
+// FusionEventRepository defines the interface for fusion event operations
+type FusionEventRepository interface {
+	ReplaceBySessionID(ctx context.Context, sessionID string, events []*FusionEvent) error
+	GetByID(ctx context.Context, id string) (*FusionEvent, error)
+	GetBySessionID(ctx context.Context, sessionID string) ([]*FusionEvent, error)
+	DeleteBySessionID(ctx context.Context, sessionID string) error
+}
+
+// FloorPlanRepository defines the interface for floor plan operations.
+// Rooms are stored and loaded together with their floor plan.
+type FloorPlanRepository interface {
+	Create(ctx context.Context, plan *FloorPlan) error
+	GetByID(ctx context.Context, id string) (*FloorPlan, error)
+	GetAll(ctx context.Context) ([]*FloorPlan, error)
+	Update(ctx context.Context, plan *FloorPlan) error
+	Delete(ctx context.Context, id string) error
+}
+
+// DevicePlacementRepository defines the interface for device placement operations
+type DevicePlacementRepository interface {
+	Create(ctx context.Context, placement *DevicePlacement) error
+	GetByID(ctx context.Context, id string) (*DevicePlacement, error)
+	GetBySessionID(ctx context.Context, sessionID string) ([]*DevicePlacement, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// EnvironmentalReadingRepository defines the interface for environmental time
+// series operations. A zero start or end leaves that side of the range open and
+// an empty metric matches every metric.
+type EnvironmentalReadingRepository interface {
+	CreateBatch(ctx context.Context, readings []*EnvironmentalReading) error
+	GetByID(ctx context.Context, id string) (*EnvironmentalReading, error)
+	GetBySessionID(ctx context.Context, sessionID string, metric EnvironmentalMetric, start, end time.Time) ([]*EnvironmentalReading, error)
+	DeleteBySessionID(ctx context.Context, sessionID string) error
+}
+
+// EnvironmentalAnomalyRepository defines the interface for environmental anomaly operations
+type EnvironmentalAnomalyRepository interface {
+	CreateBatch(ctx context.Context, anomalies []*EnvironmentalAnomaly) error
+	GetBySessionID(ctx context.Context, sessionID string) ([]*EnvironmentalAnomaly, error)
+}
+
+// SensorRegistrationRepository defines the interface for sensor registration
+// operations. Save replaces any earlier registration of the device.
+type SensorRegistrationRepository interface {
+	Save(ctx context.Context, registration *SensorRegistration) error
+	GetByDeviceID(ctx context.Context, deviceID string) (*SensorRegistration, error)
+	GetBySessionID(ctx context.Context, sessionID string) ([]*SensorRegistration, error)
+	Delete(ctx context.Context, deviceID string) error
+}
+
+// InvestigatorRepository defines the interface for investigator operations
+type InvestigatorRepository interface {
+	Create(ctx context.Context, investigator *Investigator) error
+	GetByID(ctx context.Context, id string) (*Investigator, error)
+	GetAll(ctx context.Context) ([]*Investigator, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// DeviceRepository defines the interface for device operations
+type DeviceRepository interface {
+	Create(ctx context.Context, device *Device) error
+	GetByID(ctx context.Context, id string) (*Device, error)
+	GetAll(ctx context.Context) ([]*Device, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// SessionParticipantRepository defines the interface for session participant
+// operations. Save replaces the role of an existing participant.
+type SessionParticipantRepository interface {
+	Save(ctx context.Context, participant *SessionParticipant) error
+	Get(ctx context.Context, sessionID, investigatorID string) (*SessionParticipant, error)
+	GetBySessionID(ctx context.Context, sessionID string) ([]*SessionParticipant, error)
+	Delete(ctx context.Context, sessionID, investigatorID string) error
+}
+
+// APIKeyRepository defines the interface for API key operations. Revoke
+// fails with sql.ErrNoRows when the key does not exist.
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *APIKey) error
+	GetByID(ctx context.Context, id string) (*APIKey, error)
+	GetAll(ctx context.Context) ([]*APIKey, error)
+	Revoke(ctx context.Context, id string, at time.Time) error
+	MarkUsed(ctx context.Context, id string, at time.Time) error
+}
+
+// TeamRepository defines the interface for team operations
+type TeamRepository interface {
+	Create(ctx context.Context, team *Team) error
+	GetByID(ctx context.Context, id string) (*Team, error)
+	GetAll(ctx context.Context) ([]*Team, error)
+	GetByInvestigator(ctx context.Context, investigatorID string) ([]*Team, error)
+	Delete(ctx context.Context, id string) error
+	AddMember(ctx context.Context, member *TeamMember) error
+	RemoveMember(ctx context.Context, teamID, investigatorID string) error
+	GetMembers(ctx context.Context, teamID string) ([]*TeamMember, error)
+	IsMember(ctx context.Context, teamID, investigatorID string) (bool, error)
+}
+
+// SessionACLRepository defines the interface for session access lists. Get
+// returns an empty, open list for sessions that have none.
+type SessionACLRepository interface {
+	Get(ctx context.Context, sessionID string) (*SessionACL, error)
+	GetOwnedSessionIDs(ctx context.Context, investigatorID string) ([]string, error)
+	SetOwnership(ctx context.Context, sessionID, ownerID, teamID string, at time.Time) error
+	AddGuest(ctx context.Context, sessionID, investigatorID string, at time.Time) error
+	RemoveGuest(ctx context.Context, sessionID, investigatorID string) error
+}
+
+// ExportRecordRepository defines the interface for export record operations
+type ExportRecordRepository interface {
+	Create(ctx context.Context, record *ExportRecord) error
+	GetByFilename(ctx context.Context, filename string) (*ExportRecord, error)
+	Delete(ctx context.Context, filename string) error
+}
+
+// ShareLinkRepository defines the interface for share link operations.
+// Revoke fails with sql.ErrNoRows when the link does not exist.
+type ShareLinkRepository interface {
+	Create(ctx context.Context, link *ShareLink) error
+	GetByID(ctx context.Context, id string) (*ShareLink, error)
+	GetBySessionID(ctx context.Context, sessionID string) ([]*ShareLink, error)
+	Revoke(ctx context.Context, id string, at time.Time) error
+}
+
+// IdempotencyRepository defines the interface for stored idempotent
+// responses. Claim stores a pending record unless the principal already
+// used the key, and reports whether it did.
+type IdempotencyRepository interface {
+	Claim(ctx context.Context, record *IdempotencyRecord) (bool, error)
+	Get(ctx context.Context, principal, key string) (*IdempotencyRecord, error)
+	Complete(ctx context.Context, record *IdempotencyRecord) error
+	Release(ctx context.Context, principal, key string) error
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// EventBatchRepository stores a batch of session events in one transaction
+type EventBatchRepository interface {
+	CreateEvents(ctx context.Context, batch *EventBatch) error
+}
+
+// ChangeRepository defines the interface for the change feed. GetSince
+// returns changes after a seq in order, optionally for one session.
+// PurgedThrough is the highest seq of a purged tombstone.
+type ChangeRepository interface {
+	GetSince(ctx context.Context, since int64, sessionID string, limit int) ([]*Change, error)
+	PurgedThrough(ctx context.Context) (int64, error)
+	PurgeTombstones(ctx context.Context, before time.Time) (int64, error)
+}
+
+// AlertRuleRepository defines the interface for alert rule operations.
+// Update and Delete fail with sql.ErrNoRows when the rule does not exist.
+type AlertRuleRepository interface {
+	Create(ctx context.Context, rule *AlertRule) error
+	GetByID(ctx context.Context, id string) (*AlertRule, error)
+	GetAll(ctx context.Context) ([]*AlertRule, error)
+	Update(ctx context.Context, rule *AlertRule) error
+	Delete(ctx context.Context, id string) error
+}
+
+// AlertRepository defines the interface for raised alerts. GetBySessionID
+// returns the latest alerts of a session first.
+type AlertRepository interface {
+	Create(ctx context.Context, alert *Alert) error
+	GetBySessionID(ctx context.Context, sessionID string, limit int) ([]*Alert, error)
+}
+
+// SearchRepository defines the interface for the full-text index. Match
+// returns the best limit hits of an FTS5 query expression, best first,
+// among the entity types given (all types when none are); Highlight
+// returns a snippet of each hit's text keyed by DocID, with the matched
+// terms between SearchHighlightStart and SearchHighlightEnd.
+type SearchRepository interface {
+	Match(ctx context.Context, expression string, types []string, limit int) ([]*SearchHit, error)
+	Highlight(ctx context.Context, expression string, docIDs []int64) (map[int64]string, error)
+}
+
+// WebhookSubscriptionRepository defines the interface for webhook
+// subscription operations. Update and Delete fail with sql.ErrNoRows when
+// the subscription does not exist.
+type WebhookSubscriptionRepository interface {
+	Create(ctx context.Context, subscription *WebhookSubscription) error
+	GetByID(ctx context.Context, id string) (*WebhookSubscription, error)
+	GetAll(ctx context.Context) ([]*WebhookSubscription, error)
+	Update(ctx context.Context, subscription *WebhookSubscription) error
+	Delete(ctx context.Context, id string) error
+}
+
+// WebhookDeliveryRepository defines the interface for the webhook delivery
+// queue and log. GetDue returns pending deliveries whose next attempt is
+// at or before a time, earliest first; Update records an attempt.
+type WebhookDeliveryRepository interface {
+	Create(ctx context.Context, delivery *WebhookDelivery) error
+	GetByID(ctx context.Context, id string) (*WebhookDelivery, error)
+	GetBySubscriptionID(ctx context.Context, subscriptionID string, status WebhookDeliveryStatus, limit int) ([]*WebhookDelivery, error)
+	GetDue(ctx context.Context, at time.Time, limit int) ([]*WebhookDelivery, error)
+	Update(ctx context.Context, delivery *WebhookDelivery) error
+	DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
 // SLSRepository defines the interface for SLS detection operations
 type SLSRepository interface {
 	Create(ctx context.Context, sls *SLSDetection) error
@@ -70,7 +286,8 @@ type InteractionRepository interface {
 	GetByType(ctx context.Context, interactionType InteractionType) ([]*UserInteraction, error)
 }
 
-// FileRepository defines the interface for file operations
+// FileRepository defines the interface for file operations.
+// ListSessionFiles returns the paths of the files recorded for a session.
 type FileRepository interface {
 	SaveFile(ctx context.Context, path string, data []byte) error
 	GetFile(ctx context.Context, path string) ([]byte, error)
@@ -78,4 +295,5 @@ type FileRepository interface {
 	FileExists(ctx context.Context, path string) (bool, error)
 	GetFileSize(ctx context.Context, path string) (int64, error)
 	ListFiles(ctx context.Context, directory string) ([]string, error)
+	ListSessionFiles(ctx context.Context, sessionID string) ([]string, error)
 }
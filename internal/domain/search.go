@@ -0,0 +1,29 @@
+package domain
+
+// Search entity types, the kinds of record the full-text index holds
+const (
+	SearchEntitySession     = "session"
+	SearchEntityEVP         = "evp"
+	SearchEntityVOX         = "vox"
+	SearchEntityInteraction = "interaction"
+)
+
+// Markers around the matched terms of a highlight read from the index
+const (
+	SearchHighlightStart = "\x02"
+	SearchHighlightEnd   = "\x03"
+)
+
+// SearchHit is one field of a record matching a full-text search: a
+// session's title or notes, an EVP's annotations, a VOX event's generated
+// text or an interaction's content or response. A higher Score is a
+// better match.
+type SearchHit struct {
+	DocID     int64   `json:"-"`
+	SessionID string  `json:"-"`
+	Type      string  `json:"type"`
+	ID        string  `json:"id"`
+	Field     string  `json:"field"`
+	Highlight string  `json:"highlight"`
+	Score     float64 `json:"score"`
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -16,6 +17,7 @@ type Session struct {
 	Status        SessionStatus     `json:"status" db:"status"`
 	CreatedAt     time.Time         `json:"created_at" db:"created_at"`
 	UpdatedAt     time.Time         `json:"updated_at" db:"updated_at"`
+	Version       int64             `json:"version" db:"version"`
 	EVPRecordings []EVPRecording    `json:"evp_recordings,omitempty"`
 	VOXEvents     []VOXEvent        `json:"vox_events,omitempty"`
 	RadarEvents   []RadarEvent      `json:"radar_events,omitempty"`
@@ -52,6 +54,58 @@ const (
 	SessionStatusArchived SessionStatus = "archived"
 )
 
+// sessionTransitions is the session lifecycle: the statuses each status may
+// move to. Archived sessions never change again.
+var sessionTransitions = map[SessionStatus][]SessionStatus{
+	SessionStatusActive:   {SessionStatusPaused, SessionStatusComplete},
+	SessionStatusPaused:   {SessionStatusActive, SessionStatusComplete},
+	SessionStatusComplete: {SessionStatusArchived},
+	SessionStatusArchived: {},
+}
+
+// IsValid reports whether s is a known session status
+func (s SessionStatus) IsValid() bool {
+	_, ok := sessionTransitions[s]
+	return ok
+}
+
+// CanTransitionTo reports whether the lifecycle allows moving from s to next
+func (s SessionStatus) CanTransitionTo(next SessionStatus) bool {
+	for _, allowed := range sessionTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// AcceptsEvents reports whether new events may be recorded in this status
+func (s SessionStatus) AcceptsEvents() bool {
+	return s == SessionStatusActive
+}
+
+// SessionStatusChange records one lifecycle transition of a session
+type SessionStatusChange struct {
+	ID         string        `json:"id" db:"id"`
+	SessionID  string        `json:"session_id" db:"session_id"`
+	FromStatus SessionStatus `json:"from_status" db:"from_status"`
+	ToStatus   SessionStatus `json:"to_status" db:"to_status"`
+	Reason     string        `json:"reason,omitempty" db:"reason"`
+	ChangedAt  time.Time     `json:"changed_at" db:"changed_at"`
+}
+
+// SessionTransitionError is returned for a status change the session
+// lifecycle does not allow
+type SessionTransitionError struct {
+	SessionID string
+	From      SessionStatus
+	To        SessionStatus
+}
+
+func (e *SessionTransitionError) Error() string {
+	return fmt.Sprintf("invalid session transition: session %s cannot move from %s to %s", e.SessionID, e.From, e.To)
+}
+
 // EVPRecording represents an Electronic Voice Phenomenon recording
 type EVPRecording struct {
 	ID             string     `json:"id" db:"id"`
@@ -64,7 +118,10 @@ type EVPRecording struct {
 	Annotations    []string   `json:"annotations" db:"annotations"`
 	Quality        EVPQuality `json:"quality" db:"quality"`
 	DetectionLevel float64    `json:"detection_level" db:"detection_level"`
+	InvestigatorID string     `json:"investigator_id,omitempty" db:"investigator_id"`
+	DeviceID       string     `json:"device_id,omitempty" db:"device_id"`
 	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	Version        int64      `json:"version" db:"version"`
 }
 
 // EVPQuality represents the quality rating of an EVP recording
@@ -77,6 +134,16 @@ const (
 	EVPQualityPoor      EVPQuality = "poor"
 )
 
+// IsValid reports whether q is a known EVP quality rating
+func (q EVPQuality) IsValid() bool {
+	switch q {
+	case EVPQualityExcellent, EVPQualityGood, EVPQualityFair, EVPQualityPoor:
+		return true
+	default:
+		return false
+	}
+}
+
 // VOXEvent represents a Voice Synthesis (VOX) communication event
 type VOXEvent struct {
 	ID              string    `json:"id" db:"id"`
@@ -90,22 +157,27 @@ type VOXEvent struct {
 	ModulationType  string    `json:"modulation_type" db:"modulation_type"`
 	UserResponse    string    `json:"user_response,omitempty" db:"user_response"`
 	ResponseDelay   float64   `json:"response_delay,omitempty" db:"response_delay"`
+	InvestigatorID  string    `json:"investigator_id,omitempty" db:"investigator_id"`
+	DeviceID        string    `json:"device_id,omitempty" db:"device_id"`
 	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	Version         int64     `json:"version" db:"version"`
 }
 
 // RadarEvent represents a radar detection event
 type RadarEvent struct {
-	ID            string        `json:"id" db:"id"`
-	SessionID     string        `json:"session_id" db:"session_id"`
-	Timestamp     time.Time     `json:"timestamp" db:"timestamp"`
-	Position      Coordinates   `json:"position" db:"position"`
-	Strength      float64       `json:"strength" db:"strength"`
-	SourceType    SourceType    `json:"source_type" db:"source_type"`
-	EMFReading    float64       `json:"emf_reading" db:"emf_reading"`
-	AudioAnomaly  float64       `json:"audio_anomaly" db:"audio_anomaly"`
-	Duration      float64       `json:"duration" db:"duration"`
-	MovementTrail []Coordinates `json:"movement_trail,omitempty" db:"movement_trail"`
-	CreatedAt     time.Time     `json:"created_at" db:"created_at"`
+	ID             string        `json:"id" db:"id"`
+	SessionID      string        `json:"session_id" db:"session_id"`
+	Timestamp      time.Time     `json:"timestamp" db:"timestamp"`
+	Position       Coordinates   `json:"position" db:"position"`
+	Strength       float64       `json:"strength" db:"strength"`
+	SourceType     SourceType    `json:"source_type" db:"source_type"`
+	EMFReading     float64       `json:"emf_reading" db:"emf_reading"`
+	AudioAnomaly   float64       `json:"audio_anomaly" db:"audio_anomaly"`
+	Duration       float64       `json:"duration" db:"duration"`
+	MovementTrail  []Coordinates `json:"movement_trail,omitempty" db:"movement_trail"`
+	InvestigatorID string        `json:"investigator_id,omitempty" db:"investigator_id"`
+	DeviceID       string        `json:"device_id,omitempty" db:"device_id"`
+	CreatedAt      time.Time     `json:"created_at" db:"created_at"`
 }
 
 // SLSDetection represents Structured Light Sensor detection data
@@ -120,6 +192,8 @@ type SLSDetection struct {
 	FilterApplied  []string         `json:"filter_applied" db:"filter_applied"`
 	Duration       float64          `json:"duration" db:"duration"`
 	Movement       MovementAnalysis `json:"movement" db:"movement"`
+	InvestigatorID string           `json:"investigator_id,omitempty" db:"investigator_id"`
+	DeviceID       string           `json:"device_id,omitempty" db:"device_id"`
 	CreatedAt      time.Time        `json:"created_at" db:"created_at"`
 }
 
@@ -134,6 +208,8 @@ type UserInteraction struct {
 	Response         string            `json:"response,omitempty" db:"response"`
 	ResponseTime     float64           `json:"response_time,omitempty" db:"response_time"`
 	RandomizerResult *RandomizerResult `json:"randomizer_result,omitempty" db:"randomizer_result"`
+	InvestigatorID   string            `json:"investigator_id,omitempty" db:"investigator_id"`
+	DeviceID         string            `json:"device_id,omitempty" db:"device_id"`
 	CreatedAt        time.Time         `json:"created_at" db:"created_at"`
 }
 
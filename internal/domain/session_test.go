@@ -56,6 +56,39 @@ func TestSessionStatus_Transitions(t *testing.T) {
 	}
 }
 
+func TestSessionStatus_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     SessionStatus
+		to       SessionStatus
+		expected bool
+	}{
+		{"ActiveToPaused", SessionStatusActive, SessionStatusPaused, true},
+		{"ActiveToComplete", SessionStatusActive, SessionStatusComplete, true},
+		{"ActiveToArchived", SessionStatusActive, SessionStatusArchived, false},
+		{"PausedToActive", SessionStatusPaused, SessionStatusActive, true},
+		{"PausedToComplete", SessionStatusPaused, SessionStatusComplete, true},
+		{"CompleteToArchived", SessionStatusComplete, SessionStatusArchived, true},
+		{"CompleteToActive", SessionStatusComplete, SessionStatusActive, false},
+		{"ArchivedToActive", SessionStatusArchived, SessionStatusActive, false},
+		{"ActiveToActive", SessionStatusActive, SessionStatusActive, false},
+		{"UnknownToActive", SessionStatus("deleted"), SessionStatusActive, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.from.CanTransitionTo(tt.to))
+		})
+	}
+}
+
+func TestSessionStatus_AcceptsEvents_OnlyActive(t *testing.T) {
+	assert.True(t, SessionStatusActive.AcceptsEvents())
+	assert.False(t, SessionStatusPaused.AcceptsEvents())
+	assert.False(t, SessionStatusComplete.AcceptsEvents())
+	assert.False(t, SessionStatusArchived.AcceptsEvents())
+}
+
 func TestLocation_Validation(t *testing.T) {
 	tests := []struct {
 		name      string
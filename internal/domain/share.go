@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"time"
+)
+
+// ShareLink grants read-only access to one session through a signed token,
+// for people without an account. EventTypes and EVPIDs narrow what the link
+// shows; empty lists show everything.
+type ShareLink struct {
+	ID         string     `json:"id" db:"id"`
+	SessionID  string     `json:"session_id" db:"session_id"`
+	Name       string     `json:"name" db:"name"`
+	EventTypes []string   `json:"event_types" db:"event_types"`
+	EVPIDs     []string   `json:"evp_ids" db:"evp_ids"`
+	CreatedBy  string     `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	ExpiresAt  time.Time  `json:"expires_at" db:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+}
+
+// IsActive reports whether the link can still be used at a given time
+func (l *ShareLink) IsActive(at time.Time) bool {
+	return l.RevokedAt == nil && at.Before(l.ExpiresAt)
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"time"
+)
+
+// EventBatch is a set of new events for one session that are stored
+// together or not at all
+type EventBatch struct {
+	VOXEvents     []*VOXEvent
+	RadarEvents   []*RadarEvent
+	SLSDetections []*SLSDetection
+	Interactions  []*UserInteraction
+}
+
+// Len returns the number of events in the batch
+func (b *EventBatch) Len() int {
+	return len(b.VOXEvents) + len(b.RadarEvents) + len(b.SLSDetections) + len(b.Interactions)
+}
+
+// ChangeOperation says whether a change wrote or deleted a record
+type ChangeOperation string
+
+const (
+	ChangeUpsert ChangeOperation = "upsert"
+	ChangeDelete ChangeOperation = "delete"
+)
+
+// Change is the latest change to one record in the change feed. Seq grows
+// with every change across all record types; a delete is a tombstone.
+type Change struct {
+	Seq        int64           `json:"seq" db:"seq"`
+	EntityType string          `json:"entity_type" db:"entity_type"`
+	EntityID   string          `json:"entity_id" db:"entity_id"`
+	SessionID  string          `json:"session_id" db:"session_id"`
+	Operation  ChangeOperation `json:"operation" db:"operation"`
+	ChangedAt  time.Time       `json:"changed_at" db:"changed_at"`
+}
@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"time"
+)
+
+// RadarTrack represents consecutive radar events associated into a single
+// moving source
+type RadarTrack struct {
+	ID           string        `json:"id" db:"id"`
+	SessionID    string        `json:"session_id" db:"session_id"`
+	EventIDs     []string      `json:"event_ids" db:"event_ids"`
+	Points       []Coordinates `json:"points" db:"points"`
+	StartTime    time.Time     `json:"start_time" db:"start_time"`
+	EndTime      time.Time     `json:"end_time" db:"end_time"`
+	DwellTime    float64       `json:"dwell_time" db:"dwell_time"`
+	Distance     float64       `json:"distance" db:"distance"`
+	Velocity     float64       `json:"velocity" db:"velocity"`
+	Heading      float64       `json:"heading" db:"heading"`
+	MeanStrength float64       `json:"mean_strength" db:"mean_strength"`
+	CreatedAt    time.Time     `json:"created_at" db:"created_at"`
+}
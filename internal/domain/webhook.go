@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookSubscription posts events of the chosen types to a URL, signed
+// with the subscription's secret. The secret is only shown when the
+// subscription is created.
+type WebhookSubscription struct {
+	ID          string    `json:"id" db:"id"`
+	URL         string    `json:"url" db:"url"`
+	EventTypes  []string  `json:"event_types" db:"event_types"`
+	Description string    `json:"description,omitempty" db:"description"`
+	Secret      string    `json:"secret,omitempty" db:"secret"`
+	Enabled     bool      `json:"enabled" db:"enabled"`
+	CreatedBy   string    `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// WebhookDeliveryStatus is where a webhook delivery stands
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryPending is waiting for its next attempt
+	WebhookDeliveryPending WebhookDeliveryStatus = "pending"
+	// WebhookDeliverySucceeded was answered with a 2xx status
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	// WebhookDeliveryFailed ran out of attempts
+	WebhookDeliveryFailed WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery is one event queued for one subscription, with the
+// outcome of its latest attempt. A replayed delivery names the delivery it
+// repeats in ReplayOf.
+type WebhookDelivery struct {
+	ID             string                `json:"id" db:"id"`
+	SubscriptionID string                `json:"subscription_id" db:"subscription_id"`
+	EventID        string                `json:"event_id" db:"event_id"`
+	EventType      string                `json:"event_type" db:"event_type"`
+	Payload        json.RawMessage       `json:"payload" db:"payload"`
+	Status         WebhookDeliveryStatus `json:"status" db:"status"`
+	Attempts       int                   `json:"attempts" db:"attempts"`
+	NextAttemptAt  *time.Time            `json:"next_attempt_at,omitempty" db:"next_attempt_at"`
+	LastAttemptAt  *time.Time            `json:"last_attempt_at,omitempty" db:"last_attempt_at"`
+	ResponseStatus int                   `json:"response_status,omitempty" db:"response_status"`
+	LastError      string                `json:"last_error,omitempty" db:"last_error"`
+	ReplayOf       string                `json:"replay_of,omitempty" db:"replay_of"`
+	CreatedAt      time.Time             `json:"created_at" db:"created_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty" db:"delivered_at"`
+}
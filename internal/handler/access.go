@@ -0,0 +1,366 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/myideascope/otherside/internal/service"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ownerRoutes are the session routes only the session owner may call
+var ownerRoutes = map[string]bool{
+	"DELETE /api/v1/sessions/{id}":                                      true,
+	"POST /api/v1/sessions/{id}/archive":                                true,
+	"PUT /api/v1/sessions/{sessionId}/acl":                              true,
+	"PUT /api/v1/sessions/{sessionId}/acl/guests/{investigatorId}":      true,
+	"DELETE /api/v1/sessions/{sessionId}/acl/guests/{investigatorId}":   true,
+	"PUT /api/v1/sessions/{sessionId}/participants/{investigatorId}":    true,
+	"DELETE /api/v1/sessions/{sessionId}/participants/{investigatorId}": true,
+	"POST /api/v1/sessions/{sessionId}/shares":                          true,
+	"GET /api/v1/sessions/{sessionId}/shares":                           true,
+	"DELETE /api/v1/sessions/{sessionId}/shares/{shareId}":              true,
+}
+
+// AccessHandler enforces per-session access and manages teams and session
+// access lists
+type AccessHandler struct {
+	accessService *service.AccessService
+	tracer        trace.Tracer
+}
+
+// NewAccessHandler creates a new access handler
+func NewAccessHandler(accessService *service.AccessService) *AccessHandler {
+	return &AccessHandler{
+		accessService: accessService,
+		tracer:        otel.Tracer("otherside/access"),
+	}
+}
+
+// Middleware rejects requests to session routes the caller has no access
+// to. Reads need guest access, owner routes need ownership and everything
+// else needs team access. It runs after routing, so register it with
+// router.Use.
+func (h *AccessHandler) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := mux.CurrentRoute(r)
+		if route == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		template, err := route.GetPathTemplate()
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		vars := mux.Vars(r)
+		sessionID := vars["sessionId"]
+		if sessionID == "" && strings.HasPrefix(template, "/api/v1/sessions/{id}") {
+			sessionID = vars["id"]
+		}
+		if sessionID == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		required := domain.AccessTeam
+		switch {
+		case ownerRoutes[r.Method+" "+template]:
+			required = domain.AccessOwner
+		case r.Method == "GET":
+			required = domain.AccessGuest
+		}
+
+		ctx, span := h.tracer.Start(r.Context(), "AccessHandler.Middleware")
+		span.SetAttributes(
+			attribute.String("session.id", sessionID),
+			attribute.String("access.required", string(required)),
+		)
+
+		err = h.accessService.AuthorizeSession(ctx, sessionID, required)
+		if err != nil {
+			span.RecordError(err)
+			span.End()
+			if strings.Contains(err.Error(), "forbidden") {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				return
+			}
+			http.Error(w, "Failed to check session access", http.StatusInternalServerError)
+			return
+		}
+		span.End()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// GetSessionACL returns the owner, team and guests of a session
+func (h *AccessHandler) GetSessionACL(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AccessHandler.GetSessionACL")
+	defer span.End()
+
+	sessionID := mux.Vars(r)["sessionId"]
+	span.SetAttributes(attribute.String("session.id", sessionID))
+
+	acl, err := h.accessService.GetSessionACL(ctx, sessionID)
+	if err != nil {
+		span.RecordError(err)
+		writeAccessError(w, err, "Failed to get session access")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(acl)
+}
+
+// SetSessionOwnership changes the owner and team of a session
+func (h *AccessHandler) SetSessionOwnership(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AccessHandler.SetSessionOwnership")
+	defer span.End()
+
+	sessionID := mux.Vars(r)["sessionId"]
+	span.SetAttributes(attribute.String("session.id", sessionID))
+
+	var req service.SessionOwnershipRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		span.RecordError(err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	acl, err := h.accessService.SetSessionOwnership(ctx, sessionID, req)
+	if err != nil {
+		span.RecordError(err)
+		writeAccessError(w, err, "Failed to set session owner")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(acl)
+}
+
+// AddSessionGuest gives an investigator read-only access to a session
+func (h *AccessHandler) AddSessionGuest(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AccessHandler.AddSessionGuest")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	sessionID, investigatorID := vars["sessionId"], vars["investigatorId"]
+	span.SetAttributes(
+		attribute.String("session.id", sessionID),
+		attribute.String("investigator.id", investigatorID),
+	)
+
+	acl, err := h.accessService.AddSessionGuest(ctx, sessionID, investigatorID)
+	if err != nil {
+		span.RecordError(err)
+		writeAccessError(w, err, "Failed to add guest")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(acl)
+}
+
+// RemoveSessionGuest withdraws an investigator's read-only access to a session
+func (h *AccessHandler) RemoveSessionGuest(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AccessHandler.RemoveSessionGuest")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	sessionID, investigatorID := vars["sessionId"], vars["investigatorId"]
+	span.SetAttributes(
+		attribute.String("session.id", sessionID),
+		attribute.String("investigator.id", investigatorID),
+	)
+
+	if err := h.accessService.RemoveSessionGuest(ctx, sessionID, investigatorID); err != nil {
+		span.RecordError(err)
+		writeAccessError(w, err, "Failed to remove guest")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateTeam creates a team with the caller as its first member
+func (h *AccessHandler) CreateTeam(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AccessHandler.CreateTeam")
+	defer span.End()
+
+	var req service.CreateTeamRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		span.RecordError(err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	team, err := h.accessService.CreateTeam(ctx, req)
+	if err != nil {
+		span.RecordError(err)
+		writeAccessError(w, err, "Failed to create team")
+		return
+	}
+
+	span.SetAttributes(attribute.String("team.id", team.ID))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(team)
+}
+
+// ListTeams lists the teams visible to the caller
+func (h *AccessHandler) ListTeams(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AccessHandler.ListTeams")
+	defer span.End()
+
+	teams, err := h.accessService.ListTeams(ctx)
+	if err != nil {
+		span.RecordError(err)
+		http.Error(w, fmt.Sprintf("Failed to list teams: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"teams": teams,
+		"total": len(teams),
+	})
+}
+
+// GetTeam retrieves a team by ID
+func (h *AccessHandler) GetTeam(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AccessHandler.GetTeam")
+	defer span.End()
+
+	teamID := mux.Vars(r)["teamId"]
+	span.SetAttributes(attribute.String("team.id", teamID))
+
+	team, err := h.accessService.GetTeam(ctx, teamID)
+	if err != nil {
+		span.RecordError(err)
+		writeAccessError(w, err, "Failed to get team")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(team)
+}
+
+// DeleteTeam deletes a team. Its sessions stay with their owners.
+func (h *AccessHandler) DeleteTeam(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AccessHandler.DeleteTeam")
+	defer span.End()
+
+	teamID := mux.Vars(r)["teamId"]
+	span.SetAttributes(attribute.String("team.id", teamID))
+
+	if err := h.accessService.DeleteTeam(ctx, teamID); err != nil {
+		span.RecordError(err)
+		writeAccessError(w, err, "Failed to delete team")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListTeamMembers lists the members of a team
+func (h *AccessHandler) ListTeamMembers(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AccessHandler.ListTeamMembers")
+	defer span.End()
+
+	teamID := mux.Vars(r)["teamId"]
+	span.SetAttributes(attribute.String("team.id", teamID))
+
+	members, err := h.accessService.ListTeamMembers(ctx, teamID)
+	if err != nil {
+		span.RecordError(err)
+		writeAccessError(w, err, "Failed to list team members")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"members": members,
+		"total":   len(members),
+	})
+}
+
+// AddTeamMember adds an investigator to a team
+func (h *AccessHandler) AddTeamMember(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AccessHandler.AddTeamMember")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	teamID, investigatorID := vars["teamId"], vars["investigatorId"]
+	span.SetAttributes(
+		attribute.String("team.id", teamID),
+		attribute.String("investigator.id", investigatorID),
+	)
+
+	if err := h.accessService.AddTeamMember(ctx, teamID, investigatorID); err != nil {
+		span.RecordError(err)
+		writeAccessError(w, err, "Failed to add team member")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RemoveTeamMember removes an investigator from a team
+func (h *AccessHandler) RemoveTeamMember(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AccessHandler.RemoveTeamMember")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	teamID, investigatorID := vars["teamId"], vars["investigatorId"]
+	span.SetAttributes(
+		attribute.String("team.id", teamID),
+		attribute.String("investigator.id", investigatorID),
+	)
+
+	if err := h.accessService.RemoveTeamMember(ctx, teamID, investigatorID); err != nil {
+		span.RecordError(err)
+		writeAccessError(w, err, "Failed to remove team member")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeAccessError maps access and team errors to HTTP status codes
+func writeAccessError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case strings.Contains(err.Error(), "forbidden"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "invalid team"),
+		strings.Contains(err.Error(), "invalid session access"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, fmt.Sprintf("%s: %v", message, err), http.StatusInternalServerError)
+	}
+}
+
+// RegisterRoutes registers team and session access routes
+func (h *AccessHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/api/v1/teams", h.CreateTeam).Methods("POST")
+	r.HandleFunc("/api/v1/teams", h.ListTeams).Methods("GET")
+	r.HandleFunc("/api/v1/teams/{teamId}", h.GetTeam).Methods("GET")
+	r.HandleFunc("/api/v1/teams/{teamId}", h.DeleteTeam).Methods("DELETE")
+	r.HandleFunc("/api/v1/teams/{teamId}/members", h.ListTeamMembers).Methods("GET")
+	r.HandleFunc("/api/v1/teams/{teamId}/members/{investigatorId}", h.AddTeamMember).Methods("PUT")
+	r.HandleFunc("/api/v1/teams/{teamId}/members/{investigatorId}", h.RemoveTeamMember).Methods("DELETE")
+	r.HandleFunc("/api/v1/sessions/{sessionId}/acl", h.GetSessionACL).Methods("GET")
+	r.HandleFunc("/api/v1/sessions/{sessionId}/acl", h.SetSessionOwnership).Methods("PUT")
+	r.HandleFunc("/api/v1/sessions/{sessionId}/acl/guests/{investigatorId}", h.AddSessionGuest).Methods("PUT")
+	r.HandleFunc("/api/v1/sessions/{sessionId}/acl/guests/{investigatorId}", h.RemoveSessionGuest).Methods("DELETE")
+}
@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/myideascope/otherside/internal/service"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// AlertHandler handles HTTP requests for alert rules and raised alerts
+type AlertHandler struct {
+	alertService *service.AlertService
+	tracer       trace.Tracer
+}
+
+// NewAlertHandler creates a new alert handler
+func NewAlertHandler(alertService *service.AlertService) *AlertHandler {
+	return &AlertHandler{
+		alertService: alertService,
+		tracer:       otel.Tracer("otherside/alerts"),
+	}
+}
+
+// CreateRule creates an alert rule
+func (h *AlertHandler) CreateRule(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AlertHandler.CreateRule")
+	defer span.End()
+
+	var req service.AlertRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		span.RecordError(err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	rule, err := h.alertService.CreateRule(ctx, req)
+	if err != nil {
+		span.RecordError(err)
+		writeAlertError(w, err, "Failed to create alert rule")
+		return
+	}
+
+	span.SetAttributes(attribute.String("alert_rule.id", rule.ID))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(rule)
+}
+
+// ListRules lists the alert rules the caller may read
+func (h *AlertHandler) ListRules(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AlertHandler.ListRules")
+	defer span.End()
+
+	rules, err := h.alertService.ListRules(ctx)
+	if err != nil {
+		span.RecordError(err)
+		writeAlertError(w, err, "Failed to list alert rules")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"alert_rules": rules,
+		"total":       len(rules),
+	})
+}
+
+// GetRule returns an alert rule
+func (h *AlertHandler) GetRule(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AlertHandler.GetRule")
+	defer span.End()
+
+	ruleID := mux.Vars(r)["ruleId"]
+	span.SetAttributes(attribute.String("alert_rule.id", ruleID))
+
+	rule, err := h.alertService.GetRule(ctx, ruleID)
+	if err != nil {
+		span.RecordError(err)
+		writeAlertError(w, err, "Failed to get alert rule")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rule)
+}
+
+// UpdateRule replaces the settings of an alert rule
+func (h *AlertHandler) UpdateRule(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AlertHandler.UpdateRule")
+	defer span.End()
+
+	ruleID := mux.Vars(r)["ruleId"]
+	span.SetAttributes(attribute.String("alert_rule.id", ruleID))
+
+	var req service.AlertRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		span.RecordError(err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	rule, err := h.alertService.UpdateRule(ctx, ruleID, req)
+	if err != nil {
+		span.RecordError(err)
+		writeAlertError(w, err, "Failed to update alert rule")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rule)
+}
+
+// DeleteRule deletes an alert rule
+func (h *AlertHandler) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AlertHandler.DeleteRule")
+	defer span.End()
+
+	ruleID := mux.Vars(r)["ruleId"]
+	span.SetAttributes(attribute.String("alert_rule.id", ruleID))
+
+	if err := h.alertService.DeleteRule(ctx, ruleID); err != nil {
+		span.RecordError(err)
+		writeAlertError(w, err, "Failed to delete alert rule")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListAlerts lists the latest alerts raised in a session
+func (h *AlertHandler) ListAlerts(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AlertHandler.ListAlerts")
+	defer span.End()
+
+	sessionID := mux.Vars(r)["sessionId"]
+	span.SetAttributes(attribute.String("session.id", sessionID))
+
+	limit := 0
+	if param := r.URL.Query().Get("limit"); param != "" {
+		parsed, err := strconv.Atoi(param)
+		if err != nil || parsed < 0 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	alerts, err := h.alertService.ListAlerts(ctx, sessionID, limit)
+	if err != nil {
+		span.RecordError(err)
+		writeAlertError(w, err, "Failed to list alerts")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"alerts": alerts,
+		"total":  len(alerts),
+	})
+}
+
+// writeAlertError maps alert errors to HTTP status codes
+func writeAlertError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case strings.Contains(err.Error(), "forbidden"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "invalid alert rule"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, fmt.Sprintf("%s: %v", message, err), http.StatusInternalServerError)
+	}
+}
+
+// RegisterRoutes registers alert routes
+func (h *AlertHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/api/v1/alert-rules", h.CreateRule).Methods("POST")
+	r.HandleFunc("/api/v1/alert-rules", h.ListRules).Methods("GET")
+	r.HandleFunc("/api/v1/alert-rules/{ruleId}", h.GetRule).Methods("GET")
+	r.HandleFunc("/api/v1/alert-rules/{ruleId}", h.UpdateRule).Methods("PUT")
+	r.HandleFunc("/api/v1/alert-rules/{ruleId}", h.DeleteRule).Methods("DELETE")
+	r.HandleFunc("/api/v1/sessions/{sessionId}/alerts", h.ListAlerts).Methods("GET")
+}
@@ -0,0 +1,383 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/myideascope/otherside/internal/service"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// AnalysisHandler handles HTTP requests for session analysis reports
+type AnalysisHandler struct {
+	voxAnalysis   *service.VOXAnalysisService
+	radarTracking *service.RadarTrackingService
+	heatmaps      *service.HeatmapService
+	fusion        *service.FusionService
+	tracer        trace.Tracer
+}
+
+// NewAnalysisHandler creates a new analysis handler
+func NewAnalysisHandler(
+	voxAnalysis *service.VOXAnalysisService,
+	radarTracking *service.RadarTrackingService,
+	heatmaps *service.HeatmapService,
+	fusion *service.FusionService,
+) *AnalysisHandler {
+	return &AnalysisHandler{
+		voxAnalysis:   voxAnalysis,
+		radarTracking: radarTracking,
+		heatmaps:      heatmaps,
+		fusion:        fusion,
+		tracer:        otel.Tracer("otherside/analysis"),
+	}
+}
+
+// GetVOXBaseline compares a session's VOX hits against a chance baseline
+func (h *AnalysisHandler) GetVOXBaseline(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AnalysisHandler.GetVOXBaseline")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	sessionID := vars["sessionId"]
+
+	// Parse query parameters
+	var opts service.VOXBaselineOptions
+	if iterationsStr := r.URL.Query().Get("iterations"); iterationsStr != "" {
+		iterations, err := strconv.Atoi(iterationsStr)
+		if err != nil || iterations <= 0 {
+			http.Error(w, "Invalid iterations", http.StatusBadRequest)
+			return
+		}
+		opts.Iterations = iterations
+	}
+
+	if seedStr := r.URL.Query().Get("seed"); seedStr != "" {
+		seed, err := strconv.ParseInt(seedStr, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid seed", http.StatusBadRequest)
+			return
+		}
+		opts.Seed = seed
+	}
+
+	if windowStr := r.URL.Query().Get("window"); windowStr != "" {
+		window, err := strconv.ParseFloat(windowStr, 64)
+		if err != nil || window <= 0 {
+			http.Error(w, "Invalid window", http.StatusBadRequest)
+			return
+		}
+		opts.RelevanceWindowSeconds = window
+	}
+
+	span.SetAttributes(
+		attribute.String("session.id", sessionID),
+		attribute.Int("baseline.iterations", opts.Iterations),
+	)
+
+	report, err := h.voxAnalysis.AnalyzeChanceBaseline(ctx, sessionID, opts)
+	if err != nil {
+		span.RecordError(err)
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, "Session not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to analyze VOX baseline: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// BuildRadarTracks rebuilds the radar tracks of a session from its radar
+// events, replacing the stored ones
+func (h *AnalysisHandler) BuildRadarTracks(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AnalysisHandler.BuildRadarTracks")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	sessionID := vars["sessionId"]
+
+	span.SetAttributes(attribute.String("session.id", sessionID))
+
+	// Parse query parameters
+	var config service.RadarTrackingConfig
+	if gateStr := r.URL.Query().Get("gate"); gateStr != "" {
+		gate, err := strconv.ParseFloat(gateStr, 64)
+		if err != nil || gate <= 0 {
+			http.Error(w, "Invalid gate", http.StatusBadRequest)
+			return
+		}
+		config.GateDistance = gate
+	}
+
+	if gapStr := r.URL.Query().Get("max_gap"); gapStr != "" {
+		gap, err := strconv.ParseFloat(gapStr, 64)
+		if err != nil || gap <= 0 {
+			http.Error(w, "Invalid max_gap", http.StatusBadRequest)
+			return
+		}
+		config.MaxGapSeconds = gap
+	}
+
+	if minStr := r.URL.Query().Get("min_events"); minStr != "" {
+		minEvents, err := strconv.Atoi(minStr)
+		if err != nil || minEvents <= 0 {
+			http.Error(w, "Invalid min_events", http.StatusBadRequest)
+			return
+		}
+		config.MinEvents = minEvents
+	}
+
+	tracks, err := h.radarTracking.BuildTracks(ctx, sessionID, config)
+	if err != nil {
+		span.RecordError(err)
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, "Session not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to build radar tracks: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	span.SetAttributes(attribute.Int("radar.track_count", len(tracks)))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"tracks": tracks,
+		"total":  len(tracks),
+	})
+}
+
+// GetRadarTracks returns the stored radar tracks of a session
+func (h *AnalysisHandler) GetRadarTracks(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AnalysisHandler.GetRadarTracks")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	sessionID := vars["sessionId"]
+
+	span.SetAttributes(attribute.String("session.id", sessionID))
+
+	tracks, err := h.radarTracking.GetTracks(ctx, sessionID)
+	if err != nil {
+		span.RecordError(err)
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, "Session not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to get radar tracks: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"tracks": tracks,
+		"total":  len(tracks),
+	})
+}
+
+// GetHeatmap renders the spatial density of a session's radar activity as
+// JSON or, with format=png, as an image
+func (h *AnalysisHandler) GetHeatmap(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AnalysisHandler.GetHeatmap")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	sessionID := vars["sessionId"]
+
+	// Parse query parameters
+	query := r.URL.Query()
+	opts := service.HeatmapOptions{
+		Metric: service.HeatmapMetric(query.Get("metric")),
+	}
+
+	for name, target := range map[string]*int{"cols": &opts.Columns, "rows": &opts.Rows} {
+		if valueStr := query.Get(name); valueStr != "" {
+			value, err := strconv.Atoi(valueStr)
+			if err != nil || value <= 0 {
+				http.Error(w, fmt.Sprintf("Invalid %s", name), http.StatusBadRequest)
+				return
+			}
+			*target = value
+		}
+	}
+
+	if bandwidthStr := query.Get("bandwidth"); bandwidthStr != "" {
+		bandwidth, err := strconv.ParseFloat(bandwidthStr, 64)
+		if err != nil || bandwidth < 0 {
+			http.Error(w, "Invalid bandwidth", http.StatusBadRequest)
+			return
+		}
+		opts.Bandwidth = bandwidth
+	}
+
+	if boundsStr := query.Get("bounds"); boundsStr != "" {
+		parts := strings.Split(boundsStr, ",")
+		if len(parts) != 4 {
+			http.Error(w, "Invalid bounds", http.StatusBadRequest)
+			return
+		}
+		values := make([]float64, len(parts))
+		for i, part := range parts {
+			value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+			if err != nil {
+				http.Error(w, "Invalid bounds", http.StatusBadRequest)
+				return
+			}
+			values[i] = value
+		}
+		opts.Bounds = &service.HeatmapBounds{MinX: values[0], MinY: values[1], MaxX: values[2], MaxY: values[3]}
+	}
+
+	for name, target := range map[string]**time.Time{"from": &opts.From, "to": &opts.To} {
+		if valueStr := query.Get(name); valueStr != "" {
+			value, err := time.Parse(time.RFC3339, valueStr)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Invalid %s", name), http.StatusBadRequest)
+				return
+			}
+			*target = &value
+		}
+	}
+
+	if sourceTypesStr := query.Get("source_type"); sourceTypesStr != "" {
+		for _, sourceType := range strings.Split(sourceTypesStr, ",") {
+			opts.SourceTypes = append(opts.SourceTypes, domain.SourceType(strings.TrimSpace(sourceType)))
+		}
+	}
+
+	span.SetAttributes(
+		attribute.String("session.id", sessionID),
+		attribute.String("heatmap.metric", string(opts.Metric)),
+	)
+
+	heatmap, err := h.heatmaps.GenerateHeatmap(ctx, sessionID, opts)
+	if err != nil {
+		span.RecordError(err)
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, "Session not found", http.StatusNotFound)
+			return
+		}
+		if strings.Contains(err.Error(), "heatmap") {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to generate heatmap: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	span.SetAttributes(attribute.Int("heatmap.event_count", heatmap.EventCount))
+
+	if query.Get("format") == "png" {
+		cellPixels, _ := strconv.Atoi(query.Get("cell_pixels"))
+		data, err := service.RenderHeatmapPNG(heatmap, cellPixels)
+		if err != nil {
+			span.RecordError(err)
+			http.Error(w, fmt.Sprintf("Failed to render heatmap: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(heatmap)
+}
+
+// CorrelateFusionEvents rebuilds a session's fusion events. The optional JSON
+// body is a fusion config; the default rules apply when it has none.
+func (h *AnalysisHandler) CorrelateFusionEvents(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AnalysisHandler.CorrelateFusionEvents")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	sessionID := vars["sessionId"]
+
+	var config service.FusionConfig
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&config); err != nil && err != io.EOF {
+			span.RecordError(err)
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	span.SetAttributes(
+		attribute.String("session.id", sessionID),
+		attribute.Int("fusion.rule_count", len(config.Rules)),
+	)
+
+	events, err := h.fusion.Correlate(ctx, sessionID, config)
+	if err != nil {
+		span.RecordError(err)
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, "Session not found", http.StatusNotFound)
+			return
+		}
+		if strings.Contains(err.Error(), "invalid fusion rule") {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to correlate events: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	span.SetAttributes(attribute.Int("fusion.event_count", len(events)))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"events": events,
+		"total":  len(events),
+	})
+}
+
+// GetFusionEvents returns the stored fusion events of a session
+func (h *AnalysisHandler) GetFusionEvents(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AnalysisHandler.GetFusionEvents")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	sessionID := vars["sessionId"]
+
+	span.SetAttributes(attribute.String("session.id", sessionID))
+
+	events, err := h.fusion.GetFusionEvents(ctx, sessionID)
+	if err != nil {
+		span.RecordError(err)
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, "Session not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to get fusion events: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"events": events,
+		"total":  len(events),
+	})
+}
+
+// RegisterRoutes registers analysis-related routes
+func (h *AnalysisHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/api/v1/sessions/{sessionId}/vox/baseline", h.GetVOXBaseline).Methods("GET")
+	r.HandleFunc("/api/v1/sessions/{sessionId}/radar/tracks", h.BuildRadarTracks).Methods("POST")
+	r.HandleFunc("/api/v1/sessions/{sessionId}/radar/tracks", h.GetRadarTracks).Methods("GET")
+	r.HandleFunc("/api/v1/sessions/{sessionId}/radar/heatmap", h.GetHeatmap).Methods("GET")
+	r.HandleFunc("/api/v1/sessions/{sessionId}/fusion", h.CorrelateFusionEvents).Methods("POST")
+	r.HandleFunc("/api/v1/sessions/{sessionId}/fusion", h.GetFusionEvents).Methods("GET")
+}
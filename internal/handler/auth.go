@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/myideascope/otherside/internal/service"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// apiKeyHeader carries an API key for clients that do not send it as a
+// bearer credential
+const apiKeyHeader = "X-API-Key"
+
+// AuthHandler authenticates API requests and issues bearer tokens
+type AuthHandler struct {
+	authService *service.AuthService
+	tracer      trace.Tracer
+}
+
+// NewAuthHandler creates a new auth handler
+func NewAuthHandler(authService *service.AuthService) *AuthHandler {
+	return &AuthHandler{
+		authService: authService,
+		tracer:      otel.Tracer("otherside/auth"),
+	}
+}
+
+// Middleware rejects API requests without a valid API key or bearer token
+// and attaches the caller's identity to the request context. The health
+// check, the web app, share links and CORS preflight requests stay public.
+func (h *AuthHandler) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "OPTIONS" || !strings.HasPrefix(r.URL.Path, "/api/") || strings.HasPrefix(r.URL.Path, sharedPathPrefix) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, span := h.tracer.Start(r.Context(), "AuthHandler.Middleware")
+
+		var identity *domain.Identity
+		var err error
+		credential, isBearer := requestCredential(r)
+		switch {
+		case credential == "":
+			err = fmt.Errorf("unauthenticated: missing credentials")
+		case isBearer && !service.IsAPIKey(credential):
+			identity, err = h.authService.AuthenticateToken(ctx, credential)
+		default:
+			identity, err = h.authService.AuthenticateAPIKey(ctx, credential)
+		}
+
+		if err != nil {
+			span.RecordError(err)
+			span.End()
+			w.Header().Set("WWW-Authenticate", `Bearer realm="otherside"`)
+			http.Error(w, "Unauthorized: "+strings.TrimPrefix(err.Error(), "unauthenticated: "), http.StatusUnauthorized)
+			return
+		}
+
+		span.SetAttributes(
+			attribute.String("auth.kind", string(identity.Kind)),
+			attribute.String("auth.id", identity.ID),
+			attribute.String("auth.method", string(identity.Method)),
+		)
+		span.End()
+
+		next.ServeHTTP(w, r.WithContext(domain.ContextWithIdentity(r.Context(), identity)))
+	})
+}
+
+// requestCredential returns the bearer credential or API key of a request
+func requestCredential(r *http.Request) (string, bool) {
+	if authorization := r.Header.Get("Authorization"); authorization != "" {
+		scheme, credential, _ := strings.Cut(authorization, " ")
+		if strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(credential), true
+		}
+	}
+	return strings.TrimSpace(r.Header.Get(apiKeyHeader)), false
+}
+
+// IssueToken exchanges an investigator's API key for a short-lived bearer token
+func (h *AuthHandler) IssueToken(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AuthHandler.IssueToken")
+	defer span.End()
+
+	token, err := h.authService.IssueToken(ctx, domain.IdentityFromContext(ctx))
+	if err != nil {
+		span.RecordError(err)
+		if strings.Contains(err.Error(), "forbidden") {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to issue token: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(token)
+}
+
+// WhoAmI returns the identity of the caller
+func (h *AuthHandler) WhoAmI(w http.ResponseWriter, r *http.Request) {
+	_, span := h.tracer.Start(r.Context(), "AuthHandler.WhoAmI")
+	defer span.End()
+
+	identity := domain.IdentityFromContext(r.Context())
+	if identity == nil {
+		http.Error(w, "Unauthorized: authentication is disabled", http.StatusUnauthorized)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(identity)
+}
+
+// RegisterRoutes registers auth routes
+func (h *AuthHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/api/v1/auth/token", h.IssueToken).Methods("POST")
+	r.HandleFunc("/api/v1/auth/whoami", h.WhoAmI).Methods("GET")
+}
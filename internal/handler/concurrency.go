@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/myideascope/otherside/internal/domain"
+)
+
+// versionETag returns the entity tag of a record's version
+func versionETag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// ifMatchVersion reads the version an update was made to from its If-Match
+// header. It returns zero when the header is missing or "*", which lets the
+// update apply to whatever version is stored.
+func ifMatchVersion(r *http.Request) (int64, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	tag := strings.TrimPrefix(header, "W/")
+	unquoted, err := strconv.Unquote(tag)
+	if err != nil {
+		return 0, fmt.Errorf("invalid If-Match header: expected one entity tag such as \"3\"")
+	}
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("invalid If-Match header: %s is not a version", header)
+	}
+	return version, nil
+}
+
+// writeVersioned writes a record with its version as the entity tag
+func writeVersioned(w http.ResponseWriter, version int64, record interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", versionETag(version))
+	json.NewEncoder(w).Encode(record)
+}
+
+// writeVersionConflict answers an update to an outdated copy with 409 and
+// the stored copy, so the client can merge its changes and try again. It
+// reports whether err was a version conflict.
+func writeVersionConflict(w http.ResponseWriter, err error) bool {
+	var conflict *domain.VersionConflictError
+	if !errors.As(err, &conflict) {
+		return false
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", versionETag(conflict.Version))
+	w.WriteHeader(http.StatusConflict)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":   conflict.Error(),
+		"current": conflict.Current,
+	})
+	return true
+}
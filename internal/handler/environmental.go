@@ -0,0 +1,295 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/myideascope/otherside/internal/service"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// EnvironmentalHandler handles HTTP requests for environmental sensor time series
+type EnvironmentalHandler struct {
+	environmentalService *service.EnvironmentalService
+	tracer               trace.Tracer
+}
+
+// NewEnvironmentalHandler creates a new environmental handler
+func NewEnvironmentalHandler(environmentalService *service.EnvironmentalService) *EnvironmentalHandler {
+	return &EnvironmentalHandler{
+		environmentalService: environmentalService,
+		tracer:               otel.Tracer("otherside/environmental"),
+	}
+}
+
+// IngestReadingsRequest is a batch of samples from one or more devices
+type IngestReadingsRequest struct {
+	DeviceID string                        `json:"device_id"`
+	Readings []service.EnvironmentalSample `json:"readings"`
+}
+
+// IngestReadings stores a batch of environmental readings
+func (h *EnvironmentalHandler) IngestReadings(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "EnvironmentalHandler.IngestReadings")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	sessionID := vars["sessionId"]
+
+	var req IngestReadingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		span.RecordError(err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("session.id", sessionID),
+		attribute.String("device.id", req.DeviceID),
+		attribute.Int("readings.count", len(req.Readings)),
+	)
+
+	result, err := h.environmentalService.IngestReadings(ctx, sessionID, req.DeviceID, req.Readings)
+	if err != nil {
+		span.RecordError(err)
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			http.Error(w, "Session not found", http.StatusNotFound)
+		case strings.Contains(err.Error(), "not active"):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case strings.Contains(err.Error(), "invalid environmental reading"):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, fmt.Sprintf("Failed to ingest readings: %v", err), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	span.SetAttributes(attribute.Int("anomalies.count", len(result.Anomalies)))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(result)
+}
+
+// GetSeries returns downsampled readings with min/max/avg per bucket
+func (h *EnvironmentalHandler) GetSeries(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "EnvironmentalHandler.GetSeries")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	sessionID := vars["sessionId"]
+
+	query, err := parseEnvironmentalSeriesQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("session.id", sessionID),
+		attribute.Int("bucket.seconds", query.BucketSeconds),
+	)
+
+	series, err := h.environmentalService.GetSeries(ctx, sessionID, query)
+	if err != nil {
+		span.RecordError(err)
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, "Session not found", http.StatusNotFound)
+			return
+		}
+		if strings.Contains(err.Error(), "invalid environmental query") {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to get readings: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"series": series,
+		"total":  len(series),
+	})
+}
+
+// GetAnomalies returns the baseline-deviation anomalies of a session
+func (h *EnvironmentalHandler) GetAnomalies(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "EnvironmentalHandler.GetAnomalies")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	sessionID := vars["sessionId"]
+
+	span.SetAttributes(attribute.String("session.id", sessionID))
+
+	anomalies, err := h.environmentalService.GetAnomalies(ctx, sessionID)
+	if err != nil {
+		span.RecordError(err)
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, "Session not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to get anomalies: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"anomalies": anomalies,
+		"total":     len(anomalies),
+	})
+}
+
+// RegisterSensorRequest registers a standalone sensor with a session
+type RegisterSensorRequest struct {
+	DeviceID string `json:"device_id"`
+	Name     string `json:"name"`
+}
+
+// RegisterSensor routes readings pushed by a sensor to a session
+func (h *EnvironmentalHandler) RegisterSensor(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "EnvironmentalHandler.RegisterSensor")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	sessionID := vars["sessionId"]
+
+	var req RegisterSensorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		span.RecordError(err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("session.id", sessionID),
+		attribute.String("device.id", req.DeviceID),
+	)
+
+	registration, err := h.environmentalService.RegisterSensor(ctx, sessionID, req.DeviceID, req.Name)
+	if err != nil {
+		span.RecordError(err)
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, "Session not found", http.StatusNotFound)
+			return
+		}
+		if strings.Contains(err.Error(), "invalid sensor registration") {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to register sensor: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(registration)
+}
+
+// GetSensors lists the sensors registered with a session
+func (h *EnvironmentalHandler) GetSensors(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "EnvironmentalHandler.GetSensors")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	sessionID := vars["sessionId"]
+
+	span.SetAttributes(attribute.String("session.id", sessionID))
+
+	sensors, err := h.environmentalService.GetSensors(ctx, sessionID)
+	if err != nil {
+		span.RecordError(err)
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, "Session not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to get sensors: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"sensors": sensors,
+		"total":   len(sensors),
+	})
+}
+
+// UnregisterSensor stops routing a sensor's readings to its session
+func (h *EnvironmentalHandler) UnregisterSensor(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "EnvironmentalHandler.UnregisterSensor")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	deviceID := vars["deviceId"]
+
+	span.SetAttributes(attribute.String("device.id", deviceID))
+
+	if err := h.environmentalService.UnregisterSensor(ctx, deviceID); err != nil {
+		span.RecordError(err)
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, "Sensor not found", http.StatusNotFound)
+			return
+		}
+		if strings.Contains(err.Error(), "forbidden") {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to unregister sensor: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseEnvironmentalSeriesQuery reads metric, device, from, to and bucket
+func parseEnvironmentalSeriesQuery(r *http.Request) (service.EnvironmentalSeriesQuery, error) {
+	var query service.EnvironmentalSeriesQuery
+	values := r.URL.Query()
+
+	if metricsStr := values.Get("metric"); metricsStr != "" {
+		for _, metric := range strings.Split(metricsStr, ",") {
+			query.Metrics = append(query.Metrics, domain.EnvironmentalMetric(strings.TrimSpace(metric)))
+		}
+	}
+
+	query.DeviceID = values.Get("device")
+
+	for name, target := range map[string]**time.Time{"from": &query.From, "to": &query.To} {
+		if valueStr := values.Get(name); valueStr != "" {
+			value, err := time.Parse(time.RFC3339, valueStr)
+			if err != nil {
+				return query, fmt.Errorf("Invalid %s", name)
+			}
+			*target = &value
+		}
+	}
+
+	if bucketStr := values.Get("bucket"); bucketStr != "" {
+		bucket, err := strconv.Atoi(bucketStr)
+		if err != nil || bucket <= 0 {
+			return query, fmt.Errorf("Invalid bucket")
+		}
+		query.BucketSeconds = bucket
+	}
+
+	return query, nil
+}
+
+// RegisterRoutes registers environmental routes
+func (h *EnvironmentalHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/api/v1/sessions/{sessionId}/environmental/readings", h.IngestReadings).Methods("POST")
+	r.HandleFunc("/api/v1/sessions/{sessionId}/environmental/readings", h.GetSeries).Methods("GET")
+	r.HandleFunc("/api/v1/sessions/{sessionId}/environmental/anomalies", h.GetAnomalies).Methods("GET")
+	r.HandleFunc("/api/v1/sessions/{sessionId}/sensors", h.RegisterSensor).Methods("POST")
+	r.HandleFunc("/api/v1/sessions/{sessionId}/sensors", h.GetSensors).Methods("GET")
+	r.HandleFunc("/api/v1/sensors/{deviceId}", h.UnregisterSensor).Methods("DELETE")
+}
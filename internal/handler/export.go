@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/myideascope/otherside/internal/domain"
 	"github.com/myideascope/otherside/internal/service"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -63,6 +64,10 @@ func (h *ExportHandler) ExportSessions(w http.ResponseWriter, r *http.Request) {
 	result, err := h.exportService.ExportSessions(ctx, req)
 	if err != nil {
 		span.RecordError(err)
+		if strings.Contains(err.Error(), "forbidden") {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		http.Error(w, fmt.Sprintf("Export failed: %v", err), http.StatusInternalServerError)
 		return
 	}
@@ -113,6 +118,10 @@ func (h *ExportHandler) DownloadExport(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !h.authorizeExport(w, r, filename, domain.AccessGuest) {
+		return
+	}
+
 	// Get file size
 	fileSize, err := fileRepo.GetFileSize(ctx, filePath)
 	if err != nil {
@@ -186,6 +195,22 @@ func (h *ExportHandler) ListExports(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Only list exports the caller may download
+	visible := make([]string, 0, len(files))
+	for _, file := range files {
+		err := h.exportService.AuthorizeExport(ctx, file, domain.AccessGuest)
+		if err == nil {
+			visible = append(visible, file)
+			continue
+		}
+		if !strings.Contains(err.Error(), "forbidden") {
+			span.RecordError(err)
+			http.Error(w, "Failed to check export access", http.StatusInternalServerError)
+			return
+		}
+	}
+	files = visible
+
 	// Convert files to export information
 	var exports []map[string]interface{}
 	for i := offset; i < len(files) && i < offset+limit; i++ {
@@ -274,6 +299,10 @@ func (h *ExportHandler) DeleteExport(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !h.authorizeExport(w, r, filename, domain.AccessOwner) {
+		return
+	}
+
 	// Delete the file
 	if err := fileRepo.DeleteFile(ctx, filePath); err != nil {
 		span.RecordError(err)
@@ -281,6 +310,11 @@ func (h *ExportHandler) DeleteExport(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// A stale record is harmless once the file is gone
+	if err := h.exportService.ForgetExport(ctx, filename); err != nil {
+		span.RecordError(err)
+	}
+
 	// Return success response
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -290,6 +324,21 @@ func (h *ExportHandler) DeleteExport(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// authorizeExport writes a 403 response unless the caller has the required
+// access to an export file
+func (h *ExportHandler) authorizeExport(w http.ResponseWriter, r *http.Request, filename string, required domain.AccessLevel) bool {
+	err := h.exportService.AuthorizeExport(r.Context(), filename, required)
+	if err == nil {
+		return true
+	}
+	if strings.Contains(err.Error(), "forbidden") {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return false
+	}
+	http.Error(w, "Failed to check export access", http.StatusInternalServerError)
+	return false
+}
+
 // RegisterRoutes registers export-related routes
 func (h *ExportHandler) RegisterRoutes(r *mux.Router) {
 	// Export operations
@@ -0,0 +1,302 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/myideascope/otherside/internal/service"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxFloorPlanImageSize bounds uploaded floor plan images
+const maxFloorPlanImageSize = 20 << 20 // 20 MB
+
+// FloorPlanHandler handles HTTP requests for floor plans and device placements
+type FloorPlanHandler struct {
+	floorPlanService *service.FloorPlanService
+	tracer           trace.Tracer
+}
+
+// NewFloorPlanHandler creates a new floor plan handler
+func NewFloorPlanHandler(floorPlanService *service.FloorPlanService) *FloorPlanHandler {
+	return &FloorPlanHandler{
+		floorPlanService: floorPlanService,
+		tracer:           otel.Tracer("otherside/floorplan"),
+	}
+}
+
+// CreateFloorPlan creates a floor plan with its rooms
+func (h *FloorPlanHandler) CreateFloorPlan(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "FloorPlanHandler.CreateFloorPlan")
+	defer span.End()
+
+	var req service.CreateFloorPlanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		span.RecordError(err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("floorplan.name", req.Name),
+		attribute.Int("floorplan.room_count", len(req.Rooms)),
+	)
+
+	plan, err := h.floorPlanService.CreateFloorPlan(ctx, req)
+	if err != nil {
+		span.RecordError(err)
+		if strings.Contains(err.Error(), "invalid floor plan") {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to create floor plan: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(plan)
+}
+
+// ListFloorPlans lists all floor plans
+func (h *FloorPlanHandler) ListFloorPlans(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "FloorPlanHandler.ListFloorPlans")
+	defer span.End()
+
+	plans, err := h.floorPlanService.ListFloorPlans(ctx)
+	if err != nil {
+		span.RecordError(err)
+		http.Error(w, fmt.Sprintf("Failed to list floor plans: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"floor_plans": plans,
+		"total":       len(plans),
+	})
+}
+
+// GetFloorPlan retrieves a floor plan by ID
+func (h *FloorPlanHandler) GetFloorPlan(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "FloorPlanHandler.GetFloorPlan")
+	defer span.End()
+
+	floorPlanID := mux.Vars(r)["floorPlanId"]
+	span.SetAttributes(attribute.String("floorplan.id", floorPlanID))
+
+	plan, err := h.floorPlanService.GetFloorPlan(ctx, floorPlanID)
+	if err != nil {
+		span.RecordError(err)
+		http.Error(w, "Floor plan not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(plan)
+}
+
+// DeleteFloorPlan deletes a floor plan with its rooms and placements
+func (h *FloorPlanHandler) DeleteFloorPlan(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "FloorPlanHandler.DeleteFloorPlan")
+	defer span.End()
+
+	floorPlanID := mux.Vars(r)["floorPlanId"]
+	span.SetAttributes(attribute.String("floorplan.id", floorPlanID))
+
+	if err := h.floorPlanService.DeleteFloorPlan(ctx, floorPlanID); err != nil {
+		span.RecordError(err)
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, "Floor plan not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to delete floor plan: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UploadFloorPlanImage stores the PNG or JPEG request body as the floor plan image
+func (h *FloorPlanHandler) UploadFloorPlanImage(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "FloorPlanHandler.UploadFloorPlanImage")
+	defer span.End()
+
+	floorPlanID := mux.Vars(r)["floorPlanId"]
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxFloorPlanImageSize))
+	if err != nil {
+		span.RecordError(err)
+		http.Error(w, "Failed to read image", http.StatusBadRequest)
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("floorplan.id", floorPlanID),
+		attribute.Int("file.size", len(data)),
+	)
+
+	plan, err := h.floorPlanService.UploadFloorPlanImage(ctx, floorPlanID, data)
+	if err != nil {
+		span.RecordError(err)
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, "Floor plan not found", http.StatusNotFound)
+			return
+		}
+		if strings.Contains(err.Error(), "invalid floor plan image") {
+			http.Error(w, "Image must be PNG or JPEG", http.StatusBadRequest)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to upload floor plan image: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(plan)
+}
+
+// GetFloorPlanImage serves the stored floor plan image
+func (h *FloorPlanHandler) GetFloorPlanImage(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "FloorPlanHandler.GetFloorPlanImage")
+	defer span.End()
+
+	floorPlanID := mux.Vars(r)["floorPlanId"]
+	span.SetAttributes(attribute.String("floorplan.id", floorPlanID))
+
+	data, format, err := h.floorPlanService.GetFloorPlanImage(ctx, floorPlanID)
+	if err != nil {
+		span.RecordError(err)
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, "Floor plan image not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to get floor plan image: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/"+format)
+	w.Write(data)
+}
+
+// PlaceDevice places a session device on a floor plan
+func (h *FloorPlanHandler) PlaceDevice(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "FloorPlanHandler.PlaceDevice")
+	defer span.End()
+
+	sessionID := mux.Vars(r)["sessionId"]
+
+	var req service.PlaceDeviceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		span.RecordError(err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.FloorPlanID == "" {
+		http.Error(w, "floor_plan_id is required", http.StatusBadRequest)
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("session.id", sessionID),
+		attribute.String("floorplan.id", req.FloorPlanID),
+	)
+
+	placement, err := h.floorPlanService.PlaceDevice(ctx, sessionID, req)
+	if err != nil {
+		span.RecordError(err)
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to place device: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(placement)
+}
+
+// GetPlacements lists the device placements of a session
+func (h *FloorPlanHandler) GetPlacements(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "FloorPlanHandler.GetPlacements")
+	defer span.End()
+
+	sessionID := mux.Vars(r)["sessionId"]
+	span.SetAttributes(attribute.String("session.id", sessionID))
+
+	placements, err := h.floorPlanService.GetPlacements(ctx, sessionID)
+	if err != nil {
+		span.RecordError(err)
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, "Session not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to get placements: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"placements": placements,
+		"total":      len(placements),
+	})
+}
+
+// GetLocatedEvents returns session events in building coordinates, optionally
+// limited to one room and to a comma-separated list of event types
+func (h *FloorPlanHandler) GetLocatedEvents(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "FloorPlanHandler.GetLocatedEvents")
+	defer span.End()
+
+	sessionID := mux.Vars(r)["sessionId"]
+
+	opts := service.LocateEventsOptions{RoomID: r.URL.Query().Get("room")}
+	if typesStr := r.URL.Query().Get("type"); typesStr != "" {
+		for _, eventType := range strings.Split(typesStr, ",") {
+			opts.EventTypes = append(opts.EventTypes, strings.TrimSpace(eventType))
+		}
+	}
+
+	span.SetAttributes(
+		attribute.String("session.id", sessionID),
+		attribute.String("room.id", opts.RoomID),
+	)
+
+	events, err := h.floorPlanService.LocateEvents(ctx, sessionID, opts)
+	if err != nil {
+		span.RecordError(err)
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to locate events: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"events": events,
+		"total":  len(events),
+	})
+}
+
+// RegisterRoutes registers floor plan related routes
+func (h *FloorPlanHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/api/v1/floorplans", h.CreateFloorPlan).Methods("POST")
+	r.HandleFunc("/api/v1/floorplans", h.ListFloorPlans).Methods("GET")
+	r.HandleFunc("/api/v1/floorplans/{floorPlanId}", h.GetFloorPlan).Methods("GET")
+	r.HandleFunc("/api/v1/floorplans/{floorPlanId}", h.DeleteFloorPlan).Methods("DELETE")
+	r.HandleFunc("/api/v1/floorplans/{floorPlanId}/image", h.UploadFloorPlanImage).Methods("PUT")
+	r.HandleFunc("/api/v1/floorplans/{floorPlanId}/image", h.GetFloorPlanImage).Methods("GET")
+	r.HandleFunc("/api/v1/sessions/{sessionId}/placements", h.PlaceDevice).Methods("POST")
+	r.HandleFunc("/api/v1/sessions/{sessionId}/placements", h.GetPlacements).Methods("GET")
+	r.HandleFunc("/api/v1/sessions/{sessionId}/located-events", h.GetLocatedEvents).Methods("GET")
+}
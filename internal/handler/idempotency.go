@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/myideascope/otherside/internal/service"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// idempotencyKeyHeader names the request header carrying a client's
+	// idempotency key
+	idempotencyKeyHeader = "Idempotency-Key"
+
+	// idempotentReplayHeader marks responses served from the stored copy
+	idempotentReplayHeader = "Idempotent-Replayed"
+
+	// maxIdempotentBodySize bounds the request bodies read for fingerprinting,
+	// the size of the largest upload: an EVP recording's multipart form
+	maxIdempotentBodySize = 32 << 20 // 32 MB
+)
+
+// IdempotencyHandler answers retried writes from stored responses
+type IdempotencyHandler struct {
+	idempotencyService *service.IdempotencyService
+	tracer             trace.Tracer
+}
+
+// NewIdempotencyHandler creates a new idempotency handler
+func NewIdempotencyHandler(idempotencyService *service.IdempotencyService) *IdempotencyHandler {
+	return &IdempotencyHandler{
+		idempotencyService: idempotencyService,
+		tracer:             otel.Tracer("otherside/idempotency"),
+	}
+}
+
+// Middleware stores the response to every API write sent with an
+// Idempotency-Key header and answers later requests with the same key from
+// the stored copy instead of handling them again. It runs after routing, so
+// register it with router.Use; unknown routes are never stored.
+func (h *IdempotencyHandler) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotencyKeyHeader)
+		if key == "" || !isIdempotentWrite(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, span := h.tracer.Start(r.Context(), "IdempotencyHandler.Middleware")
+		defer span.End()
+		span.SetAttributes(attribute.String("idempotency.key", key))
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentBodySize))
+		if err != nil {
+			span.RecordError(err)
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, "Failed to read request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		stored, err := h.idempotencyService.Begin(ctx, service.IdempotentRequest{
+			Key:    key,
+			Method: r.Method,
+			Path:   r.URL.RequestURI(),
+			Body:   requestFingerprint(r, body),
+		})
+		if err != nil {
+			span.RecordError(err)
+			writeIdempotencyError(w, err)
+			return
+		}
+		if stored != nil {
+			span.SetAttributes(attribute.Bool("idempotency.replayed", true))
+			if stored.ContentType != "" {
+				w.Header().Set("Content-Type", stored.ContentType)
+			}
+			w.Header().Set(idempotentReplayHeader, "true")
+			w.WriteHeader(stored.StatusCode)
+			w.Write(stored.ResponseBody)
+			return
+		}
+
+		// The response is stored even when the client has gone away, which
+		// is when it will need it most
+		storeCtx := context.WithoutCancel(r.Context())
+		recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		completed := false
+		defer func() {
+			if !completed {
+				if err := h.idempotencyService.Release(storeCtx, key); err != nil {
+					log.Printf("Failed to release idempotency key %q: %v", key, err)
+				}
+			}
+		}()
+
+		next.ServeHTTP(recorder, r)
+
+		if err := h.idempotencyService.Complete(storeCtx, key, recorder.status, recorder.Header().Get("Content-Type"), recorder.body.Bytes()); err != nil {
+			log.Printf("Failed to store response for idempotency key %q: %v", key, err)
+			return
+		}
+		completed = true
+	})
+}
+
+// isIdempotentWrite reports whether a request is an API write whose
+// response may be stored
+func isIdempotentWrite(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return strings.HasPrefix(r.URL.Path, "/api/")
+	default:
+		return false
+	}
+}
+
+// requestFingerprint returns the part of a request body that identifies
+// it. Multipart bodies drop their boundary, which clients pick anew for
+// every attempt.
+func requestFingerprint(r *http.Request, body []byte) []byte {
+	mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") || params["boundary"] == "" {
+		return body
+	}
+	return bytes.ReplaceAll(body, []byte(params["boundary"]), nil)
+}
+
+// responseRecorder passes a response through while keeping a copy of its
+// status and body
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+// writeIdempotencyError maps idempotency key errors to HTTP status codes
+func writeIdempotencyError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "invalid idempotency key"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case strings.Contains(err.Error(), "idempotency key in progress"):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case strings.Contains(err.Error(), "idempotency key reused"):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	default:
+		http.Error(w, fmt.Sprintf("Failed to check idempotency key: %v", err), http.StatusInternalServerError)
+	}
+}
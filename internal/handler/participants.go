@@ -0,0 +1,310 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/myideascope/otherside/internal/service"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ParticipantHandler handles HTTP requests for investigators, devices and
+// session participants
+type ParticipantHandler struct {
+	participantService *service.ParticipantService
+	tracer             trace.Tracer
+}
+
+// NewParticipantHandler creates a new participant handler
+func NewParticipantHandler(participantService *service.ParticipantService) *ParticipantHandler {
+	return &ParticipantHandler{
+		participantService: participantService,
+		tracer:             otel.Tracer("otherside/participants"),
+	}
+}
+
+// CreateInvestigator creates an investigator
+func (h *ParticipantHandler) CreateInvestigator(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "ParticipantHandler.CreateInvestigator")
+	defer span.End()
+
+	var req service.CreateInvestigatorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		span.RecordError(err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	investigator, err := h.participantService.CreateInvestigator(ctx, req)
+	if err != nil {
+		span.RecordError(err)
+		writeParticipantError(w, err, "Failed to create investigator")
+		return
+	}
+
+	span.SetAttributes(attribute.String("investigator.id", investigator.ID))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(investigator)
+}
+
+// ListInvestigators lists all investigators
+func (h *ParticipantHandler) ListInvestigators(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "ParticipantHandler.ListInvestigators")
+	defer span.End()
+
+	investigators, err := h.participantService.ListInvestigators(ctx)
+	if err != nil {
+		span.RecordError(err)
+		http.Error(w, fmt.Sprintf("Failed to list investigators: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"investigators": investigators,
+		"total":         len(investigators),
+	})
+}
+
+// GetInvestigator retrieves an investigator by ID
+func (h *ParticipantHandler) GetInvestigator(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "ParticipantHandler.GetInvestigator")
+	defer span.End()
+
+	investigatorID := mux.Vars(r)["investigatorId"]
+	span.SetAttributes(attribute.String("investigator.id", investigatorID))
+
+	investigator, err := h.participantService.GetInvestigator(ctx, investigatorID)
+	if err != nil {
+		span.RecordError(err)
+		http.Error(w, "Investigator not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(investigator)
+}
+
+// DeleteInvestigator deletes an investigator. Events they recorded keep
+// their data but lose the attribution.
+func (h *ParticipantHandler) DeleteInvestigator(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "ParticipantHandler.DeleteInvestigator")
+	defer span.End()
+
+	investigatorID := mux.Vars(r)["investigatorId"]
+	span.SetAttributes(attribute.String("investigator.id", investigatorID))
+
+	if err := h.participantService.DeleteInvestigator(ctx, investigatorID); err != nil {
+		span.RecordError(err)
+		writeParticipantError(w, err, "Failed to delete investigator")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateDevice registers a device
+func (h *ParticipantHandler) CreateDevice(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "ParticipantHandler.CreateDevice")
+	defer span.End()
+
+	var req service.CreateDeviceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		span.RecordError(err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	device, err := h.participantService.CreateDevice(ctx, req)
+	if err != nil {
+		span.RecordError(err)
+		writeParticipantError(w, err, "Failed to create device")
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("device.id", device.ID),
+		attribute.String("device.kind", device.Kind),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(device)
+}
+
+// ListDevices lists all devices
+func (h *ParticipantHandler) ListDevices(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "ParticipantHandler.ListDevices")
+	defer span.End()
+
+	devices, err := h.participantService.ListDevices(ctx)
+	if err != nil {
+		span.RecordError(err)
+		http.Error(w, fmt.Sprintf("Failed to list devices: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"devices": devices,
+		"total":   len(devices),
+	})
+}
+
+// GetDevice retrieves a device by ID
+func (h *ParticipantHandler) GetDevice(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "ParticipantHandler.GetDevice")
+	defer span.End()
+
+	deviceID := mux.Vars(r)["deviceId"]
+	span.SetAttributes(attribute.String("device.id", deviceID))
+
+	device, err := h.participantService.GetDevice(ctx, deviceID)
+	if err != nil {
+		span.RecordError(err)
+		http.Error(w, "Device not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(device)
+}
+
+// DeleteDevice deletes a device
+func (h *ParticipantHandler) DeleteDevice(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "ParticipantHandler.DeleteDevice")
+	defer span.End()
+
+	deviceID := mux.Vars(r)["deviceId"]
+	span.SetAttributes(attribute.String("device.id", deviceID))
+
+	if err := h.participantService.DeleteDevice(ctx, deviceID); err != nil {
+		span.RecordError(err)
+		writeParticipantError(w, err, "Failed to delete device")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddParticipant adds an investigator to a session, or changes their role
+func (h *ParticipantHandler) AddParticipant(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "ParticipantHandler.AddParticipant")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	sessionID := vars["sessionId"]
+	investigatorID := vars["investigatorId"]
+
+	var req struct {
+		Role domain.ParticipantRole `json:"role"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		span.RecordError(err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("session.id", sessionID),
+		attribute.String("investigator.id", investigatorID),
+		attribute.String("participant.role", string(req.Role)),
+	)
+
+	participant, err := h.participantService.AddParticipant(ctx, sessionID, investigatorID, req.Role)
+	if err != nil {
+		span.RecordError(err)
+		writeParticipantError(w, err, "Failed to add participant")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(participant)
+}
+
+// ListParticipants lists the investigators taking part in a session
+func (h *ParticipantHandler) ListParticipants(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "ParticipantHandler.ListParticipants")
+	defer span.End()
+
+	sessionID := mux.Vars(r)["sessionId"]
+	span.SetAttributes(attribute.String("session.id", sessionID))
+
+	participants, err := h.participantService.ListParticipants(ctx, sessionID)
+	if err != nil {
+		span.RecordError(err)
+		writeParticipantError(w, err, "Failed to list participants")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"participants": participants,
+		"total":        len(participants),
+	})
+}
+
+// RemoveParticipant removes an investigator from a session
+func (h *ParticipantHandler) RemoveParticipant(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "ParticipantHandler.RemoveParticipant")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	sessionID := vars["sessionId"]
+	investigatorID := vars["investigatorId"]
+
+	span.SetAttributes(
+		attribute.String("session.id", sessionID),
+		attribute.String("investigator.id", investigatorID),
+	)
+
+	if err := h.participantService.RemoveParticipant(ctx, sessionID, investigatorID); err != nil {
+		span.RecordError(err)
+		writeParticipantError(w, err, "Failed to remove participant")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeParticipantError maps participant service errors to HTTP statuses
+func writeParticipantError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case strings.Contains(err.Error(), "forbidden"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "invalid participant"),
+		strings.Contains(err.Error(), "invalid investigator"),
+		strings.Contains(err.Error(), "invalid device"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case strings.Contains(err.Error(), "read-only"),
+		strings.Contains(err.Error(), "still owns"):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, fmt.Sprintf("%s: %v", message, err), http.StatusInternalServerError)
+	}
+}
+
+// RegisterRoutes registers investigator, device and participant routes
+func (h *ParticipantHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/api/v1/investigators", h.CreateInvestigator).Methods("POST")
+	r.HandleFunc("/api/v1/investigators", h.ListInvestigators).Methods("GET")
+	r.HandleFunc("/api/v1/investigators/{investigatorId}", h.GetInvestigator).Methods("GET")
+	r.HandleFunc("/api/v1/investigators/{investigatorId}", h.DeleteInvestigator).Methods("DELETE")
+	r.HandleFunc("/api/v1/devices", h.CreateDevice).Methods("POST")
+	r.HandleFunc("/api/v1/devices", h.ListDevices).Methods("GET")
+	r.HandleFunc("/api/v1/devices/{deviceId}", h.GetDevice).Methods("GET")
+	r.HandleFunc("/api/v1/devices/{deviceId}", h.DeleteDevice).Methods("DELETE")
+	r.HandleFunc("/api/v1/sessions/{sessionId}/participants", h.ListParticipants).Methods("GET")
+	r.HandleFunc("/api/v1/sessions/{sessionId}/participants/{investigatorId}", h.AddParticipant).Methods("PUT")
+	r.HandleFunc("/api/v1/sessions/{sessionId}/participants/{investigatorId}", h.RemoveParticipant).Methods("DELETE")
+}
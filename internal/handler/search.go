@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/myideascope/otherside/internal/service"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SearchHandler handles HTTP requests for full-text search
+type SearchHandler struct {
+	searchService *service.SearchService
+	tracer        trace.Tracer
+}
+
+// NewSearchHandler creates a new search handler
+func NewSearchHandler(searchService *service.SearchService) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+		tracer:        otel.Tracer("otherside/search"),
+	}
+}
+
+// Search returns the sessions whose text matches q, with their hits
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "SearchHandler.Search")
+	defer span.End()
+
+	query, err := parseSearchQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	span.SetAttributes(
+		attribute.StringSlice("filter.types", query.Types),
+		attribute.Int("pagination.limit", query.Limit),
+	)
+
+	result, err := h.searchService.Search(ctx, query)
+	if err != nil {
+		span.RecordError(err)
+		if strings.Contains(err.Error(), "invalid search query") {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to search: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	span.SetAttributes(attribute.Int("search.sessions", result.Total))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// parseSearchQuery reads q, type, limit and hits
+func parseSearchQuery(r *http.Request) (service.SearchQuery, error) {
+	values := r.URL.Query()
+	query := service.SearchQuery{Q: values.Get("q")}
+
+	for _, param := range values["type"] {
+		for _, entityType := range strings.Split(param, ",") {
+			if entityType = strings.TrimSpace(entityType); entityType != "" {
+				query.Types = append(query.Types, entityType)
+			}
+		}
+	}
+
+	if param := values.Get("limit"); param != "" {
+		limit, err := strconv.Atoi(param)
+		if err != nil || limit < 0 {
+			return query, fmt.Errorf("invalid limit: %s", param)
+		}
+		query.Limit = limit
+	}
+	if param := values.Get("hits"); param != "" {
+		hits, err := strconv.Atoi(param)
+		if err != nil || hits < 0 {
+			return query, fmt.Errorf("invalid hits: %s", param)
+		}
+		query.HitsPerSession = hits
+	}
+
+	return query, nil
+}
+
+// RegisterRoutes registers search routes
+func (h *SearchHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/api/v1/search", h.Search).Methods("GET")
+}
@@ -55,6 +55,18 @@ func (h *SessionHandler) CreateSession(w http.ResponseWriter, r *http.Request) {
 	session, err := h.sessionService.CreateSession(ctx, req)
 	if err != nil {
 		span.RecordError(err)
+		switch {
+		case strings.Contains(err.Error(), "forbidden"):
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		case strings.Contains(err.Error(), "team not found"),
+			strings.Contains(err.Error(), "invalid id:"):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		case strings.Contains(err.Error(), "id conflict"):
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
 		http.Error(w, fmt.Sprintf("Failed to create session: %v", err), http.StatusInternalServerError)
 		return
 	}
@@ -77,6 +89,10 @@ func (h *SessionHandler) GetSession(w http.ResponseWriter, r *http.Request) {
 	session, err := h.sessionService.GetSessionSummary(ctx, sessionID)
 	if err != nil {
 		span.RecordError(err)
+		if strings.Contains(err.Error(), "forbidden") {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		if strings.Contains(err.Error(), "not found") {
 			http.Error(w, "Session not found", http.StatusNotFound)
 			return
@@ -85,8 +101,7 @@ func (h *SessionHandler) GetSession(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(session)
+	writeVersioned(w, session.Session.Version, session)
 }
 
 // ProcessEVP processes EVP audio data
@@ -143,14 +158,17 @@ func (h *SessionHandler) ProcessEVP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	metadata := service.EVPMetadata{
-		FilePath:    header.Filename,
-		Annotations: annotationList,
+		ID:             r.FormValue("id"),
+		FilePath:       header.Filename,
+		Annotations:    annotationList,
+		InvestigatorID: r.FormValue("investigator_id"),
+		DeviceID:       r.FormValue("device_id"),
 	}
 
 	evp, err := h.sessionService.ProcessEVPRecording(ctx, sessionID, floatData, metadata)
 	if err != nil {
 		span.RecordError(err)
-		http.Error(w, fmt.Sprintf("Failed to process EVP: %v", err), http.StatusInternalServerError)
+		writeSessionEventError(w, err, "Failed to process EVP")
 		return
 	}
 
@@ -185,7 +203,7 @@ func (h *SessionHandler) GenerateVOX(w http.ResponseWriter, r *http.Request) {
 	voxEvent, err := h.sessionService.GenerateVOXCommunication(ctx, sessionID, triggerData)
 	if err != nil {
 		span.RecordError(err)
-		http.Error(w, fmt.Sprintf("Failed to generate VOX: %v", err), http.StatusInternalServerError)
+		writeSessionEventError(w, err, "Failed to generate VOX")
 		return
 	}
 
@@ -226,7 +244,7 @@ func (h *SessionHandler) ProcessRadar(w http.ResponseWriter, r *http.Request) {
 	radarEvent, err := h.sessionService.ProcessRadarEvent(ctx, sessionID, radarData)
 	if err != nil {
 		span.RecordError(err)
-		http.Error(w, fmt.Sprintf("Failed to process radar event: %v", err), http.StatusInternalServerError)
+		writeSessionEventError(w, err, "Failed to process radar event")
 		return
 	}
 
@@ -260,7 +278,7 @@ func (h *SessionHandler) ProcessSLS(w http.ResponseWriter, r *http.Request) {
 	slsDetection, err := h.sessionService.ProcessSLSDetection(ctx, sessionID, slsData)
 	if err != nil {
 		span.RecordError(err)
-		http.Error(w, fmt.Sprintf("Failed to process SLS detection: %v", err), http.StatusInternalServerError)
+		writeSessionEventError(w, err, "Failed to process SLS detection")
 		return
 	}
 
@@ -294,7 +312,7 @@ func (h *SessionHandler) RecordInteraction(w http.ResponseWriter, r *http.Reques
 	interaction, err := h.sessionService.RecordUserInteraction(ctx, sessionID, interactionData)
 	if err != nil {
 		span.RecordError(err)
-		http.Error(w, fmt.Sprintf("Failed to record interaction: %v", err), http.StatusInternalServerError)
+		writeSessionEventError(w, err, "Failed to record interaction")
 		return
 	}
 
@@ -425,6 +443,10 @@ func (h *SessionHandler) GetSessionEvents(w http.ResponseWriter, r *http.Request
 	summary, err := h.sessionService.GetSessionSummary(ctx, sessionID)
 	if err != nil {
 		span.RecordError(err)
+		if strings.Contains(err.Error(), "forbidden") {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		http.Error(w, fmt.Sprintf("Failed to get session events: %v", err), http.StatusInternalServerError)
 		return
 	}
@@ -481,7 +503,9 @@ func (h *SessionHandler) RegisterRoutes(r *mux.Router) {
 
 	// Paranormal investigation features
 	r.HandleFunc("/api/v1/sessions/{sessionId}/evp", h.ProcessEVP).Methods("POST")
+	r.HandleFunc("/api/v1/sessions/{sessionId}/evp/{evpId}", h.UpdateEVP).Methods("PATCH")
 	r.HandleFunc("/api/v1/sessions/{sessionId}/vox", h.GenerateVOX).Methods("POST")
+	r.HandleFunc("/api/v1/sessions/{sessionId}/vox/{voxId}", h.UpdateVOX).Methods("PATCH")
 	r.HandleFunc("/api/v1/sessions/{sessionId}/radar", h.ProcessRadar).Methods("POST")
 	r.HandleFunc("/api/v1/sessions/{sessionId}/sls", h.ProcessSLS).Methods("POST")
 	r.HandleFunc("/api/v1/sessions/{sessionId}/interactions", h.RecordInteraction).Methods("POST")
@@ -493,12 +517,138 @@ func (h *SessionHandler) RegisterRoutes(r *mux.Router) {
 	r.HandleFunc("/health", h.HealthCheck).Methods("GET")
 }
 
+// UpdateEVP edits the annotations or quality rating of an EVP recording.
+// An If-Match header with the recording's ETag makes the update fail with
+// 409 if someone else changed the recording first.
+func (h *SessionHandler) UpdateEVP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "SessionHandler.UpdateEVP")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	sessionID := vars["sessionId"]
+	evpID := vars["evpId"]
+
+	span.SetAttributes(
+		attribute.String("session.id", sessionID),
+		attribute.String("evp.id", evpID),
+	)
+
+	var req service.UpdateEVPRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		span.RecordError(err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if version != 0 {
+		req.Version = version
+	}
+
+	evp, err := h.sessionService.UpdateEVP(ctx, sessionID, evpID, req)
+	if err != nil {
+		span.RecordError(err)
+		writeEventUpdateError(w, err, "Failed to update EVP recording")
+		return
+	}
+
+	writeVersioned(w, evp.Version, evp)
+}
+
+// UpdateVOX records the investigator's response to a VOX event, with the
+// same If-Match handling as UpdateEVP
+func (h *SessionHandler) UpdateVOX(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "SessionHandler.UpdateVOX")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	sessionID := vars["sessionId"]
+	voxID := vars["voxId"]
+
+	span.SetAttributes(
+		attribute.String("session.id", sessionID),
+		attribute.String("vox.id", voxID),
+	)
+
+	var req service.UpdateVOXRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		span.RecordError(err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if version != 0 {
+		req.Version = version
+	}
+
+	vox, err := h.sessionService.UpdateVOX(ctx, sessionID, voxID, req)
+	if err != nil {
+		span.RecordError(err)
+		writeEventUpdateError(w, err, "Failed to update VOX event")
+		return
+	}
+
+	writeVersioned(w, vox.Version, vox)
+}
+
+// writeEventUpdateError maps an error editing a session event to its status code
+func writeEventUpdateError(w http.ResponseWriter, err error, message string) {
+	if writeVersionConflict(w, err) {
+		return
+	}
+	switch {
+	case strings.Contains(err.Error(), "forbidden"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "invalid evp update"),
+		strings.Contains(err.Error(), "invalid vox update"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case strings.Contains(err.Error(), "session not found"):
+		http.Error(w, "Session not found", http.StatusNotFound)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "read-only"):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, fmt.Sprintf("%s: %v", message, err), http.StatusInternalServerError)
+	}
+}
+
+// writeSessionEventError maps an error from recording a session event to
+// its status code
+func writeSessionEventError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case strings.Contains(err.Error(), "forbidden"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "invalid attribution"),
+		strings.Contains(err.Error(), "invalid id:"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case strings.Contains(err.Error(), "id conflict"):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, "Session not found", http.StatusNotFound)
+	case strings.Contains(err.Error(), "not active"):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, fmt.Sprintf("%s: %v", message, err), http.StatusInternalServerError)
+	}
+}
+
 // CORS middleware
 func (h *SessionHandler) CORSMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, If-Match, "+idempotencyKeyHeader)
+		w.Header().Set("Access-Control-Expose-Headers", "ETag, "+idempotentReplayHeader)
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
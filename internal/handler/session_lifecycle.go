@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/myideascope/otherside/internal/service"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SessionLifecycleHandler handles HTTP requests that pause, resume,
+// complete, archive, edit and delete sessions
+type SessionLifecycleHandler struct {
+	lifecycleService *service.SessionLifecycleService
+	tracer           trace.Tracer
+}
+
+// NewSessionLifecycleHandler creates a new session lifecycle handler
+func NewSessionLifecycleHandler(lifecycleService *service.SessionLifecycleService) *SessionLifecycleHandler {
+	return &SessionLifecycleHandler{
+		lifecycleService: lifecycleService,
+		tracer:           otel.Tracer("otherside/lifecycle"),
+	}
+}
+
+// PauseSession pauses an active session
+func (h *SessionLifecycleHandler) PauseSession(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, "SessionLifecycleHandler.PauseSession", h.lifecycleService.PauseSession)
+}
+
+// ResumeSession resumes a paused session
+func (h *SessionLifecycleHandler) ResumeSession(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, "SessionLifecycleHandler.ResumeSession", h.lifecycleService.ResumeSession)
+}
+
+// CompleteSession ends an investigation
+func (h *SessionLifecycleHandler) CompleteSession(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, "SessionLifecycleHandler.CompleteSession", h.lifecycleService.CompleteSession)
+}
+
+// ArchiveSession archives a complete session
+func (h *SessionLifecycleHandler) ArchiveSession(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, "SessionLifecycleHandler.ArchiveSession", h.lifecycleService.ArchiveSession)
+}
+
+// UpdateSession edits the title, notes, location or environmental
+// conditions. An If-Match header with the session's ETag makes the update
+// fail with 409 if someone else changed the session first.
+func (h *SessionLifecycleHandler) UpdateSession(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "SessionLifecycleHandler.UpdateSession")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	sessionID := vars["id"]
+
+	span.SetAttributes(attribute.String("session.id", sessionID))
+
+	var req service.UpdateSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		span.RecordError(err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// If-Match takes precedence over a version in the body
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if version != 0 {
+		req.Version = version
+	}
+
+	session, err := h.lifecycleService.UpdateSession(ctx, sessionID, req)
+	if err != nil {
+		span.RecordError(err)
+		if writeVersionConflict(w, err) {
+			return
+		}
+		writeLifecycleError(w, err, "Failed to update session")
+		return
+	}
+
+	writeVersioned(w, session.Version, session)
+}
+
+// DeleteSession deletes a session and its stored files
+func (h *SessionLifecycleHandler) DeleteSession(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "SessionLifecycleHandler.DeleteSession")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	sessionID := vars["id"]
+
+	span.SetAttributes(attribute.String("session.id", sessionID))
+
+	if err := h.lifecycleService.DeleteSession(ctx, sessionID); err != nil {
+		span.RecordError(err)
+		writeLifecycleError(w, err, "Failed to delete session")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetStatusHistory lists the lifecycle transitions of a session
+func (h *SessionLifecycleHandler) GetStatusHistory(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "SessionLifecycleHandler.GetStatusHistory")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	sessionID := vars["id"]
+
+	span.SetAttributes(attribute.String("session.id", sessionID))
+
+	history, err := h.lifecycleService.GetStatusHistory(ctx, sessionID)
+	if err != nil {
+		span.RecordError(err)
+		writeLifecycleError(w, err, "Failed to get status history")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"history": history,
+		"total":   len(history),
+	})
+}
+
+// transition runs one lifecycle transition and returns the updated session
+func (h *SessionLifecycleHandler) transition(
+	w http.ResponseWriter,
+	r *http.Request,
+	spanName string,
+	apply func(context.Context, string) (*domain.Session, error),
+) {
+	ctx, span := h.tracer.Start(r.Context(), spanName)
+	defer span.End()
+
+	vars := mux.Vars(r)
+	sessionID := vars["id"]
+
+	span.SetAttributes(attribute.String("session.id", sessionID))
+
+	session, err := apply(ctx, sessionID)
+	if err != nil {
+		span.RecordError(err)
+		writeLifecycleError(w, err, "Failed to change session status")
+		return
+	}
+
+	span.SetAttributes(attribute.String("session.status", string(session.Status)))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(session)
+}
+
+// writeLifecycleError maps a lifecycle error to its status code
+func writeLifecycleError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case strings.Contains(err.Error(), "forbidden"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, "Session not found", http.StatusNotFound)
+	case strings.Contains(err.Error(), "invalid session transition"),
+		strings.Contains(err.Error(), "read-only"),
+		strings.Contains(err.Error(), "still active"):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case strings.Contains(err.Error(), "invalid session update"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, fmt.Sprintf("%s: %v", message, err), http.StatusInternalServerError)
+	}
+}
+
+// RegisterRoutes registers session lifecycle routes
+func (h *SessionLifecycleHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/api/v1/sessions/{id}", h.UpdateSession).Methods("PATCH")
+	r.HandleFunc("/api/v1/sessions/{id}", h.DeleteSession).Methods("DELETE")
+	r.HandleFunc("/api/v1/sessions/{id}/pause", h.PauseSession).Methods("POST")
+	r.HandleFunc("/api/v1/sessions/{id}/resume", h.ResumeSession).Methods("POST")
+	r.HandleFunc("/api/v1/sessions/{id}/complete", h.CompleteSession).Methods("POST")
+	r.HandleFunc("/api/v1/sessions/{id}/archive", h.ArchiveSession).Methods("POST")
+	r.HandleFunc("/api/v1/sessions/{id}/status-history", h.GetStatusHistory).Methods("GET")
+}
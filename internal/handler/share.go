@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/myideascope/otherside/internal/service"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// sharedPathPrefix is where share link holders read their session without
+// an account
+const sharedPathPrefix = "/api/v1/shared/"
+
+// ShareHandler handles HTTP requests for session share links and the
+// public read-only view behind them
+type ShareHandler struct {
+	shareService *service.ShareService
+	tracer       trace.Tracer
+}
+
+// NewShareHandler creates a new share handler
+func NewShareHandler(shareService *service.ShareService) *ShareHandler {
+	return &ShareHandler{
+		shareService: shareService,
+		tracer:       otel.Tracer("otherside/share"),
+	}
+}
+
+// CreateShareLink creates a share link to a session and returns its token once
+func (h *ShareHandler) CreateShareLink(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "ShareHandler.CreateShareLink")
+	defer span.End()
+
+	sessionID := mux.Vars(r)["sessionId"]
+	span.SetAttributes(attribute.String("session.id", sessionID))
+
+	var req service.CreateShareLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		span.RecordError(err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	link, token, err := h.shareService.CreateShareLink(ctx, sessionID, req)
+	if err != nil {
+		span.RecordError(err)
+		writeShareError(w, err, "Failed to create share link")
+		return
+	}
+
+	span.SetAttributes(attribute.String("share.id", link.ID))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"share_link": link,
+		"token":      token,
+		"path":       sharedPathPrefix + token,
+	})
+}
+
+// ListShareLinks lists the share links of a session
+func (h *ShareHandler) ListShareLinks(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "ShareHandler.ListShareLinks")
+	defer span.End()
+
+	sessionID := mux.Vars(r)["sessionId"]
+	span.SetAttributes(attribute.String("session.id", sessionID))
+
+	links, err := h.shareService.ListShareLinks(ctx, sessionID)
+	if err != nil {
+		span.RecordError(err)
+		writeShareError(w, err, "Failed to list share links")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"share_links": links,
+		"total":       len(links),
+	})
+}
+
+// RevokeShareLink stops a share link from working
+func (h *ShareHandler) RevokeShareLink(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "ShareHandler.RevokeShareLink")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	sessionID, shareID := vars["sessionId"], vars["shareId"]
+	span.SetAttributes(
+		attribute.String("session.id", sessionID),
+		attribute.String("share.id", shareID),
+	)
+
+	if err := h.shareService.RevokeShareLink(ctx, sessionID, shareID); err != nil {
+		span.RecordError(err)
+		writeShareError(w, err, "Failed to revoke share link")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetSharedView returns the session and scope behind a share link
+func (h *ShareHandler) GetSharedView(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "ShareHandler.GetSharedView")
+	defer span.End()
+
+	view, err := h.shareService.GetSharedView(ctx, mux.Vars(r)["token"])
+	if err != nil {
+		span.RecordError(err)
+		writeShareError(w, err, "Failed to get shared session")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(view)
+}
+
+// GetSharedTimeline returns the shared part of a session timeline. It takes
+// the same query parameters as the session timeline.
+func (h *ShareHandler) GetSharedTimeline(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "ShareHandler.GetSharedTimeline")
+	defer span.End()
+
+	query, err := parseTimelineQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	span.SetAttributes(
+		attribute.StringSlice("filter.types", query.Types),
+		attribute.Int("pagination.limit", query.Limit),
+		attribute.Int("pagination.offset", query.Offset),
+	)
+
+	page, err := h.shareService.GetSharedTimeline(ctx, mux.Vars(r)["token"], query)
+	if err != nil {
+		span.RecordError(err)
+		writeShareError(w, err, "Failed to get shared timeline")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+// GetSharedReport returns a summary of the shared session
+func (h *ShareHandler) GetSharedReport(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "ShareHandler.GetSharedReport")
+	defer span.End()
+
+	report, err := h.shareService.GetSharedReport(ctx, mux.Vars(r)["token"])
+	if err != nil {
+		span.RecordError(err)
+		writeShareError(w, err, "Failed to get shared report")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// GetSharedEVPAudio streams the audio of a shared EVP recording
+func (h *ShareHandler) GetSharedEVPAudio(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "ShareHandler.GetSharedEVPAudio")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	evpID := vars["evpId"]
+	span.SetAttributes(attribute.String("evp.id", evpID))
+
+	data, filename, err := h.shareService.GetSharedEVPAudio(ctx, vars["token"], evpID)
+	if err != nil {
+		span.RecordError(err)
+		writeShareError(w, err, "Failed to get EVP audio")
+		return
+	}
+
+	contentType := mime.TypeByExtension(strings.ToLower(filepath.Ext(filename)))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=\"%s\"", filename))
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(data)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// writeShareError maps share link errors to HTTP status codes. Expired and
+// revoked links get 410 so clients can tell them from mistyped ones.
+func writeShareError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case strings.Contains(err.Error(), "forbidden"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "share link expired"),
+		strings.Contains(err.Error(), "share link revoked"):
+		http.Error(w, err.Error(), http.StatusGone)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "invalid share link"),
+		strings.Contains(err.Error(), "invalid timeline query"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, fmt.Sprintf("%s: %v", message, err), http.StatusInternalServerError)
+	}
+}
+
+// RegisterRoutes registers share link routes
+func (h *ShareHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/api/v1/sessions/{sessionId}/shares", h.CreateShareLink).Methods("POST")
+	r.HandleFunc("/api/v1/sessions/{sessionId}/shares", h.ListShareLinks).Methods("GET")
+	r.HandleFunc("/api/v1/sessions/{sessionId}/shares/{shareId}", h.RevokeShareLink).Methods("DELETE")
+	r.HandleFunc(sharedPathPrefix+"{token}", h.GetSharedView).Methods("GET")
+	r.HandleFunc(sharedPathPrefix+"{token}/timeline", h.GetSharedTimeline).Methods("GET")
+	r.HandleFunc(sharedPathPrefix+"{token}/report", h.GetSharedReport).Methods("GET")
+	r.HandleFunc(sharedPathPrefix+"{token}/evp/{evpId}/audio", h.GetSharedEVPAudio).Methods("GET")
+}
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/myideascope/otherside/internal/service"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// defaultStreamHeartbeat is how often an idle stream sends a comment, so
+// that proxies and clients do not take it for a dead connection
+const defaultStreamHeartbeat = 15 * time.Second
+
+// streamRetry is how long clients wait before reconnecting a dropped stream
+const streamRetry = 3 * time.Second
+
+// StreamHandler serves live session events as Server-Sent Events
+type StreamHandler struct {
+	sessionService *service.SessionService
+	heartbeat      time.Duration
+	tracer         trace.Tracer
+}
+
+// NewStreamHandler creates a new stream handler sending a heartbeat every
+// heartbeat, or every defaultStreamHeartbeat when it is not positive
+func NewStreamHandler(sessionService *service.SessionService, heartbeat time.Duration) *StreamHandler {
+	if heartbeat <= 0 {
+		heartbeat = defaultStreamHeartbeat
+	}
+	return &StreamHandler{
+		sessionService: sessionService,
+		heartbeat:      heartbeat,
+		tracer:         otel.Tracer("otherside/stream"),
+	}
+}
+
+// StreamSessionEvents streams the events recorded to a session as they
+// arrive. Clients resume after a dropped connection with the Last-Event-ID
+// header, which EventSource sends on its own, or the last_event_id query
+// parameter; types limits the stream to a comma-separated list of event
+// types.
+func (h *StreamHandler) StreamSessionEvents(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "StreamHandler.StreamSessionEvents")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	sessionID := vars["id"]
+
+	span.SetAttributes(attribute.String("session.id", sessionID))
+
+	lastEventID, err := lastEventID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var types []string
+	if param := r.URL.Query().Get("types"); param != "" {
+		for _, eventType := range strings.Split(param, ",") {
+			if eventType = strings.TrimSpace(eventType); eventType != "" {
+				types = append(types, eventType)
+			}
+		}
+	}
+
+	sub, missed, err := h.sessionService.SubscribeEvents(ctx, sessionID, lastEventID, types)
+	if err != nil {
+		span.RecordError(err)
+		writeStreamError(w, err)
+		return
+	}
+	defer sub.Close()
+
+	// A stream outlives the server's write timeout
+	controller := http.NewResponseController(w)
+	if err := controller.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		span.RecordError(err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds())
+	for _, event := range missed {
+		if err := writeLiveEvent(w, event); err != nil {
+			return
+		}
+	}
+	if err := controller.Flush(); err != nil {
+		span.RecordError(err)
+		return
+	}
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-sub.Events:
+			if !ok {
+				// Dropped for falling behind or shutting down; the client
+				// reconnects and resumes from its last event
+				return
+			}
+			if err := writeLiveEvent(w, event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+		}
+		if err := controller.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// lastEventID reads the ID of the last event a reconnecting client received
+func lastEventID(r *http.Request) (int64, error) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("last_event_id")
+	}
+	if value == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || id < 0 {
+		return 0, fmt.Errorf("invalid last event id: %q", value)
+	}
+	return id, nil
+}
+
+// writeLiveEvent writes one event in the Server-Sent Events format
+func writeLiveEvent(w http.ResponseWriter, event service.LiveEvent) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
+
+// writeStreamError maps an error opening a stream to its status code
+func writeStreamError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "forbidden"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, "Session not found", http.StatusNotFound)
+	case strings.Contains(err.Error(), "invalid event type"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case strings.Contains(err.Error(), "not enabled"):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	default:
+		http.Error(w, fmt.Sprintf("Failed to open event stream: %v", err), http.StatusInternalServerError)
+	}
+}
+
+// RegisterRoutes registers live stream routes
+func (h *StreamHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/api/v1/sessions/{id}/stream", h.StreamSessionEvents).Methods("GET")
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/myideascope/otherside/internal/service"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SyncHandler handles HTTP requests from clients syncing offline queues
+type SyncHandler struct {
+	syncService *service.SyncService
+	tracer      trace.Tracer
+}
+
+// NewSyncHandler creates a new sync handler
+func NewSyncHandler(syncService *service.SyncService) *SyncHandler {
+	return &SyncHandler{
+		syncService: syncService,
+		tracer:      otel.Tracer("otherside/sync"),
+	}
+}
+
+// ApplyBatch applies a batch of queued operations. Operations that fail do
+// not fail the request; each gets its own result.
+func (h *SyncHandler) ApplyBatch(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "SyncHandler.ApplyBatch")
+	defer span.End()
+
+	var req service.SyncBatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		span.RecordError(err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	span.SetAttributes(attribute.Int("sync.operations", len(req.Operations)))
+
+	response, err := h.syncService.ApplyBatch(ctx, req)
+	if err != nil {
+		span.RecordError(err)
+		writeSyncError(w, err, "Failed to apply sync batch")
+		return
+	}
+
+	span.SetAttributes(
+		attribute.Int("sync.created", response.Created),
+		attribute.Int("sync.duplicates", response.Duplicates),
+		attribute.Int("sync.rejected", response.Rejected),
+		attribute.Int("sync.failed", response.Failed),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetChanges returns the changes after a cursor (since, session_id, limit)
+func (h *SyncHandler) GetChanges(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "SyncHandler.GetChanges")
+	defer span.End()
+
+	query := service.ChangesQuery{SessionID: r.URL.Query().Get("session_id")}
+	if since := r.URL.Query().Get("since"); since != "" {
+		seq, err := strconv.ParseInt(since, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid cursor: since must be a cursor returned by this endpoint", http.StatusBadRequest)
+			return
+		}
+		query.Since = seq
+	}
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		query.Limit = parsed
+	}
+
+	span.SetAttributes(
+		attribute.Int64("sync.since", query.Since),
+		attribute.String("session.id", query.SessionID),
+	)
+
+	page, err := h.syncService.GetChanges(ctx, query)
+	if err != nil {
+		span.RecordError(err)
+		writeSyncError(w, err, "Failed to get changes")
+		return
+	}
+
+	span.SetAttributes(attribute.Int("sync.changes", len(page.Changes)))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+// writeSyncError maps sync errors to HTTP status codes
+func writeSyncError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case strings.Contains(err.Error(), "forbidden"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "invalid sync batch"),
+		strings.Contains(err.Error(), "invalid cursor"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, fmt.Sprintf("%s: %v", message, err), http.StatusInternalServerError)
+	}
+}
+
+// RegisterRoutes registers sync routes
+func (h *SyncHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/api/v1/sync/batch", h.ApplyBatch).Methods("POST")
+	r.HandleFunc("/api/v1/sync/changes", h.GetChanges).Methods("GET")
+}
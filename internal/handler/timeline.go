@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/myideascope/otherside/internal/service"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TimelineHandler handles HTTP requests for unified session timelines
+type TimelineHandler struct {
+	timelineService *service.TimelineService
+	tracer          trace.Tracer
+}
+
+// NewTimelineHandler creates a new timeline handler
+func NewTimelineHandler(timelineService *service.TimelineService) *TimelineHandler {
+	return &TimelineHandler{
+		timelineService: timelineService,
+		tracer:          otel.Tracer("otherside/timeline"),
+	}
+}
+
+// GetTimeline returns a paginated, chronological stream of a session's events
+func (h *TimelineHandler) GetTimeline(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TimelineHandler.GetTimeline")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	sessionID := vars["sessionId"]
+
+	query, err := parseTimelineQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("session.id", sessionID),
+		attribute.StringSlice("filter.types", query.Types),
+		attribute.String("filter.investigator_id", query.InvestigatorID),
+		attribute.String("filter.device_id", query.DeviceID),
+		attribute.Int("pagination.limit", query.Limit),
+		attribute.Int("pagination.offset", query.Offset),
+	)
+
+	page, err := h.timelineService.GetTimeline(ctx, sessionID, query)
+	if err != nil {
+		span.RecordError(err)
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, "Session not found", http.StatusNotFound)
+			return
+		}
+		if strings.Contains(err.Error(), "invalid timeline query") {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to get timeline: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+// parseTimelineQuery reads type, from, to, min_confidence, investigator_id,
+// device_id, limit and offset
+func parseTimelineQuery(r *http.Request) (service.TimelineQuery, error) {
+	var query service.TimelineQuery
+	values := r.URL.Query()
+
+	query.InvestigatorID = values.Get("investigator_id")
+	query.DeviceID = values.Get("device_id")
+
+	if typesStr := values.Get("type"); typesStr != "" {
+		for _, eventType := range strings.Split(typesStr, ",") {
+			query.Types = append(query.Types, strings.TrimSpace(eventType))
+		}
+	}
+
+	for name, target := range map[string]**time.Time{"from": &query.From, "to": &query.To} {
+		if valueStr := values.Get(name); valueStr != "" {
+			value, err := time.Parse(time.RFC3339, valueStr)
+			if err != nil {
+				return query, fmt.Errorf("Invalid %s", name)
+			}
+			*target = &value
+		}
+	}
+
+	if minStr := values.Get("min_confidence"); minStr != "" {
+		minConfidence, err := strconv.ParseFloat(minStr, 64)
+		if err != nil || minConfidence < 0 || minConfidence > 1 {
+			return query, fmt.Errorf("Invalid min_confidence")
+		}
+		query.MinConfidence = minConfidence
+	}
+
+	if limitStr := values.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			return query, fmt.Errorf("Invalid limit")
+		}
+		query.Limit = limit
+	}
+
+	if offsetStr := values.Get("offset"); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			return query, fmt.Errorf("Invalid offset")
+		}
+		query.Offset = offset
+	}
+
+	return query, nil
+}
+
+// RegisterRoutes registers timeline routes
+func (h *TimelineHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/api/v1/sessions/{sessionId}/timeline", h.GetTimeline).Methods("GET")
+}
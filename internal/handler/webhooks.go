@@ -0,0 +1,264 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/myideascope/otherside/internal/service"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WebhookHandler handles HTTP requests for webhook subscriptions and their
+// delivery log
+type WebhookHandler struct {
+	webhookService *service.WebhookService
+	tracer         trace.Tracer
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(webhookService *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+		tracer:         otel.Tracer("otherside/webhooks"),
+	}
+}
+
+// CreateSubscription creates a webhook subscription. The response holds
+// its signing secret, which is not shown again.
+func (h *WebhookHandler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "WebhookHandler.CreateSubscription")
+	defer span.End()
+
+	var req service.WebhookSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		span.RecordError(err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	subscription, err := h.webhookService.CreateSubscription(ctx, req)
+	if err != nil {
+		span.RecordError(err)
+		writeWebhookError(w, err, "Failed to create webhook subscription")
+		return
+	}
+
+	span.SetAttributes(attribute.String("webhook.id", subscription.ID))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(subscription)
+}
+
+// ListSubscriptions lists the caller's webhook subscriptions
+func (h *WebhookHandler) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "WebhookHandler.ListSubscriptions")
+	defer span.End()
+
+	subscriptions, err := h.webhookService.ListSubscriptions(ctx)
+	if err != nil {
+		span.RecordError(err)
+		writeWebhookError(w, err, "Failed to list webhook subscriptions")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"webhooks": subscriptions,
+		"total":    len(subscriptions),
+	})
+}
+
+// GetSubscription returns a webhook subscription
+func (h *WebhookHandler) GetSubscription(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "WebhookHandler.GetSubscription")
+	defer span.End()
+
+	webhookID := mux.Vars(r)["webhookId"]
+	span.SetAttributes(attribute.String("webhook.id", webhookID))
+
+	subscription, err := h.webhookService.GetSubscription(ctx, webhookID)
+	if err != nil {
+		span.RecordError(err)
+		writeWebhookError(w, err, "Failed to get webhook subscription")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(subscription)
+}
+
+// UpdateSubscription replaces the settings of a webhook subscription
+func (h *WebhookHandler) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "WebhookHandler.UpdateSubscription")
+	defer span.End()
+
+	webhookID := mux.Vars(r)["webhookId"]
+	span.SetAttributes(attribute.String("webhook.id", webhookID))
+
+	var req service.WebhookSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		span.RecordError(err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	subscription, err := h.webhookService.UpdateSubscription(ctx, webhookID, req)
+	if err != nil {
+		span.RecordError(err)
+		writeWebhookError(w, err, "Failed to update webhook subscription")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(subscription)
+}
+
+// DeleteSubscription deletes a webhook subscription and its delivery log
+func (h *WebhookHandler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "WebhookHandler.DeleteSubscription")
+	defer span.End()
+
+	webhookID := mux.Vars(r)["webhookId"]
+	span.SetAttributes(attribute.String("webhook.id", webhookID))
+
+	if err := h.webhookService.DeleteSubscription(ctx, webhookID); err != nil {
+		span.RecordError(err)
+		writeWebhookError(w, err, "Failed to delete webhook subscription")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PingSubscription queues a ping delivery to a webhook subscription
+func (h *WebhookHandler) PingSubscription(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "WebhookHandler.PingSubscription")
+	defer span.End()
+
+	webhookID := mux.Vars(r)["webhookId"]
+	span.SetAttributes(attribute.String("webhook.id", webhookID))
+
+	delivery, err := h.webhookService.PingSubscription(ctx, webhookID)
+	if err != nil {
+		span.RecordError(err)
+		writeWebhookError(w, err, "Failed to ping webhook subscription")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(delivery)
+}
+
+// ListDeliveries lists the latest deliveries of a webhook subscription
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "WebhookHandler.ListDeliveries")
+	defer span.End()
+
+	webhookID := mux.Vars(r)["webhookId"]
+	span.SetAttributes(attribute.String("webhook.id", webhookID))
+
+	limit := 0
+	if param := r.URL.Query().Get("limit"); param != "" {
+		parsed, err := strconv.Atoi(param)
+		if err != nil || parsed < 0 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+	status := domain.WebhookDeliveryStatus(r.URL.Query().Get("status"))
+
+	deliveries, err := h.webhookService.ListDeliveries(ctx, webhookID, status, limit)
+	if err != nil {
+		span.RecordError(err)
+		writeWebhookError(w, err, "Failed to list webhook deliveries")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"deliveries": deliveries,
+		"total":      len(deliveries),
+	})
+}
+
+// GetDelivery returns a delivery of a webhook subscription
+func (h *WebhookHandler) GetDelivery(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "WebhookHandler.GetDelivery")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	span.SetAttributes(
+		attribute.String("webhook.id", vars["webhookId"]),
+		attribute.String("webhook.delivery_id", vars["deliveryId"]),
+	)
+
+	delivery, err := h.webhookService.GetDelivery(ctx, vars["webhookId"], vars["deliveryId"])
+	if err != nil {
+		span.RecordError(err)
+		writeWebhookError(w, err, "Failed to get webhook delivery")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(delivery)
+}
+
+// ReplayDelivery queues an earlier delivery again
+func (h *WebhookHandler) ReplayDelivery(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "WebhookHandler.ReplayDelivery")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	span.SetAttributes(
+		attribute.String("webhook.id", vars["webhookId"]),
+		attribute.String("webhook.delivery_id", vars["deliveryId"]),
+	)
+
+	delivery, err := h.webhookService.ReplayDelivery(ctx, vars["webhookId"], vars["deliveryId"])
+	if err != nil {
+		span.RecordError(err)
+		writeWebhookError(w, err, "Failed to replay webhook delivery")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(delivery)
+}
+
+// writeWebhookError maps webhook errors to HTTP status codes
+func writeWebhookError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case strings.Contains(err.Error(), "forbidden"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "invalid webhook"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, fmt.Sprintf("%s: %v", message, err), http.StatusInternalServerError)
+	}
+}
+
+// RegisterRoutes registers webhook routes
+func (h *WebhookHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/api/v1/webhooks", h.CreateSubscription).Methods("POST")
+	r.HandleFunc("/api/v1/webhooks", h.ListSubscriptions).Methods("GET")
+	r.HandleFunc("/api/v1/webhooks/{webhookId}", h.GetSubscription).Methods("GET")
+	r.HandleFunc("/api/v1/webhooks/{webhookId}", h.UpdateSubscription).Methods("PUT")
+	r.HandleFunc("/api/v1/webhooks/{webhookId}", h.DeleteSubscription).Methods("DELETE")
+	r.HandleFunc("/api/v1/webhooks/{webhookId}/ping", h.PingSubscription).Methods("POST")
+	r.HandleFunc("/api/v1/webhooks/{webhookId}/deliveries", h.ListDeliveries).Methods("GET")
+	r.HandleFunc("/api/v1/webhooks/{webhookId}/deliveries/{deliveryId}", h.GetDelivery).Methods("GET")
+	r.HandleFunc("/api/v1/webhooks/{webhookId}/deliveries/{deliveryId}/replay", h.ReplayDelivery).Methods("POST")
+}
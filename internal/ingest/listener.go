@@ -0,0 +1,278 @@
+package ingest
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/myideascope/otherside/internal/service"
+)
+
+const (
+	// maxLineBytes bounds a single feed line
+	maxLineBytes = 64 * 1024
+	// maxBatchSamples flushes a TCP batch even while more data is buffered
+	maxBatchSamples = 1000
+	// tcpIdleTimeout closes connections of sensors that went quiet
+	tcpIdleTimeout = 5 * time.Minute
+)
+
+// Ingester stores samples pushed by a registered sensor
+type Ingester interface {
+	IngestSensorReadings(ctx context.Context, deviceID string, samples []service.EnvironmentalSample) (*service.EnvironmentalIngestResult, error)
+}
+
+// Config holds the listen addresses of the sensor feed. An empty address
+// disables that transport.
+type Config struct {
+	TCPAddr string
+	UDPAddr string
+}
+
+// Listener accepts newline-delimited JSON or InfluxDB line protocol from
+// standalone sensors over TCP and UDP. Lines are parsed, grouped by device
+// and written through the ingester; rejected lines are logged and skipped.
+type Listener struct {
+	config   Config
+	ingester Ingester
+
+	tcp net.Listener
+	udp net.PacketConn
+
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+	wg    sync.WaitGroup
+	done  chan struct{}
+}
+
+// NewListener creates a new sensor feed listener
+func NewListener(config Config, ingester Ingester) *Listener {
+	return &Listener{
+		config:   config,
+		ingester: ingester,
+		conns:    make(map[net.Conn]struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start binds the configured addresses and serves them in the background
+func (l *Listener) Start() error {
+	if l.config.TCPAddr != "" {
+		tcp, err := net.Listen("tcp", l.config.TCPAddr)
+		if err != nil {
+			return err
+		}
+		l.tcp = tcp
+		log.Printf("Sensor ingest listening on tcp %s", tcp.Addr())
+
+		l.wg.Add(1)
+		go l.acceptTCP()
+	}
+
+	if l.config.UDPAddr != "" {
+		udp, err := net.ListenPacket("udp", l.config.UDPAddr)
+		if err != nil {
+			if l.tcp != nil {
+				l.tcp.Close()
+			}
+			return err
+		}
+		l.udp = udp
+		log.Printf("Sensor ingest listening on udp %s", udp.LocalAddr())
+
+		l.wg.Add(1)
+		go l.serveUDP()
+	}
+
+	return nil
+}
+
+// TCPAddr returns the bound TCP address, or nil when TCP is disabled
+func (l *Listener) TCPAddr() net.Addr {
+	if l.tcp == nil {
+		return nil
+	}
+	return l.tcp.Addr()
+}
+
+// UDPAddr returns the bound UDP address, or nil when UDP is disabled
+func (l *Listener) UDPAddr() net.Addr {
+	if l.udp == nil {
+		return nil
+	}
+	return l.udp.LocalAddr()
+}
+
+// Shutdown stops accepting data, closes open connections and waits for
+// in-flight batches to be written
+func (l *Listener) Shutdown(ctx context.Context) error {
+	close(l.done)
+
+	if l.tcp != nil {
+		l.tcp.Close()
+	}
+	if l.udp != nil {
+		l.udp.Close()
+	}
+
+	l.mu.Lock()
+	for conn := range l.conns {
+		// Unblock pending reads; the handler flushes what it has and exits
+		conn.Close()
+	}
+	l.mu.Unlock()
+
+	finished := make(chan struct{})
+	go func() {
+		l.wg.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *Listener) acceptTCP() {
+	defer l.wg.Done()
+
+	for {
+		conn, err := l.tcp.Accept()
+		if err != nil {
+			select {
+			case <-l.done:
+				return
+			default:
+			}
+			log.Printf("Sensor ingest accept failed: %v", err)
+			continue
+		}
+
+		l.mu.Lock()
+		select {
+		case <-l.done:
+			// Accepted while shutting down, after open connections were closed
+			l.mu.Unlock()
+			conn.Close()
+			return
+		default:
+		}
+		l.conns[conn] = struct{}{}
+		l.mu.Unlock()
+
+		l.wg.Add(1)
+		go l.serveTCP(conn)
+	}
+}
+
+// serveTCP reads lines until the connection closes. A batch is flushed
+// whenever the reader has no more buffered data, so bursts become one
+// write while a trickle of readings is stored line by line.
+func (l *Listener) serveTCP(conn net.Conn) {
+	defer l.wg.Done()
+	defer func() {
+		l.mu.Lock()
+		delete(l.conns, conn)
+		l.mu.Unlock()
+		conn.Close()
+	}()
+
+	reader := bufio.NewReaderSize(conn, maxLineBytes)
+	var batch []service.EnvironmentalSample
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(tcpIdleTimeout))
+		line, err := reader.ReadSlice('\n')
+		if len(line) > 0 && !errors.Is(err, bufio.ErrBufferFull) {
+			batch = l.appendLine(batch, line, conn.RemoteAddr())
+		}
+		if errors.Is(err, bufio.ErrBufferFull) {
+			log.Printf("Sensor ingest from %s: line exceeds %d bytes, closing connection", conn.RemoteAddr(), maxLineBytes)
+			l.flush(batch, conn.RemoteAddr())
+			return
+		}
+		if err != nil {
+			l.flush(batch, conn.RemoteAddr())
+			return
+		}
+
+		if reader.Buffered() == 0 || len(batch) >= maxBatchSamples {
+			l.flush(batch, conn.RemoteAddr())
+			batch = batch[:0]
+		}
+	}
+}
+
+// serveUDP treats every datagram as one batch of lines
+func (l *Listener) serveUDP() {
+	defer l.wg.Done()
+
+	buf := make([]byte, maxLineBytes)
+	for {
+		n, addr, err := l.udp.ReadFrom(buf)
+		if err != nil {
+			select {
+			case <-l.done:
+				return
+			default:
+			}
+			log.Printf("Sensor ingest read failed: %v", err)
+			continue
+		}
+
+		var batch []service.EnvironmentalSample
+		scanner := bufio.NewScanner(bytes.NewReader(buf[:n]))
+		scanner.Buffer(make([]byte, 0, n+1), n+1)
+		for scanner.Scan() {
+			batch = l.appendLine(batch, scanner.Bytes(), addr)
+		}
+		l.flush(batch, addr)
+	}
+}
+
+func (l *Listener) appendLine(batch []service.EnvironmentalSample, line []byte, from net.Addr) []service.EnvironmentalSample {
+	if len(bytes.TrimSpace(line)) == 0 {
+		return batch
+	}
+
+	sample, err := ParseLine(line, time.Now())
+	if err != nil {
+		log.Printf("Sensor ingest from %s: %v", from, err)
+		return batch
+	}
+
+	return append(batch, sample)
+}
+
+// flush writes a batch through the ingester, one call per device
+func (l *Listener) flush(batch []service.EnvironmentalSample, from net.Addr) {
+	if len(batch) == 0 {
+		return
+	}
+
+	var devices []string
+	byDevice := make(map[string][]service.EnvironmentalSample)
+	for _, sample := range batch {
+		if _, ok := byDevice[sample.DeviceID]; !ok {
+			devices = append(devices, sample.DeviceID)
+		}
+		byDevice[sample.DeviceID] = append(byDevice[sample.DeviceID], sample)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	for _, device := range devices {
+		if _, err := l.ingester.IngestSensorReadings(ctx, device, byDevice[device]); err != nil {
+			log.Printf("Sensor ingest from %s: dropped %d samples of %s: %v", from, len(byDevice[device]), device, err)
+		}
+	}
+}
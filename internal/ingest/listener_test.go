@@ -0,0 +1,102 @@
+package ingest
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/myideascope/otherside/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingIngester collects the batches written through the listener
+type recordingIngester struct {
+	mu      sync.Mutex
+	batches map[string][]service.EnvironmentalSample
+}
+
+func (r *recordingIngester) IngestSensorReadings(ctx context.Context, deviceID string, samples []service.EnvironmentalSample) (*service.EnvironmentalIngestResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches[deviceID] = append(r.batches[deviceID], samples...)
+	return &service.EnvironmentalIngestResult{Accepted: len(samples)}, nil
+}
+
+func (r *recordingIngester) count(deviceID string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.batches[deviceID])
+}
+
+func startTestListener(t *testing.T) (*Listener, *recordingIngester) {
+	ingester := &recordingIngester{batches: make(map[string][]service.EnvironmentalSample)}
+	listener := NewListener(Config{TCPAddr: "127.0.0.1:0", UDPAddr: "127.0.0.1:0"}, ingester)
+	require.NoError(t, listener.Start())
+	return listener, ingester
+}
+
+func TestListener_TCP_IngestsLinesByDevice(t *testing.T) {
+	// Arrange
+	listener, ingester := startTestListener(t)
+
+	conn, err := net.Dial("tcp", listener.TCPAddr().String())
+	require.NoError(t, err)
+
+	// Act
+	_, err = conn.Write([]byte(
+		"environment,device=logger-1 temperature=17.8\n" +
+			"not a reading\n" +
+			`{"device_id":"emf-1","values":{"emf":0.4}}` + "\n" +
+			"environment,device=logger-1 temperature=17.6\n"))
+	require.NoError(t, err)
+	conn.Close()
+
+	// Assert
+	assert.Eventually(t, func() bool {
+		return ingester.count("logger-1") == 2 && ingester.count("emf-1") == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, listener.Shutdown(context.Background()))
+}
+
+func TestListener_UDP_IngestsDatagram(t *testing.T) {
+	// Arrange
+	listener, ingester := startTestListener(t)
+
+	conn, err := net.Dial("udp", listener.UDPAddr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// Act
+	_, err = conn.Write([]byte("emf,device=k2 value=0.4\nemf,device=k2 value=0.5"))
+	require.NoError(t, err)
+
+	// Assert
+	assert.Eventually(t, func() bool {
+		return ingester.count("k2") == 2
+	}, 2*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, listener.Shutdown(context.Background()))
+}
+
+func TestListener_Shutdown_ClosesIdleConnections(t *testing.T) {
+	// Arrange
+	listener, _ := startTestListener(t)
+
+	conn, err := net.Dial("tcp", listener.TCPAddr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	// Act
+	time.Sleep(50 * time.Millisecond) // let the connection be accepted
+	err = listener.Shutdown(ctx)
+
+	// Assert
+	require.NoError(t, err)
+}
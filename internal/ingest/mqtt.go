@@ -0,0 +1,368 @@
+package ingest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/myideascope/otherside/internal/service"
+)
+
+// MQTT route kinds
+const (
+	MQTTRouteEnvironmental = "environmental"
+	MQTTRouteRadar         = "radar"
+	MQTTRouteVOX           = "vox"
+)
+
+// mqttDevicePlaceholder marks the topic level that carries the device ID
+const mqttDevicePlaceholder = "{device}"
+
+const (
+	defaultMQTTClientID             = "otherside"
+	defaultMQTTConnectTimeout       = 10 * time.Second
+	defaultMQTTMaxReconnectInterval = 2 * time.Minute
+	mqttShutdownQuiesce             = 5 * time.Second
+	mqttQoS                         = 1
+)
+
+// MQTTRoute maps the messages of one topic pattern to an event kind. The
+// pattern uses MQTT wildcards and may name the device level with {device},
+// as in "rigs/{device}/emf".
+type MQTTRoute struct {
+	Topic string `json:"topic"`
+	Kind  string `json:"kind"`
+}
+
+// MQTTConfig configures the MQTT bridge
+type MQTTConfig struct {
+	BrokerURL string
+	ClientID  string
+	Username  string
+	Password  string
+	Routes    []MQTTRoute
+	// ConnectTimeout bounds each connection attempt
+	ConnectTimeout time.Duration
+	// MaxReconnectInterval caps the exponential backoff between reconnects
+	MaxReconnectInterval time.Duration
+}
+
+// SensorSessions resolves the session a sensor is registered with
+type SensorSessions interface {
+	GetSensorSession(ctx context.Context, deviceID string) (string, error)
+}
+
+// RadarProcessor stores radar events
+type RadarProcessor interface {
+	ProcessRadarEvent(ctx context.Context, sessionID string, radarData service.RadarEventData) (*domain.RadarEvent, error)
+}
+
+// VOXTrigger generates VOX communication from trigger data
+type VOXTrigger interface {
+	GenerateVOXCommunication(ctx context.Context, sessionID string, triggerData service.VOXTriggerData) (*domain.VOXEvent, error)
+}
+
+// MQTTBridge subscribes to sensor topics at QoS 1 and writes each message
+// through the service layer. Messages are handled one at a time and
+// acknowledged after they were processed, so the broker redelivers messages
+// that were in flight when the connection dropped. Failed messages are
+// logged and acknowledged, since redelivering them would fail again.
+type MQTTBridge struct {
+	config        MQTTConfig
+	environmental Ingester
+	sensors       SensorSessions
+	radar         RadarProcessor
+	vox           VOXTrigger
+
+	mu     sync.Mutex
+	client mqtt.Client
+}
+
+// NewMQTTBridge creates a new MQTT bridge. Zero timeouts fall back to the defaults.
+func NewMQTTBridge(
+	config MQTTConfig,
+	environmental Ingester,
+	sensors SensorSessions,
+	radar RadarProcessor,
+	vox VOXTrigger,
+) (*MQTTBridge, error) {
+	if config.BrokerURL == "" {
+		return nil, fmt.Errorf("invalid MQTT config: broker URL is required")
+	}
+	if len(config.Routes) == 0 {
+		return nil, fmt.Errorf("invalid MQTT config: at least one route is required")
+	}
+	for _, route := range config.Routes {
+		if err := validateMQTTRoute(route); err != nil {
+			return nil, err
+		}
+	}
+
+	if config.ClientID == "" {
+		config.ClientID = defaultMQTTClientID
+	}
+	if config.ConnectTimeout <= 0 {
+		config.ConnectTimeout = defaultMQTTConnectTimeout
+	}
+	if config.MaxReconnectInterval <= 0 {
+		config.MaxReconnectInterval = defaultMQTTMaxReconnectInterval
+	}
+
+	return &MQTTBridge{
+		config:        config,
+		environmental: environmental,
+		sensors:       sensors,
+		radar:         radar,
+		vox:           vox,
+	}, nil
+}
+
+// ParseMQTTRoutes reads routes written as "pattern=kind" pairs separated by
+// semicolons, e.g. "rigs/{device}/env=environmental;rigs/{device}/radar=radar"
+func ParseMQTTRoutes(spec string) ([]MQTTRoute, error) {
+	var routes []MQTTRoute
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		separator := strings.LastIndex(entry, "=")
+		if separator < 0 {
+			return nil, fmt.Errorf("invalid MQTT route %q: expected pattern=kind", entry)
+		}
+
+		route := MQTTRoute{
+			Topic: strings.TrimSpace(entry[:separator]),
+			Kind:  strings.TrimSpace(entry[separator+1:]),
+		}
+		if err := validateMQTTRoute(route); err != nil {
+			return nil, err
+		}
+		routes = append(routes, route)
+	}
+
+	return routes, nil
+}
+
+// Start connects to the broker in the background. The bridge keeps retrying
+// with backoff until Shutdown, and subscribes again after every reconnect.
+func (b *MQTTBridge) Start() error {
+	options := mqtt.NewClientOptions().
+		AddBroker(b.config.BrokerURL).
+		SetClientID(b.config.ClientID).
+		SetUsername(b.config.Username).
+		SetPassword(b.config.Password).
+		// A persistent session keeps QoS 1 messages queued while we are away
+		SetCleanSession(false).
+		SetOrderMatters(true).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectTimeout(b.config.ConnectTimeout).
+		SetConnectRetryInterval(time.Second).
+		SetMaxReconnectInterval(b.config.MaxReconnectInterval).
+		SetOnConnectHandler(b.subscribe).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			log.Printf("MQTT bridge lost connection to %s: %v", b.config.BrokerURL, err)
+		})
+
+	client := mqtt.NewClient(options)
+
+	b.mu.Lock()
+	b.client = client
+	b.mu.Unlock()
+
+	token := client.Connect()
+	if token.WaitTimeout(b.config.ConnectTimeout) && token.Error() != nil {
+		return fmt.Errorf("failed to connect to MQTT broker: %w", token.Error())
+	}
+	if !client.IsConnected() {
+		log.Printf("MQTT broker %s not reachable yet, retrying in the background", b.config.BrokerURL)
+	}
+
+	return nil
+}
+
+// Shutdown disconnects from the broker, letting the message in progress finish
+func (b *MQTTBridge) Shutdown(ctx context.Context) error {
+	b.mu.Lock()
+	client := b.client
+	b.mu.Unlock()
+
+	if client == nil {
+		return nil
+	}
+
+	// Disconnect waits up to the quiesce period for the handler to finish
+	quiesce := mqttShutdownQuiesce
+	if deadline, ok := ctx.Deadline(); ok {
+		quiesce = min(quiesce, time.Until(deadline))
+	}
+	client.Disconnect(uint(max(0, quiesce.Milliseconds())))
+
+	return nil
+}
+
+// subscribe (re)subscribes every route after a connection is established
+func (b *MQTTBridge) subscribe(client mqtt.Client) {
+	log.Printf("MQTT bridge connected to %s", b.config.BrokerURL)
+
+	for _, route := range b.config.Routes {
+		route := route
+		filter := mqttSubscriptionFilter(route.Topic)
+
+		token := client.Subscribe(filter, mqttQoS, func(_ mqtt.Client, message mqtt.Message) {
+			b.handleMessage(route, message.Topic(), message.Payload())
+		})
+		go func() {
+			if token.WaitTimeout(b.config.ConnectTimeout) && token.Error() != nil {
+				log.Printf("MQTT bridge failed to subscribe to %s: %v", filter, token.Error())
+			}
+		}()
+	}
+}
+
+// handleMessage maps one payload to events of the route's kind
+func (b *MQTTBridge) handleMessage(route MQTTRoute, topic string, payload []byte) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	topicDevice := mqttTopicDevice(route.Topic, topic)
+
+	var err error
+	switch route.Kind {
+	case MQTTRouteEnvironmental:
+		err = b.handleEnvironmental(ctx, topicDevice, payload)
+	case MQTTRouteRadar:
+		err = b.handleRadar(ctx, topicDevice, payload)
+	case MQTTRouteVOX:
+		err = b.handleVOX(ctx, topicDevice, payload)
+	}
+
+	if err != nil {
+		log.Printf("MQTT bridge dropped message on %s: %v", topic, err)
+	}
+}
+
+// handleEnvironmental accepts one or more JSON or line protocol readings
+func (b *MQTTBridge) handleEnvironmental(ctx context.Context, topicDevice string, payload []byte) error {
+	receivedAt := time.Now()
+
+	var devices []string
+	byDevice := make(map[string][]service.EnvironmentalSample)
+	for _, line := range bytes.Split(payload, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		sample, err := parseLine(line, receivedAt, topicDevice)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := byDevice[sample.DeviceID]; !ok {
+			devices = append(devices, sample.DeviceID)
+		}
+		byDevice[sample.DeviceID] = append(byDevice[sample.DeviceID], sample)
+	}
+
+	for _, device := range devices {
+		if _, err := b.environmental.IngestSensorReadings(ctx, device, byDevice[device]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *MQTTBridge) handleRadar(ctx context.Context, topicDevice string, payload []byte) error {
+	var radarData service.RadarEventData
+	if err := json.Unmarshal(payload, &radarData); err != nil {
+		return fmt.Errorf("invalid radar payload: %w", err)
+	}
+
+	if radarData.DeviceID == "" {
+		radarData.DeviceID = topicDevice
+	}
+	sessionID, err := b.sessionFor(ctx, radarData.DeviceID)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.radar.ProcessRadarEvent(ctx, sessionID, radarData)
+	return err
+}
+
+func (b *MQTTBridge) handleVOX(ctx context.Context, topicDevice string, payload []byte) error {
+	var triggerData service.VOXTriggerData
+	if err := json.Unmarshal(payload, &triggerData); err != nil {
+		return fmt.Errorf("invalid VOX trigger payload: %w", err)
+	}
+
+	if triggerData.DeviceID == "" {
+		triggerData.DeviceID = topicDevice
+	}
+	sessionID, err := b.sessionFor(ctx, triggerData.DeviceID)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.vox.GenerateVOXCommunication(ctx, sessionID, triggerData)
+	return err
+}
+
+// sessionFor resolves the session a device is registered with
+func (b *MQTTBridge) sessionFor(ctx context.Context, device string) (string, error) {
+	if device == "" {
+		return "", fmt.Errorf("message names no device")
+	}
+
+	return b.sensors.GetSensorSession(ctx, device)
+}
+
+func validateMQTTRoute(route MQTTRoute) error {
+	switch route.Kind {
+	case MQTTRouteEnvironmental, MQTTRouteRadar, MQTTRouteVOX:
+	default:
+		return fmt.Errorf("invalid MQTT route %s: unknown kind %q", route.Topic, route.Kind)
+	}
+
+	levels := strings.Split(route.Topic, "/")
+	for i, level := range levels {
+		if route.Topic == "" || (level == "#" && i != len(levels)-1) {
+			return fmt.Errorf("invalid MQTT route %q: malformed topic pattern", route.Topic)
+		}
+	}
+
+	return nil
+}
+
+// mqttSubscriptionFilter turns a route pattern into an MQTT topic filter
+func mqttSubscriptionFilter(pattern string) string {
+	return strings.ReplaceAll(pattern, mqttDevicePlaceholder, "+")
+}
+
+// mqttTopicDevice returns the topic level at the pattern's {device}
+// placeholder, or "" when the pattern has none
+func mqttTopicDevice(pattern, topic string) string {
+	patternLevels := strings.Split(pattern, "/")
+	topicLevels := strings.Split(topic, "/")
+
+	for i, level := range patternLevels {
+		if level == "#" || i >= len(topicLevels) {
+			return ""
+		}
+		if level == mqttDevicePlaceholder {
+			return topicLevels[i]
+		}
+	}
+
+	return ""
+}
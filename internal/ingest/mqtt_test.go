@@ -0,0 +1,267 @@
+package ingest
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.mqtt.golang/packets"
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/myideascope/otherside/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testBroker is a minimal MQTT 3.1.1 broker that accepts one client at a
+// time, records subscriptions and publishes QoS 1 messages on demand
+type testBroker struct {
+	t        *testing.T
+	listener net.Listener
+
+	mu            sync.Mutex
+	conn          net.Conn
+	connects      int
+	subscriptions map[string]byte
+	acks          chan uint16
+}
+
+func newTestBroker(t *testing.T) *testBroker {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	broker := &testBroker{
+		t:             t,
+		listener:      listener,
+		subscriptions: make(map[string]byte),
+		acks:          make(chan uint16, 16),
+	}
+	go broker.serve()
+	t.Cleanup(func() {
+		listener.Close()
+		broker.dropClient()
+	})
+
+	return broker
+}
+
+func (b *testBroker) url() string {
+	return "tcp://" + b.listener.Addr().String()
+}
+
+func (b *testBroker) serve() {
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			return
+		}
+		b.mu.Lock()
+		b.conn = conn
+		b.mu.Unlock()
+		go b.handle(conn)
+	}
+}
+
+func (b *testBroker) handle(conn net.Conn) {
+	for {
+		packet, err := packets.ReadPacket(conn)
+		if err != nil {
+			return
+		}
+
+		switch p := packet.(type) {
+		case *packets.ConnectPacket:
+			b.mu.Lock()
+			b.connects++
+			b.mu.Unlock()
+			b.write(conn, packets.NewControlPacket(packets.Connack))
+		case *packets.SubscribePacket:
+			ack := packets.NewControlPacket(packets.Suback).(*packets.SubackPacket)
+			ack.MessageID = p.MessageID
+			b.mu.Lock()
+			for i, topic := range p.Topics {
+				b.subscriptions[topic] = p.Qoss[i]
+				ack.ReturnCodes = append(ack.ReturnCodes, p.Qoss[i])
+			}
+			b.mu.Unlock()
+			b.write(conn, ack)
+		case *packets.PingreqPacket:
+			b.write(conn, packets.NewControlPacket(packets.Pingresp))
+		case *packets.PubackPacket:
+			b.acks <- p.MessageID
+		case *packets.DisconnectPacket:
+			conn.Close()
+			return
+		}
+	}
+}
+
+func (b *testBroker) write(conn net.Conn, packet packets.ControlPacket) {
+	if err := packet.Write(conn); err != nil {
+		b.t.Logf("test broker write failed: %v", err)
+	}
+}
+
+// publish sends a QoS 1 message and waits for the client's PUBACK
+func (b *testBroker) publish(topic string, payload string, messageID uint16) {
+	publish := packets.NewControlPacket(packets.Publish).(*packets.PublishPacket)
+	publish.TopicName = topic
+	publish.Qos = 1
+	publish.MessageID = messageID
+	publish.Payload = []byte(payload)
+
+	b.mu.Lock()
+	conn := b.conn
+	b.mu.Unlock()
+	require.NotNil(b.t, conn)
+	b.write(conn, publish)
+
+	select {
+	case id := <-b.acks:
+		assert.Equal(b.t, messageID, id)
+	case <-time.After(5 * time.Second):
+		b.t.Fatalf("no PUBACK for message %d", messageID)
+	}
+}
+
+func (b *testBroker) dropClient() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.conn != nil {
+		b.conn.Close()
+		b.conn = nil
+	}
+}
+
+func (b *testBroker) state() (int, map[string]byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	subscriptions := make(map[string]byte, len(b.subscriptions))
+	for topic, qos := range b.subscriptions {
+		subscriptions[topic] = qos
+	}
+	return b.connects, subscriptions
+}
+
+// recordingServices stands in for the service layer behind the bridge
+type recordingServices struct {
+	recordingIngester
+
+	mu       sync.Mutex
+	radar    map[string][]service.RadarEventData
+	triggers map[string][]service.VOXTriggerData
+}
+
+func newRecordingServices() *recordingServices {
+	return &recordingServices{
+		recordingIngester: recordingIngester{batches: make(map[string][]service.EnvironmentalSample)},
+		radar:             make(map[string][]service.RadarEventData),
+		triggers:          make(map[string][]service.VOXTriggerData),
+	}
+}
+
+func (r *recordingServices) GetSensorSession(ctx context.Context, deviceID string) (string, error) {
+	return "session-of-" + deviceID, nil
+}
+
+func (r *recordingServices) ProcessRadarEvent(ctx context.Context, sessionID string, radarData service.RadarEventData) (*domain.RadarEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.radar[sessionID] = append(r.radar[sessionID], radarData)
+	return &domain.RadarEvent{}, nil
+}
+
+func (r *recordingServices) GenerateVOXCommunication(ctx context.Context, sessionID string, triggerData service.VOXTriggerData) (*domain.VOXEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.triggers[sessionID] = append(r.triggers[sessionID], triggerData)
+	return &domain.VOXEvent{}, nil
+}
+
+func startTestBridge(t *testing.T, broker *testBroker, services *recordingServices) *MQTTBridge {
+	routes, err := ParseMQTTRoutes("rigs/{device}/env=environmental; rigs/{device}/radar=radar; rigs/{device}/vox=vox")
+	require.NoError(t, err)
+
+	bridge, err := NewMQTTBridge(MQTTConfig{
+		BrokerURL:            broker.url(),
+		ClientID:             "otherside-test",
+		Routes:               routes,
+		ConnectTimeout:       2 * time.Second,
+		MaxReconnectInterval: time.Second,
+	}, services, services, services, services)
+	require.NoError(t, err)
+	require.NoError(t, bridge.Start())
+	t.Cleanup(func() { bridge.Shutdown(context.Background()) })
+
+	require.Eventually(t, func() bool {
+		_, subscriptions := broker.state()
+		return len(subscriptions) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	return bridge
+}
+
+func TestMQTTBridge_Messages_RoutedToServicesAndAcknowledged(t *testing.T) {
+	// Arrange
+	broker := newTestBroker(t)
+	services := newRecordingServices()
+	startTestBridge(t, broker, services)
+
+	_, subscriptions := broker.state()
+	assert.Equal(t, map[string]byte{"rigs/+/env": 1, "rigs/+/radar": 1, "rigs/+/vox": 1}, subscriptions)
+
+	// Act
+	broker.publish("rigs/logger-1/env", "environment temperature=17.8\n{\"values\":{\"emf\":0.4}}", 1)
+	broker.publish("rigs/radar-1/radar", `{"position":{"x":1,"y":2},"strength":0.7,"emf_reading":1.5,"duration":2}`, 2)
+	broker.publish("rigs/k2/vox", `{"device_id":"k2-override","emf_anomaly":0.8}`, 3)
+
+	// Assert
+	assert.Equal(t, 2, services.count("logger-1"))
+	services.mu.Lock()
+	defer services.mu.Unlock()
+	require.Len(t, services.radar["session-of-radar-1"], 1)
+	assert.Equal(t, 0.7, services.radar["session-of-radar-1"][0].Strength)
+	assert.Equal(t, "radar-1", services.radar["session-of-radar-1"][0].DeviceID)
+	require.Len(t, services.triggers["session-of-k2-override"], 1)
+	assert.Equal(t, 0.8, services.triggers["session-of-k2-override"][0].EMFAnomaly)
+	assert.Equal(t, "k2-override", services.triggers["session-of-k2-override"][0].DeviceID)
+}
+
+func TestMQTTBridge_ConnectionLost_ReconnectsAndResubscribes(t *testing.T) {
+	// Arrange
+	broker := newTestBroker(t)
+	services := newRecordingServices()
+	startTestBridge(t, broker, services)
+
+	// Act
+	broker.mu.Lock()
+	broker.subscriptions = make(map[string]byte)
+	broker.mu.Unlock()
+	broker.dropClient()
+
+	// Assert
+	require.Eventually(t, func() bool {
+		connects, subscriptions := broker.state()
+		return connects == 2 && len(subscriptions) == 3
+	}, 10*time.Second, 20*time.Millisecond)
+
+	broker.publish("rigs/logger-1/env", `{"values":{"humidity":55}}`, 4)
+	assert.Equal(t, 1, services.count("logger-1"))
+}
+
+func TestParseMQTTRoutes_InvalidKind_ReturnsError(t *testing.T) {
+	// Act
+	routes, err := ParseMQTTRoutes("rigs/{device}/env=environmental;rigs/#/x=radar")
+
+	// Assert
+	require.Error(t, err)
+	assert.Nil(t, routes)
+	assert.Contains(t, err.Error(), "malformed topic pattern")
+}
+
+func TestMQTTTopicDevice_ExtractsPlaceholderLevel(t *testing.T) {
+	assert.Equal(t, "logger-1", mqttTopicDevice("site/+/{device}/env", "site/cellar/logger-1/env"))
+	assert.Equal(t, "", mqttTopicDevice("site/#", "site/cellar/logger-1/env"))
+	assert.Equal(t, "", mqttTopicDevice("site/+/env", "site/cellar/env"))
+}
@@ -0,0 +1,202 @@
+package ingest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/myideascope/otherside/internal/service"
+)
+
+// deviceTags are the line protocol tags that identify the sending device
+var deviceTags = []string{"device", "device_id"}
+
+// ParseLine decodes one line of a sensor feed. Lines starting with '{' are
+// read as JSON, anything else as InfluxDB line protocol. Samples without a
+// timestamp are stamped with receivedAt.
+func ParseLine(line []byte, receivedAt time.Time) (service.EnvironmentalSample, error) {
+	return parseLine(line, receivedAt, "")
+}
+
+// parseLine is ParseLine with a device for lines that do not name one
+func parseLine(line []byte, receivedAt time.Time, defaultDevice string) (service.EnvironmentalSample, error) {
+	line = bytes.TrimSpace(line)
+	if len(line) > 0 && line[0] == '{' {
+		return parseJSON(line, receivedAt, defaultDevice)
+	}
+	return parseLineProtocol(string(line), receivedAt, defaultDevice)
+}
+
+// ParseJSON decodes an NDJSON line with the same shape as a REST sample:
+// {"device_id": "emf-1", "timestamp": "2024-10-31T22:00:00Z", "values": {"emf": 0.4}}
+func ParseJSON(line []byte, receivedAt time.Time) (service.EnvironmentalSample, error) {
+	return parseJSON(line, receivedAt, "")
+}
+
+func parseJSON(line []byte, receivedAt time.Time, defaultDevice string) (service.EnvironmentalSample, error) {
+	var sample service.EnvironmentalSample
+	if err := json.Unmarshal(line, &sample); err != nil {
+		return sample, fmt.Errorf("invalid JSON sample: %w", err)
+	}
+
+	if sample.DeviceID == "" {
+		sample.DeviceID = defaultDevice
+	}
+	if sample.DeviceID == "" {
+		return sample, fmt.Errorf("invalid JSON sample: device_id is required")
+	}
+	if sample.Timestamp.IsZero() {
+		sample.Timestamp = receivedAt
+	}
+
+	return sample, nil
+}
+
+// ParseLineProtocol decodes an InfluxDB line protocol point such as
+// "environment,device=logger-2 temperature=17.8,humidity=61i 1730412000000000000".
+// Fields named after a metric map to that metric; a lone "value" field maps
+// to the measurement name, so "emf,device=k2 value=0.4" works too. Other
+// fields are ignored. Timestamps are in nanoseconds.
+func ParseLineProtocol(line string, receivedAt time.Time) (service.EnvironmentalSample, error) {
+	return parseLineProtocol(line, receivedAt, "")
+}
+
+func parseLineProtocol(line string, receivedAt time.Time, defaultDevice string) (service.EnvironmentalSample, error) {
+	sample := service.EnvironmentalSample{
+		Values: make(map[domain.EnvironmentalMetric]float64),
+	}
+
+	sections := splitUnescaped(line, ' ', true)
+	if len(sections) < 2 || len(sections) > 3 {
+		return sample, fmt.Errorf("invalid line protocol: expected measurement, fields and optional timestamp")
+	}
+
+	seriesKey := splitUnescaped(sections[0], ',', false)
+	measurement := unescape(seriesKey[0])
+	if measurement == "" {
+		return sample, fmt.Errorf("invalid line protocol: missing measurement")
+	}
+
+	tags := make(map[string]string)
+	for _, tag := range seriesKey[1:] {
+		key, value, ok := cutUnescaped(tag, '=')
+		if !ok {
+			return sample, fmt.Errorf("invalid line protocol: malformed tag %q", tag)
+		}
+		tags[unescape(key)] = unescape(value)
+	}
+	sample.DeviceID = defaultDevice
+	for _, tag := range deviceTags {
+		if device, ok := tags[tag]; ok {
+			sample.DeviceID = device
+			break
+		}
+	}
+	if sample.DeviceID == "" {
+		return sample, fmt.Errorf("invalid line protocol: a device tag is required")
+	}
+
+	for _, field := range splitUnescaped(sections[1], ',', true) {
+		key, raw, ok := cutUnescaped(field, '=')
+		if !ok {
+			return sample, fmt.Errorf("invalid line protocol: malformed field %q", field)
+		}
+
+		value, numeric := parseFieldValue(raw)
+		if !numeric {
+			continue
+		}
+
+		metric := domain.EnvironmentalMetric(unescape(key))
+		if metric == "value" {
+			metric = domain.EnvironmentalMetric(measurement)
+		}
+		if service.IsEnvironmentalMetric(metric) {
+			sample.Values[metric] = value
+		}
+	}
+	if len(sample.Values) == 0 {
+		return sample, fmt.Errorf("invalid line protocol: no environmental fields in %q", measurement)
+	}
+
+	sample.Timestamp = receivedAt
+	if len(sections) == 3 {
+		nanos, err := strconv.ParseInt(sections[2], 10, 64)
+		if err != nil {
+			return sample, fmt.Errorf("invalid line protocol: bad timestamp %q", sections[2])
+		}
+		sample.Timestamp = time.Unix(0, nanos)
+	}
+
+	return sample, nil
+}
+
+// parseFieldValue reads float, integer and unsigned fields. Strings and
+// booleans are not numeric.
+func parseFieldValue(raw string) (float64, bool) {
+	if raw == "" || raw[0] == '"' {
+		return 0, false
+	}
+	raw = strings.TrimSuffix(strings.TrimSuffix(raw, "i"), "u")
+	value, err := strconv.ParseFloat(raw, 64)
+	return value, err == nil
+}
+
+// splitUnescaped splits on sep outside backslash escapes and, when
+// quoted is set, outside double-quoted strings
+func splitUnescaped(s string, sep byte, quoted bool) []string {
+	var parts []string
+	inQuotes := false
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\':
+			i++
+		case s[i] == '"' && quoted:
+			inQuotes = !inQuotes
+		case s[i] == sep && !inQuotes:
+			if sep == ' ' && i == start {
+				// Collapse runs of spaces between sections
+				start = i + 1
+				continue
+			}
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	if start < len(s) {
+		parts = append(parts, s[start:])
+	}
+	return parts
+}
+
+// cutUnescaped cuts s around the first unescaped sep
+func cutUnescaped(s string, sep byte) (string, string, bool) {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case sep:
+			return s[:i], s[i+1:], true
+		}
+	}
+	return s, "", false
+}
+
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,75 @@
+package ingest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLine_LineProtocol_MapsFieldsAndTimestamp(t *testing.T) {
+	// Arrange
+	line := []byte(`environment,site=cellar,device=logger\ 2 temperature=17.8,humidity=61i,note="cold, damp",battery=98 1730412000000000000` + "\n")
+
+	// Act
+	sample, err := ParseLine(line, time.Now())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "logger 2", sample.DeviceID)
+	assert.Equal(t, map[domain.EnvironmentalMetric]float64{"temperature": 17.8, "humidity": 61}, sample.Values)
+	assert.True(t, sample.Timestamp.Equal(time.Unix(1730412000, 0)))
+}
+
+func TestParseLine_LineProtocolValueField_UsesMeasurementAsMetric(t *testing.T) {
+	// Arrange
+	receivedAt := time.Now()
+
+	// Act
+	sample, err := ParseLine([]byte("emf,device_id=k2 value=0.4"), receivedAt)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "k2", sample.DeviceID)
+	assert.Equal(t, 0.4, sample.Values[domain.EnvironmentalMetricEMF])
+	assert.Equal(t, receivedAt, sample.Timestamp)
+}
+
+func TestParseLine_JSON_DecodesSample(t *testing.T) {
+	// Arrange
+	line := []byte(`{"device_id":"emf-1","timestamp":"2024-10-31T22:00:00Z","values":{"emf":1.2}}`)
+
+	// Act
+	sample, err := ParseLine(line, time.Now())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "emf-1", sample.DeviceID)
+	assert.Equal(t, 1.2, sample.Values[domain.EnvironmentalMetricEMF])
+	assert.True(t, sample.Timestamp.Equal(time.Date(2024, 10, 31, 22, 0, 0, 0, time.UTC)))
+}
+
+func TestParseLine_InvalidLines_ReturnError(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{"missing device tag", "environment temperature=17.8"},
+		{"no environmental fields", "environment,device=x battery=98"},
+		{"bad timestamp", "environment,device=x temperature=17.8 yesterday"},
+		{"JSON without device", `{"values":{"emf":1}}`},
+		{"malformed JSON", `{"device_id":`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			_, err := ParseLine([]byte(tt.line), time.Now())
+
+			// Assert
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+)
+
+// SQLiteTeamRepository implements TeamRepository using SQLite
+type SQLiteTeamRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteTeamRepository creates a new SQLite team repository
+func NewSQLiteTeamRepository(db *sql.DB) *SQLiteTeamRepository {
+	return &SQLiteTeamRepository{db: db}
+}
+
+// Create creates a new team
+func (r *SQLiteTeamRepository) Create(ctx context.Context, team *domain.Team) error {
+	query := `INSERT INTO teams (id, name, created_at) VALUES (?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, team.ID, team.Name, team.CreatedAt)
+	return err
+}
+
+// GetByID retrieves a team by ID
+func (r *SQLiteTeamRepository) GetByID(ctx context.Context, id string) (*domain.Team, error) {
+	query := `SELECT id, name, created_at FROM teams WHERE id = ?`
+
+	var team domain.Team
+	if err := r.db.QueryRowContext(ctx, query, id).Scan(&team.ID, &team.Name, &team.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return &team, nil
+}
+
+// GetAll retrieves all teams ordered by name
+func (r *SQLiteTeamRepository) GetAll(ctx context.Context) ([]*domain.Team, error) {
+	query := `SELECT id, name, created_at FROM teams ORDER BY name ASC`
+	return r.queryTeams(ctx, query)
+}
+
+// GetByInvestigator retrieves the teams an investigator belongs to, ordered by name
+func (r *SQLiteTeamRepository) GetByInvestigator(ctx context.Context, investigatorID string) ([]*domain.Team, error) {
+	query := `
+		SELECT t.id, t.name, t.created_at
+		FROM teams t
+		JOIN team_members m ON m.team_id = t.id
+		WHERE m.investigator_id = ?
+		ORDER BY t.name ASC`
+	return r.queryTeams(ctx, query, investigatorID)
+}
+
+// Delete deletes a team and its memberships. Sessions it owned keep their
+// owner and guests.
+func (r *SQLiteTeamRepository) Delete(ctx context.Context, id string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM team_members WHERE team_id = ?`, id); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE session_acl SET team_id = NULL WHERE team_id = ?`, id); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM teams WHERE id = ?`, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// AddMember adds an investigator to a team. Adding an existing member keeps
+// their join time.
+func (r *SQLiteTeamRepository) AddMember(ctx context.Context, member *domain.TeamMember) error {
+	query := `
+		INSERT INTO team_members (team_id, investigator_id, joined_at) VALUES (?, ?, ?)
+		ON CONFLICT(team_id, investigator_id) DO NOTHING`
+	_, err := r.db.ExecContext(ctx, query, member.TeamID, member.InvestigatorID, member.JoinedAt)
+	return err
+}
+
+// RemoveMember removes an investigator from a team
+func (r *SQLiteTeamRepository) RemoveMember(ctx context.Context, teamID, investigatorID string) error {
+	query := `DELETE FROM team_members WHERE team_id = ? AND investigator_id = ?`
+	_, err := r.db.ExecContext(ctx, query, teamID, investigatorID)
+	return err
+}
+
+// GetMembers retrieves the members of a team ordered by join time
+func (r *SQLiteTeamRepository) GetMembers(ctx context.Context, teamID string) ([]*domain.TeamMember, error) {
+	query := `
+		SELECT team_id, investigator_id, joined_at
+		FROM team_members WHERE team_id = ? ORDER BY joined_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []*domain.TeamMember
+	for rows.Next() {
+		var member domain.TeamMember
+		if err := rows.Scan(&member.TeamID, &member.InvestigatorID, &member.JoinedAt); err != nil {
+			return nil, err
+		}
+		members = append(members, &member)
+	}
+
+	return members, rows.Err()
+}
+
+// IsMember reports whether an investigator belongs to a team
+func (r *SQLiteTeamRepository) IsMember(ctx context.Context, teamID, investigatorID string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM team_members WHERE team_id = ? AND investigator_id = ?)`
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, query, teamID, investigatorID).Scan(&exists)
+	return exists, err
+}
+
+func (r *SQLiteTeamRepository) queryTeams(ctx context.Context, query string, args ...interface{}) ([]*domain.Team, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var teams []*domain.Team
+	for rows.Next() {
+		var team domain.Team
+		if err := rows.Scan(&team.ID, &team.Name, &team.CreatedAt); err != nil {
+			return nil, err
+		}
+		teams = append(teams, &team)
+	}
+
+	return teams, rows.Err()
+}
+
+// SQLiteSessionACLRepository implements SessionACLRepository using SQLite
+type SQLiteSessionACLRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteSessionACLRepository creates a new SQLite session ACL repository
+func NewSQLiteSessionACLRepository(db *sql.DB) *SQLiteSessionACLRepository {
+	return &SQLiteSessionACLRepository{db: db}
+}
+
+// Get retrieves the access list of a session
+func (r *SQLiteSessionACLRepository) Get(ctx context.Context, sessionID string) (*domain.SessionACL, error) {
+	acl := &domain.SessionACL{SessionID: sessionID, GuestIDs: []string{}}
+
+	query := `
+		SELECT COALESCE(owner_id, ''), COALESCE(team_id, ''), updated_at
+		FROM session_acl WHERE session_id = ?`
+	err := r.db.QueryRowContext(ctx, query, sessionID).Scan(&acl.OwnerID, &acl.TeamID, &acl.UpdatedAt)
+	if err == sql.ErrNoRows {
+		acl.Open = true
+	} else if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT investigator_id FROM session_guests WHERE session_id = ? ORDER BY granted_at ASC`, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var guestID string
+		if err := rows.Scan(&guestID); err != nil {
+			return nil, err
+		}
+		acl.GuestIDs = append(acl.GuestIDs, guestID)
+	}
+
+	return acl, rows.Err()
+}
+
+// GetOwnedSessionIDs retrieves the IDs of the sessions an investigator owns
+func (r *SQLiteSessionACLRepository) GetOwnedSessionIDs(ctx context.Context, investigatorID string) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT session_id FROM session_acl WHERE owner_id = ? ORDER BY session_id ASC`, investigatorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessionIDs []string
+	for rows.Next() {
+		var sessionID string
+		if err := rows.Scan(&sessionID); err != nil {
+			return nil, err
+		}
+		sessionIDs = append(sessionIDs, sessionID)
+	}
+
+	return sessionIDs, rows.Err()
+}
+
+// SetOwnership sets the owner and team of a session. Empty IDs clear them.
+func (r *SQLiteSessionACLRepository) SetOwnership(ctx context.Context, sessionID, ownerID, teamID string, at time.Time) error {
+	query := `
+		INSERT INTO session_acl (session_id, owner_id, team_id, updated_at)
+		VALUES (?, NULLIF(?, ''), NULLIF(?, ''), ?)
+		ON CONFLICT(session_id) DO UPDATE SET
+			owner_id = excluded.owner_id,
+			team_id = excluded.team_id,
+			updated_at = excluded.updated_at`
+
+	_, err := r.db.ExecContext(ctx, query, sessionID, ownerID, teamID, at)
+	return err
+}
+
+// AddGuest gives an investigator read-only access to a session
+func (r *SQLiteSessionACLRepository) AddGuest(ctx context.Context, sessionID, investigatorID string, at time.Time) error {
+	query := `
+		INSERT INTO session_guests (session_id, investigator_id, granted_at) VALUES (?, ?, ?)
+		ON CONFLICT(session_id, investigator_id) DO NOTHING`
+	_, err := r.db.ExecContext(ctx, query, sessionID, investigatorID, at)
+	return err
+}
+
+// RemoveGuest withdraws an investigator's read-only access to a session
+func (r *SQLiteSessionACLRepository) RemoveGuest(ctx context.Context, sessionID, investigatorID string) error {
+	query := `DELETE FROM session_guests WHERE session_id = ? AND investigator_id = ?`
+	_, err := r.db.ExecContext(ctx, query, sessionID, investigatorID)
+	return err
+}
+
+// SQLiteExportRecordRepository implements ExportRecordRepository using SQLite
+type SQLiteExportRecordRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteExportRecordRepository creates a new SQLite export record repository
+func NewSQLiteExportRecordRepository(db *sql.DB) *SQLiteExportRecordRepository {
+	return &SQLiteExportRecordRepository{db: db}
+}
+
+// Create stores an export record
+func (r *SQLiteExportRecordRepository) Create(ctx context.Context, record *domain.ExportRecord) error {
+	sessionIDsJSON, err := json.Marshal(record.SessionIDs)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO export_records (filename, created_by, session_ids, created_at)
+		VALUES (?, NULLIF(?, ''), ?, ?)`
+	_, err = r.db.ExecContext(ctx, query, record.Filename, record.CreatedBy, string(sessionIDsJSON), record.CreatedAt)
+	return err
+}
+
+// GetByFilename retrieves the record of an export file
+func (r *SQLiteExportRecordRepository) GetByFilename(ctx context.Context, filename string) (*domain.ExportRecord, error) {
+	query := `
+		SELECT filename, COALESCE(created_by, ''), session_ids, created_at
+		FROM export_records WHERE filename = ?`
+
+	var record domain.ExportRecord
+	var sessionIDsJSON string
+	err := r.db.QueryRowContext(ctx, query, filename).Scan(
+		&record.Filename, &record.CreatedBy, &sessionIDsJSON, &record.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(sessionIDsJSON), &record.SessionIDs); err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Delete removes the record of an export file
+func (r *SQLiteExportRecordRepository) Delete(ctx context.Context, filename string) error {
+	query := `DELETE FROM export_records WHERE filename = ?`
+	_, err := r.db.ExecContext(ctx, query, filename)
+	return err
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteTeamRepository_Delete_ClearsTeamFromSessions(t *testing.T) {
+	// Arrange
+	db := setupMigratedTestDB(t)
+	defer cleanupTestDB(db)
+	investigatorRepo := NewSQLiteInvestigatorRepository(db)
+	teamRepo := NewSQLiteTeamRepository(db)
+	aclRepo := NewSQLiteSessionACLRepository(db)
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Second)
+	require.NoError(t, investigatorRepo.Create(ctx, &domain.Investigator{ID: "inv-1", Name: "Ada", CreatedAt: now}))
+	require.NoError(t, teamRepo.Create(ctx, &domain.Team{ID: "team-1", Name: "Night shift", CreatedAt: now}))
+	require.NoError(t, teamRepo.AddMember(ctx, &domain.TeamMember{TeamID: "team-1", InvestigatorID: "inv-1", JoinedAt: now}))
+	require.NoError(t, aclRepo.SetOwnership(ctx, "session-1", "inv-1", "team-1", now))
+
+	// Act
+	err := teamRepo.Delete(ctx, "team-1")
+
+	// Assert
+	require.NoError(t, err)
+	acl, err := aclRepo.Get(ctx, "session-1")
+	require.NoError(t, err)
+	assert.Equal(t, "inv-1", acl.OwnerID)
+	assert.Empty(t, acl.TeamID)
+	assert.False(t, acl.Open)
+	teams, err := teamRepo.GetByInvestigator(ctx, "inv-1")
+	require.NoError(t, err)
+	assert.Empty(t, teams)
+}
+
+func TestSQLiteSessionACLRepository_Get_NoRow_ReturnsOpenACL(t *testing.T) {
+	// Arrange
+	db := setupMigratedTestDB(t)
+	defer cleanupTestDB(db)
+	repo := NewSQLiteSessionACLRepository(db)
+
+	// Act
+	acl, err := repo.Get(context.Background(), "session-1")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "session-1", acl.SessionID)
+	assert.Empty(t, acl.OwnerID)
+	assert.Empty(t, acl.GuestIDs)
+	assert.True(t, acl.Open)
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/myideascope/otherside/internal/domain"
+)
+
+// SQLiteAlertRuleRepository implements AlertRuleRepository using SQLite
+type SQLiteAlertRuleRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteAlertRuleRepository creates a new SQLite alert rule repository
+func NewSQLiteAlertRuleRepository(db *sql.DB) *SQLiteAlertRuleRepository {
+	return &SQLiteAlertRuleRepository{db: db}
+}
+
+// Create stores a new alert rule
+func (r *SQLiteAlertRuleRepository) Create(ctx context.Context, rule *domain.AlertRule) error {
+	conditionsJSON, err := json.Marshal(rule.Conditions)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO alert_rules (id, name, session_id, conditions, window_seconds, debounce_seconds,
+			webhook_url, enabled, created_by, created_at, updated_at)
+		VALUES (?, ?, NULLIF(?, ''), ?, ?, ?, NULLIF(?, ''), ?, NULLIF(?, ''), ?, ?)`
+
+	_, err = r.db.ExecContext(ctx, query,
+		rule.ID, rule.Name, rule.SessionID, string(conditionsJSON), rule.WindowSeconds, rule.DebounceSeconds,
+		rule.WebhookURL, rule.Enabled, rule.CreatedBy, rule.CreatedAt, rule.UpdatedAt,
+	)
+
+	return err
+}
+
+// GetByID retrieves an alert rule by ID
+func (r *SQLiteAlertRuleRepository) GetByID(ctx context.Context, id string) (*domain.AlertRule, error) {
+	query := `
+		SELECT id, name, COALESCE(session_id, ''), conditions, window_seconds, debounce_seconds,
+			COALESCE(webhook_url, ''), enabled, COALESCE(created_by, ''), created_at, updated_at
+		FROM alert_rules WHERE id = ?`
+
+	return scanAlertRule(r.db.QueryRowContext(ctx, query, id))
+}
+
+// GetAll retrieves every alert rule, oldest first
+func (r *SQLiteAlertRuleRepository) GetAll(ctx context.Context) ([]*domain.AlertRule, error) {
+	query := `
+		SELECT id, name, COALESCE(session_id, ''), conditions, window_seconds, debounce_seconds,
+			COALESCE(webhook_url, ''), enabled, COALESCE(created_by, ''), created_at, updated_at
+		FROM alert_rules ORDER BY created_at, id`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []*domain.AlertRule
+	for rows.Next() {
+		rule, err := scanAlertRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, rows.Err()
+}
+
+// Update replaces an alert rule's settings. Its session and author stay.
+func (r *SQLiteAlertRuleRepository) Update(ctx context.Context, rule *domain.AlertRule) error {
+	conditionsJSON, err := json.Marshal(rule.Conditions)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE alert_rules SET name = ?, conditions = ?, window_seconds = ?, debounce_seconds = ?,
+			webhook_url = NULLIF(?, ''), enabled = ?, updated_at = ?
+		WHERE id = ?`
+
+	result, err := r.db.ExecContext(ctx, query,
+		rule.Name, string(conditionsJSON), rule.WindowSeconds, rule.DebounceSeconds,
+		rule.WebhookURL, rule.Enabled, rule.UpdatedAt, rule.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// Delete removes an alert rule. The alerts it raised are kept.
+func (r *SQLiteAlertRuleRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM alert_rules WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// scanAlertRule scans one alert_rules row from a Row or Rows
+func scanAlertRule(row interface{ Scan(...interface{}) error }) (*domain.AlertRule, error) {
+	var rule domain.AlertRule
+	var conditionsJSON string
+
+	err := row.Scan(
+		&rule.ID, &rule.Name, &rule.SessionID, &conditionsJSON, &rule.WindowSeconds, &rule.DebounceSeconds,
+		&rule.WebhookURL, &rule.Enabled, &rule.CreatedBy, &rule.CreatedAt, &rule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(conditionsJSON), &rule.Conditions); err != nil {
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+// SQLiteAlertRepository implements AlertRepository using SQLite
+type SQLiteAlertRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteAlertRepository creates a new SQLite alert repository
+func NewSQLiteAlertRepository(db *sql.DB) *SQLiteAlertRepository {
+	return &SQLiteAlertRepository{db: db}
+}
+
+// Create stores a raised alert
+func (r *SQLiteAlertRepository) Create(ctx context.Context, alert *domain.Alert) error {
+	matches := alert.Matches
+	if matches == nil {
+		matches = []domain.AlertMatch{}
+	}
+	matchesJSON, err := json.Marshal(matches)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO alerts (id, rule_id, rule_name, session_id, message, matches, triggered_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	_, err = r.db.ExecContext(ctx, query,
+		alert.ID, alert.RuleID, alert.RuleName, alert.SessionID, alert.Message, string(matchesJSON), alert.TriggeredAt,
+	)
+
+	return err
+}
+
+// GetBySessionID retrieves up to limit alerts of a session, newest first
+func (r *SQLiteAlertRepository) GetBySessionID(ctx context.Context, sessionID string, limit int) ([]*domain.Alert, error) {
+	query := `
+		SELECT id, rule_id, rule_name, session_id, message, matches, triggered_at
+		FROM alerts WHERE session_id = ?
+		ORDER BY triggered_at DESC, id DESC
+		LIMIT ?`
+
+	rows, err := r.db.QueryContext(ctx, query, sessionID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var alerts []*domain.Alert
+	for rows.Next() {
+		var alert domain.Alert
+		var matchesJSON string
+		if err := rows.Scan(
+			&alert.ID, &alert.RuleID, &alert.RuleName, &alert.SessionID, &alert.Message, &matchesJSON, &alert.TriggeredAt,
+		); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(matchesJSON), &alert.Matches); err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, &alert)
+	}
+
+	return alerts, rows.Err()
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteAlertRuleRepository_Update_GlobalRule_RoundTripsConditions(t *testing.T) {
+	// Arrange
+	db := setupMigratedTestDB(t)
+	defer cleanupTestDB(db)
+	repo := NewSQLiteAlertRuleRepository(db)
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Second)
+	rule := &domain.AlertRule{
+		ID:              "rule-1",
+		Name:            "EMF spike",
+		Conditions:      []domain.AlertCondition{{Event: "environmental", Field: "emf", Op: ">", Value: 5.0}},
+		DebounceSeconds: 30,
+		Enabled:         true,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	require.NoError(t, repo.Create(ctx, rule))
+
+	// Act
+	rule.Conditions = append(rule.Conditions, domain.AlertCondition{Event: "evp", Field: "class", Op: "==", Value: "A"})
+	rule.WindowSeconds = 3
+	rule.WebhookURL = "http://localhost:9000/alerts"
+	rule.Enabled = false
+	require.NoError(t, repo.Update(ctx, rule))
+	stored, err := repo.GetByID(ctx, rule.ID)
+	require.NoError(t, err)
+	all, err := repo.GetAll(ctx)
+	require.NoError(t, err)
+	missingErr := repo.Delete(ctx, "rule-missing")
+
+	// Assert
+	assert.Empty(t, stored.SessionID)
+	assert.Empty(t, stored.CreatedBy)
+	assert.Equal(t, rule.Conditions, stored.Conditions)
+	assert.Equal(t, 3.0, stored.WindowSeconds)
+	assert.Equal(t, "http://localhost:9000/alerts", stored.WebhookURL)
+	assert.False(t, stored.Enabled)
+	require.Len(t, all, 1)
+	assert.ErrorIs(t, missingErr, sql.ErrNoRows)
+}
+
+func TestSQLiteAlertRepository_GetBySessionID_ReturnsLatestFirst(t *testing.T) {
+	// Arrange
+	db := setupMigratedTestDB(t)
+	defer cleanupTestDB(db)
+	sessions := NewSQLiteSessionRepository(db)
+	repo := NewSQLiteAlertRepository(db)
+	ctx := context.Background()
+
+	session := createTestSession()
+	require.NoError(t, sessions.Create(ctx, session))
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	for i, id := range []string{"alert-1", "alert-2", "alert-3"} {
+		require.NoError(t, repo.Create(ctx, &domain.Alert{
+			ID:        id,
+			RuleID:    "rule-1",
+			RuleName:  "EMF spike",
+			SessionID: session.ID,
+			Message:   "EMF spike: environmental emf 6 > 5",
+			Matches: []domain.AlertMatch{
+				{Event: "environmental", EventID: "reading-" + id, Field: "emf", Value: 6.0, Timestamp: start},
+			},
+			TriggeredAt: start.Add(time.Duration(i) * time.Minute),
+		}))
+	}
+
+	// Act
+	alerts, err := repo.GetBySessionID(ctx, session.ID, 2)
+	require.NoError(t, err)
+
+	// Assert
+	require.Len(t, alerts, 2)
+	assert.Equal(t, "alert-3", alerts[0].ID)
+	assert.Equal(t, "alert-2", alerts[1].ID)
+	require.Len(t, alerts[0].Matches, 1)
+	assert.Equal(t, "reading-alert-3", alerts[0].Matches[0].EventID)
+	assert.Equal(t, 6.0, alerts[0].Matches[0].Value)
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+)
+
+// SQLiteAPIKeyRepository implements APIKeyRepository using SQLite
+type SQLiteAPIKeyRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteAPIKeyRepository creates a new SQLite API key repository
+func NewSQLiteAPIKeyRepository(db *sql.DB) *SQLiteAPIKeyRepository {
+	return &SQLiteAPIKeyRepository{db: db}
+}
+
+// Create stores a new API key
+func (r *SQLiteAPIKeyRepository) Create(ctx context.Context, key *domain.APIKey) error {
+	query := `
+		INSERT INTO api_keys (id, name, key_hash, principal_kind, principal_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		key.ID, key.Name, key.KeyHash, key.PrincipalKind, key.PrincipalID, key.CreatedAt,
+	)
+
+	return err
+}
+
+// GetByID retrieves an API key by ID, including revoked keys
+func (r *SQLiteAPIKeyRepository) GetByID(ctx context.Context, id string) (*domain.APIKey, error) {
+	query := `
+		SELECT id, name, key_hash, principal_kind, principal_id, created_at, last_used_at, revoked_at
+		FROM api_keys WHERE id = ?`
+
+	return scanAPIKey(r.db.QueryRowContext(ctx, query, id))
+}
+
+// GetAll retrieves all API keys, newest first
+func (r *SQLiteAPIKeyRepository) GetAll(ctx context.Context) ([]*domain.APIKey, error) {
+	query := `
+		SELECT id, name, key_hash, principal_kind, principal_id, created_at, last_used_at, revoked_at
+		FROM api_keys ORDER BY created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []*domain.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// Revoke marks an API key as revoked. Revoking a revoked key keeps the
+// original revocation time.
+func (r *SQLiteAPIKeyRepository) Revoke(ctx context.Context, id string, at time.Time) error {
+	query := `UPDATE api_keys SET revoked_at = COALESCE(revoked_at, ?) WHERE id = ?`
+
+	result, err := r.db.ExecContext(ctx, query, at, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// MarkUsed records when an API key was last used
+func (r *SQLiteAPIKeyRepository) MarkUsed(ctx context.Context, id string, at time.Time) error {
+	query := `UPDATE api_keys SET last_used_at = ? WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, at, id)
+	return err
+}
+
+// scanAPIKey scans one api_keys row from a Row or Rows
+func scanAPIKey(row interface{ Scan(...interface{}) error }) (*domain.APIKey, error) {
+	var key domain.APIKey
+	var lastUsedAt, revokedAt sql.NullTime
+
+	err := row.Scan(
+		&key.ID, &key.Name, &key.KeyHash, &key.PrincipalKind, &key.PrincipalID,
+		&key.CreatedAt, &lastUsedAt, &revokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if lastUsedAt.Valid {
+		key.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+
+	return &key, nil
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+)
+
+// SQLiteChangeRepository implements ChangeRepository using SQLite. The
+// changes table is written by triggers on the record tables.
+type SQLiteChangeRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteChangeRepository creates a new SQLite change repository
+func NewSQLiteChangeRepository(db *sql.DB) *SQLiteChangeRepository {
+	return &SQLiteChangeRepository{db: db}
+}
+
+// GetSince retrieves up to limit changes with a seq above since, oldest
+// first. An empty session ID matches every session.
+func (r *SQLiteChangeRepository) GetSince(ctx context.Context, since int64, sessionID string, limit int) ([]*domain.Change, error) {
+	query := `
+		SELECT seq, entity_type, entity_id, session_id, operation, changed_at
+		FROM changes
+		WHERE seq > ? AND (? = '' OR session_id = ?)
+		ORDER BY seq
+		LIMIT ?`
+
+	rows, err := r.db.QueryContext(ctx, query, since, sessionID, sessionID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []*domain.Change
+	for rows.Next() {
+		var change domain.Change
+		if err := rows.Scan(
+			&change.Seq, &change.EntityType, &change.EntityID, &change.SessionID,
+			&change.Operation, &change.ChangedAt,
+		); err != nil {
+			return nil, err
+		}
+		changes = append(changes, &change)
+	}
+
+	return changes, rows.Err()
+}
+
+// PurgedThrough returns the highest seq of a purged tombstone, or zero
+func (r *SQLiteChangeRepository) PurgedThrough(ctx context.Context) (int64, error) {
+	var seq int64
+	err := r.db.QueryRowContext(ctx, `SELECT purged_through FROM change_feed_state WHERE id = 1`).Scan(&seq)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return seq, err
+}
+
+// PurgeTombstones removes tombstones older than a time, remembering the
+// highest seq removed, and returns how many were removed
+func (r *SQLiteChangeRepository) PurgeTombstones(ctx context.Context, before time.Time) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// changed_at is written by SQLite as UTC text
+	cutoff := before.UTC().Format("2006-01-02 15:04:05")
+
+	var maxSeq sql.NullInt64
+	err = tx.QueryRowContext(ctx,
+		`SELECT MAX(seq) FROM changes WHERE operation = ? AND changed_at < ?`, domain.ChangeDelete, cutoff,
+	).Scan(&maxSeq)
+	if err != nil {
+		return 0, err
+	}
+	if !maxSeq.Valid {
+		return 0, nil
+	}
+
+	result, err := tx.ExecContext(ctx,
+		`DELETE FROM changes WHERE operation = ? AND seq <= ?`, domain.ChangeDelete, maxSeq.Int64)
+	if err != nil {
+		return 0, err
+	}
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE change_feed_state SET purged_through = MAX(purged_through, ?) WHERE id = 1`, maxSeq.Int64)
+	if err != nil {
+		return 0, err
+	}
+
+	return purged, tx.Commit()
+}
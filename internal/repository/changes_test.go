@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteChangeRepository_PurgeTombstones_DeletedSession_MovesPurgeHorizon(t *testing.T) {
+	// Arrange
+	db := setupMigratedTestDB(t)
+	defer cleanupTestDB(db)
+	sessions := NewSQLiteSessionRepository(db)
+	repo := NewSQLiteChangeRepository(db)
+	ctx := context.Background()
+
+	kept := createTestSession()
+	kept.ID = "session-kept"
+	deleted := createTestSession()
+	deleted.ID = "session-deleted"
+	require.NoError(t, sessions.Create(ctx, kept))
+	require.NoError(t, sessions.Create(ctx, deleted))
+	require.NoError(t, sessions.Delete(ctx, deleted.ID))
+
+	// Act
+	before, err := repo.GetSince(ctx, 0, "", 10)
+	require.NoError(t, err)
+	purged, err := repo.PurgeTombstones(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	after, err := repo.GetSince(ctx, 0, "", 10)
+	require.NoError(t, err)
+	purgedThrough, err := repo.PurgedThrough(ctx)
+	require.NoError(t, err)
+
+	// Assert
+	require.Len(t, before, 2)
+	assert.Equal(t, kept.ID, before[0].EntityID)
+	assert.Equal(t, domain.ChangeUpsert, before[0].Operation)
+	assert.Equal(t, deleted.ID, before[1].EntityID)
+	assert.Equal(t, domain.ChangeDelete, before[1].Operation)
+	assert.Greater(t, before[1].Seq, before[0].Seq)
+	assert.Equal(t, int64(1), purged)
+	require.Len(t, after, 1)
+	assert.Equal(t, kept.ID, after[0].EntityID)
+	assert.Equal(t, before[1].Seq, purgedThrough)
+}
+
+func TestSQLiteChangeRepository_GetSince_TouchedSession_NotChanged(t *testing.T) {
+	// Arrange
+	db := setupMigratedTestDB(t)
+	defer cleanupTestDB(db)
+	sessions := NewSQLiteSessionRepository(db)
+	repo := NewSQLiteChangeRepository(db)
+	ctx := context.Background()
+
+	session := createTestSession()
+	require.NoError(t, sessions.Create(ctx, session))
+	created, err := repo.GetSince(ctx, 0, "", 10)
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+
+	// Act
+	require.NoError(t, sessions.Touch(ctx, session.ID, time.Now().Add(time.Minute)))
+	touched, err := repo.GetSince(ctx, created[0].Seq, "", 10)
+	require.NoError(t, err)
+	session.Notes = "Knocking in the attic"
+	require.NoError(t, sessions.Update(ctx, session))
+	updated, err := repo.GetSince(ctx, created[0].Seq, "", 10)
+	require.NoError(t, err)
+
+	// Assert
+	assert.Empty(t, touched)
+	require.Len(t, updated, 1)
+	assert.Equal(t, session.ID, updated[0].EntityID)
+	assert.Equal(t, domain.ChangeUpsert, updated[0].Operation)
+}
+
+func TestSQLiteChangeRepository_GetSince_PlacementDeleted_TombstonesFloorPlanEntry(t *testing.T) {
+	// Arrange
+	db := setupMigratedTestDB(t)
+	defer cleanupTestDB(db)
+	sessions := NewSQLiteSessionRepository(db)
+	plans := NewSQLiteFloorPlanRepository(db)
+	placements := NewSQLiteDevicePlacementRepository(db)
+	repo := NewSQLiteChangeRepository(db)
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Second)
+	session := createTestSession()
+	require.NoError(t, sessions.Create(ctx, session))
+	plan := &domain.FloorPlan{ID: "plan-1", Name: "Ground floor", CreatedAt: now, UpdatedAt: now}
+	require.NoError(t, plans.Create(ctx, plan))
+	placement := &domain.DevicePlacement{
+		ID: "placement-1", SessionID: session.ID, FloorPlanID: plan.ID, DeviceID: "radar-1",
+		PlacedAt: now, CreatedAt: now,
+	}
+	require.NoError(t, placements.Create(ctx, placement))
+	placed, err := repo.GetSince(ctx, 0, "", 10)
+	require.NoError(t, err)
+
+	// Act
+	plan.Name = "Cellar"
+	require.NoError(t, plans.Update(ctx, plan))
+	renamed, err := repo.GetSince(ctx, placed[len(placed)-1].Seq, "", 10)
+	require.NoError(t, err)
+	require.NoError(t, placements.Delete(ctx, placement.ID))
+	removed, err := repo.GetSince(ctx, renamed[len(renamed)-1].Seq, "", 10)
+	require.NoError(t, err)
+
+	// Assert
+	operations := map[string]domain.ChangeOperation{}
+	for _, change := range placed {
+		operations[change.EntityType+":"+change.EntityID] = change.Operation
+	}
+	assert.Equal(t, domain.ChangeUpsert, operations["floor_plan:"+session.ID+"/plan-1"])
+	assert.Equal(t, domain.ChangeUpsert, operations["placement:placement-1"])
+
+	require.Len(t, renamed, 1)
+	assert.Equal(t, "floor_plan", renamed[0].EntityType)
+	assert.Equal(t, session.ID, renamed[0].SessionID)
+
+	operations = map[string]domain.ChangeOperation{}
+	for _, change := range removed {
+		operations[change.EntityType+":"+change.EntityID] = change.Operation
+	}
+	assert.Equal(t, domain.ChangeDelete, operations["floor_plan:"+session.ID+"/plan-1"])
+	assert.Equal(t, domain.ChangeDelete, operations["placement:placement-1"])
+}
+
+func TestSQLiteChangeRepository_GetSince_GuestAdded_ResendsSession(t *testing.T) {
+	// Arrange
+	db := setupMigratedTestDB(t)
+	defer cleanupTestDB(db)
+	sessions := NewSQLiteSessionRepository(db)
+	readings := NewSQLiteEnvironmentalReadingRepository(db)
+	acls := NewSQLiteSessionACLRepository(db)
+	repo := NewSQLiteChangeRepository(db)
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Second)
+	session := createTestSession()
+	require.NoError(t, sessions.Create(ctx, session))
+	require.NoError(t, readings.CreateBatch(ctx, []*domain.EnvironmentalReading{{
+		ID: "reading-1", SessionID: session.ID, DeviceID: "sensor-1", Timestamp: now,
+		Metric: domain.EnvironmentalMetricTemperature, Value: 12.5, CreatedAt: now,
+	}}))
+	before, err := repo.GetSince(ctx, 0, "", 10)
+	require.NoError(t, err)
+	cursor := before[len(before)-1].Seq
+
+	// Act
+	require.NoError(t, acls.AddGuest(ctx, session.ID, "guest", now))
+	after, err := repo.GetSince(ctx, cursor, "", 10)
+	require.NoError(t, err)
+
+	// Assert
+	types := map[string]string{}
+	for _, change := range after {
+		types[change.EntityType] = change.EntityID
+	}
+	assert.Len(t, after, 3)
+	assert.Equal(t, session.ID, types["session"])
+	assert.Equal(t, "reading-1", types["environmental"])
+	assert.Equal(t, session.ID, types["acl"])
+}
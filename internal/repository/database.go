@@ -26,8 +26,16 @@ func NewDB(cfg *config.DatabaseConfig) (*DB, error) {
 		dbPath = filepath.Join(".", dbPath)
 	}
 
+	// SQLite only enforces foreign keys, and so only cascades deletes from
+	// sessions to their events, on connections that ask for it. The action
+	// of each foreign key is listed in DATABASE_SYSTEM.md.
+	dsn := dbPath
+	if cfg.Driver == "sqlite3" {
+		dsn += "?_foreign_keys=on"
+	}
+
 	// Open database connection
-	db, err := sql.Open(cfg.Driver, dbPath)
+	db, err := sql.Open(cfg.Driver, dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -6,9 +6,11 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/myideascope/otherside/internal/config"
+	"github.com/myideascope/otherside/internal/domain"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -208,6 +210,96 @@ func TestDB_RelativePathHandling(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestNewDB_DeleteSession_CascadesToEvents(t *testing.T) {
+	// Arrange - NewDB reads migrations relative to the repository root
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	require.NoError(t, os.Chdir(filepath.Join("..", "..")))
+
+	db, err := NewDB(&config.DatabaseConfig{Driver: "sqlite3", Database: filepath.Join(t.TempDir(), "otherside.db")})
+	require.NoError(t, err)
+	defer db.Close()
+	ctx := context.Background()
+
+	sessions := NewSQLiteSessionRepository(db.DB)
+	session := createTestSession()
+	require.NoError(t, sessions.Create(ctx, session))
+	now := time.Now()
+	require.NoError(t, NewSQLiteVOXRepository(db.DB).Create(ctx, &domain.VOXEvent{
+		ID: "vox-1", SessionID: session.ID, Timestamp: now, GeneratedText: "hello",
+		PhoneticBank: "english", LanguagePack: "english", ModulationType: "am", CreatedAt: now,
+	}))
+	require.NoError(t, NewSQLiteInteractionRepository(db.DB).Create(ctx, &domain.UserInteraction{
+		ID: "interaction-1", SessionID: session.ID, Timestamp: now, Type: domain.InteractionTypeText,
+		Content: "Is anyone here?", CreatedAt: now,
+	}))
+	require.NoError(t, NewSQLiteSessionACLRepository(db.DB).SetOwnership(ctx, session.ID, "", "", now))
+	fileRepo := NewSQLiteFileRepository(db.DB, t.TempDir())
+
+	// Act
+	saveErr := fileRepo.SaveFile(ctx, "exports/otherside_export.json", []byte("{}"))
+	deleteErr := sessions.Delete(ctx, session.ID)
+	_, orphanErr := db.ExecContext(ctx, `INSERT INTO vox_events (id, session_id, timestamp, generated_text, phonetic_bank, trigger_strength, language_pack, modulation_type, created_at)
+		VALUES ('vox-2', 'session-missing', ?, '', '', 0, '', '', ?)`, now, now)
+
+	// Assert
+	assert.NoError(t, saveErr)
+	require.NoError(t, deleteErr)
+	for _, table := range []string{"vox_events", "user_interactions", "session_acl"} {
+		var count int
+		require.NoError(t, db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+table+" WHERE session_id = ?", session.ID).Scan(&count))
+		assert.Zero(t, count, table)
+	}
+	var tombstones int
+	require.NoError(t, db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM changes WHERE session_id = ? AND operation = 'delete'", session.ID).Scan(&tombstones))
+	assert.Equal(t, 4, tombstones)
+	require.Error(t, orphanErr)
+	assert.Contains(t, orphanErr.Error(), "FOREIGN KEY constraint failed")
+}
+
+func TestMigrations_OrphanedRows_ForeignKeyCheckPasses(t *testing.T) {
+	// Arrange - rows left behind while foreign keys were not enforced
+	db := setupMigratedTestDB(t)
+	defer cleanupTestDB(db)
+	ctx := context.Background()
+
+	sessions := NewSQLiteSessionRepository(db)
+	session := createTestSession()
+	require.NoError(t, sessions.Create(ctx, session))
+	now := time.Now()
+	_, err := db.ExecContext(ctx, `INSERT INTO vox_events (id, session_id, timestamp, generated_text, phonetic_bank, trigger_strength, language_pack, modulation_type, investigator_id, created_at)
+		VALUES ('vox-orphan', 'session-deleted', ?, '', '', 0, '', '', NULL, ?), ('vox-kept', ?, ?, '', '', 0, '', '', 'investigator-deleted', ?)`,
+		now, now, session.ID, now, now)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `INSERT INTO session_acl (session_id, owner_id, team_id, updated_at) VALUES (?, 'investigator-deleted', 'team-deleted', ?)`, session.ID, now)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `INSERT INTO files (id, session_id, file_path, file_type, file_size, created_at) VALUES ('file-orphan', 'session-deleted', 'a.wav', 'audio', 1, ?)`, now)
+	require.NoError(t, err)
+	migration, err := os.ReadFile(filepath.Join("migrations", "023_remove_orphaned_rows.sql"))
+	require.NoError(t, err)
+
+	// Act
+	_, err = db.ExecContext(ctx, string(migration))
+	require.NoError(t, err)
+
+	// Assert
+	rows, err := db.QueryContext(ctx, "PRAGMA foreign_key_check")
+	require.NoError(t, err)
+	defer rows.Close()
+	assert.False(t, rows.Next(), "no row should violate a foreign key")
+
+	var voxCount int
+	var investigatorID, ownerID, teamID sql.NullString
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT COUNT(*) FROM vox_events").Scan(&voxCount))
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT investigator_id FROM vox_events WHERE id = 'vox-kept'").Scan(&investigatorID))
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT owner_id, team_id FROM session_acl WHERE session_id = ?", session.ID).Scan(&ownerID, &teamID))
+	assert.Equal(t, 1, voxCount)
+	assert.False(t, investigatorID.Valid)
+	assert.False(t, ownerID.Valid)
+	assert.False(t, teamID.Valid)
+}
+
 func TestDB_ConnectionStringValidation(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -0,0 +1,278 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+)
+
+// SQLiteEnvironmentalReadingRepository implements EnvironmentalReadingRepository using SQLite
+type SQLiteEnvironmentalReadingRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteEnvironmentalReadingRepository creates a new SQLite environmental reading repository
+func NewSQLiteEnvironmentalReadingRepository(db *sql.DB) *SQLiteEnvironmentalReadingRepository {
+	return &SQLiteEnvironmentalReadingRepository{db: db}
+}
+
+// CreateBatch inserts readings in one transaction. Timestamps are stored in
+// UTC so that range queries compare consistently.
+func (r *SQLiteEnvironmentalReadingRepository) CreateBatch(ctx context.Context, readings []*domain.EnvironmentalReading) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO environmental_readings (id, session_id, device_id, timestamp, metric, value, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, reading := range readings {
+		_, err := stmt.ExecContext(ctx,
+			reading.ID, reading.SessionID, reading.DeviceID, reading.Timestamp.UTC(),
+			reading.Metric, reading.Value, reading.CreatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert environmental reading %s: %w", reading.ID, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetByID retrieves a reading by ID
+func (r *SQLiteEnvironmentalReadingRepository) GetByID(ctx context.Context, id string) (*domain.EnvironmentalReading, error) {
+	query := `
+		SELECT id, session_id, device_id, timestamp, metric, value, created_at
+		FROM environmental_readings WHERE id = ?`
+
+	var reading domain.EnvironmentalReading
+
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&reading.ID, &reading.SessionID, &reading.DeviceID, &reading.Timestamp,
+		&reading.Metric, &reading.Value, &reading.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &reading, nil
+}
+
+// GetBySessionID retrieves readings of a session in chronological order
+func (r *SQLiteEnvironmentalReadingRepository) GetBySessionID(ctx context.Context, sessionID string, metric domain.EnvironmentalMetric, start, end time.Time) ([]*domain.EnvironmentalReading, error) {
+	conditions := []string{"session_id = ?"}
+	args := []interface{}{sessionID}
+
+	if metric != "" {
+		conditions = append(conditions, "metric = ?")
+		args = append(args, metric)
+	}
+	if !start.IsZero() {
+		conditions = append(conditions, "timestamp >= ?")
+		args = append(args, start.UTC())
+	}
+	if !end.IsZero() {
+		conditions = append(conditions, "timestamp <= ?")
+		args = append(args, end.UTC())
+	}
+
+	query := `
+		SELECT id, session_id, device_id, timestamp, metric, value, created_at
+		FROM environmental_readings WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY timestamp ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var readings []*domain.EnvironmentalReading
+	for rows.Next() {
+		var reading domain.EnvironmentalReading
+
+		err := rows.Scan(
+			&reading.ID, &reading.SessionID, &reading.DeviceID, &reading.Timestamp,
+			&reading.Metric, &reading.Value, &reading.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		readings = append(readings, &reading)
+	}
+
+	return readings, rows.Err()
+}
+
+// DeleteBySessionID deletes all readings of a session
+func (r *SQLiteEnvironmentalReadingRepository) DeleteBySessionID(ctx context.Context, sessionID string) error {
+	query := `DELETE FROM environmental_readings WHERE session_id = ?`
+	_, err := r.db.ExecContext(ctx, query, sessionID)
+	return err
+}
+
+// SQLiteEnvironmentalAnomalyRepository implements EnvironmentalAnomalyRepository using SQLite
+type SQLiteEnvironmentalAnomalyRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteEnvironmentalAnomalyRepository creates a new SQLite environmental anomaly repository
+func NewSQLiteEnvironmentalAnomalyRepository(db *sql.DB) *SQLiteEnvironmentalAnomalyRepository {
+	return &SQLiteEnvironmentalAnomalyRepository{db: db}
+}
+
+// CreateBatch inserts anomalies in one transaction
+func (r *SQLiteEnvironmentalAnomalyRepository) CreateBatch(ctx context.Context, anomalies []*domain.EnvironmentalAnomaly) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO environmental_anomalies (
+			id, session_id, reading_id, device_id, metric, timestamp, value,
+			baseline, std_dev, deviation, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	for _, anomaly := range anomalies {
+		_, err := tx.ExecContext(ctx, query,
+			anomaly.ID, anomaly.SessionID, anomaly.ReadingID, anomaly.DeviceID, anomaly.Metric,
+			anomaly.Timestamp.UTC(), anomaly.Value, anomaly.Baseline, anomaly.StdDev, anomaly.Deviation,
+			anomaly.CreatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert environmental anomaly %s: %w", anomaly.ID, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetBySessionID retrieves anomalies of a session in chronological order
+func (r *SQLiteEnvironmentalAnomalyRepository) GetBySessionID(ctx context.Context, sessionID string) ([]*domain.EnvironmentalAnomaly, error) {
+	query := `
+		SELECT id, session_id, reading_id, device_id, metric, timestamp, value,
+			baseline, std_dev, deviation, created_at
+		FROM environmental_anomalies WHERE session_id = ? ORDER BY timestamp ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var anomalies []*domain.EnvironmentalAnomaly
+	for rows.Next() {
+		var anomaly domain.EnvironmentalAnomaly
+
+		err := rows.Scan(
+			&anomaly.ID, &anomaly.SessionID, &anomaly.ReadingID, &anomaly.DeviceID, &anomaly.Metric,
+			&anomaly.Timestamp, &anomaly.Value, &anomaly.Baseline, &anomaly.StdDev, &anomaly.Deviation,
+			&anomaly.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		anomalies = append(anomalies, &anomaly)
+	}
+
+	return anomalies, rows.Err()
+}
+
+// SQLiteSensorRegistrationRepository implements SensorRegistrationRepository using SQLite
+type SQLiteSensorRegistrationRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteSensorRegistrationRepository creates a new SQLite sensor registration repository
+func NewSQLiteSensorRegistrationRepository(db *sql.DB) *SQLiteSensorRegistrationRepository {
+	return &SQLiteSensorRegistrationRepository{db: db}
+}
+
+// Save registers a device with a session, replacing any earlier registration
+func (r *SQLiteSensorRegistrationRepository) Save(ctx context.Context, registration *domain.SensorRegistration) error {
+	query := `
+		INSERT INTO sensor_registrations (device_id, session_id, name, registered_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(device_id) DO UPDATE SET
+			session_id = excluded.session_id,
+			name = excluded.name,
+			registered_at = excluded.registered_at`
+
+	_, err := r.db.ExecContext(ctx, query,
+		registration.DeviceID, registration.SessionID, registration.Name, registration.RegisteredAt,
+	)
+
+	return err
+}
+
+// GetByDeviceID retrieves the registration of a device
+func (r *SQLiteSensorRegistrationRepository) GetByDeviceID(ctx context.Context, deviceID string) (*domain.SensorRegistration, error) {
+	query := `
+		SELECT device_id, session_id, name, registered_at
+		FROM sensor_registrations WHERE device_id = ?`
+
+	var registration domain.SensorRegistration
+	var name sql.NullString
+
+	err := r.db.QueryRowContext(ctx, query, deviceID).Scan(
+		&registration.DeviceID, &registration.SessionID, &name, &registration.RegisteredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	registration.Name = name.String
+
+	return &registration, nil
+}
+
+// GetBySessionID retrieves the devices registered with a session
+func (r *SQLiteSensorRegistrationRepository) GetBySessionID(ctx context.Context, sessionID string) ([]*domain.SensorRegistration, error) {
+	query := `
+		SELECT device_id, session_id, name, registered_at
+		FROM sensor_registrations WHERE session_id = ? ORDER BY registered_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var registrations []*domain.SensorRegistration
+	for rows.Next() {
+		var registration domain.SensorRegistration
+		var name sql.NullString
+
+		err := rows.Scan(&registration.DeviceID, &registration.SessionID, &name, &registration.RegisteredAt)
+		if err != nil {
+			return nil, err
+		}
+
+		registration.Name = name.String
+		registrations = append(registrations, &registration)
+	}
+
+	return registrations, rows.Err()
+}
+
+// Delete removes the registration of a device
+func (r *SQLiteSensorRegistrationRepository) Delete(ctx context.Context, deviceID string) error {
+	query := `DELETE FROM sensor_registrations WHERE device_id = ?`
+	_, err := r.db.ExecContext(ctx, query, deviceID)
+	return err
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteEnvironmentalReadingRepository_GetBySessionID_FiltersMetricAndRange(t *testing.T) {
+	// Arrange
+	db := setupMigratedTestDB(t)
+	defer cleanupTestDB(db)
+	repo := NewSQLiteEnvironmentalReadingRepository(db)
+	ctx := context.Background()
+
+	// Readings arrive with mixed zones but must compare on the same clock
+	base := time.Date(2026, 10, 31, 22, 0, 0, 0, time.UTC)
+	tokyo := time.FixedZone("JST", 9*60*60)
+	var readings []*domain.EnvironmentalReading
+	for i, metric := range []domain.EnvironmentalMetric{"temperature", "emf", "temperature", "temperature"} {
+		timestamp := base.Add(time.Duration(i) * time.Minute)
+		if i%2 == 1 {
+			timestamp = timestamp.In(tokyo)
+		}
+		readings = append(readings, &domain.EnvironmentalReading{
+			ID:        string(rune('a' + i)),
+			SessionID: "session-1",
+			DeviceID:  "probe-1",
+			Timestamp: timestamp,
+			Metric:    metric,
+			Value:     float64(i),
+			CreatedAt: base,
+		})
+	}
+	require.NoError(t, repo.CreateBatch(ctx, readings))
+
+	// Act
+	all, err := repo.GetBySessionID(ctx, "session-1", "", time.Time{}, time.Time{})
+	require.NoError(t, err)
+	temperatures, err := repo.GetBySessionID(ctx, "session-1", domain.EnvironmentalMetricTemperature,
+		base.Add(30*time.Second).In(tokyo), base.Add(3*time.Minute))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Len(t, all, 4)
+	require.Len(t, temperatures, 2)
+	assert.Equal(t, "c", temperatures[0].ID)
+	assert.Equal(t, "d", temperatures[1].ID)
+	assert.True(t, temperatures[1].Timestamp.Equal(base.Add(3*time.Minute)))
+}
+
+func TestSQLiteEnvironmentalAnomalyRepository_CreateBatch_RoundTrips(t *testing.T) {
+	// Arrange
+	db := setupMigratedTestDB(t)
+	defer cleanupTestDB(db)
+	repo := NewSQLiteEnvironmentalAnomalyRepository(db)
+	ctx := context.Background()
+
+	timestamp := time.Now().Add(-time.Hour).Truncate(time.Second)
+	anomaly := &domain.EnvironmentalAnomaly{
+		ID:        "anomaly-1",
+		SessionID: "session-1",
+		ReadingID: "reading-1",
+		DeviceID:  "probe-1",
+		Metric:    domain.EnvironmentalMetricTemperature,
+		Timestamp: timestamp,
+		Value:     11.5,
+		Baseline:  18.2,
+		StdDev:    0.4,
+		Deviation: -16.75,
+		CreatedAt: timestamp,
+	}
+
+	// Act
+	require.NoError(t, repo.CreateBatch(ctx, []*domain.EnvironmentalAnomaly{anomaly}))
+	anomalies, err := repo.GetBySessionID(ctx, "session-1")
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, anomalies, 1)
+	assert.Equal(t, "reading-1", anomalies[0].ReadingID)
+	assert.Equal(t, domain.EnvironmentalMetricTemperature, anomalies[0].Metric)
+	assert.Equal(t, -16.75, anomalies[0].Deviation)
+	assert.True(t, timestamp.Equal(anomalies[0].Timestamp))
+}
+
+func TestSQLiteSensorRegistrationRepository_Save_ReplacesEarlierRegistration(t *testing.T) {
+	// Arrange
+	db := setupMigratedTestDB(t)
+	defer cleanupTestDB(db)
+	repo := NewSQLiteSensorRegistrationRepository(db)
+	ctx := context.Background()
+
+	registeredAt := time.Now().Truncate(time.Second)
+	require.NoError(t, repo.Save(ctx, &domain.SensorRegistration{
+		DeviceID: "emf-1", SessionID: "session-1", Name: "K2 meter", RegisteredAt: registeredAt,
+	}))
+
+	// Act
+	err := repo.Save(ctx, &domain.SensorRegistration{
+		DeviceID: "emf-1", SessionID: "session-2", RegisteredAt: registeredAt.Add(time.Hour),
+	})
+
+	// Assert
+	require.NoError(t, err)
+	registration, err := repo.GetByDeviceID(ctx, "emf-1")
+	require.NoError(t, err)
+	assert.Equal(t, "session-2", registration.SessionID)
+	assert.Empty(t, registration.Name)
+
+	previous, err := repo.GetBySessionID(ctx, "session-1")
+	require.NoError(t, err)
+	assert.Empty(t, previous)
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/myideascope/otherside/internal/domain"
+)
+
+// execer is the part of *sql.DB and *sql.Tx used to insert rows, so that
+// inserts can run alone or inside a transaction
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// SQLiteEventBatchRepository implements EventBatchRepository using SQLite
+type SQLiteEventBatchRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteEventBatchRepository creates a new SQLite event batch repository
+func NewSQLiteEventBatchRepository(db *sql.DB) *SQLiteEventBatchRepository {
+	return &SQLiteEventBatchRepository{db: db}
+}
+
+// CreateEvents inserts every event of a batch, or none of them when one
+// insert fails
+func (r *SQLiteEventBatchRepository) CreateEvents(ctx context.Context, batch *domain.EventBatch) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, vox := range batch.VOXEvents {
+		if err := insertVOXEvent(ctx, tx, vox); err != nil {
+			return err
+		}
+	}
+	for _, radar := range batch.RadarEvents {
+		if err := insertRadarEvent(ctx, tx, radar); err != nil {
+			return err
+		}
+	}
+	for _, sls := range batch.SLSDetections {
+		if err := insertSLSDetection(ctx, tx, sls); err != nil {
+			return err
+		}
+	}
+	for _, interaction := range batch.Interactions {
+		if err := insertUserInteraction(ctx, tx, interaction); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
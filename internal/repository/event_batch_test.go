@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteEventBatchRepository_CreateEvents_FailedInsert_StoresNothing(t *testing.T) {
+	// Arrange
+	db := setupMigratedTestDB(t)
+	defer cleanupTestDB(db)
+	repo := NewSQLiteEventBatchRepository(db)
+	interactionRepo := NewSQLiteInteractionRepository(db)
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Second)
+	interaction := func(id string) *domain.UserInteraction {
+		return &domain.UserInteraction{
+			ID: id, SessionID: "session-1", Type: domain.InteractionTypeText, Timestamp: now, CreatedAt: now,
+		}
+	}
+	require.NoError(t, interactionRepo.Create(ctx, interaction("interaction-1")))
+
+	// Act
+	err := repo.CreateEvents(ctx, &domain.EventBatch{
+		RadarEvents: []*domain.RadarEvent{{
+			ID: "radar-1", SessionID: "session-1", Timestamp: now, Strength: 0.8,
+			SourceType: domain.SourceTypeEMF, CreatedAt: now,
+		}},
+		Interactions: []*domain.UserInteraction{interaction("interaction-2"), interaction("interaction-1")},
+	})
+
+	// Assert
+	require.Error(t, err)
+	interactions, err := interactionRepo.GetBySessionID(ctx, "session-1")
+	require.NoError(t, err)
+	assert.Len(t, interactions, 1)
+	_, err = NewSQLiteRadarRepository(db).GetByID(ctx, "radar-1")
+	assert.Error(t, err)
+}
@@ -173,7 +173,7 @@ func (fm *FileManager) UpdateFile(ctx context.Context, filePath string, newConte
 // ListFilesBySession returns all files for a session
 func (fm *FileManager) ListFilesBySession(ctx context.Context, sessionID string) ([]*FileMetadata, error) {
 	query := `
-		SELECT id, session_id, file_path, file_type, file_size, 
+		SELECT id, COALESCE(session_id, ''), file_path, file_type, file_size, 
 		       mime_type, checksum, created_at 
 		FROM files 
 		WHERE session_id = ? 
@@ -307,7 +307,7 @@ func (fm *FileManager) GetStorageStats(ctx context.Context) (map[string]interfac
 func (fm *FileManager) storeMetadata(ctx context.Context, metadata *FileMetadata) error {
 	query := `
 		INSERT INTO files (id, session_id, file_path, file_type, file_size, mime_type, checksum, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+		VALUES (?, NULLIF(?, ''), ?, ?, ?, ?, ?, ?)`
 
 	_, err := fm.db.ExecContext(ctx, query,
 		metadata.ID, metadata.SessionID, metadata.FilePath,
@@ -319,7 +319,7 @@ func (fm *FileManager) storeMetadata(ctx context.Context, metadata *FileMetadata
 
 func (fm *FileManager) getFileMetadata(ctx context.Context, filePath string) (*FileMetadata, error) {
 	query := `
-		SELECT id, session_id, file_path, file_type, file_size, 
+		SELECT id, COALESCE(session_id, ''), file_path, file_type, file_size, 
 		       mime_type, checksum, created_at 
 		FROM files 
 		WHERE file_path = ?`
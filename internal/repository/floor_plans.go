@@ -0,0 +1,285 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/myideascope/otherside/internal/domain"
+)
+
+// SQLiteFloorPlanRepository implements FloorPlanRepository using SQLite
+type SQLiteFloorPlanRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteFloorPlanRepository creates a new SQLite floor plan repository
+func NewSQLiteFloorPlanRepository(db *sql.DB) *SQLiteFloorPlanRepository {
+	return &SQLiteFloorPlanRepository{db: db}
+}
+
+// Create inserts a floor plan and its rooms in one transaction
+func (r *SQLiteFloorPlanRepository) Create(ctx context.Context, plan *domain.FloorPlan) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO floor_plans (id, name, description, image_path, width, height, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err = tx.ExecContext(ctx, query,
+		plan.ID, plan.Name, plan.Description, plan.ImagePath, plan.Width, plan.Height,
+		plan.CreatedAt, plan.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert floor plan: %w", err)
+	}
+
+	if err := insertRooms(ctx, tx, plan); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetByID retrieves a floor plan with its rooms by ID
+func (r *SQLiteFloorPlanRepository) GetByID(ctx context.Context, id string) (*domain.FloorPlan, error) {
+	query := `
+		SELECT id, name, description, image_path, width, height, created_at, updated_at
+		FROM floor_plans WHERE id = ?`
+
+	var plan domain.FloorPlan
+	var description, imagePath sql.NullString
+
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&plan.ID, &plan.Name, &description, &imagePath, &plan.Width, &plan.Height,
+		&plan.CreatedAt, &plan.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	plan.Description = description.String
+	plan.ImagePath = imagePath.String
+
+	rooms, err := r.getRooms(ctx, plan.ID)
+	if err != nil {
+		return nil, err
+	}
+	plan.Rooms = rooms
+
+	return &plan, nil
+}
+
+// GetAll retrieves all floor plans with their rooms
+func (r *SQLiteFloorPlanRepository) GetAll(ctx context.Context) ([]*domain.FloorPlan, error) {
+	query := `
+		SELECT id, name, description, image_path, width, height, created_at, updated_at
+		FROM floor_plans ORDER BY name ASC`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var plans []*domain.FloorPlan
+	for rows.Next() {
+		var plan domain.FloorPlan
+		var description, imagePath sql.NullString
+
+		err := rows.Scan(
+			&plan.ID, &plan.Name, &description, &imagePath, &plan.Width, &plan.Height,
+			&plan.CreatedAt, &plan.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		plan.Description = description.String
+		plan.ImagePath = imagePath.String
+		plans = append(plans, &plan)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, plan := range plans {
+		rooms, err := r.getRooms(ctx, plan.ID)
+		if err != nil {
+			return nil, err
+		}
+		plan.Rooms = rooms
+	}
+
+	return plans, nil
+}
+
+// Update updates a floor plan and replaces its rooms in one transaction
+func (r *SQLiteFloorPlanRepository) Update(ctx context.Context, plan *domain.FloorPlan) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE floor_plans SET
+			name = ?, description = ?, image_path = ?, width = ?, height = ?, updated_at = ?
+		WHERE id = ?`
+
+	_, err = tx.ExecContext(ctx, query,
+		plan.Name, plan.Description, plan.ImagePath, plan.Width, plan.Height, plan.UpdatedAt,
+		plan.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update floor plan: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM rooms WHERE floor_plan_id = ?`, plan.ID); err != nil {
+		return fmt.Errorf("failed to delete rooms: %w", err)
+	}
+
+	if err := insertRooms(ctx, tx, plan); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Delete deletes a floor plan; rooms and placements cascade
+func (r *SQLiteFloorPlanRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM floor_plans WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+func (r *SQLiteFloorPlanRepository) getRooms(ctx context.Context, floorPlanID string) ([]domain.Room, error) {
+	query := `SELECT id, floor_plan_id, name, polygon FROM rooms WHERE floor_plan_id = ? ORDER BY name ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, floorPlanID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rooms []domain.Room
+	for rows.Next() {
+		var room domain.Room
+		var polygonJSON string
+
+		if err := rows.Scan(&room.ID, &room.FloorPlanID, &room.Name, &polygonJSON); err != nil {
+			return nil, err
+		}
+
+		json.Unmarshal([]byte(polygonJSON), &room.Polygon)
+		rooms = append(rooms, room)
+	}
+
+	return rooms, rows.Err()
+}
+
+func insertRooms(ctx context.Context, tx *sql.Tx, plan *domain.FloorPlan) error {
+	query := `INSERT INTO rooms (id, floor_plan_id, name, polygon) VALUES (?, ?, ?, ?)`
+
+	for _, room := range plan.Rooms {
+		polygonJSON, _ := json.Marshal(room.Polygon)
+
+		if _, err := tx.ExecContext(ctx, query, room.ID, plan.ID, room.Name, polygonJSON); err != nil {
+			return fmt.Errorf("failed to insert room %s: %w", room.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// SQLiteDevicePlacementRepository implements DevicePlacementRepository using SQLite
+type SQLiteDevicePlacementRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteDevicePlacementRepository creates a new SQLite device placement repository
+func NewSQLiteDevicePlacementRepository(db *sql.DB) *SQLiteDevicePlacementRepository {
+	return &SQLiteDevicePlacementRepository{db: db}
+}
+
+// Create inserts a new device placement
+func (r *SQLiteDevicePlacementRepository) Create(ctx context.Context, placement *domain.DevicePlacement) error {
+	query := `
+		INSERT INTO device_placements (
+			id, session_id, floor_plan_id, device_id, origin_x, origin_y, origin_z,
+			rotation, placed_at, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		placement.ID, placement.SessionID, placement.FloorPlanID, placement.DeviceID,
+		placement.Origin.X, placement.Origin.Y, placement.Origin.Z,
+		placement.Rotation, placement.PlacedAt, placement.CreatedAt,
+	)
+
+	return err
+}
+
+// GetByID retrieves a device placement by ID
+func (r *SQLiteDevicePlacementRepository) GetByID(ctx context.Context, id string) (*domain.DevicePlacement, error) {
+	query := `
+		SELECT id, session_id, floor_plan_id, device_id, origin_x, origin_y, origin_z,
+			rotation, placed_at, created_at
+		FROM device_placements WHERE id = ?`
+
+	var placement domain.DevicePlacement
+
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&placement.ID, &placement.SessionID, &placement.FloorPlanID, &placement.DeviceID,
+		&placement.Origin.X, &placement.Origin.Y, &placement.Origin.Z,
+		&placement.Rotation, &placement.PlacedAt, &placement.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &placement, nil
+}
+
+// GetBySessionID retrieves device placements by session ID, oldest first
+func (r *SQLiteDevicePlacementRepository) GetBySessionID(ctx context.Context, sessionID string) ([]*domain.DevicePlacement, error) {
+	query := `
+		SELECT id, session_id, floor_plan_id, device_id, origin_x, origin_y, origin_z,
+			rotation, placed_at, created_at
+		FROM device_placements WHERE session_id = ? ORDER BY placed_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var placements []*domain.DevicePlacement
+	for rows.Next() {
+		var placement domain.DevicePlacement
+
+		err := rows.Scan(
+			&placement.ID, &placement.SessionID, &placement.FloorPlanID, &placement.DeviceID,
+			&placement.Origin.X, &placement.Origin.Y, &placement.Origin.Z,
+			&placement.Rotation, &placement.PlacedAt, &placement.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		placements = append(placements, &placement)
+	}
+
+	return placements, rows.Err()
+}
+
+// Delete deletes a device placement
+func (r *SQLiteDevicePlacementRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM device_placements WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteFloorPlanRepository_Update_ReplacesRooms(t *testing.T) {
+	// Arrange
+	db := setupMigratedTestDB(t)
+	defer cleanupTestDB(db)
+	repo := NewSQLiteFloorPlanRepository(db)
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Second)
+	plan := &domain.FloorPlan{
+		ID:        "plan-1",
+		Name:      "Ground floor",
+		Width:     10,
+		Height:    5,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Rooms: []domain.Room{
+			{ID: "hall", Name: "Hall", Polygon: []domain.Coordinates{{X: 0, Y: 0}, {X: 5, Y: 0}, {X: 5, Y: 5}}},
+		},
+	}
+	require.NoError(t, repo.Create(ctx, plan))
+
+	plan.ImagePath = "floorplans/plan-1/plan.png"
+	plan.Rooms = []domain.Room{
+		{ID: "kitchen", Name: "Kitchen", Polygon: []domain.Coordinates{{X: 5, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 5}}},
+	}
+
+	// Act
+	err := repo.Update(ctx, plan)
+
+	// Assert
+	require.NoError(t, err)
+	stored, err := repo.GetByID(ctx, "plan-1")
+	require.NoError(t, err)
+	assert.Equal(t, "floorplans/plan-1/plan.png", stored.ImagePath)
+	require.Len(t, stored.Rooms, 1)
+	assert.Equal(t, "kitchen", stored.Rooms[0].ID)
+	assert.Equal(t, "plan-1", stored.Rooms[0].FloorPlanID)
+	assert.Equal(t, plan.Rooms[0].Polygon, stored.Rooms[0].Polygon)
+}
+
+func TestSQLiteDevicePlacementRepository_GetBySessionID_OrdersByPlacedAt(t *testing.T) {
+	// Arrange
+	db := setupMigratedTestDB(t)
+	defer cleanupTestDB(db)
+	repo := NewSQLiteDevicePlacementRepository(db)
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Second)
+	later := &domain.DevicePlacement{
+		ID: "p2", SessionID: "session-1", FloorPlanID: "plan-1", DeviceID: "phone",
+		Origin: domain.Coordinates{X: 7, Y: 2}, PlacedAt: now.Add(time.Minute), CreatedAt: now,
+	}
+	earlier := &domain.DevicePlacement{
+		ID: "p1", SessionID: "session-1", FloorPlanID: "plan-1", DeviceID: "phone",
+		Origin: domain.Coordinates{X: 2, Y: 2, Z: 1}, Rotation: 1.5, PlacedAt: now, CreatedAt: now,
+	}
+	require.NoError(t, repo.Create(ctx, later))
+	require.NoError(t, repo.Create(ctx, earlier))
+
+	// Act
+	placements, err := repo.GetBySessionID(ctx, "session-1")
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, placements, 2)
+	assert.Equal(t, "p1", placements[0].ID)
+	assert.Equal(t, earlier.Origin, placements[0].Origin)
+	assert.Equal(t, 1.5, placements[0].Rotation)
+	assert.Equal(t, "p2", placements[1].ID)
+}
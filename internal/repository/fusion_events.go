@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/myideascope/otherside/internal/domain"
+)
+
+// SQLiteFusionEventRepository implements FusionEventRepository using SQLite
+type SQLiteFusionEventRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteFusionEventRepository creates a new SQLite fusion event repository
+func NewSQLiteFusionEventRepository(db *sql.DB) *SQLiteFusionEventRepository {
+	return &SQLiteFusionEventRepository{db: db}
+}
+
+// ReplaceBySessionID replaces all stored fusion events of a session in one transaction
+func (r *SQLiteFusionEventRepository) ReplaceBySessionID(ctx context.Context, sessionID string, events []*domain.FusionEvent) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM fusion_events WHERE session_id = ?`, sessionID); err != nil {
+		return fmt.Errorf("failed to delete fusion events: %w", err)
+	}
+
+	query := `
+		INSERT INTO fusion_events (
+			id, session_id, rule, start_time, end_time, contributors, confidence, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+	for _, event := range events {
+		contributorsJSON, _ := json.Marshal(event.Contributors)
+
+		_, err := tx.ExecContext(ctx, query,
+			event.ID, sessionID, event.Rule, event.StartTime, event.EndTime,
+			contributorsJSON, event.Confidence, event.CreatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert fusion event %s: %w", event.ID, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetByID retrieves a fusion event by ID
+func (r *SQLiteFusionEventRepository) GetByID(ctx context.Context, id string) (*domain.FusionEvent, error) {
+	query := `
+		SELECT id, session_id, rule, start_time, end_time, contributors, confidence, created_at
+		FROM fusion_events WHERE id = ?`
+
+	var event domain.FusionEvent
+	var contributorsJSON string
+
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&event.ID, &event.SessionID, &event.Rule, &event.StartTime, &event.EndTime,
+		&contributorsJSON, &event.Confidence, &event.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	json.Unmarshal([]byte(contributorsJSON), &event.Contributors)
+
+	return &event, nil
+}
+
+// GetBySessionID retrieves fusion events by session ID
+func (r *SQLiteFusionEventRepository) GetBySessionID(ctx context.Context, sessionID string) ([]*domain.FusionEvent, error) {
+	query := `
+		SELECT id, session_id, rule, start_time, end_time, contributors, confidence, created_at
+		FROM fusion_events WHERE session_id = ? ORDER BY start_time ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*domain.FusionEvent
+	for rows.Next() {
+		var event domain.FusionEvent
+		var contributorsJSON string
+
+		err := rows.Scan(
+			&event.ID, &event.SessionID, &event.Rule, &event.StartTime, &event.EndTime,
+			&contributorsJSON, &event.Confidence, &event.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		json.Unmarshal([]byte(contributorsJSON), &event.Contributors)
+
+		events = append(events, &event)
+	}
+
+	return events, rows.Err()
+}
+
+// DeleteBySessionID deletes all fusion events of a session
+func (r *SQLiteFusionEventRepository) DeleteBySessionID(ctx context.Context, sessionID string) error {
+	query := `DELETE FROM fusion_events WHERE session_id = ?`
+	_, err := r.db.ExecContext(ctx, query, sessionID)
+	return err
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteFusionEventRepository_ReplaceBySessionID_StoresContributors(t *testing.T) {
+	// Arrange
+	db := setupMigratedTestDB(t)
+	defer cleanupTestDB(db)
+	repo := NewSQLiteFusionEventRepository(db)
+	ctx := context.Background()
+
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	event := &domain.FusionEvent{
+		ID:        "fusion-1",
+		SessionID: "session-1",
+		Rule:      "radar_sls",
+		StartTime: start,
+		EndTime:   start.Add(2 * time.Second),
+		Contributors: []domain.FusionContributor{
+			{EventType: "radar", EventID: "r1", Timestamp: start, Confidence: 0.5},
+			{EventType: "sls", EventID: "s1", Timestamp: start.Add(time.Second), Confidence: 0.8},
+		},
+		Confidence: 0.9,
+		CreatedAt:  start,
+	}
+
+	// Act
+	require.NoError(t, repo.ReplaceBySessionID(ctx, "session-1", []*domain.FusionEvent{event}))
+	events, err := repo.GetBySessionID(ctx, "session-1")
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "radar_sls", events[0].Rule)
+	require.Len(t, events[0].Contributors, 2)
+	assert.Equal(t, "s1", events[0].Contributors[1].EventID)
+	assert.True(t, event.Contributors[1].Timestamp.Equal(events[0].Contributors[1].Timestamp))
+	assert.Equal(t, 0.9, events[0].Confidence)
+}
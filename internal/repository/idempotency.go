@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+)
+
+// SQLiteIdempotencyRepository implements IdempotencyRepository using SQLite
+type SQLiteIdempotencyRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteIdempotencyRepository creates a new SQLite idempotency repository
+func NewSQLiteIdempotencyRepository(db *sql.DB) *SQLiteIdempotencyRepository {
+	return &SQLiteIdempotencyRepository{db: db}
+}
+
+// Claim stores a pending record for a key. It returns false, and leaves the
+// stored record alone, when the principal already used the key.
+func (r *SQLiteIdempotencyRepository) Claim(ctx context.Context, record *domain.IdempotencyRecord) (bool, error) {
+	query := `
+		INSERT INTO idempotency_keys (principal, idempotency_key, method, path, request_hash, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (principal, idempotency_key) DO NOTHING`
+
+	result, err := r.db.ExecContext(ctx, query,
+		record.Principal, record.Key, record.Method, record.Path, record.RequestHash, record.CreatedAt,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
+
+// Get retrieves the record a principal stored for a key
+func (r *SQLiteIdempotencyRepository) Get(ctx context.Context, principal, key string) (*domain.IdempotencyRecord, error) {
+	query := `
+		SELECT principal, idempotency_key, method, path, request_hash, status_code,
+			COALESCE(content_type, ''), response_body, created_at, completed_at
+		FROM idempotency_keys WHERE principal = ? AND idempotency_key = ?`
+
+	var record domain.IdempotencyRecord
+	var completedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, query, principal, key).Scan(
+		&record.Principal, &record.Key, &record.Method, &record.Path, &record.RequestHash, &record.StatusCode,
+		&record.ContentType, &record.ResponseBody, &record.CreatedAt, &completedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if completedAt.Valid {
+		record.CompletedAt = &completedAt.Time
+	}
+
+	return &record, nil
+}
+
+// Complete stores the response to a claimed key
+func (r *SQLiteIdempotencyRepository) Complete(ctx context.Context, record *domain.IdempotencyRecord) error {
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = ?, content_type = NULLIF(?, ''), response_body = ?, completed_at = ?
+		WHERE principal = ? AND idempotency_key = ?`
+
+	result, err := r.db.ExecContext(ctx, query,
+		record.StatusCode, record.ContentType, record.ResponseBody, record.CompletedAt,
+		record.Principal, record.Key,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// Release forgets a key so that the request can be sent again
+func (r *SQLiteIdempotencyRepository) Release(ctx context.Context, principal, key string) error {
+	_, err := r.db.ExecContext(ctx,
+		`DELETE FROM idempotency_keys WHERE principal = ? AND idempotency_key = ?`, principal, key)
+	return err
+}
+
+// DeleteBefore removes records created before a time and returns how many
+// were removed
+func (r *SQLiteIdempotencyRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE created_at < ?`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
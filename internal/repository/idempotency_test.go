@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteIdempotencyRepository_Claim_SecondClaim_KeepsFirstRecord(t *testing.T) {
+	// Arrange
+	db := setupMigratedTestDB(t)
+	defer cleanupTestDB(db)
+	repo := NewSQLiteIdempotencyRepository(db)
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Second)
+	record := &domain.IdempotencyRecord{
+		Principal: "investigator:inv-1", Key: "sync-1", Method: "POST", Path: "/api/v1/sessions",
+		RequestHash: "first", CreatedAt: now,
+	}
+
+	// Act
+	firstClaim, err := repo.Claim(ctx, record)
+	require.NoError(t, err)
+	secondClaim, err := repo.Claim(ctx, &domain.IdempotencyRecord{
+		Principal: "investigator:inv-1", Key: "sync-1", Method: "POST", Path: "/api/v1/sessions",
+		RequestHash: "second", CreatedAt: now,
+	})
+	require.NoError(t, err)
+	completedAt := now.Add(time.Second)
+	require.NoError(t, repo.Complete(ctx, &domain.IdempotencyRecord{
+		Principal: "investigator:inv-1", Key: "sync-1", StatusCode: 201,
+		ContentType: "application/json", ResponseBody: []byte(`{"id":"1"}`), CompletedAt: &completedAt,
+	}))
+	stored, err := repo.Get(ctx, "investigator:inv-1", "sync-1")
+	require.NoError(t, err)
+	purged, err := repo.DeleteBefore(ctx, now.Add(time.Hour))
+	require.NoError(t, err)
+
+	// Assert
+	assert.True(t, firstClaim)
+	assert.False(t, secondClaim)
+	assert.Equal(t, "first", stored.RequestHash)
+	assert.Equal(t, 201, stored.StatusCode)
+	assert.Equal(t, []byte(`{"id":"1"}`), stored.ResponseBody)
+	assert.True(t, stored.IsComplete())
+	assert.Equal(t, int64(1), purged)
+	_, err = repo.Get(ctx, "investigator:inv-1", "sync-1")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
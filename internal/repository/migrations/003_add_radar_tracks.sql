@@ -0,0 +1,21 @@
+-- Migration: 003_add_radar_tracks
+-- Radar tracks associate consecutive radar events into movement paths
+
+CREATE TABLE IF NOT EXISTS radar_tracks (
+    id TEXT PRIMARY KEY,
+    session_id TEXT NOT NULL,
+    event_ids TEXT NOT NULL, -- JSON array of radar event IDs in track order
+    points TEXT NOT NULL, -- JSON array of coordinate positions
+    start_time DATETIME NOT NULL,
+    end_time DATETIME NOT NULL,
+    dwell_time REAL NOT NULL,
+    distance REAL NOT NULL,
+    velocity REAL NOT NULL,
+    heading REAL NOT NULL,
+    mean_strength REAL NOT NULL,
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_radar_tracks_session_id ON radar_tracks(session_id);
+CREATE INDEX IF NOT EXISTS idx_radar_tracks_start_time ON radar_tracks(start_time);
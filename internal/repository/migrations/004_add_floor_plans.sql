@@ -0,0 +1,40 @@
+-- Migration: 004_add_floor_plans
+-- Floor plans and device placements relate device-relative positions to a building
+
+CREATE TABLE IF NOT EXISTS floor_plans (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    description TEXT,
+    image_path TEXT,
+    width REAL NOT NULL DEFAULT 0,
+    height REAL NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS rooms (
+    id TEXT PRIMARY KEY,
+    floor_plan_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    polygon TEXT NOT NULL, -- JSON array of coordinates in metres
+    FOREIGN KEY (floor_plan_id) REFERENCES floor_plans(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS device_placements (
+    id TEXT PRIMARY KEY,
+    session_id TEXT NOT NULL,
+    floor_plan_id TEXT NOT NULL,
+    device_id TEXT NOT NULL,
+    origin_x REAL NOT NULL,
+    origin_y REAL NOT NULL,
+    origin_z REAL NOT NULL DEFAULT 0,
+    rotation REAL NOT NULL DEFAULT 0, -- radians from building +X to device +X
+    placed_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE,
+    FOREIGN KEY (floor_plan_id) REFERENCES floor_plans(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_rooms_floor_plan_id ON rooms(floor_plan_id);
+CREATE INDEX IF NOT EXISTS idx_device_placements_session_id ON device_placements(session_id);
+CREATE INDEX IF NOT EXISTS idx_device_placements_placed_at ON device_placements(placed_at);
@@ -0,0 +1,18 @@
+-- Migration: 005_add_fusion_events
+-- Fusion events correlate EVP, radar, SLS and EMF activity within a time window
+
+CREATE TABLE IF NOT EXISTS fusion_events (
+    id TEXT PRIMARY KEY,
+    session_id TEXT NOT NULL,
+    rule TEXT NOT NULL,
+    start_time DATETIME NOT NULL,
+    end_time DATETIME NOT NULL,
+    contributors TEXT NOT NULL, -- JSON array of contributing event references
+    confidence REAL NOT NULL,
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_fusion_events_session_id ON fusion_events(session_id);
+CREATE INDEX IF NOT EXISTS idx_fusion_events_start_time ON fusion_events(start_time);
+CREATE INDEX IF NOT EXISTS idx_fusion_events_confidence ON fusion_events(confidence);
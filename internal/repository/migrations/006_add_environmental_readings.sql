@@ -0,0 +1,33 @@
+-- Migration: 006_add_environmental_readings
+-- Continuous environmental sensor time series and baseline-deviation anomalies
+
+CREATE TABLE IF NOT EXISTS environmental_readings (
+    id TEXT PRIMARY KEY,
+    session_id TEXT NOT NULL,
+    device_id TEXT NOT NULL,
+    timestamp DATETIME NOT NULL, -- stored in UTC
+    metric TEXT NOT NULL CHECK (metric IN ('temperature', 'humidity', 'pressure', 'emf', 'light', 'noise')),
+    value REAL NOT NULL,
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS environmental_anomalies (
+    id TEXT PRIMARY KEY,
+    session_id TEXT NOT NULL,
+    reading_id TEXT NOT NULL,
+    device_id TEXT NOT NULL,
+    metric TEXT NOT NULL,
+    timestamp DATETIME NOT NULL,
+    value REAL NOT NULL,
+    baseline REAL NOT NULL,
+    std_dev REAL NOT NULL,
+    deviation REAL NOT NULL, -- signed deviation in standard deviations
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_environmental_readings_session_metric_time ON environmental_readings(session_id, metric, timestamp);
+CREATE INDEX IF NOT EXISTS idx_environmental_readings_device_id ON environmental_readings(device_id);
+CREATE INDEX IF NOT EXISTS idx_environmental_anomalies_session_id ON environmental_anomalies(session_id);
+CREATE INDEX IF NOT EXISTS idx_environmental_anomalies_timestamp ON environmental_anomalies(timestamp);
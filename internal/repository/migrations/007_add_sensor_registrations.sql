@@ -0,0 +1,12 @@
+-- Migration: 007_add_sensor_registrations
+-- Maps standalone sensors pushing over the ingest listener to a session
+
+CREATE TABLE IF NOT EXISTS sensor_registrations (
+    device_id TEXT PRIMARY KEY,
+    session_id TEXT NOT NULL,
+    name TEXT,
+    registered_at DATETIME NOT NULL,
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_sensor_registrations_session_id ON sensor_registrations(session_id);
@@ -0,0 +1,14 @@
+-- Migration: 008_add_session_status_history
+-- Records every lifecycle transition of a session
+
+CREATE TABLE IF NOT EXISTS session_status_history (
+    id TEXT PRIMARY KEY,
+    session_id TEXT NOT NULL,
+    from_status TEXT NOT NULL CHECK (from_status IN ('active', 'paused', 'complete', 'archived')),
+    to_status TEXT NOT NULL CHECK (to_status IN ('active', 'paused', 'complete', 'archived')),
+    reason TEXT,
+    changed_at DATETIME NOT NULL,
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_session_status_history_session_id ON session_status_history(session_id, changed_at);
@@ -0,0 +1,48 @@
+-- Migration: 009_add_participants
+-- Investigators, devices and session participants, and per-event attribution
+
+CREATE TABLE IF NOT EXISTS investigators (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    email TEXT,
+    created_at DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS devices (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    kind TEXT,
+    serial_number TEXT,
+    created_at DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS session_participants (
+    session_id TEXT NOT NULL,
+    investigator_id TEXT NOT NULL,
+    role TEXT NOT NULL CHECK (role IN ('lead', 'investigator', 'observer')),
+    joined_at DATETIME NOT NULL,
+    PRIMARY KEY (session_id, investigator_id),
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE,
+    FOREIGN KEY (investigator_id) REFERENCES investigators(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_session_participants_investigator_id ON session_participants(investigator_id);
+
+-- Events keep their attribution optional; device IDs are not constrained
+-- because sensors may report before they are catalogued
+ALTER TABLE evp_recordings ADD COLUMN investigator_id TEXT REFERENCES investigators(id) ON DELETE SET NULL;
+ALTER TABLE evp_recordings ADD COLUMN device_id TEXT;
+ALTER TABLE vox_events ADD COLUMN investigator_id TEXT REFERENCES investigators(id) ON DELETE SET NULL;
+ALTER TABLE vox_events ADD COLUMN device_id TEXT;
+ALTER TABLE radar_events ADD COLUMN investigator_id TEXT REFERENCES investigators(id) ON DELETE SET NULL;
+ALTER TABLE radar_events ADD COLUMN device_id TEXT;
+ALTER TABLE sls_detections ADD COLUMN investigator_id TEXT REFERENCES investigators(id) ON DELETE SET NULL;
+ALTER TABLE sls_detections ADD COLUMN device_id TEXT;
+ALTER TABLE user_interactions ADD COLUMN investigator_id TEXT REFERENCES investigators(id) ON DELETE SET NULL;
+ALTER TABLE user_interactions ADD COLUMN device_id TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_evp_recordings_investigator_id ON evp_recordings(investigator_id);
+CREATE INDEX IF NOT EXISTS idx_vox_events_investigator_id ON vox_events(investigator_id);
+CREATE INDEX IF NOT EXISTS idx_radar_events_investigator_id ON radar_events(investigator_id);
+CREATE INDEX IF NOT EXISTS idx_sls_detections_investigator_id ON sls_detections(investigator_id);
+CREATE INDEX IF NOT EXISTS idx_user_interactions_investigator_id ON user_interactions(investigator_id);
@@ -0,0 +1,15 @@
+-- Migration: 010_add_api_keys
+-- API keys for devices and investigators. Only a hash of each key is stored.
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    key_hash TEXT NOT NULL,
+    principal_kind TEXT NOT NULL CHECK (principal_kind IN ('device', 'investigator')),
+    principal_id TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    last_used_at DATETIME,
+    revoked_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_principal ON api_keys(principal_kind, principal_id);
@@ -0,0 +1,48 @@
+-- Migration: 011_add_access_control
+-- Teams, per-session owners, teams and read-only guests, and export ownership
+
+CREATE TABLE IF NOT EXISTS teams (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    created_at DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS team_members (
+    team_id TEXT NOT NULL,
+    investigator_id TEXT NOT NULL,
+    joined_at DATETIME NOT NULL,
+    PRIMARY KEY (team_id, investigator_id),
+    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE,
+    FOREIGN KEY (investigator_id) REFERENCES investigators(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_members_investigator_id ON team_members(investigator_id);
+
+-- Sessions without a row here, or without an owner, are open to everyone
+CREATE TABLE IF NOT EXISTS session_acl (
+    session_id TEXT PRIMARY KEY,
+    owner_id TEXT REFERENCES investigators(id) ON DELETE SET NULL,
+    team_id TEXT REFERENCES teams(id) ON DELETE SET NULL,
+    updated_at DATETIME NOT NULL,
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_session_acl_owner_id ON session_acl(owner_id);
+CREATE INDEX IF NOT EXISTS idx_session_acl_team_id ON session_acl(team_id);
+
+CREATE TABLE IF NOT EXISTS session_guests (
+    session_id TEXT NOT NULL,
+    investigator_id TEXT NOT NULL,
+    granted_at DATETIME NOT NULL,
+    PRIMARY KEY (session_id, investigator_id),
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE,
+    FOREIGN KEY (investigator_id) REFERENCES investigators(id) ON DELETE CASCADE
+);
+
+-- Exports without a record predate access control and stay open
+CREATE TABLE IF NOT EXISTS export_records (
+    filename TEXT PRIMARY KEY,
+    created_by TEXT,
+    session_ids TEXT NOT NULL,
+    created_at DATETIME NOT NULL
+);
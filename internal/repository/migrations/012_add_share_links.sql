@@ -0,0 +1,19 @@
+-- Migration: 012_add_share_links
+-- Expiring read-only links to one session for people without an account.
+-- The link token is signed and not stored; event_types and evp_ids are JSON
+-- arrays, empty for no restriction.
+
+CREATE TABLE IF NOT EXISTS share_links (
+    id TEXT PRIMARY KEY,
+    session_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    event_types TEXT NOT NULL,
+    evp_ids TEXT NOT NULL,
+    created_by TEXT,
+    created_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,
+    revoked_at DATETIME,
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_share_links_session_id ON share_links(session_id);
@@ -0,0 +1,21 @@
+-- Migration: 013_add_idempotency_keys
+-- Responses to requests sent with an Idempotency-Key header, so that a
+-- retried request is answered from here instead of running twice. Keys are
+-- scoped to the principal that sent them; a row without completed_at is a
+-- request still in progress.
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    principal TEXT NOT NULL,
+    idempotency_key TEXT NOT NULL,
+    method TEXT NOT NULL,
+    path TEXT NOT NULL,
+    request_hash TEXT NOT NULL,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    content_type TEXT,
+    response_body BLOB,
+    created_at DATETIME NOT NULL,
+    completed_at DATETIME,
+    PRIMARY KEY (principal, idempotency_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at ON idempotency_keys(created_at);
@@ -0,0 +1,153 @@
+-- Migration: 014_add_change_feed
+-- A change sequence across sessions and their events for clients syncing
+-- several devices. Triggers keep one row per record holding its latest
+-- change, so seq only grows and a deleted record leaves a tombstone.
+-- Tombstones are purged after a while; change_feed_state remembers the
+-- highest purged seq so that clients with older cursors know to resync.
+
+CREATE TABLE IF NOT EXISTS changes (
+    seq INTEGER PRIMARY KEY AUTOINCREMENT,
+    entity_type TEXT NOT NULL,
+    entity_id TEXT NOT NULL,
+    session_id TEXT NOT NULL,
+    operation TEXT NOT NULL,
+    changed_at DATETIME NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_changes_entity ON changes(entity_type, entity_id);
+CREATE INDEX IF NOT EXISTS idx_changes_session_id ON changes(session_id, seq);
+
+CREATE TABLE IF NOT EXISTS change_feed_state (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    purged_through INTEGER NOT NULL
+);
+
+INSERT OR IGNORE INTO change_feed_state (id, purged_through) VALUES (1, 0);
+
+-- sessions
+CREATE TRIGGER IF NOT EXISTS changes_sessions_insert AFTER INSERT ON sessions
+BEGIN
+    INSERT OR REPLACE INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('session', NEW.id, NEW.id, 'upsert', CURRENT_TIMESTAMP);
+END;
+
+CREATE TRIGGER IF NOT EXISTS changes_sessions_update AFTER UPDATE ON sessions
+BEGIN
+    INSERT OR REPLACE INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('session', NEW.id, NEW.id, 'upsert', CURRENT_TIMESTAMP);
+END;
+
+CREATE TRIGGER IF NOT EXISTS changes_sessions_delete AFTER DELETE ON sessions
+BEGIN
+    INSERT OR REPLACE INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('session', OLD.id, OLD.id, 'delete', CURRENT_TIMESTAMP);
+END;
+
+-- evp_recordings
+CREATE TRIGGER IF NOT EXISTS changes_evp_recordings_insert AFTER INSERT ON evp_recordings
+BEGIN
+    INSERT OR REPLACE INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('evp', NEW.id, NEW.session_id, 'upsert', CURRENT_TIMESTAMP);
+END;
+
+CREATE TRIGGER IF NOT EXISTS changes_evp_recordings_update AFTER UPDATE ON evp_recordings
+BEGIN
+    INSERT OR REPLACE INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('evp', NEW.id, NEW.session_id, 'upsert', CURRENT_TIMESTAMP);
+END;
+
+CREATE TRIGGER IF NOT EXISTS changes_evp_recordings_delete AFTER DELETE ON evp_recordings
+BEGIN
+    INSERT OR REPLACE INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('evp', OLD.id, OLD.session_id, 'delete', CURRENT_TIMESTAMP);
+END;
+
+-- vox_events
+CREATE TRIGGER IF NOT EXISTS changes_vox_events_insert AFTER INSERT ON vox_events
+BEGIN
+    INSERT OR REPLACE INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('vox', NEW.id, NEW.session_id, 'upsert', CURRENT_TIMESTAMP);
+END;
+
+CREATE TRIGGER IF NOT EXISTS changes_vox_events_update AFTER UPDATE ON vox_events
+BEGIN
+    INSERT OR REPLACE INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('vox', NEW.id, NEW.session_id, 'upsert', CURRENT_TIMESTAMP);
+END;
+
+CREATE TRIGGER IF NOT EXISTS changes_vox_events_delete AFTER DELETE ON vox_events
+BEGIN
+    INSERT OR REPLACE INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('vox', OLD.id, OLD.session_id, 'delete', CURRENT_TIMESTAMP);
+END;
+
+-- radar_events
+CREATE TRIGGER IF NOT EXISTS changes_radar_events_insert AFTER INSERT ON radar_events
+BEGIN
+    INSERT OR REPLACE INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('radar', NEW.id, NEW.session_id, 'upsert', CURRENT_TIMESTAMP);
+END;
+
+CREATE TRIGGER IF NOT EXISTS changes_radar_events_update AFTER UPDATE ON radar_events
+BEGIN
+    INSERT OR REPLACE INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('radar', NEW.id, NEW.session_id, 'upsert', CURRENT_TIMESTAMP);
+END;
+
+CREATE TRIGGER IF NOT EXISTS changes_radar_events_delete AFTER DELETE ON radar_events
+BEGIN
+    INSERT OR REPLACE INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('radar', OLD.id, OLD.session_id, 'delete', CURRENT_TIMESTAMP);
+END;
+
+-- sls_detections
+CREATE TRIGGER IF NOT EXISTS changes_sls_detections_insert AFTER INSERT ON sls_detections
+BEGIN
+    INSERT OR REPLACE INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('sls', NEW.id, NEW.session_id, 'upsert', CURRENT_TIMESTAMP);
+END;
+
+CREATE TRIGGER IF NOT EXISTS changes_sls_detections_update AFTER UPDATE ON sls_detections
+BEGIN
+    INSERT OR REPLACE INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('sls', NEW.id, NEW.session_id, 'upsert', CURRENT_TIMESTAMP);
+END;
+
+CREATE TRIGGER IF NOT EXISTS changes_sls_detections_delete AFTER DELETE ON sls_detections
+BEGIN
+    INSERT OR REPLACE INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('sls', OLD.id, OLD.session_id, 'delete', CURRENT_TIMESTAMP);
+END;
+
+-- user_interactions
+CREATE TRIGGER IF NOT EXISTS changes_user_interactions_insert AFTER INSERT ON user_interactions
+BEGIN
+    INSERT OR REPLACE INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('interaction', NEW.id, NEW.session_id, 'upsert', CURRENT_TIMESTAMP);
+END;
+
+CREATE TRIGGER IF NOT EXISTS changes_user_interactions_update AFTER UPDATE ON user_interactions
+BEGIN
+    INSERT OR REPLACE INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('interaction', NEW.id, NEW.session_id, 'upsert', CURRENT_TIMESTAMP);
+END;
+
+CREATE TRIGGER IF NOT EXISTS changes_user_interactions_delete AFTER DELETE ON user_interactions
+BEGIN
+    INSERT OR REPLACE INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('interaction', OLD.id, OLD.session_id, 'delete', CURRENT_TIMESTAMP);
+END;
+
+-- Existing records start the feed
+INSERT OR IGNORE INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+SELECT 'session', id, id, 'upsert', CURRENT_TIMESTAMP FROM sessions;
+INSERT OR IGNORE INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+SELECT 'evp', id, session_id, 'upsert', CURRENT_TIMESTAMP FROM evp_recordings;
+INSERT OR IGNORE INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+SELECT 'vox', id, session_id, 'upsert', CURRENT_TIMESTAMP FROM vox_events;
+INSERT OR IGNORE INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+SELECT 'radar', id, session_id, 'upsert', CURRENT_TIMESTAMP FROM radar_events;
+INSERT OR IGNORE INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+SELECT 'sls', id, session_id, 'upsert', CURRENT_TIMESTAMP FROM sls_detections;
+INSERT OR IGNORE INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+SELECT 'interaction', id, session_id, 'upsert', CURRENT_TIMESTAMP FROM user_interactions;
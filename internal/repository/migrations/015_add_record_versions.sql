@@ -0,0 +1,8 @@
+-- Migration: 015_add_record_versions
+-- Version numbers for records that can be edited after they are created.
+-- Every update must name the version it was based on and bumps it, so two
+-- investigators editing the same record cannot overwrite each other.
+
+ALTER TABLE sessions ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE evp_recordings ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE vox_events ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
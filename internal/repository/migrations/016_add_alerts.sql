@@ -0,0 +1,35 @@
+-- Migration: 016_add_alerts
+-- Alert rules evaluated against incoming events, and the alerts they
+-- raised. conditions and matches are JSON arrays; a rule without a
+-- session_id applies to every session. Alerts keep the rule name so they
+-- still read well after the rule is deleted.
+
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    session_id TEXT,
+    conditions TEXT NOT NULL,
+    window_seconds REAL NOT NULL DEFAULT 0,
+    debounce_seconds REAL NOT NULL DEFAULT 0,
+    webhook_url TEXT,
+    enabled INTEGER NOT NULL DEFAULT 1,
+    created_by TEXT,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_rules_session_id ON alert_rules(session_id);
+
+CREATE TABLE IF NOT EXISTS alerts (
+    id TEXT PRIMARY KEY,
+    rule_id TEXT NOT NULL,
+    rule_name TEXT NOT NULL,
+    session_id TEXT NOT NULL,
+    message TEXT NOT NULL,
+    matches TEXT NOT NULL,
+    triggered_at DATETIME NOT NULL,
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_alerts_session_id ON alerts(session_id, triggered_at);
@@ -0,0 +1,150 @@
+-- Migration: 018_add_search_index
+-- Full-text search over session titles and notes, EVP annotations, VOX
+-- generated text and interaction content and responses. Every indexed
+-- field of a record is one row of search_documents, whose docid is the
+-- docid of its text in the search_index full-text table. Triggers keep
+-- both in step with the records, and existing records are indexed here.
+-- The index is FTS4 because FTS5 is not compiled into the default
+-- go-sqlite3 build; annotations are indexed as their text values.
+
+CREATE TABLE IF NOT EXISTS search_documents (
+    docid INTEGER PRIMARY KEY AUTOINCREMENT,
+    entity_type TEXT NOT NULL,
+    entity_id TEXT NOT NULL,
+    field TEXT NOT NULL,
+    session_id TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_search_documents_entity ON search_documents(entity_type, entity_id, field);
+
+CREATE VIRTUAL TABLE IF NOT EXISTS search_index USING fts4(body, tokenize=unicode61 "remove_diacritics=1");
+
+-- sessions
+CREATE TRIGGER IF NOT EXISTS search_sessions_insert AFTER INSERT ON sessions
+BEGIN
+    INSERT INTO search_documents (entity_type, entity_id, field, session_id) VALUES ('session', NEW.id, 'title', NEW.id);
+    INSERT INTO search_index (docid, body) VALUES (last_insert_rowid(), NEW.title);
+    INSERT INTO search_documents (entity_type, entity_id, field, session_id) VALUES ('session', NEW.id, 'notes', NEW.id);
+    INSERT INTO search_index (docid, body) VALUES (last_insert_rowid(), COALESCE(NEW.notes, ''));
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_sessions_update AFTER UPDATE OF title, notes ON sessions
+BEGIN
+    UPDATE search_index SET body = NEW.title
+    WHERE docid = (SELECT docid FROM search_documents WHERE entity_type = 'session' AND entity_id = NEW.id AND field = 'title');
+    UPDATE search_index SET body = COALESCE(NEW.notes, '')
+    WHERE docid = (SELECT docid FROM search_documents WHERE entity_type = 'session' AND entity_id = NEW.id AND field = 'notes');
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_sessions_delete AFTER DELETE ON sessions
+BEGIN
+    DELETE FROM search_index WHERE docid IN (SELECT docid FROM search_documents WHERE entity_type = 'session' AND entity_id = OLD.id);
+    DELETE FROM search_documents WHERE entity_type = 'session' AND entity_id = OLD.id;
+END;
+
+-- evp_recordings
+CREATE TRIGGER IF NOT EXISTS search_evp_recordings_insert AFTER INSERT ON evp_recordings
+BEGIN
+    INSERT INTO search_documents (entity_type, entity_id, field, session_id) VALUES ('evp', NEW.id, 'annotations', NEW.session_id);
+    INSERT INTO search_index (docid, body) VALUES (last_insert_rowid(),
+        CASE WHEN json_valid(CAST(NEW.annotations AS TEXT))
+            THEN COALESCE((SELECT group_concat(value, ' | ') FROM json_each(CAST(NEW.annotations AS TEXT)) WHERE type = 'text'), '')
+            ELSE COALESCE(CAST(NEW.annotations AS TEXT), '')
+        END);
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_evp_recordings_update AFTER UPDATE OF annotations ON evp_recordings
+BEGIN
+    UPDATE search_index SET body =
+        CASE WHEN json_valid(CAST(NEW.annotations AS TEXT))
+            THEN COALESCE((SELECT group_concat(value, ' | ') FROM json_each(CAST(NEW.annotations AS TEXT)) WHERE type = 'text'), '')
+            ELSE COALESCE(CAST(NEW.annotations AS TEXT), '')
+        END
+    WHERE docid = (SELECT docid FROM search_documents WHERE entity_type = 'evp' AND entity_id = NEW.id AND field = 'annotations');
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_evp_recordings_delete AFTER DELETE ON evp_recordings
+BEGIN
+    DELETE FROM search_index WHERE docid IN (SELECT docid FROM search_documents WHERE entity_type = 'evp' AND entity_id = OLD.id);
+    DELETE FROM search_documents WHERE entity_type = 'evp' AND entity_id = OLD.id;
+END;
+
+-- vox_events
+CREATE TRIGGER IF NOT EXISTS search_vox_events_insert AFTER INSERT ON vox_events
+BEGIN
+    INSERT INTO search_documents (entity_type, entity_id, field, session_id) VALUES ('vox', NEW.id, 'generated_text', NEW.session_id);
+    INSERT INTO search_index (docid, body) VALUES (last_insert_rowid(), NEW.generated_text);
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_vox_events_update AFTER UPDATE OF generated_text ON vox_events
+BEGIN
+    UPDATE search_index SET body = NEW.generated_text
+    WHERE docid = (SELECT docid FROM search_documents WHERE entity_type = 'vox' AND entity_id = NEW.id AND field = 'generated_text');
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_vox_events_delete AFTER DELETE ON vox_events
+BEGIN
+    DELETE FROM search_index WHERE docid IN (SELECT docid FROM search_documents WHERE entity_type = 'vox' AND entity_id = OLD.id);
+    DELETE FROM search_documents WHERE entity_type = 'vox' AND entity_id = OLD.id;
+END;
+
+-- user_interactions
+CREATE TRIGGER IF NOT EXISTS search_user_interactions_insert AFTER INSERT ON user_interactions
+BEGIN
+    INSERT INTO search_documents (entity_type, entity_id, field, session_id) VALUES ('interaction', NEW.id, 'content', NEW.session_id);
+    INSERT INTO search_index (docid, body) VALUES (last_insert_rowid(), NEW.content);
+    INSERT INTO search_documents (entity_type, entity_id, field, session_id) VALUES ('interaction', NEW.id, 'response', NEW.session_id);
+    INSERT INTO search_index (docid, body) VALUES (last_insert_rowid(), COALESCE(NEW.response, ''));
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_user_interactions_update AFTER UPDATE OF content, response ON user_interactions
+BEGIN
+    UPDATE search_index SET body = NEW.content
+    WHERE docid = (SELECT docid FROM search_documents WHERE entity_type = 'interaction' AND entity_id = NEW.id AND field = 'content');
+    UPDATE search_index SET body = COALESCE(NEW.response, '')
+    WHERE docid = (SELECT docid FROM search_documents WHERE entity_type = 'interaction' AND entity_id = NEW.id AND field = 'response');
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_user_interactions_delete AFTER DELETE ON user_interactions
+BEGIN
+    DELETE FROM search_index WHERE docid IN (SELECT docid FROM search_documents WHERE entity_type = 'interaction' AND entity_id = OLD.id);
+    DELETE FROM search_documents WHERE entity_type = 'interaction' AND entity_id = OLD.id;
+END;
+
+-- Index the records stored before this migration
+INSERT INTO search_documents (entity_type, entity_id, field, session_id)
+SELECT 'session', id, 'title', id FROM sessions;
+INSERT INTO search_documents (entity_type, entity_id, field, session_id)
+SELECT 'session', id, 'notes', id FROM sessions;
+INSERT INTO search_documents (entity_type, entity_id, field, session_id)
+SELECT 'evp', id, 'annotations', session_id FROM evp_recordings;
+INSERT INTO search_documents (entity_type, entity_id, field, session_id)
+SELECT 'vox', id, 'generated_text', session_id FROM vox_events;
+INSERT INTO search_documents (entity_type, entity_id, field, session_id)
+SELECT 'interaction', id, 'content', session_id FROM user_interactions;
+INSERT INTO search_documents (entity_type, entity_id, field, session_id)
+SELECT 'interaction', id, 'response', session_id FROM user_interactions;
+
+INSERT INTO search_index (docid, body)
+SELECT d.docid, CASE d.field WHEN 'title' THEN s.title ELSE COALESCE(s.notes, '') END
+FROM search_documents d JOIN sessions s ON s.id = d.entity_id
+WHERE d.entity_type = 'session';
+
+INSERT INTO search_index (docid, body)
+SELECT d.docid,
+    CASE WHEN json_valid(CAST(e.annotations AS TEXT))
+        THEN COALESCE((SELECT group_concat(value, ' | ') FROM json_each(CAST(e.annotations AS TEXT)) WHERE type = 'text'), '')
+        ELSE COALESCE(CAST(e.annotations AS TEXT), '')
+    END
+FROM search_documents d JOIN evp_recordings e ON e.id = d.entity_id
+WHERE d.entity_type = 'evp';
+
+INSERT INTO search_index (docid, body)
+SELECT d.docid, v.generated_text
+FROM search_documents d JOIN vox_events v ON v.id = d.entity_id
+WHERE d.entity_type = 'vox';
+
+INSERT INTO search_index (docid, body)
+SELECT d.docid, CASE d.field WHEN 'content' THEN i.content ELSE COALESCE(i.response, '') END
+FROM search_documents d JOIN user_interactions i ON i.id = d.entity_id
+WHERE d.entity_type = 'interaction';
@@ -0,0 +1,32 @@
+-- Migration: 019_allow_files_without_session
+-- Files such as exports and uploaded audio belong to no session and were
+-- stored with an empty session_id, which fails the foreign key once it is
+-- enforced. Rebuild files with a nullable session_id, storing NULL for
+-- those files and dropping the rows of sessions that have been deleted.
+
+CREATE TABLE files_new (
+    id TEXT PRIMARY KEY,
+    session_id TEXT,
+    file_path TEXT NOT NULL,
+    file_type TEXT NOT NULL,
+    file_size INTEGER NOT NULL,
+    mime_type TEXT,
+    checksum TEXT,
+    created_at DATETIME NOT NULL,
+    last_accessed DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+
+INSERT INTO files_new (id, session_id, file_path, file_type, file_size, mime_type, checksum, created_at, last_accessed)
+SELECT id, NULLIF(session_id, ''), file_path, file_type, file_size, mime_type, checksum, created_at, last_accessed
+FROM files
+WHERE session_id = '' OR session_id IN (SELECT id FROM sessions);
+
+DROP TABLE files;
+ALTER TABLE files_new RENAME TO files;
+
+CREATE INDEX IF NOT EXISTS idx_files_session_id ON files(session_id);
+CREATE INDEX IF NOT EXISTS idx_files_file_type ON files(file_type);
+CREATE INDEX IF NOT EXISTS idx_files_created_at ON files(created_at);
+CREATE INDEX IF NOT EXISTS idx_files_file_size ON files(file_size);
+CREATE INDEX IF NOT EXISTS idx_files_last_accessed ON files(last_accessed);
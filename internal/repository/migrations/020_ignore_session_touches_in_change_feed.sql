@@ -0,0 +1,17 @@
+-- Migration: 020_ignore_session_touches_in_change_feed
+-- Saving session state touches every cached session's updated_at, which
+-- sent every active session to syncing clients each time. Only changes to
+-- a session's content now reach the change feed.
+
+DROP TRIGGER IF EXISTS changes_sessions_update;
+
+CREATE TRIGGER IF NOT EXISTS changes_sessions_update AFTER UPDATE OF
+    title, location_latitude, location_longitude, location_address, location_description, location_venue,
+    start_time, end_time, notes,
+    env_temperature, env_humidity, env_pressure, env_emf_level, env_light_level, env_noise_level,
+    status, version
+ON sessions
+BEGIN
+    INSERT OR REPLACE INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('session', NEW.id, NEW.id, 'upsert', CURRENT_TIMESTAMP);
+END;
@@ -0,0 +1,24 @@
+-- Migration: 021_protect_session_owners
+-- Admin investigators manage investigators and devices. Deleting a session's
+-- owner used to clear owner_id, which left the session open to everyone;
+-- rebuild session_acl so that an investigator who owns sessions cannot be
+-- deleted until they are handed over.
+
+ALTER TABLE investigators ADD COLUMN is_admin INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE session_acl_new (
+    session_id TEXT PRIMARY KEY,
+    owner_id TEXT REFERENCES investigators(id) ON DELETE RESTRICT,
+    team_id TEXT REFERENCES teams(id) ON DELETE SET NULL,
+    updated_at DATETIME NOT NULL,
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+
+INSERT INTO session_acl_new (session_id, owner_id, team_id, updated_at)
+SELECT session_id, owner_id, team_id, updated_at FROM session_acl;
+
+DROP TABLE session_acl;
+ALTER TABLE session_acl_new RENAME TO session_acl;
+
+CREATE INDEX IF NOT EXISTS idx_session_acl_owner_id ON session_acl(owner_id);
+CREATE INDEX IF NOT EXISTS idx_session_acl_team_id ON session_acl(team_id);
@@ -0,0 +1,141 @@
+-- Migration: 022_use_fts5_search_index
+-- Move the full-text index from FTS4 to FTS5, so that hits are ranked and
+-- highlighted by SQLite's own bm25() and snippet() and can be ordered in
+-- the query that matches them. FTS5 needs the server to be built with the
+-- sqlite_fts5 tag. search_documents is kept: the rowid of each text in the
+-- new search_index is the docid of its document, and the triggers are
+-- recreated to write rowids instead of docids.
+
+DROP TRIGGER IF EXISTS search_sessions_insert;
+DROP TRIGGER IF EXISTS search_sessions_update;
+DROP TRIGGER IF EXISTS search_sessions_delete;
+DROP TRIGGER IF EXISTS search_evp_recordings_insert;
+DROP TRIGGER IF EXISTS search_evp_recordings_update;
+DROP TRIGGER IF EXISTS search_evp_recordings_delete;
+DROP TRIGGER IF EXISTS search_vox_events_insert;
+DROP TRIGGER IF EXISTS search_vox_events_update;
+DROP TRIGGER IF EXISTS search_vox_events_delete;
+DROP TRIGGER IF EXISTS search_user_interactions_insert;
+DROP TRIGGER IF EXISTS search_user_interactions_update;
+DROP TRIGGER IF EXISTS search_user_interactions_delete;
+
+DROP TABLE IF EXISTS search_index;
+
+CREATE VIRTUAL TABLE search_index USING fts5(body, tokenize = 'unicode61 remove_diacritics 1');
+
+-- sessions
+CREATE TRIGGER IF NOT EXISTS search_sessions_insert AFTER INSERT ON sessions
+BEGIN
+    INSERT INTO search_documents (entity_type, entity_id, field, session_id) VALUES ('session', NEW.id, 'title', NEW.id);
+    INSERT INTO search_index (rowid, body) VALUES (last_insert_rowid(), NEW.title);
+    INSERT INTO search_documents (entity_type, entity_id, field, session_id) VALUES ('session', NEW.id, 'notes', NEW.id);
+    INSERT INTO search_index (rowid, body) VALUES (last_insert_rowid(), COALESCE(NEW.notes, ''));
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_sessions_update AFTER UPDATE OF title, notes ON sessions
+BEGIN
+    UPDATE search_index SET body = NEW.title
+    WHERE rowid = (SELECT docid FROM search_documents WHERE entity_type = 'session' AND entity_id = NEW.id AND field = 'title');
+    UPDATE search_index SET body = COALESCE(NEW.notes, '')
+    WHERE rowid = (SELECT docid FROM search_documents WHERE entity_type = 'session' AND entity_id = NEW.id AND field = 'notes');
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_sessions_delete AFTER DELETE ON sessions
+BEGIN
+    DELETE FROM search_index WHERE rowid IN (SELECT docid FROM search_documents WHERE entity_type = 'session' AND entity_id = OLD.id);
+    DELETE FROM search_documents WHERE entity_type = 'session' AND entity_id = OLD.id;
+END;
+
+-- evp_recordings
+CREATE TRIGGER IF NOT EXISTS search_evp_recordings_insert AFTER INSERT ON evp_recordings
+BEGIN
+    INSERT INTO search_documents (entity_type, entity_id, field, session_id) VALUES ('evp', NEW.id, 'annotations', NEW.session_id);
+    INSERT INTO search_index (rowid, body) VALUES (last_insert_rowid(),
+        CASE WHEN json_valid(CAST(NEW.annotations AS TEXT))
+            THEN COALESCE((SELECT group_concat(value, ' | ') FROM json_each(CAST(NEW.annotations AS TEXT)) WHERE type = 'text'), '')
+            ELSE COALESCE(CAST(NEW.annotations AS TEXT), '')
+        END);
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_evp_recordings_update AFTER UPDATE OF annotations ON evp_recordings
+BEGIN
+    UPDATE search_index SET body =
+        CASE WHEN json_valid(CAST(NEW.annotations AS TEXT))
+            THEN COALESCE((SELECT group_concat(value, ' | ') FROM json_each(CAST(NEW.annotations AS TEXT)) WHERE type = 'text'), '')
+            ELSE COALESCE(CAST(NEW.annotations AS TEXT), '')
+        END
+    WHERE rowid = (SELECT docid FROM search_documents WHERE entity_type = 'evp' AND entity_id = NEW.id AND field = 'annotations');
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_evp_recordings_delete AFTER DELETE ON evp_recordings
+BEGIN
+    DELETE FROM search_index WHERE rowid IN (SELECT docid FROM search_documents WHERE entity_type = 'evp' AND entity_id = OLD.id);
+    DELETE FROM search_documents WHERE entity_type = 'evp' AND entity_id = OLD.id;
+END;
+
+-- vox_events
+CREATE TRIGGER IF NOT EXISTS search_vox_events_insert AFTER INSERT ON vox_events
+BEGIN
+    INSERT INTO search_documents (entity_type, entity_id, field, session_id) VALUES ('vox', NEW.id, 'generated_text', NEW.session_id);
+    INSERT INTO search_index (rowid, body) VALUES (last_insert_rowid(), NEW.generated_text);
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_vox_events_update AFTER UPDATE OF generated_text ON vox_events
+BEGIN
+    UPDATE search_index SET body = NEW.generated_text
+    WHERE rowid = (SELECT docid FROM search_documents WHERE entity_type = 'vox' AND entity_id = NEW.id AND field = 'generated_text');
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_vox_events_delete AFTER DELETE ON vox_events
+BEGIN
+    DELETE FROM search_index WHERE rowid IN (SELECT docid FROM search_documents WHERE entity_type = 'vox' AND entity_id = OLD.id);
+    DELETE FROM search_documents WHERE entity_type = 'vox' AND entity_id = OLD.id;
+END;
+
+-- user_interactions
+CREATE TRIGGER IF NOT EXISTS search_user_interactions_insert AFTER INSERT ON user_interactions
+BEGIN
+    INSERT INTO search_documents (entity_type, entity_id, field, session_id) VALUES ('interaction', NEW.id, 'content', NEW.session_id);
+    INSERT INTO search_index (rowid, body) VALUES (last_insert_rowid(), NEW.content);
+    INSERT INTO search_documents (entity_type, entity_id, field, session_id) VALUES ('interaction', NEW.id, 'response', NEW.session_id);
+    INSERT INTO search_index (rowid, body) VALUES (last_insert_rowid(), COALESCE(NEW.response, ''));
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_user_interactions_update AFTER UPDATE OF content, response ON user_interactions
+BEGIN
+    UPDATE search_index SET body = NEW.content
+    WHERE rowid = (SELECT docid FROM search_documents WHERE entity_type = 'interaction' AND entity_id = NEW.id AND field = 'content');
+    UPDATE search_index SET body = COALESCE(NEW.response, '')
+    WHERE rowid = (SELECT docid FROM search_documents WHERE entity_type = 'interaction' AND entity_id = NEW.id AND field = 'response');
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_user_interactions_delete AFTER DELETE ON user_interactions
+BEGIN
+    DELETE FROM search_index WHERE rowid IN (SELECT docid FROM search_documents WHERE entity_type = 'interaction' AND entity_id = OLD.id);
+    DELETE FROM search_documents WHERE entity_type = 'interaction' AND entity_id = OLD.id;
+END;
+
+-- Reindex the documents of the records stored so far
+INSERT INTO search_index (rowid, body)
+SELECT d.docid, CASE d.field WHEN 'title' THEN s.title ELSE COALESCE(s.notes, '') END
+FROM search_documents d JOIN sessions s ON s.id = d.entity_id
+WHERE d.entity_type = 'session';
+
+INSERT INTO search_index (rowid, body)
+SELECT d.docid,
+    CASE WHEN json_valid(CAST(e.annotations AS TEXT))
+        THEN COALESCE((SELECT group_concat(value, ' | ') FROM json_each(CAST(e.annotations AS TEXT)) WHERE type = 'text'), '')
+        ELSE COALESCE(CAST(e.annotations AS TEXT), '')
+    END
+FROM search_documents d JOIN evp_recordings e ON e.id = d.entity_id
+WHERE d.entity_type = 'evp';
+
+INSERT INTO search_index (rowid, body)
+SELECT d.docid, v.generated_text
+FROM search_documents d JOIN vox_events v ON v.id = d.entity_id
+WHERE d.entity_type = 'vox';
+
+INSERT INTO search_index (rowid, body)
+SELECT d.docid, CASE d.field WHEN 'content' THEN i.content ELSE COALESCE(i.response, '') END
+FROM search_documents d JOIN user_interactions i ON i.id = d.entity_id
+WHERE d.entity_type = 'interaction';
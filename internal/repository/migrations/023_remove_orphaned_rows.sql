@@ -0,0 +1,47 @@
+-- Migration: 023_remove_orphaned_rows
+-- Foreign keys are enforced since the server opens its database with
+-- _foreign_keys=on. Before that, deleting a session, investigator, team,
+-- floor plan or webhook subscription left the rows referring to it behind,
+-- and any later write to those rows would now fail the constraint. Apply
+-- each foreign key's ON DELETE action to the rows orphaned so far, so that
+-- PRAGMA foreign_key_check passes.
+
+-- ON DELETE CASCADE from sessions
+DELETE FROM evp_recordings WHERE session_id NOT IN (SELECT id FROM sessions);
+DELETE FROM vox_events WHERE session_id NOT IN (SELECT id FROM sessions);
+DELETE FROM radar_events WHERE session_id NOT IN (SELECT id FROM sessions);
+DELETE FROM sls_detections WHERE session_id NOT IN (SELECT id FROM sessions);
+DELETE FROM user_interactions WHERE session_id NOT IN (SELECT id FROM sessions);
+DELETE FROM radar_tracks WHERE session_id NOT IN (SELECT id FROM sessions);
+DELETE FROM fusion_events WHERE session_id NOT IN (SELECT id FROM sessions);
+DELETE FROM environmental_readings WHERE session_id NOT IN (SELECT id FROM sessions);
+DELETE FROM environmental_anomalies WHERE session_id NOT IN (SELECT id FROM sessions);
+DELETE FROM sensor_registrations WHERE session_id NOT IN (SELECT id FROM sessions);
+DELETE FROM session_status_history WHERE session_id NOT IN (SELECT id FROM sessions);
+DELETE FROM device_placements WHERE session_id NOT IN (SELECT id FROM sessions);
+DELETE FROM session_participants WHERE session_id NOT IN (SELECT id FROM sessions);
+DELETE FROM session_acl WHERE session_id NOT IN (SELECT id FROM sessions);
+DELETE FROM session_guests WHERE session_id NOT IN (SELECT id FROM sessions);
+DELETE FROM share_links WHERE session_id NOT IN (SELECT id FROM sessions);
+DELETE FROM alerts WHERE session_id NOT IN (SELECT id FROM sessions);
+DELETE FROM alert_rules WHERE session_id IS NOT NULL AND session_id NOT IN (SELECT id FROM sessions);
+DELETE FROM files WHERE session_id IS NOT NULL AND session_id NOT IN (SELECT id FROM sessions);
+
+-- ON DELETE CASCADE from floor_plans, teams, investigators and webhook_subscriptions
+DELETE FROM rooms WHERE floor_plan_id NOT IN (SELECT id FROM floor_plans);
+DELETE FROM device_placements WHERE floor_plan_id NOT IN (SELECT id FROM floor_plans);
+DELETE FROM team_members WHERE team_id NOT IN (SELECT id FROM teams);
+DELETE FROM team_members WHERE investigator_id NOT IN (SELECT id FROM investigators);
+DELETE FROM session_participants WHERE investigator_id NOT IN (SELECT id FROM investigators);
+DELETE FROM session_guests WHERE investigator_id NOT IN (SELECT id FROM investigators);
+DELETE FROM webhook_deliveries WHERE subscription_id NOT IN (SELECT id FROM webhook_subscriptions);
+
+-- ON DELETE SET NULL. A session whose owner is gone keeps its access
+-- record without an owner, which leaves it to admins rather than opening it.
+UPDATE session_acl SET team_id = NULL WHERE team_id IS NOT NULL AND team_id NOT IN (SELECT id FROM teams);
+UPDATE session_acl SET owner_id = NULL WHERE owner_id IS NOT NULL AND owner_id NOT IN (SELECT id FROM investigators);
+UPDATE evp_recordings SET investigator_id = NULL WHERE investigator_id IS NOT NULL AND investigator_id NOT IN (SELECT id FROM investigators);
+UPDATE vox_events SET investigator_id = NULL WHERE investigator_id IS NOT NULL AND investigator_id NOT IN (SELECT id FROM investigators);
+UPDATE radar_events SET investigator_id = NULL WHERE investigator_id IS NOT NULL AND investigator_id NOT IN (SELECT id FROM investigators);
+UPDATE sls_detections SET investigator_id = NULL WHERE investigator_id IS NOT NULL AND investigator_id NOT IN (SELECT id FROM investigators);
+UPDATE user_interactions SET investigator_id = NULL WHERE investigator_id IS NOT NULL AND investigator_id NOT IN (SELECT id FROM investigators);
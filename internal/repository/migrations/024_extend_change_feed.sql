@@ -0,0 +1,266 @@
+-- Migration: 024_extend_change_feed
+-- The change feed carried sessions and their events only. Environmental
+-- readings, radar tracks, fusion events, device placements with the floor
+-- plans they use, participants and access lists now reach it too.
+-- Floor plans are shared between sessions, so each session using one has
+-- its own 'session_id/floor_plan_id' entry, as participants have
+-- 'session_id/investigator_id'. The access list of a session is one entry
+-- covering its owner, team and guests.
+--
+-- Changes stay in the feed at their latest seq only, so a guest or team
+-- member let into a session would never see what was recorded before with
+-- their cursor already past it. Granting access therefore moves all of the
+-- session's changes to the end of the feed, through change_resends.
+--
+-- The triggers delete a record's earlier change before adding the new one
+-- rather than using INSERT OR REPLACE: statements with an ON CONFLICT clause,
+-- such as the upserts saving participants and access lists, impose their
+-- own conflict handling on the statements of the triggers they fire.
+
+CREATE TABLE IF NOT EXISTS change_resends (
+    seq INTEGER PRIMARY KEY,
+    entity_type TEXT NOT NULL,
+    entity_id TEXT NOT NULL,
+    session_id TEXT NOT NULL,
+    operation TEXT NOT NULL
+);
+
+-- environmental_readings
+CREATE TRIGGER IF NOT EXISTS changes_environmental_readings_insert AFTER INSERT ON environmental_readings
+BEGIN
+    DELETE FROM changes WHERE entity_type = 'environmental' AND entity_id = NEW.id;
+    INSERT INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('environmental', NEW.id, NEW.session_id, 'upsert', CURRENT_TIMESTAMP);
+END;
+
+CREATE TRIGGER IF NOT EXISTS changes_environmental_readings_update AFTER UPDATE ON environmental_readings
+BEGIN
+    DELETE FROM changes WHERE entity_type = 'environmental' AND entity_id = NEW.id;
+    INSERT INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('environmental', NEW.id, NEW.session_id, 'upsert', CURRENT_TIMESTAMP);
+END;
+
+CREATE TRIGGER IF NOT EXISTS changes_environmental_readings_delete AFTER DELETE ON environmental_readings
+BEGIN
+    DELETE FROM changes WHERE entity_type = 'environmental' AND entity_id = OLD.id;
+    INSERT INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('environmental', OLD.id, OLD.session_id, 'delete', CURRENT_TIMESTAMP);
+END;
+
+-- radar_tracks
+CREATE TRIGGER IF NOT EXISTS changes_radar_tracks_insert AFTER INSERT ON radar_tracks
+BEGIN
+    DELETE FROM changes WHERE entity_type = 'radar_track' AND entity_id = NEW.id;
+    INSERT INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('radar_track', NEW.id, NEW.session_id, 'upsert', CURRENT_TIMESTAMP);
+END;
+
+CREATE TRIGGER IF NOT EXISTS changes_radar_tracks_update AFTER UPDATE ON radar_tracks
+BEGIN
+    DELETE FROM changes WHERE entity_type = 'radar_track' AND entity_id = NEW.id;
+    INSERT INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('radar_track', NEW.id, NEW.session_id, 'upsert', CURRENT_TIMESTAMP);
+END;
+
+CREATE TRIGGER IF NOT EXISTS changes_radar_tracks_delete AFTER DELETE ON radar_tracks
+BEGIN
+    DELETE FROM changes WHERE entity_type = 'radar_track' AND entity_id = OLD.id;
+    INSERT INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('radar_track', OLD.id, OLD.session_id, 'delete', CURRENT_TIMESTAMP);
+END;
+
+-- fusion_events
+CREATE TRIGGER IF NOT EXISTS changes_fusion_events_insert AFTER INSERT ON fusion_events
+BEGIN
+    DELETE FROM changes WHERE entity_type = 'fusion' AND entity_id = NEW.id;
+    INSERT INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('fusion', NEW.id, NEW.session_id, 'upsert', CURRENT_TIMESTAMP);
+END;
+
+CREATE TRIGGER IF NOT EXISTS changes_fusion_events_update AFTER UPDATE ON fusion_events
+BEGIN
+    DELETE FROM changes WHERE entity_type = 'fusion' AND entity_id = NEW.id;
+    INSERT INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('fusion', NEW.id, NEW.session_id, 'upsert', CURRENT_TIMESTAMP);
+END;
+
+CREATE TRIGGER IF NOT EXISTS changes_fusion_events_delete AFTER DELETE ON fusion_events
+BEGIN
+    DELETE FROM changes WHERE entity_type = 'fusion' AND entity_id = OLD.id;
+    INSERT INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('fusion', OLD.id, OLD.session_id, 'delete', CURRENT_TIMESTAMP);
+END;
+
+-- device_placements, and the floor plans they use
+CREATE TRIGGER IF NOT EXISTS changes_device_placements_insert AFTER INSERT ON device_placements
+BEGIN
+    DELETE FROM changes WHERE entity_type = 'floor_plan' AND entity_id = NEW.session_id || '/' || NEW.floor_plan_id;
+    INSERT INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('floor_plan', NEW.session_id || '/' || NEW.floor_plan_id, NEW.session_id, 'upsert', CURRENT_TIMESTAMP);
+    DELETE FROM changes WHERE entity_type = 'placement' AND entity_id = NEW.id;
+    INSERT INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('placement', NEW.id, NEW.session_id, 'upsert', CURRENT_TIMESTAMP);
+END;
+
+CREATE TRIGGER IF NOT EXISTS changes_device_placements_update AFTER UPDATE ON device_placements
+BEGIN
+    DELETE FROM changes WHERE entity_type = 'floor_plan' AND entity_id = NEW.session_id || '/' || NEW.floor_plan_id;
+    INSERT INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('floor_plan', NEW.session_id || '/' || NEW.floor_plan_id, NEW.session_id, 'upsert', CURRENT_TIMESTAMP);
+    DELETE FROM changes WHERE entity_type = 'placement' AND entity_id = NEW.id;
+    INSERT INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('placement', NEW.id, NEW.session_id, 'upsert', CURRENT_TIMESTAMP);
+END;
+
+-- A session's floor plan entry goes with its last placement on the plan,
+-- which also covers deleting the floor plan
+CREATE TRIGGER IF NOT EXISTS changes_device_placements_delete AFTER DELETE ON device_placements
+BEGIN
+    DELETE FROM changes WHERE entity_type = 'placement' AND entity_id = OLD.id;
+    INSERT INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('placement', OLD.id, OLD.session_id, 'delete', CURRENT_TIMESTAMP);
+    DELETE FROM changes
+    WHERE entity_type = 'floor_plan' AND entity_id = OLD.session_id || '/' || OLD.floor_plan_id
+        AND NOT EXISTS (
+            SELECT 1 FROM device_placements WHERE session_id = OLD.session_id AND floor_plan_id = OLD.floor_plan_id
+        );
+    INSERT INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    SELECT 'floor_plan', OLD.session_id || '/' || OLD.floor_plan_id, OLD.session_id, 'delete', CURRENT_TIMESTAMP
+    WHERE NOT EXISTS (
+        SELECT 1 FROM device_placements WHERE session_id = OLD.session_id AND floor_plan_id = OLD.floor_plan_id
+    );
+END;
+
+CREATE TRIGGER IF NOT EXISTS changes_floor_plans_update AFTER UPDATE ON floor_plans
+BEGIN
+    INSERT INTO change_resends (seq, entity_type, entity_id, session_id, operation)
+    SELECT seq, entity_type, entity_id, session_id, operation FROM changes
+    WHERE entity_type = 'floor_plan' AND operation = 'upsert' AND entity_id = session_id || '/' || NEW.id;
+    DELETE FROM changes WHERE seq IN (SELECT seq FROM change_resends);
+    INSERT INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    SELECT entity_type, entity_id, session_id, operation, CURRENT_TIMESTAMP FROM change_resends ORDER BY seq;
+    DELETE FROM change_resends;
+END;
+
+-- session_participants
+CREATE TRIGGER IF NOT EXISTS changes_session_participants_insert AFTER INSERT ON session_participants
+BEGIN
+    DELETE FROM changes WHERE entity_type = 'participant' AND entity_id = NEW.session_id || '/' || NEW.investigator_id;
+    INSERT INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('participant', NEW.session_id || '/' || NEW.investigator_id, NEW.session_id, 'upsert', CURRENT_TIMESTAMP);
+END;
+
+CREATE TRIGGER IF NOT EXISTS changes_session_participants_update AFTER UPDATE ON session_participants
+BEGIN
+    DELETE FROM changes WHERE entity_type = 'participant' AND entity_id = NEW.session_id || '/' || NEW.investigator_id;
+    INSERT INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('participant', NEW.session_id || '/' || NEW.investigator_id, NEW.session_id, 'upsert', CURRENT_TIMESTAMP);
+END;
+
+CREATE TRIGGER IF NOT EXISTS changes_session_participants_delete AFTER DELETE ON session_participants
+BEGIN
+    DELETE FROM changes WHERE entity_type = 'participant' AND entity_id = OLD.session_id || '/' || OLD.investigator_id;
+    INSERT INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('participant', OLD.session_id || '/' || OLD.investigator_id, OLD.session_id, 'delete', CURRENT_TIMESTAMP);
+END;
+
+-- session_acl and session_guests. Setting the owner or team may let new
+-- investigators in, and so may clearing them, which opens the session.
+CREATE TRIGGER IF NOT EXISTS changes_session_acl_insert AFTER INSERT ON session_acl
+BEGIN
+    DELETE FROM changes WHERE entity_type = 'acl' AND entity_id = NEW.session_id;
+    INSERT INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('acl', NEW.session_id, NEW.session_id, 'upsert', CURRENT_TIMESTAMP);
+    INSERT INTO change_resends (seq, entity_type, entity_id, session_id, operation)
+    SELECT seq, entity_type, entity_id, session_id, operation FROM changes
+    WHERE session_id = NEW.session_id;
+    DELETE FROM changes WHERE seq IN (SELECT seq FROM change_resends);
+    INSERT INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    SELECT entity_type, entity_id, session_id, operation, CURRENT_TIMESTAMP FROM change_resends ORDER BY seq;
+    DELETE FROM change_resends;
+END;
+
+CREATE TRIGGER IF NOT EXISTS changes_session_acl_update AFTER UPDATE ON session_acl
+BEGIN
+    DELETE FROM changes WHERE entity_type = 'acl' AND entity_id = NEW.session_id;
+    INSERT INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('acl', NEW.session_id, NEW.session_id, 'upsert', CURRENT_TIMESTAMP);
+    INSERT INTO change_resends (seq, entity_type, entity_id, session_id, operation)
+    SELECT seq, entity_type, entity_id, session_id, operation FROM changes
+    WHERE session_id = NEW.session_id
+        AND (OLD.owner_id IS NOT NEW.owner_id OR OLD.team_id IS NOT NEW.team_id);
+    DELETE FROM changes WHERE seq IN (SELECT seq FROM change_resends);
+    INSERT INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    SELECT entity_type, entity_id, session_id, operation, CURRENT_TIMESTAMP FROM change_resends ORDER BY seq;
+    DELETE FROM change_resends;
+END;
+
+CREATE TRIGGER IF NOT EXISTS changes_session_acl_delete AFTER DELETE ON session_acl
+BEGIN
+    DELETE FROM changes WHERE entity_type = 'acl' AND entity_id = OLD.session_id;
+    INSERT INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('acl', OLD.session_id, OLD.session_id, 'delete', CURRENT_TIMESTAMP);
+END;
+
+CREATE TRIGGER IF NOT EXISTS changes_session_guests_insert AFTER INSERT ON session_guests
+BEGIN
+    DELETE FROM changes WHERE entity_type = 'acl' AND entity_id = NEW.session_id;
+    INSERT INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES ('acl', NEW.session_id, NEW.session_id, 'upsert', CURRENT_TIMESTAMP);
+    INSERT INTO change_resends (seq, entity_type, entity_id, session_id, operation)
+    SELECT seq, entity_type, entity_id, session_id, operation FROM changes
+    WHERE session_id = NEW.session_id;
+    DELETE FROM changes WHERE seq IN (SELECT seq FROM change_resends);
+    INSERT INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    SELECT entity_type, entity_id, session_id, operation, CURRENT_TIMESTAMP FROM change_resends ORDER BY seq;
+    DELETE FROM change_resends;
+END;
+
+-- Guests also go when their session is deleted, taking its access list along
+CREATE TRIGGER IF NOT EXISTS changes_session_guests_delete AFTER DELETE ON session_guests
+BEGIN
+    DELETE FROM changes WHERE entity_type = 'acl' AND entity_id = OLD.session_id;
+    INSERT INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    VALUES (
+        'acl', OLD.session_id, OLD.session_id,
+        CASE WHEN EXISTS (SELECT 1 FROM sessions WHERE id = OLD.session_id) THEN 'upsert' ELSE 'delete' END,
+        CURRENT_TIMESTAMP
+    );
+END;
+
+-- team_members: a new member may read every session of the team
+CREATE TRIGGER IF NOT EXISTS changes_team_members_insert AFTER INSERT ON team_members
+BEGIN
+    INSERT INTO change_resends (seq, entity_type, entity_id, session_id, operation)
+    SELECT seq, entity_type, entity_id, session_id, operation FROM changes
+    WHERE session_id IN (SELECT session_id FROM session_acl WHERE team_id = NEW.team_id);
+    DELETE FROM changes WHERE seq IN (SELECT seq FROM change_resends);
+    INSERT INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+    SELECT entity_type, entity_id, session_id, operation, CURRENT_TIMESTAMP FROM change_resends ORDER BY seq;
+    DELETE FROM change_resends;
+END;
+
+-- Records stored before this migration
+INSERT OR IGNORE INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+SELECT 'environmental', id, session_id, 'upsert', CURRENT_TIMESTAMP FROM environmental_readings;
+
+INSERT OR IGNORE INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+SELECT 'radar_track', id, session_id, 'upsert', CURRENT_TIMESTAMP FROM radar_tracks;
+
+INSERT OR IGNORE INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+SELECT 'fusion', id, session_id, 'upsert', CURRENT_TIMESTAMP FROM fusion_events;
+
+INSERT OR IGNORE INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+SELECT DISTINCT 'floor_plan', session_id || '/' || floor_plan_id, session_id, 'upsert', CURRENT_TIMESTAMP
+FROM device_placements;
+
+INSERT OR IGNORE INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+SELECT 'placement', id, session_id, 'upsert', CURRENT_TIMESTAMP FROM device_placements;
+
+INSERT OR IGNORE INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+SELECT 'participant', session_id || '/' || investigator_id, session_id, 'upsert', CURRENT_TIMESTAMP
+FROM session_participants;
+
+INSERT OR IGNORE INTO changes (entity_type, entity_id, session_id, operation, changed_at)
+SELECT 'acl', session_id, session_id, 'upsert', CURRENT_TIMESTAMP
+FROM (SELECT session_id FROM session_acl UNION SELECT session_id FROM session_guests);
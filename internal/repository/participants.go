@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/myideascope/otherside/internal/domain"
+)
+
+// SQLiteInvestigatorRepository implements InvestigatorRepository using SQLite
+type SQLiteInvestigatorRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteInvestigatorRepository creates a new SQLite investigator repository
+func NewSQLiteInvestigatorRepository(db *sql.DB) *SQLiteInvestigatorRepository {
+	return &SQLiteInvestigatorRepository{db: db}
+}
+
+// Create creates a new investigator
+func (r *SQLiteInvestigatorRepository) Create(ctx context.Context, investigator *domain.Investigator) error {
+	query := `INSERT INTO investigators (id, name, email, is_admin, created_at) VALUES (?, ?, ?, ?, ?)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		investigator.ID, investigator.Name, investigator.Email, investigator.IsAdmin, investigator.CreatedAt,
+	)
+
+	return err
+}
+
+// GetByID retrieves an investigator by ID
+func (r *SQLiteInvestigatorRepository) GetByID(ctx context.Context, id string) (*domain.Investigator, error) {
+	query := `SELECT id, name, COALESCE(email, ''), is_admin, created_at FROM investigators WHERE id = ?`
+
+	var investigator domain.Investigator
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&investigator.ID, &investigator.Name, &investigator.Email, &investigator.IsAdmin, &investigator.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &investigator, nil
+}
+
+// GetAll retrieves all investigators ordered by name
+func (r *SQLiteInvestigatorRepository) GetAll(ctx context.Context) ([]*domain.Investigator, error) {
+	query := `SELECT id, name, COALESCE(email, ''), is_admin, created_at FROM investigators ORDER BY name ASC`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var investigators []*domain.Investigator
+	for rows.Next() {
+		var investigator domain.Investigator
+		if err := rows.Scan(&investigator.ID, &investigator.Name, &investigator.Email, &investigator.IsAdmin, &investigator.CreatedAt); err != nil {
+			return nil, err
+		}
+		investigators = append(investigators, &investigator)
+	}
+
+	return investigators, rows.Err()
+}
+
+// Delete deletes an investigator. Their session memberships go with them
+// and the events they recorded lose their attribution. Investigators who
+// still own sessions cannot be deleted.
+func (r *SQLiteInvestigatorRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM investigators WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+// SQLiteDeviceRepository implements DeviceRepository using SQLite
+type SQLiteDeviceRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteDeviceRepository creates a new SQLite device repository
+func NewSQLiteDeviceRepository(db *sql.DB) *SQLiteDeviceRepository {
+	return &SQLiteDeviceRepository{db: db}
+}
+
+// Create creates a new device
+func (r *SQLiteDeviceRepository) Create(ctx context.Context, device *domain.Device) error {
+	query := `INSERT INTO devices (id, name, kind, serial_number, created_at) VALUES (?, ?, ?, ?, ?)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		device.ID, device.Name, device.Kind, device.SerialNumber, device.CreatedAt,
+	)
+
+	return err
+}
+
+// GetByID retrieves a device by ID
+func (r *SQLiteDeviceRepository) GetByID(ctx context.Context, id string) (*domain.Device, error) {
+	query := `
+		SELECT id, name, COALESCE(kind, ''), COALESCE(serial_number, ''), created_at
+		FROM devices WHERE id = ?`
+
+	var device domain.Device
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&device.ID, &device.Name, &device.Kind, &device.SerialNumber, &device.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &device, nil
+}
+
+// GetAll retrieves all devices ordered by name
+func (r *SQLiteDeviceRepository) GetAll(ctx context.Context) ([]*domain.Device, error) {
+	query := `
+		SELECT id, name, COALESCE(kind, ''), COALESCE(serial_number, ''), created_at
+		FROM devices ORDER BY name ASC`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var devices []*domain.Device
+	for rows.Next() {
+		var device domain.Device
+		if err := rows.Scan(&device.ID, &device.Name, &device.Kind, &device.SerialNumber, &device.CreatedAt); err != nil {
+			return nil, err
+		}
+		devices = append(devices, &device)
+	}
+
+	return devices, rows.Err()
+}
+
+// Delete deletes a device. Events keep the device ID they were recorded with.
+func (r *SQLiteDeviceRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM devices WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+// SQLiteSessionParticipantRepository implements SessionParticipantRepository using SQLite
+type SQLiteSessionParticipantRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteSessionParticipantRepository creates a new SQLite session participant repository
+func NewSQLiteSessionParticipantRepository(db *sql.DB) *SQLiteSessionParticipantRepository {
+	return &SQLiteSessionParticipantRepository{db: db}
+}
+
+// Save adds an investigator to a session or changes their role. The join
+// time of an existing participant is kept.
+func (r *SQLiteSessionParticipantRepository) Save(ctx context.Context, participant *domain.SessionParticipant) error {
+	query := `
+		INSERT INTO session_participants (session_id, investigator_id, role, joined_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(session_id, investigator_id) DO UPDATE SET role = excluded.role`
+
+	_, err := r.db.ExecContext(ctx, query,
+		participant.SessionID, participant.InvestigatorID, participant.Role, participant.JoinedAt,
+	)
+
+	return err
+}
+
+// Get retrieves one participant of a session
+func (r *SQLiteSessionParticipantRepository) Get(ctx context.Context, sessionID, investigatorID string) (*domain.SessionParticipant, error) {
+	query := `
+		SELECT session_id, investigator_id, role, joined_at
+		FROM session_participants WHERE session_id = ? AND investigator_id = ?`
+
+	var participant domain.SessionParticipant
+	err := r.db.QueryRowContext(ctx, query, sessionID, investigatorID).Scan(
+		&participant.SessionID, &participant.InvestigatorID, &participant.Role, &participant.JoinedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &participant, nil
+}
+
+// GetBySessionID retrieves the participants of a session in joining order
+func (r *SQLiteSessionParticipantRepository) GetBySessionID(ctx context.Context, sessionID string) ([]*domain.SessionParticipant, error) {
+	query := `
+		SELECT session_id, investigator_id, role, joined_at
+		FROM session_participants WHERE session_id = ? ORDER BY joined_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var participants []*domain.SessionParticipant
+	for rows.Next() {
+		var participant domain.SessionParticipant
+		err := rows.Scan(&participant.SessionID, &participant.InvestigatorID, &participant.Role, &participant.JoinedAt)
+		if err != nil {
+			return nil, err
+		}
+		participants = append(participants, &participant)
+	}
+
+	return participants, rows.Err()
+}
+
+// Delete removes an investigator from a session
+func (r *SQLiteSessionParticipantRepository) Delete(ctx context.Context, sessionID, investigatorID string) error {
+	query := `DELETE FROM session_participants WHERE session_id = ? AND investigator_id = ?`
+	_, err := r.db.ExecContext(ctx, query, sessionID, investigatorID)
+	return err
+}
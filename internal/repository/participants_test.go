@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteSessionParticipantRepository_Save_ExistingParticipant_KeepsJoinedAt(t *testing.T) {
+	// Arrange
+	db := setupMigratedTestDB(t)
+	defer cleanupTestDB(db)
+	investigatorRepo := NewSQLiteInvestigatorRepository(db)
+	repo := NewSQLiteSessionParticipantRepository(db)
+	ctx := context.Background()
+
+	joined := time.Now().Add(-time.Hour).Truncate(time.Second)
+	require.NoError(t, investigatorRepo.Create(ctx, &domain.Investigator{ID: "inv-1", Name: "Ada", CreatedAt: joined}))
+	require.NoError(t, repo.Save(ctx, &domain.SessionParticipant{
+		SessionID: "session-1", InvestigatorID: "inv-1", Role: domain.ParticipantRoleObserver, JoinedAt: joined,
+	}))
+
+	// Act
+	err := repo.Save(ctx, &domain.SessionParticipant{
+		SessionID: "session-1", InvestigatorID: "inv-1", Role: domain.ParticipantRoleLead, JoinedAt: time.Now(),
+	})
+
+	// Assert
+	require.NoError(t, err)
+	participants, err := repo.GetBySessionID(ctx, "session-1")
+	require.NoError(t, err)
+	require.Len(t, participants, 1)
+	assert.Equal(t, domain.ParticipantRoleLead, participants[0].Role)
+	assert.True(t, joined.Equal(participants[0].JoinedAt))
+}
+
+func TestSQLiteEVPRepository_Create_WithAttribution_RoundTrips(t *testing.T) {
+	// Arrange
+	db := setupMigratedTestDB(t)
+	defer cleanupTestDB(db)
+	investigatorRepo := NewSQLiteInvestigatorRepository(db)
+	repo := NewSQLiteEVPRepository(db)
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Second)
+	require.NoError(t, investigatorRepo.Create(ctx, &domain.Investigator{ID: "inv-1", Name: "Ada", CreatedAt: now}))
+
+	attributed := &domain.EVPRecording{
+		ID: "evp-1", SessionID: "session-1", FilePath: "evp-1.wav", Timestamp: now,
+		Quality: domain.EVPQualityGood, InvestigatorID: "inv-1", DeviceID: "recorder-1", CreatedAt: now,
+	}
+	unattributed := &domain.EVPRecording{
+		ID: "evp-2", SessionID: "session-1", FilePath: "evp-2.wav", Timestamp: now.Add(time.Second),
+		Quality: domain.EVPQualityGood, CreatedAt: now,
+	}
+
+	// Act
+	require.NoError(t, repo.Create(ctx, attributed))
+	require.NoError(t, repo.Create(ctx, unattributed))
+
+	// Assert
+	evps, err := repo.GetBySessionID(ctx, "session-1")
+	require.NoError(t, err)
+	require.Len(t, evps, 2)
+	byID := map[string]*domain.EVPRecording{}
+	for _, evp := range evps {
+		byID[evp.ID] = evp
+	}
+	assert.Equal(t, "inv-1", byID["evp-1"].InvestigatorID)
+	assert.Equal(t, "recorder-1", byID["evp-1"].DeviceID)
+	assert.Empty(t, byID["evp-2"].InvestigatorID)
+	assert.Empty(t, byID["evp-2"].DeviceID)
+}
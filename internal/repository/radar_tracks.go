@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/myideascope/otherside/internal/domain"
+)
+
+// SQLiteRadarTrackRepository implements RadarTrackRepository using SQLite
+type SQLiteRadarTrackRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteRadarTrackRepository creates a new SQLite radar track repository
+func NewSQLiteRadarTrackRepository(db *sql.DB) *SQLiteRadarTrackRepository {
+	return &SQLiteRadarTrackRepository{db: db}
+}
+
+// ReplaceBySessionID replaces all stored tracks of a session in one transaction
+func (r *SQLiteRadarTrackRepository) ReplaceBySessionID(ctx context.Context, sessionID string, tracks []*domain.RadarTrack) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM radar_tracks WHERE session_id = ?`, sessionID); err != nil {
+		return fmt.Errorf("failed to delete radar tracks: %w", err)
+	}
+
+	query := `
+		INSERT INTO radar_tracks (
+			id, session_id, event_ids, points, start_time, end_time,
+			dwell_time, distance, velocity, heading, mean_strength, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	for _, track := range tracks {
+		eventIDsJSON, _ := json.Marshal(track.EventIDs)
+		pointsJSON, _ := json.Marshal(track.Points)
+
+		_, err := tx.ExecContext(ctx, query,
+			track.ID, sessionID, eventIDsJSON, pointsJSON, track.StartTime, track.EndTime,
+			track.DwellTime, track.Distance, track.Velocity, track.Heading, track.MeanStrength,
+			track.CreatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert radar track %s: %w", track.ID, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetByID retrieves a radar track by ID
+func (r *SQLiteRadarTrackRepository) GetByID(ctx context.Context, id string) (*domain.RadarTrack, error) {
+	query := `
+		SELECT id, session_id, event_ids, points, start_time, end_time,
+			dwell_time, distance, velocity, heading, mean_strength, created_at
+		FROM radar_tracks WHERE id = ?`
+
+	var track domain.RadarTrack
+	var eventIDsJSON, pointsJSON string
+
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&track.ID, &track.SessionID, &eventIDsJSON, &pointsJSON, &track.StartTime, &track.EndTime,
+		&track.DwellTime, &track.Distance, &track.Velocity, &track.Heading, &track.MeanStrength,
+		&track.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	json.Unmarshal([]byte(eventIDsJSON), &track.EventIDs)
+	json.Unmarshal([]byte(pointsJSON), &track.Points)
+
+	return &track, nil
+}
+
+// GetBySessionID retrieves radar tracks by session ID
+func (r *SQLiteRadarTrackRepository) GetBySessionID(ctx context.Context, sessionID string) ([]*domain.RadarTrack, error) {
+	query := `
+		SELECT id, session_id, event_ids, points, start_time, end_time,
+			dwell_time, distance, velocity, heading, mean_strength, created_at
+		FROM radar_tracks WHERE session_id = ? ORDER BY start_time ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tracks []*domain.RadarTrack
+	for rows.Next() {
+		var track domain.RadarTrack
+		var eventIDsJSON, pointsJSON string
+
+		err := rows.Scan(
+			&track.ID, &track.SessionID, &eventIDsJSON, &pointsJSON, &track.StartTime, &track.EndTime,
+			&track.DwellTime, &track.Distance, &track.Velocity, &track.Heading, &track.MeanStrength,
+			&track.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		json.Unmarshal([]byte(eventIDsJSON), &track.EventIDs)
+		json.Unmarshal([]byte(pointsJSON), &track.Points)
+
+		tracks = append(tracks, &track)
+	}
+
+	return tracks, rows.Err()
+}
+
+// DeleteBySessionID deletes all radar tracks of a session
+func (r *SQLiteRadarTrackRepository) DeleteBySessionID(ctx context.Context, sessionID string) error {
+	query := `DELETE FROM radar_tracks WHERE session_id = ?`
+	_, err := r.db.ExecContext(ctx, query, sessionID)
+	return err
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupMigratedTestDB opens an in-memory database with every migration in
+// the migrations directory applied
+func setupMigratedTestDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+
+	// Each pooled connection would otherwise get its own empty database
+	db.SetMaxOpenConns(1)
+
+	migrator := NewMigrator(db, "migrations")
+	require.NoError(t, migrator.Initialize(context.Background()))
+	require.NoError(t, migrator.Up(context.Background()))
+
+	return db
+}
+
+func TestSQLiteRadarTrackRepository_ReplaceBySessionID_ReplacesTracks(t *testing.T) {
+	// Arrange
+	db := setupMigratedTestDB(t)
+	defer cleanupTestDB(db)
+	repo := NewSQLiteRadarTrackRepository(db)
+	ctx := context.Background()
+
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	first := &domain.RadarTrack{
+		ID:           "track-1",
+		SessionID:    "session-1",
+		EventIDs:     []string{"r1", "r2"},
+		Points:       []domain.Coordinates{{X: 1, Y: 0}, {X: 2, Y: 0}},
+		StartTime:    start,
+		EndTime:      start.Add(2 * time.Second),
+		DwellTime:    2,
+		Distance:     1,
+		Velocity:     1,
+		Heading:      0,
+		MeanStrength: 0.6,
+		CreatedAt:    start,
+	}
+	second := *first
+	second.ID = "track-2"
+
+	// Act
+	require.NoError(t, repo.ReplaceBySessionID(ctx, "session-1", []*domain.RadarTrack{first}))
+	require.NoError(t, repo.ReplaceBySessionID(ctx, "session-1", []*domain.RadarTrack{&second}))
+	tracks, err := repo.GetBySessionID(ctx, "session-1")
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, tracks, 1)
+	assert.Equal(t, "track-2", tracks[0].ID)
+	assert.Equal(t, []string{"r1", "r2"}, tracks[0].EventIDs)
+	assert.Equal(t, first.Points, tracks[0].Points)
+	assert.True(t, first.StartTime.Equal(tracks[0].StartTime))
+	assert.Equal(t, 0.6, tracks[0].MeanStrength)
+}
+
+func TestSQLiteRadarTrackRepository_DeleteBySessionID_Success(t *testing.T) {
+	// Arrange
+	db := setupMigratedTestDB(t)
+	defer cleanupTestDB(db)
+	repo := NewSQLiteRadarTrackRepository(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	track := &domain.RadarTrack{ID: "track-1", SessionID: "session-1", StartTime: now, EndTime: now, CreatedAt: now}
+	require.NoError(t, repo.ReplaceBySessionID(ctx, "session-1", []*domain.RadarTrack{track}))
+
+	// Act
+	err := repo.DeleteBySessionID(ctx, "session-1")
+
+	// Assert
+	require.NoError(t, err)
+	tracks, err := repo.GetBySessionID(ctx, "session-1")
+	require.NoError(t, err)
+	assert.Empty(t, tracks)
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/myideascope/otherside/internal/domain"
+)
+
+// searchSnippetTokens is roughly how many words a highlight holds
+const searchSnippetTokens = 16
+
+// SQLiteSearchRepository implements SearchRepository over the search_index
+// full-text table
+type SQLiteSearchRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteSearchRepository creates a new SQLite search repository
+func NewSQLiteSearchRepository(db *sql.DB) *SQLiteSearchRepository {
+	return &SQLiteSearchRepository{db: db}
+}
+
+// Match returns the best limit hits of an FTS5 query expression among
+// types, ranked with bm25()
+func (r *SQLiteSearchRepository) Match(ctx context.Context, expression string, types []string, limit int) ([]*domain.SearchHit, error) {
+	query := `
+		SELECT d.docid, d.session_id, d.entity_type, d.entity_id, d.field, -bm25(search_index) AS score
+		FROM search_index
+		JOIN search_documents d ON d.docid = search_index.rowid
+		WHERE search_index MATCH ?`
+	args := []interface{}{expression}
+
+	if len(types) > 0 {
+		placeholders := make([]string, len(types))
+		for i, entityType := range types {
+			placeholders[i] = "?"
+			args = append(args, entityType)
+		}
+		query += ` AND d.entity_type IN (` + strings.Join(placeholders, ", ") + `)`
+	}
+
+	query += ` ORDER BY score DESC, d.docid LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hits []*domain.SearchHit
+	for rows.Next() {
+		var hit domain.SearchHit
+		if err := rows.Scan(&hit.DocID, &hit.SessionID, &hit.Type, &hit.ID, &hit.Field, &hit.Score); err != nil {
+			return nil, err
+		}
+		hits = append(hits, &hit)
+	}
+
+	return hits, rows.Err()
+}
+
+// Highlight returns a snippet of the text of each of docIDs matching an
+// FTS query expression
+func (r *SQLiteSearchRepository) Highlight(ctx context.Context, expression string, docIDs []int64) (map[int64]string, error) {
+	highlights := make(map[int64]string, len(docIDs))
+	if len(docIDs) == 0 {
+		return highlights, nil
+	}
+
+	placeholders := make([]string, len(docIDs))
+	args := []interface{}{domain.SearchHighlightStart, domain.SearchHighlightEnd, searchSnippetTokens, expression}
+	for i, docID := range docIDs {
+		placeholders[i] = "?"
+		args = append(args, docID)
+	}
+
+	query := `
+		SELECT rowid, snippet(search_index, 0, ?, ?, '…', ?)
+		FROM search_index
+		WHERE search_index MATCH ? AND rowid IN (` + strings.Join(placeholders, ", ") + `)`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var docID int64
+		var snippet string
+		if err := rows.Scan(&docID, &snippet); err != nil {
+			return nil, err
+		}
+		highlights[docID] = snippet
+	}
+
+	return highlights, rows.Err()
+}
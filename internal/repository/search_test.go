@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteSearchRepository_Match_TriggersKeepIndexCurrent(t *testing.T) {
+	// Arrange
+	db := setupMigratedTestDB(t)
+	defer cleanupTestDB(db)
+	sessions := NewSQLiteSessionRepository(db)
+	evps := NewSQLiteEVPRepository(db)
+	voxEvents := NewSQLiteVOXRepository(db)
+	interactions := NewSQLiteInteractionRepository(db)
+	repo := NewSQLiteSearchRepository(db)
+	ctx := context.Background()
+
+	session := createTestSession()
+	session.Notes = "Cold spot by the basement stairs"
+	require.NoError(t, sessions.Create(ctx, session))
+	now := time.Now()
+	evp := &domain.EVPRecording{
+		ID: "evp-1", SessionID: session.ID, FilePath: "evp-1.wav", Duration: 3, Timestamp: now,
+		Annotations: []string{"Whisper near the furnace"}, Quality: domain.EVPQualityGood, CreatedAt: now,
+	}
+	require.NoError(t, evps.Create(ctx, evp))
+	require.NoError(t, voxEvents.Create(ctx, &domain.VOXEvent{
+		ID: "vox-1", SessionID: session.ID, Timestamp: now, GeneratedText: "help basement",
+		PhoneticBank: "english", LanguagePack: "english", ModulationType: "am", CreatedAt: now,
+	}))
+	interaction := &domain.UserInteraction{
+		ID: "interaction-1", SessionID: session.ID, Timestamp: now, Type: domain.InteractionTypeText,
+		Content: "Is anyone down in the basement?", CreatedAt: now,
+	}
+	require.NoError(t, interactions.Create(ctx, interaction))
+
+	// Act
+	basement, err := repo.Match(ctx, `"basement"`, nil, 10)
+	require.NoError(t, err)
+
+	evp.Annotations = []string{"Knocking behind the basement door"}
+	require.NoError(t, evps.Update(ctx, evp))
+	require.NoError(t, interactions.Delete(ctx, interaction.ID))
+	updated, err := repo.Match(ctx, `"basement"`, nil, 10)
+	require.NoError(t, err)
+	furnace, err := repo.Match(ctx, `"furnace"`, nil, 10)
+	require.NoError(t, err)
+
+	var voxDocID int64
+	for _, hit := range updated {
+		if hit.Type == domain.SearchEntityVOX {
+			voxDocID = hit.DocID
+		}
+	}
+	highlights, err := repo.Highlight(ctx, `"help" "base"*`, []int64{voxDocID})
+	require.NoError(t, err)
+
+	// Assert
+	assert.ElementsMatch(t, []string{"session/notes", "vox/generated_text", "interaction/content"}, searchHitFields(basement))
+	assert.ElementsMatch(t, []string{"session/notes", "vox/generated_text", "evp/annotations"}, searchHitFields(updated))
+	assert.Empty(t, furnace)
+	for _, hit := range updated {
+		assert.Equal(t, session.ID, hit.SessionID)
+		assert.Greater(t, hit.Score, 0.0)
+	}
+	assert.Equal(t, "\x02help\x03 \x02basement\x03", highlights[voxDocID])
+}
+
+func TestSQLiteSearchRepository_Match_ShortFieldWithMoreHits_ScoresHigher(t *testing.T) {
+	// Arrange
+	db := setupMigratedTestDB(t)
+	defer cleanupTestDB(db)
+	sessions := NewSQLiteSessionRepository(db)
+	repo := NewSQLiteSearchRepository(db)
+	ctx := context.Background()
+
+	for id, notes := range map[string]string{
+		"session-short":     "Help help",
+		"session-long":      "We heard something that might have been a call for help from the far end of the attic",
+		"session-unrelated": "Nothing of note tonight",
+	} {
+		session := createTestSession()
+		session.ID = id
+		session.Notes = notes
+		require.NoError(t, sessions.Create(ctx, session))
+	}
+
+	// Act
+	hits, err := repo.Match(ctx, `"help"`, nil, 10)
+	require.NoError(t, err)
+
+	// Assert
+	require.Len(t, hits, 2)
+	scores := make(map[string]float64)
+	for _, hit := range hits {
+		scores[hit.SessionID] = hit.Score
+	}
+	assert.Greater(t, scores["session-short"], scores["session-long"])
+}
+
+func TestSQLiteSearchRepository_Match_RanksAndFiltersBeforeLimit(t *testing.T) {
+	// Arrange
+	db := setupMigratedTestDB(t)
+	defer cleanupTestDB(db)
+	sessions := NewSQLiteSessionRepository(db)
+	voxEvents := NewSQLiteVOXRepository(db)
+	repo := NewSQLiteSearchRepository(db)
+	ctx := context.Background()
+
+	session := createTestSession()
+	session.Notes = "Someone asked for help in the long corridor behind the old kitchen"
+	require.NoError(t, sessions.Create(ctx, session))
+	now := time.Now()
+	for i, text := range []string{"help", "help help", "help me"} {
+		require.NoError(t, voxEvents.Create(ctx, &domain.VOXEvent{
+			ID: fmt.Sprintf("vox-%d", i), SessionID: session.ID, Timestamp: now, GeneratedText: text,
+			PhoneticBank: "english", LanguagePack: "english", ModulationType: "am", CreatedAt: now,
+		}))
+	}
+
+	// Act
+	best, err := repo.Match(ctx, `"help"`, nil, 2)
+	require.NoError(t, err)
+	sessionOnly, err := repo.Match(ctx, `"help"`, []string{domain.SearchEntitySession}, 1)
+	require.NoError(t, err)
+
+	// Assert
+	require.Len(t, best, 2)
+	assert.Equal(t, "vox-1", best[0].ID)
+	assert.Equal(t, "vox-0", best[1].ID)
+	assert.Greater(t, best[0].Score, best[1].Score)
+	require.Len(t, sessionOnly, 1)
+	assert.Equal(t, "session/notes", sessionOnly[0].Type+"/"+sessionOnly[0].Field)
+}
+
+// searchHitFields names the fields of hits as type/field
+func searchHitFields(hits []*domain.SearchHit) []string {
+	fields := make([]string, len(hits))
+	for i, hit := range hits {
+		fields[i] = hit.Type + "/" + hit.Field
+	}
+	return fields
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteSessionRepository_UpdateStatus_RecordsHistory(t *testing.T) {
+	// Arrange
+	db := setupMigratedTestDB(t)
+	defer cleanupTestDB(db)
+	repo := NewSQLiteSessionRepository(db)
+	ctx := context.Background()
+
+	session := createTestSession()
+	session.EndTime = nil
+	require.NoError(t, repo.Create(ctx, session))
+
+	endTime := time.Now().Truncate(time.Second)
+	session.Status = domain.SessionStatusComplete
+	session.EndTime = &endTime
+	change := &domain.SessionStatusChange{
+		ID:         "change-1",
+		SessionID:  session.ID,
+		FromStatus: domain.SessionStatusActive,
+		ToStatus:   domain.SessionStatusComplete,
+		Reason:     "completed",
+		ChangedAt:  endTime,
+	}
+
+	// Act
+	err := repo.UpdateStatus(ctx, session, change)
+
+	// Assert
+	require.NoError(t, err)
+
+	stored, err := repo.GetByID(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.SessionStatusComplete, stored.Status)
+	require.NotNil(t, stored.EndTime)
+	assert.True(t, endTime.Equal(*stored.EndTime))
+
+	history, err := repo.GetStatusHistory(ctx, session.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, domain.SessionStatusActive, history[0].FromStatus)
+	assert.Equal(t, domain.SessionStatusComplete, history[0].ToStatus)
+	assert.Equal(t, "completed", history[0].Reason)
+}
+
+func TestSQLiteSessionRepository_UpdateStatus_StaleFromStatus_ReturnsErrNoRows(t *testing.T) {
+	// Arrange
+	db := setupMigratedTestDB(t)
+	defer cleanupTestDB(db)
+	repo := NewSQLiteSessionRepository(db)
+	ctx := context.Background()
+
+	session := createTestSession()
+	require.NoError(t, repo.Create(ctx, session))
+
+	session.Status = domain.SessionStatusActive
+	change := &domain.SessionStatusChange{
+		ID:         "change-1",
+		SessionID:  session.ID,
+		FromStatus: domain.SessionStatusPaused,
+		ToStatus:   domain.SessionStatusActive,
+		ChangedAt:  time.Now(),
+	}
+
+	// Act
+	err := repo.UpdateStatus(ctx, session, change)
+
+	// Assert
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	history, err := repo.GetStatusHistory(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Empty(t, history)
+}
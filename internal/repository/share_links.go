@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+)
+
+// SQLiteShareLinkRepository implements ShareLinkRepository using SQLite
+type SQLiteShareLinkRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteShareLinkRepository creates a new SQLite share link repository
+func NewSQLiteShareLinkRepository(db *sql.DB) *SQLiteShareLinkRepository {
+	return &SQLiteShareLinkRepository{db: db}
+}
+
+// Create stores a new share link
+func (r *SQLiteShareLinkRepository) Create(ctx context.Context, link *domain.ShareLink) error {
+	eventTypesJSON, err := json.Marshal(nonNilStrings(link.EventTypes))
+	if err != nil {
+		return err
+	}
+	evpIDsJSON, err := json.Marshal(nonNilStrings(link.EVPIDs))
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO share_links (id, session_id, name, event_types, evp_ids, created_by, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, NULLIF(?, ''), ?, ?)`
+
+	_, err = r.db.ExecContext(ctx, query,
+		link.ID, link.SessionID, link.Name, string(eventTypesJSON), string(evpIDsJSON),
+		link.CreatedBy, link.CreatedAt, link.ExpiresAt,
+	)
+
+	return err
+}
+
+// GetByID retrieves a share link by ID, including revoked and expired links
+func (r *SQLiteShareLinkRepository) GetByID(ctx context.Context, id string) (*domain.ShareLink, error) {
+	query := `
+		SELECT id, session_id, name, event_types, evp_ids, COALESCE(created_by, ''),
+			created_at, expires_at, revoked_at
+		FROM share_links WHERE id = ?`
+
+	return scanShareLink(r.db.QueryRowContext(ctx, query, id))
+}
+
+// GetBySessionID retrieves the share links of a session, newest first
+func (r *SQLiteShareLinkRepository) GetBySessionID(ctx context.Context, sessionID string) ([]*domain.ShareLink, error) {
+	query := `
+		SELECT id, session_id, name, event_types, evp_ids, COALESCE(created_by, ''),
+			created_at, expires_at, revoked_at
+		FROM share_links WHERE session_id = ? ORDER BY created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []*domain.ShareLink
+	for rows.Next() {
+		link, err := scanShareLink(rows)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+
+	return links, rows.Err()
+}
+
+// Revoke marks a share link as revoked. Revoking a revoked link keeps the
+// original revocation time.
+func (r *SQLiteShareLinkRepository) Revoke(ctx context.Context, id string, at time.Time) error {
+	query := `UPDATE share_links SET revoked_at = COALESCE(revoked_at, ?) WHERE id = ?`
+
+	result, err := r.db.ExecContext(ctx, query, at, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// scanShareLink scans one share_links row from a Row or Rows
+func scanShareLink(row interface{ Scan(...interface{}) error }) (*domain.ShareLink, error) {
+	var link domain.ShareLink
+	var eventTypesJSON, evpIDsJSON string
+	var revokedAt sql.NullTime
+
+	err := row.Scan(
+		&link.ID, &link.SessionID, &link.Name, &eventTypesJSON, &evpIDsJSON, &link.CreatedBy,
+		&link.CreatedAt, &link.ExpiresAt, &revokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(eventTypesJSON), &link.EventTypes); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(evpIDsJSON), &link.EVPIDs); err != nil {
+		return nil, err
+	}
+	if revokedAt.Valid {
+		link.RevokedAt = &revokedAt.Time
+	}
+
+	return &link, nil
+}
+
+// nonNilStrings stores empty lists as [] rather than null
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteShareLinkRepository_Revoke_KeepsScopeAndFirstRevocation(t *testing.T) {
+	// Arrange
+	db := setupMigratedTestDB(t)
+	defer cleanupTestDB(db)
+	repo := NewSQLiteShareLinkRepository(db)
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Second)
+	require.NoError(t, repo.Create(ctx, &domain.ShareLink{
+		ID: "share-1", SessionID: "session-1", Name: "Owners", EventTypes: []string{"evp"},
+		CreatedAt: now, ExpiresAt: now.Add(time.Hour),
+	}))
+
+	// Act
+	firstErr := repo.Revoke(ctx, "share-1", now)
+	secondErr := repo.Revoke(ctx, "share-1", now.Add(time.Minute))
+	missingErr := repo.Revoke(ctx, "share-404", now)
+
+	// Assert
+	require.NoError(t, firstErr)
+	require.NoError(t, secondErr)
+	assert.ErrorIs(t, missingErr, sql.ErrNoRows)
+	link, err := repo.GetByID(ctx, "share-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"evp"}, link.EventTypes)
+	assert.Equal(t, []string{}, link.EVPIDs)
+	require.NotNil(t, link.RevokedAt)
+	assert.True(t, now.Equal(*link.RevokedAt))
+	assert.False(t, link.IsActive(now))
+}
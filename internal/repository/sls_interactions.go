@@ -25,6 +25,11 @@ func NewSQLiteSLSRepository(db *sql.DB) *SQLiteSLSRepository {
 
 // Create creates a new SLS detection
 func (r *SQLiteSLSRepository) Create(ctx context.Context, sls *domain.SLSDetection) error {
+	return insertSLSDetection(ctx, r.db, sls)
+}
+
+// insertSLSDetection inserts a SLS detection row through a database or transaction
+func insertSLSDetection(ctx context.Context, exec execer, sls *domain.SLSDetection) error {
 	skeletalJSON, _ := json.Marshal(sls.SkeletalPoints)
 	filtersJSON, _ := json.Marshal(sls.FilterApplied)
 
@@ -34,16 +39,17 @@ func (r *SQLiteSLSRepository) Create(ctx context.Context, sls *domain.SLSDetecti
 			bounding_box_top_left_x, bounding_box_top_left_y,
 			bounding_box_bottom_right_x, bounding_box_bottom_right_y,
 			bounding_box_width, bounding_box_height, video_frame, filter_applied,
-			duration, movement_speed, movement_direction, movement_pattern, created_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+			duration, movement_speed, movement_direction, movement_pattern, created_at, investigator_id, device_id
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''))`
 
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := exec.ExecContext(ctx, query,
 		sls.ID, sls.SessionID, sls.Timestamp, skeletalJSON, sls.Confidence,
 		sls.BoundingBox.TopLeft.X, sls.BoundingBox.TopLeft.Y,
 		sls.BoundingBox.BottomRight.X, sls.BoundingBox.BottomRight.Y,
 		sls.BoundingBox.Width, sls.BoundingBox.Height, sls.VideoFrame, filtersJSON,
 		sls.Duration, sls.Movement.Speed, sls.Movement.Direction, sls.Movement.Pattern,
 		sls.CreatedAt,
+		sls.InvestigatorID, sls.DeviceID,
 	)
 
 	return err
@@ -56,7 +62,8 @@ func (r *SQLiteSLSRepository) GetByID(ctx context.Context, id string) (*domain.S
 			bounding_box_top_left_x, bounding_box_top_left_y,
 			bounding_box_bottom_right_x, bounding_box_bottom_right_y,
 			bounding_box_width, bounding_box_height, video_frame, filter_applied,
-			duration, movement_speed, movement_direction, movement_pattern, created_at
+			duration, movement_speed, movement_direction, movement_pattern, created_at,
+			COALESCE(investigator_id, ''), COALESCE(device_id, '')
 		FROM sls_detections WHERE id = ?`
 
 	var sls domain.SLSDetection
@@ -68,7 +75,7 @@ func (r *SQLiteSLSRepository) GetByID(ctx context.Context, id string) (*domain.S
 		&sls.BoundingBox.BottomRight.X, &sls.BoundingBox.BottomRight.Y,
 		&sls.BoundingBox.Width, &sls.BoundingBox.Height, &sls.VideoFrame, &filtersJSON,
 		&sls.Duration, &sls.Movement.Speed, &sls.Movement.Direction, &sls.Movement.Pattern,
-		&sls.CreatedAt,
+		&sls.CreatedAt, &sls.InvestigatorID, &sls.DeviceID,
 	)
 
 	if err != nil {
@@ -88,7 +95,8 @@ func (r *SQLiteSLSRepository) GetBySessionID(ctx context.Context, sessionID stri
 			bounding_box_top_left_x, bounding_box_top_left_y,
 			bounding_box_bottom_right_x, bounding_box_bottom_right_y,
 			bounding_box_width, bounding_box_height, video_frame, filter_applied,
-			duration, movement_speed, movement_direction, movement_pattern, created_at
+			duration, movement_speed, movement_direction, movement_pattern, created_at,
+			COALESCE(investigator_id, ''), COALESCE(device_id, '')
 		FROM sls_detections WHERE session_id = ? ORDER BY timestamp DESC`
 
 	rows, err := r.db.QueryContext(ctx, query, sessionID)
@@ -108,7 +116,7 @@ func (r *SQLiteSLSRepository) GetBySessionID(ctx context.Context, sessionID stri
 			&sls.BoundingBox.BottomRight.X, &sls.BoundingBox.BottomRight.Y,
 			&sls.BoundingBox.Width, &sls.BoundingBox.Height, &sls.VideoFrame, &filtersJSON,
 			&sls.Duration, &sls.Movement.Speed, &sls.Movement.Direction, &sls.Movement.Pattern,
-			&sls.CreatedAt,
+			&sls.CreatedAt, &sls.InvestigatorID, &sls.DeviceID,
 		)
 		if err != nil {
 			return nil, err
@@ -164,7 +172,8 @@ func (r *SQLiteSLSRepository) GetByConfidenceRange(ctx context.Context, minConfi
 			bounding_box_top_left_x, bounding_box_top_left_y,
 			bounding_box_bottom_right_x, bounding_box_bottom_right_y,
 			bounding_box_width, bounding_box_height, video_frame, filter_applied,
-			duration, movement_speed, movement_direction, movement_pattern, created_at
+			duration, movement_speed, movement_direction, movement_pattern, created_at,
+			COALESCE(investigator_id, ''), COALESCE(device_id, '')
 		FROM sls_detections WHERE confidence >= ? ORDER BY confidence DESC`
 
 	rows, err := r.db.QueryContext(ctx, query, minConfidence)
@@ -184,7 +193,7 @@ func (r *SQLiteSLSRepository) GetByConfidenceRange(ctx context.Context, minConfi
 			&sls.BoundingBox.BottomRight.X, &sls.BoundingBox.BottomRight.Y,
 			&sls.BoundingBox.Width, &sls.BoundingBox.Height, &sls.VideoFrame, &filtersJSON,
 			&sls.Duration, &sls.Movement.Speed, &sls.Movement.Direction, &sls.Movement.Pattern,
-			&sls.CreatedAt,
+			&sls.CreatedAt, &sls.InvestigatorID, &sls.DeviceID,
 		)
 		if err != nil {
 			return nil, err
@@ -206,7 +215,8 @@ func (r *SQLiteSLSRepository) GetByDuration(ctx context.Context, minDuration flo
 			bounding_box_top_left_x, bounding_box_top_left_y,
 			bounding_box_bottom_right_x, bounding_box_bottom_right_y,
 			bounding_box_width, bounding_box_height, video_frame, filter_applied,
-			duration, movement_speed, movement_direction, movement_pattern, created_at
+			duration, movement_speed, movement_direction, movement_pattern, created_at,
+			COALESCE(investigator_id, ''), COALESCE(device_id, '')
 		FROM sls_detections WHERE duration >= ? ORDER BY duration DESC`
 
 	rows, err := r.db.QueryContext(ctx, query, minDuration)
@@ -226,7 +236,7 @@ func (r *SQLiteSLSRepository) GetByDuration(ctx context.Context, minDuration flo
 			&sls.BoundingBox.BottomRight.X, &sls.BoundingBox.BottomRight.Y,
 			&sls.BoundingBox.Width, &sls.BoundingBox.Height, &sls.VideoFrame, &filtersJSON,
 			&sls.Duration, &sls.Movement.Speed, &sls.Movement.Direction, &sls.Movement.Pattern,
-			&sls.CreatedAt,
+			&sls.CreatedAt, &sls.InvestigatorID, &sls.DeviceID,
 		)
 		if err != nil {
 			return nil, err
@@ -253,6 +263,11 @@ func NewSQLiteInteractionRepository(db *sql.DB) *SQLiteInteractionRepository {
 
 // Create creates a new user interaction
 func (r *SQLiteInteractionRepository) Create(ctx context.Context, interaction *domain.UserInteraction) error {
+	return insertUserInteraction(ctx, r.db, interaction)
+}
+
+// insertUserInteraction inserts a user interaction row through a database or transaction
+func insertUserInteraction(ctx context.Context, exec execer, interaction *domain.UserInteraction) error {
 	var randType, randResult, randRange sql.NullString
 	if interaction.RandomizerResult != nil {
 		randType = sql.NullString{String: interaction.RandomizerResult.Type, Valid: true}
@@ -265,13 +280,14 @@ func (r *SQLiteInteractionRepository) Create(ctx context.Context, interaction *d
 	query := `
 		INSERT INTO user_interactions (
 			id, session_id, timestamp, type, content, audio_path, response, response_time,
-			randomizer_type, randomizer_result, randomizer_range, created_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+			randomizer_type, randomizer_result, randomizer_range, created_at, investigator_id, device_id
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''))`
 
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := exec.ExecContext(ctx, query,
 		interaction.ID, interaction.SessionID, interaction.Timestamp, interaction.Type,
 		interaction.Content, interaction.AudioPath, interaction.Response, interaction.ResponseTime,
 		randType, randResult, randRange, interaction.CreatedAt,
+		interaction.InvestigatorID, interaction.DeviceID,
 	)
 
 	return err
@@ -281,7 +297,8 @@ func (r *SQLiteInteractionRepository) Create(ctx context.Context, interaction *d
 func (r *SQLiteInteractionRepository) GetByID(ctx context.Context, id string) (*domain.UserInteraction, error) {
 	query := `
 		SELECT id, session_id, timestamp, type, content, audio_path, response, response_time,
-			randomizer_type, randomizer_result, randomizer_range, created_at
+			randomizer_type, randomizer_result, randomizer_range, created_at,
+			COALESCE(investigator_id, ''), COALESCE(device_id, '')
 		FROM user_interactions WHERE id = ?`
 
 	var interaction domain.UserInteraction
@@ -292,7 +309,7 @@ func (r *SQLiteInteractionRepository) GetByID(ctx context.Context, id string) (*
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&interaction.ID, &interaction.SessionID, &interaction.Timestamp, &interaction.Type,
 		&interaction.Content, &audioPath, &response, &responseTime,
-		&randType, &randResult, &randRange, &interaction.CreatedAt,
+		&randType, &randResult, &randRange, &interaction.CreatedAt, &interaction.InvestigatorID, &interaction.DeviceID,
 	)
 
 	if err != nil {
@@ -324,7 +341,8 @@ func (r *SQLiteInteractionRepository) GetByID(ctx context.Context, id string) (*
 func (r *SQLiteInteractionRepository) GetBySessionID(ctx context.Context, sessionID string) ([]*domain.UserInteraction, error) {
 	query := `
 		SELECT id, session_id, timestamp, type, content, audio_path, response, response_time,
-			randomizer_type, randomizer_result, randomizer_range, created_at
+			randomizer_type, randomizer_result, randomizer_range, created_at,
+			COALESCE(investigator_id, ''), COALESCE(device_id, '')
 		FROM user_interactions WHERE session_id = ? ORDER BY timestamp DESC`
 
 	rows, err := r.db.QueryContext(ctx, query, sessionID)
@@ -343,7 +361,7 @@ func (r *SQLiteInteractionRepository) GetBySessionID(ctx context.Context, sessio
 		err := rows.Scan(
 			&interaction.ID, &interaction.SessionID, &interaction.Timestamp, &interaction.Type,
 			&interaction.Content, &audioPath, &response, &responseTime,
-			&randType, &randResult, &randRange, &interaction.CreatedAt,
+			&randType, &randResult, &randRange, &interaction.CreatedAt, &interaction.InvestigatorID, &interaction.DeviceID,
 		)
 		if err != nil {
 			return nil, err
@@ -410,7 +428,8 @@ func (r *SQLiteInteractionRepository) Delete(ctx context.Context, id string) err
 func (r *SQLiteInteractionRepository) GetByType(ctx context.Context, interactionType domain.InteractionType) ([]*domain.UserInteraction, error) {
 	query := `
 		SELECT id, session_id, timestamp, type, content, audio_path, response, response_time,
-			randomizer_type, randomizer_result, randomizer_range, created_at
+			randomizer_type, randomizer_result, randomizer_range, created_at,
+			COALESCE(investigator_id, ''), COALESCE(device_id, '')
 		FROM user_interactions WHERE type = ? ORDER BY timestamp DESC`
 
 	rows, err := r.db.QueryContext(ctx, query, interactionType)
@@ -429,7 +448,7 @@ func (r *SQLiteInteractionRepository) GetByType(ctx context.Context, interaction
 		err := rows.Scan(
 			&interaction.ID, &interaction.SessionID, &interaction.Timestamp, &interaction.Type,
 			&interaction.Content, &audioPath, &response, &responseTime,
-			&randType, &randResult, &randRange, &interaction.CreatedAt,
+			&randType, &randResult, &randRange, &interaction.CreatedAt, &interaction.InvestigatorID, &interaction.DeviceID,
 		)
 		if err != nil {
 			return nil, err
@@ -490,7 +509,7 @@ func (r *SQLiteFileRepository) SaveFile(ctx context.Context, path string, data [
 	// Record metadata in database
 	query := `
 		INSERT INTO files (id, session_id, file_path, file_type, file_size, mime_type, created_at)
-		VALUES (?, '', ?, 'user_file', ?, '', datetime('now'))`
+		VALUES (?, NULL, ?, 'user_file', ?, '', datetime('now'))`
 
 	// Generate safe file ID with fallback to current time
 	var id string
@@ -573,3 +592,23 @@ func (r *SQLiteFileRepository) ListFiles(ctx context.Context, directory string)
 
 	return fileNames, nil
 }
+
+// ListSessionFiles returns the paths of the files recorded for a session
+func (r *SQLiteFileRepository) ListSessionFiles(ctx context.Context, sessionID string) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT file_path FROM files WHERE session_id = ? ORDER BY file_path`, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var paths []string
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+
+	return paths, rows.Err()
+}
@@ -29,9 +29,10 @@ func (r *SQLiteSessionRepository) Create(ctx context.Context, session *domain.Se
 			id, title, location_latitude, location_longitude, location_address, 
 			location_description, location_venue, start_time, end_time, notes,
 			env_temperature, env_humidity, env_pressure, env_emf_level, 
-			env_light_level, env_noise_level, status, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+			env_light_level, env_noise_level, status, created_at, updated_at, version
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
+	session.Version = 1
 	_, err := r.db.ExecContext(ctx, query,
 		session.ID, session.Title, session.Location.Latitude, session.Location.Longitude,
 		session.Location.Address, session.Location.Description, session.Location.Venue,
@@ -39,7 +40,7 @@ func (r *SQLiteSessionRepository) Create(ctx context.Context, session *domain.Se
 		session.Environmental.Temperature, session.Environmental.Humidity,
 		session.Environmental.Pressure, session.Environmental.EMFLevel,
 		session.Environmental.LightLevel, session.Environmental.NoiseLevel,
-		session.Status, session.CreatedAt, session.UpdatedAt,
+		session.Status, session.CreatedAt, session.UpdatedAt, session.Version,
 	)
 
 	return err
@@ -51,7 +52,7 @@ func (r *SQLiteSessionRepository) GetByID(ctx context.Context, id string) (*doma
 		SELECT id, title, location_latitude, location_longitude, location_address,
 			location_description, location_venue, start_time, end_time, notes,
 			env_temperature, env_humidity, env_pressure, env_emf_level,
-			env_light_level, env_noise_level, status, created_at, updated_at
+			env_light_level, env_noise_level, status, created_at, updated_at, version
 		FROM sessions WHERE id = ?`
 
 	var session domain.Session
@@ -64,7 +65,7 @@ func (r *SQLiteSessionRepository) GetByID(ctx context.Context, id string) (*doma
 		&session.Environmental.Temperature, &session.Environmental.Humidity,
 		&session.Environmental.Pressure, &session.Environmental.EMFLevel,
 		&session.Environmental.LightLevel, &session.Environmental.NoiseLevel,
-		&session.Status, &session.CreatedAt, &session.UpdatedAt,
+		&session.Status, &session.CreatedAt, &session.UpdatedAt, &session.Version,
 	)
 
 	if err != nil {
@@ -84,7 +85,7 @@ func (r *SQLiteSessionRepository) GetAll(ctx context.Context, limit, offset int)
 		SELECT id, title, location_latitude, location_longitude, location_address,
 			location_description, location_venue, start_time, end_time, notes,
 			env_temperature, env_humidity, env_pressure, env_emf_level,
-			env_light_level, env_noise_level, status, created_at, updated_at
+			env_light_level, env_noise_level, status, created_at, updated_at, version
 		FROM sessions ORDER BY created_at DESC LIMIT ? OFFSET ?`
 
 	rows, err := r.db.QueryContext(ctx, query, limit, offset)
@@ -105,7 +106,7 @@ func (r *SQLiteSessionRepository) GetAll(ctx context.Context, limit, offset int)
 			&session.Environmental.Temperature, &session.Environmental.Humidity,
 			&session.Environmental.Pressure, &session.Environmental.EMFLevel,
 			&session.Environmental.LightLevel, &session.Environmental.NoiseLevel,
-			&session.Status, &session.CreatedAt, &session.UpdatedAt,
+			&session.Status, &session.CreatedAt, &session.UpdatedAt, &session.Version,
 		)
 		if err != nil {
 			return nil, err
@@ -127,7 +128,7 @@ func (r *SQLiteSessionRepository) GetByStatus(ctx context.Context, status domain
 		SELECT id, title, location_latitude, location_longitude, location_address,
 			location_description, location_venue, start_time, end_time, notes,
 			env_temperature, env_humidity, env_pressure, env_emf_level,
-			env_light_level, env_noise_level, status, created_at, updated_at
+			env_light_level, env_noise_level, status, created_at, updated_at, version
 		FROM sessions WHERE status = ? ORDER BY created_at DESC`
 
 	rows, err := r.db.QueryContext(ctx, query, status)
@@ -148,7 +149,7 @@ func (r *SQLiteSessionRepository) GetByStatus(ctx context.Context, status domain
 			&session.Environmental.Temperature, &session.Environmental.Humidity,
 			&session.Environmental.Pressure, &session.Environmental.EMFLevel,
 			&session.Environmental.LightLevel, &session.Environmental.NoiseLevel,
-			&session.Status, &session.CreatedAt, &session.UpdatedAt,
+			&session.Status, &session.CreatedAt, &session.UpdatedAt, &session.Version,
 		)
 		if err != nil {
 			return nil, err
@@ -164,7 +165,10 @@ func (r *SQLiteSessionRepository) GetByStatus(ctx context.Context, status domain
 	return sessions, rows.Err()
 }
 
-// Update updates a session
+// Update updates a session and moves it to the next version. The update
+// only applies while the stored version still equals session.Version, or
+// to any version when that is zero; otherwise it returns
+// domain.ErrVersionConflict, or sql.ErrNoRows when the session is gone.
 func (r *SQLiteSessionRepository) Update(ctx context.Context, session *domain.Session) error {
 	query := `
 		UPDATE sessions SET
@@ -173,22 +177,122 @@ func (r *SQLiteSessionRepository) Update(ctx context.Context, session *domain.Se
 			start_time = ?, end_time = ?, notes = ?,
 			env_temperature = ?, env_humidity = ?, env_pressure = ?,
 			env_emf_level = ?, env_light_level = ?, env_noise_level = ?,
-			status = ?, updated_at = ?
-		WHERE id = ?`
+			status = ?, updated_at = ?, version = version + 1
+		WHERE id = ? AND (? = 0 OR version = ?)
+		RETURNING version`
 
-	session.UpdatedAt = time.Now()
+	updatedAt := time.Now()
 
-	_, err := r.db.ExecContext(ctx, query,
+	var version int64
+	err := r.db.QueryRowContext(ctx, query,
 		session.Title, session.Location.Latitude, session.Location.Longitude,
 		session.Location.Address, session.Location.Description, session.Location.Venue,
 		session.StartTime, session.EndTime, session.Notes,
 		session.Environmental.Temperature, session.Environmental.Humidity,
 		session.Environmental.Pressure, session.Environmental.EMFLevel,
 		session.Environmental.LightLevel, session.Environmental.NoiseLevel,
-		session.Status, session.UpdatedAt, session.ID,
+		session.Status, updatedAt, session.ID, session.Version, session.Version,
+	).Scan(&version)
+	if err == sql.ErrNoRows {
+		return missingOrConflict(ctx, r.db, "sessions", session.ID)
+	}
+	if err != nil {
+		return err
+	}
+
+	session.UpdatedAt = updatedAt
+	session.Version = version
+	return nil
+}
+
+// UpdateStatus writes the session's status, end time and updated time and
+// records the change in session_status_history, in one transaction. The
+// update only applies while the stored status still equals
+// change.FromStatus; otherwise it returns sql.ErrNoRows. Like Update, it
+// moves the session to the next version.
+func (r *SQLiteSessionRepository) UpdateStatus(ctx context.Context, session *domain.Session, change *domain.SessionStatusChange) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var version int64
+	err = tx.QueryRowContext(ctx, `
+		UPDATE sessions SET status = ?, end_time = ?, updated_at = ?, version = version + 1
+		WHERE id = ? AND status = ?
+		RETURNING version`,
+		change.ToStatus, session.EndTime, session.UpdatedAt, session.ID, change.FromStatus,
+	).Scan(&version)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO session_status_history (id, session_id, from_status, to_status, reason, changed_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		change.ID, change.SessionID, change.FromStatus, change.ToStatus, change.Reason, change.ChangedAt,
 	)
+	if err != nil {
+		return fmt.Errorf("failed to record status change: %w", err)
+	}
 
-	return err
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	session.Version = version
+	return nil
+}
+
+// Touch sets the updated time of a session without changing anything else
+func (r *SQLiteSessionRepository) Touch(ctx context.Context, id string, at time.Time) error {
+	result, err := r.db.ExecContext(ctx, "UPDATE sessions SET updated_at = ? WHERE id = ?", at, id)
+	if err != nil {
+		return err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetStatusHistory retrieves the status changes of a session, oldest first
+func (r *SQLiteSessionRepository) GetStatusHistory(ctx context.Context, sessionID string) ([]*domain.SessionStatusChange, error) {
+	query := `
+		SELECT id, session_id, from_status, to_status, reason, changed_at
+		FROM session_status_history
+		WHERE session_id = ?
+		ORDER BY changed_at ASC, rowid ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []*domain.SessionStatusChange
+	for rows.Next() {
+		var change domain.SessionStatusChange
+		var reason sql.NullString
+
+		if err := rows.Scan(
+			&change.ID, &change.SessionID, &change.FromStatus, &change.ToStatus, &reason, &change.ChangedAt,
+		); err != nil {
+			return nil, err
+		}
+		change.Reason = reason.String
+
+		changes = append(changes, &change)
+	}
+
+	return changes, rows.Err()
 }
 
 // Delete deletes a session
@@ -204,7 +308,7 @@ func (r *SQLiteSessionRepository) GetByDateRange(ctx context.Context, start, end
 		SELECT id, title, location_latitude, location_longitude, location_address,
 			location_description, location_venue, start_time, end_time, notes,
 			env_temperature, env_humidity, env_pressure, env_emf_level,
-			env_light_level, env_noise_level, status, created_at, updated_at
+			env_light_level, env_noise_level, status, created_at, updated_at, version
 		FROM sessions 
 		WHERE start_time >= ? AND start_time <= ?
 		ORDER BY start_time DESC`
@@ -227,7 +331,7 @@ func (r *SQLiteSessionRepository) GetByDateRange(ctx context.Context, start, end
 			&session.Environmental.Temperature, &session.Environmental.Humidity,
 			&session.Environmental.Pressure, &session.Environmental.EMFLevel,
 			&session.Environmental.LightLevel, &session.Environmental.NoiseLevel,
-			&session.Status, &session.CreatedAt, &session.UpdatedAt,
+			&session.Status, &session.CreatedAt, &session.UpdatedAt, &session.Version,
 		)
 		if err != nil {
 			return nil, err
@@ -261,13 +365,15 @@ func (r *SQLiteEVPRepository) Create(ctx context.Context, evp *domain.EVPRecordi
 	query := `
 		INSERT INTO evp_recordings (
 			id, session_id, file_path, duration, timestamp, waveform_data,
-			processed_path, annotations, quality, detection_level, created_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+			processed_path, annotations, quality, detection_level, created_at, investigator_id, device_id, version
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), ?)`
 
+	evp.Version = 1
 	_, err := r.db.ExecContext(ctx, query,
 		evp.ID, evp.SessionID, evp.FilePath, evp.Duration, evp.Timestamp,
 		waveformJSON, evp.ProcessedPath, annotationsJSON,
 		evp.Quality, evp.DetectionLevel, evp.CreatedAt,
+		evp.InvestigatorID, evp.DeviceID, evp.Version,
 	)
 
 	return err
@@ -277,7 +383,8 @@ func (r *SQLiteEVPRepository) Create(ctx context.Context, evp *domain.EVPRecordi
 func (r *SQLiteEVPRepository) GetByID(ctx context.Context, id string) (*domain.EVPRecording, error) {
 	query := `
 		SELECT id, session_id, file_path, duration, timestamp, waveform_data,
-			processed_path, annotations, quality, detection_level, created_at
+			processed_path, annotations, quality, detection_level, created_at,
+			COALESCE(investigator_id, ''), COALESCE(device_id, ''), version
 		FROM evp_recordings WHERE id = ?`
 
 	var evp domain.EVPRecording
@@ -286,7 +393,7 @@ func (r *SQLiteEVPRepository) GetByID(ctx context.Context, id string) (*domain.E
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&evp.ID, &evp.SessionID, &evp.FilePath, &evp.Duration, &evp.Timestamp,
 		&waveformJSON, &evp.ProcessedPath, &annotationsJSON,
-		&evp.Quality, &evp.DetectionLevel, &evp.CreatedAt,
+		&evp.Quality, &evp.DetectionLevel, &evp.CreatedAt, &evp.InvestigatorID, &evp.DeviceID, &evp.Version,
 	)
 
 	if err != nil {
@@ -303,7 +410,8 @@ func (r *SQLiteEVPRepository) GetByID(ctx context.Context, id string) (*domain.E
 func (r *SQLiteEVPRepository) GetBySessionID(ctx context.Context, sessionID string) ([]*domain.EVPRecording, error) {
 	query := `
 		SELECT id, session_id, file_path, duration, timestamp, waveform_data,
-			processed_path, annotations, quality, detection_level, created_at
+			processed_path, annotations, quality, detection_level, created_at,
+			COALESCE(investigator_id, ''), COALESCE(device_id, ''), version
 		FROM evp_recordings WHERE session_id = ? ORDER BY timestamp DESC`
 
 	rows, err := r.db.QueryContext(ctx, query, sessionID)
@@ -320,7 +428,7 @@ func (r *SQLiteEVPRepository) GetBySessionID(ctx context.Context, sessionID stri
 		err := rows.Scan(
 			&evp.ID, &evp.SessionID, &evp.FilePath, &evp.Duration, &evp.Timestamp,
 			&waveformJSON, &evp.ProcessedPath, &annotationsJSON,
-			&evp.Quality, &evp.DetectionLevel, &evp.CreatedAt,
+			&evp.Quality, &evp.DetectionLevel, &evp.CreatedAt, &evp.InvestigatorID, &evp.DeviceID, &evp.Version,
 		)
 		if err != nil {
 			return nil, err
@@ -335,7 +443,8 @@ func (r *SQLiteEVPRepository) GetBySessionID(ctx context.Context, sessionID stri
 	return evps, rows.Err()
 }
 
-// Update updates an EVP recording
+// Update updates an EVP recording and moves it to the next version, under
+// the same version check as SQLiteSessionRepository.Update
 func (r *SQLiteEVPRepository) Update(ctx context.Context, evp *domain.EVPRecording) error {
 	waveformJSON, _ := json.Marshal(evp.WaveformData)
 	annotationsJSON, _ := json.Marshal(evp.Annotations)
@@ -343,14 +452,19 @@ func (r *SQLiteEVPRepository) Update(ctx context.Context, evp *domain.EVPRecordi
 	query := `
 		UPDATE evp_recordings SET
 			file_path = ?, duration = ?, timestamp = ?, waveform_data = ?,
-			processed_path = ?, annotations = ?, quality = ?, detection_level = ?
-		WHERE id = ?`
+			processed_path = ?, annotations = ?, quality = ?, detection_level = ?,
+			version = version + 1
+		WHERE id = ? AND (? = 0 OR version = ?)
+		RETURNING version`
 
-	_, err := r.db.ExecContext(ctx, query,
+	err := r.db.QueryRowContext(ctx, query,
 		evp.FilePath, evp.Duration, evp.Timestamp, waveformJSON,
 		evp.ProcessedPath, annotationsJSON, evp.Quality, evp.DetectionLevel,
-		evp.ID,
-	)
+		evp.ID, evp.Version, evp.Version,
+	).Scan(&evp.Version)
+	if err == sql.ErrNoRows {
+		return missingOrConflict(ctx, r.db, "evp_recordings", evp.ID)
+	}
 
 	return err
 }
@@ -366,7 +480,8 @@ func (r *SQLiteEVPRepository) Delete(ctx context.Context, id string) error {
 func (r *SQLiteEVPRepository) GetByQuality(ctx context.Context, quality domain.EVPQuality) ([]*domain.EVPRecording, error) {
 	query := `
 		SELECT id, session_id, file_path, duration, timestamp, waveform_data,
-			processed_path, annotations, quality, detection_level, created_at
+			processed_path, annotations, quality, detection_level, created_at,
+			COALESCE(investigator_id, ''), COALESCE(device_id, ''), version
 		FROM evp_recordings WHERE quality = ? ORDER BY timestamp DESC`
 
 	rows, err := r.db.QueryContext(ctx, query, quality)
@@ -383,7 +498,7 @@ func (r *SQLiteEVPRepository) GetByQuality(ctx context.Context, quality domain.E
 		err := rows.Scan(
 			&evp.ID, &evp.SessionID, &evp.FilePath, &evp.Duration, &evp.Timestamp,
 			&waveformJSON, &evp.ProcessedPath, &annotationsJSON,
-			&evp.Quality, &evp.DetectionLevel, &evp.CreatedAt,
+			&evp.Quality, &evp.DetectionLevel, &evp.CreatedAt, &evp.InvestigatorID, &evp.DeviceID, &evp.Version,
 		)
 		if err != nil {
 			return nil, err
@@ -402,7 +517,8 @@ func (r *SQLiteEVPRepository) GetByQuality(ctx context.Context, quality domain.E
 func (r *SQLiteEVPRepository) GetByDetectionLevel(ctx context.Context, minLevel float64) ([]*domain.EVPRecording, error) {
 	query := `
 		SELECT id, session_id, file_path, duration, timestamp, waveform_data,
-			processed_path, annotations, quality, detection_level, created_at
+			processed_path, annotations, quality, detection_level, created_at,
+			COALESCE(investigator_id, ''), COALESCE(device_id, ''), version
 		FROM evp_recordings WHERE detection_level >= ? ORDER BY detection_level DESC`
 
 	rows, err := r.db.QueryContext(ctx, query, minLevel)
@@ -419,7 +535,7 @@ func (r *SQLiteEVPRepository) GetByDetectionLevel(ctx context.Context, minLevel
 		err := rows.Scan(
 			&evp.ID, &evp.SessionID, &evp.FilePath, &evp.Duration, &evp.Timestamp,
 			&waveformJSON, &evp.ProcessedPath, &annotationsJSON,
-			&evp.Quality, &evp.DetectionLevel, &evp.CreatedAt,
+			&evp.Quality, &evp.DetectionLevel, &evp.CreatedAt, &evp.InvestigatorID, &evp.DeviceID, &evp.Version,
 		)
 		if err != nil {
 			return nil, err
@@ -434,6 +550,18 @@ func (r *SQLiteEVPRepository) GetByDetectionLevel(ctx context.Context, minLevel
 	return evps, rows.Err()
 }
 
+// missingOrConflict explains a versioned update that matched no row: it
+// returns sql.ErrNoRows when the record is gone and
+// domain.ErrVersionConflict when it has moved on to another version
+func missingOrConflict(ctx context.Context, db *sql.DB, table, id string) error {
+	var exists int
+	err := db.QueryRowContext(ctx, "SELECT 1 FROM "+table+" WHERE id = ?", id).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	return domain.ErrVersionConflict
+}
+
 // Database initialization and migration functions
 
 // NewSQLiteDB creates a new SQLite database connection
@@ -34,7 +34,8 @@ func setupTestSchema(t *testing.T, db *sql.DB) {
 			env_noise_level REAL,
 			status TEXT NOT NULL,
 			created_at DATETIME NOT NULL,
-			updated_at DATETIME NOT NULL
+			updated_at DATETIME NOT NULL,
+			version INTEGER NOT NULL DEFAULT 1
 		);
 
 		CREATE TABLE evp_recordings (
@@ -48,7 +49,10 @@ func setupTestSchema(t *testing.T, db *sql.DB) {
 			annotations TEXT,
 			quality TEXT NOT NULL,
 			detection_level REAL NOT NULL,
-			created_at DATETIME NOT NULL
+			investigator_id TEXT,
+			device_id TEXT,
+			created_at DATETIME NOT NULL,
+			version INTEGER NOT NULL DEFAULT 1
 		);
 
 		CREATE TABLE vox_events (
@@ -61,7 +65,10 @@ func setupTestSchema(t *testing.T, db *sql.DB) {
 			response_delay INTEGER,
 			randomizer_result TEXT,
 			audio_path TEXT,
-			created_at DATETIME NOT NULL
+			investigator_id TEXT,
+			device_id TEXT,
+			created_at DATETIME NOT NULL,
+			version INTEGER NOT NULL DEFAULT 1
 		);
 
 		CREATE TABLE radar_events (
@@ -72,6 +79,8 @@ func setupTestSchema(t *testing.T, db *sql.DB) {
 			position_x REAL NOT NULL,
 			position_y REAL NOT NULL,
 			movement_trail TEXT,
+			investigator_id TEXT,
+			device_id TEXT,
 			created_at DATETIME NOT NULL
 		);
 
@@ -83,6 +92,8 @@ func setupTestSchema(t *testing.T, db *sql.DB) {
 			skeletal_points TEXT,
 			bounding_box TEXT,
 			movement_pattern TEXT,
+			investigator_id TEXT,
+			device_id TEXT,
 			created_at DATETIME NOT NULL
 		);
 
@@ -93,6 +104,8 @@ func setupTestSchema(t *testing.T, db *sql.DB) {
 			response_time INTEGER,
 			randomizer_result TEXT,
 			audio_path TEXT,
+			investigator_id TEXT,
+			device_id TEXT,
 			created_at DATETIME NOT NULL
 		);
 
@@ -1427,3 +1440,34 @@ func TestSQLiteSessionRepository_UnicodeHandling_Success(t *testing.T) {
 	assert.Equal(t, session.Notes, retrieved.Notes)
 	assert.Equal(t, session.Location.Address, retrieved.Location.Address)
 }
+
+func TestSQLiteSessionRepository_Update_StaleVersion_ReturnsVersionConflict(t *testing.T) {
+	// Arrange
+	db := setupMigratedTestDB(t)
+	defer cleanupTestDB(db)
+	repo := NewSQLiteSessionRepository(db)
+	ctx := context.Background()
+
+	session := createTestSession()
+	require.NoError(t, repo.Create(ctx, session))
+	stale := *session
+
+	// Act
+	session.Notes = "First edit"
+	firstErr := repo.Update(ctx, session)
+	stale.Notes = "Second edit"
+	staleErr := repo.Update(ctx, &stale)
+	missing := createTestSession()
+	missing.ID = "missing-session"
+	missingErr := repo.Update(ctx, missing)
+
+	// Assert
+	require.NoError(t, firstErr)
+	assert.Equal(t, int64(2), session.Version)
+	assert.ErrorIs(t, staleErr, domain.ErrVersionConflict)
+	assert.ErrorIs(t, missingErr, sql.ErrNoRows)
+	stored, err := repo.GetByID(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "First edit", stored.Notes)
+	assert.Equal(t, int64(2), stored.Version)
+}
@@ -21,18 +21,25 @@ func NewSQLiteVOXRepository(db *sql.DB) *SQLiteVOXRepository {
 
 // Create creates a new VOX event
 func (r *SQLiteVOXRepository) Create(ctx context.Context, vox *domain.VOXEvent) error {
+	return insertVOXEvent(ctx, r.db, vox)
+}
+
+// insertVOXEvent inserts a VOX event row through a database or transaction
+func insertVOXEvent(ctx context.Context, exec execer, vox *domain.VOXEvent) error {
 	frequencyJSON, _ := json.Marshal(vox.FrequencyData)
 
 	query := `
 		INSERT INTO vox_events (
 			id, session_id, timestamp, generated_text, phonetic_bank, frequency_data,
-			trigger_strength, language_pack, modulation_type, user_response, response_delay, created_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+			trigger_strength, language_pack, modulation_type, user_response, response_delay, created_at, investigator_id, device_id, version
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), ?)`
 
-	_, err := r.db.ExecContext(ctx, query,
+	vox.Version = 1
+	_, err := exec.ExecContext(ctx, query,
 		vox.ID, vox.SessionID, vox.Timestamp, vox.GeneratedText, vox.PhoneticBank,
 		frequencyJSON, vox.TriggerStrength, vox.LanguagePack, vox.ModulationType,
 		vox.UserResponse, vox.ResponseDelay, vox.CreatedAt,
+		vox.InvestigatorID, vox.DeviceID, vox.Version,
 	)
 
 	return err
@@ -42,7 +49,8 @@ func (r *SQLiteVOXRepository) Create(ctx context.Context, vox *domain.VOXEvent)
 func (r *SQLiteVOXRepository) GetByID(ctx context.Context, id string) (*domain.VOXEvent, error) {
 	query := `
 		SELECT id, session_id, timestamp, generated_text, phonetic_bank, frequency_data,
-			trigger_strength, language_pack, modulation_type, user_response, response_delay, created_at
+			trigger_strength, language_pack, modulation_type, user_response, response_delay, created_at,
+			COALESCE(investigator_id, ''), COALESCE(device_id, ''), version
 		FROM vox_events WHERE id = ?`
 
 	var vox domain.VOXEvent
@@ -52,7 +60,7 @@ func (r *SQLiteVOXRepository) GetByID(ctx context.Context, id string) (*domain.V
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&vox.ID, &vox.SessionID, &vox.Timestamp, &vox.GeneratedText, &vox.PhoneticBank,
 		&frequencyJSON, &vox.TriggerStrength, &vox.LanguagePack, &vox.ModulationType,
-		&userResponse, &responseDelay, &vox.CreatedAt,
+		&userResponse, &responseDelay, &vox.CreatedAt, &vox.InvestigatorID, &vox.DeviceID, &vox.Version,
 	)
 
 	if err != nil {
@@ -77,7 +85,8 @@ func (r *SQLiteVOXRepository) GetByID(ctx context.Context, id string) (*domain.V
 func (r *SQLiteVOXRepository) GetBySessionID(ctx context.Context, sessionID string) ([]*domain.VOXEvent, error) {
 	query := `
 		SELECT id, session_id, timestamp, generated_text, phonetic_bank, frequency_data,
-			trigger_strength, language_pack, modulation_type, user_response, response_delay, created_at
+			trigger_strength, language_pack, modulation_type, user_response, response_delay, created_at,
+			COALESCE(investigator_id, ''), COALESCE(device_id, ''), version
 		FROM vox_events WHERE session_id = ? ORDER BY timestamp DESC`
 
 	rows, err := r.db.QueryContext(ctx, query, sessionID)
@@ -95,7 +104,7 @@ func (r *SQLiteVOXRepository) GetBySessionID(ctx context.Context, sessionID stri
 		err := rows.Scan(
 			&vox.ID, &vox.SessionID, &vox.Timestamp, &vox.GeneratedText, &vox.PhoneticBank,
 			&frequencyJSON, &vox.TriggerStrength, &vox.LanguagePack, &vox.ModulationType,
-			&userResponse, &responseDelay, &vox.CreatedAt,
+			&userResponse, &responseDelay, &vox.CreatedAt, &vox.InvestigatorID, &vox.DeviceID, &vox.Version,
 		)
 		if err != nil {
 			return nil, err
@@ -118,7 +127,8 @@ func (r *SQLiteVOXRepository) GetBySessionID(ctx context.Context, sessionID stri
 	return voxEvents, rows.Err()
 }
 
-// Update updates a VOX event
+// Update updates a VOX event and moves it to the next version, under the
+// same version check as SQLiteSessionRepository.Update
 func (r *SQLiteVOXRepository) Update(ctx context.Context, vox *domain.VOXEvent) error {
 	frequencyJSON, _ := json.Marshal(vox.FrequencyData)
 
@@ -126,14 +136,18 @@ func (r *SQLiteVOXRepository) Update(ctx context.Context, vox *domain.VOXEvent)
 		UPDATE vox_events SET
 			timestamp = ?, generated_text = ?, phonetic_bank = ?, frequency_data = ?,
 			trigger_strength = ?, language_pack = ?, modulation_type = ?,
-			user_response = ?, response_delay = ?
-		WHERE id = ?`
+			user_response = ?, response_delay = ?, version = version + 1
+		WHERE id = ? AND (? = 0 OR version = ?)
+		RETURNING version`
 
-	_, err := r.db.ExecContext(ctx, query,
+	err := r.db.QueryRowContext(ctx, query,
 		vox.Timestamp, vox.GeneratedText, vox.PhoneticBank, frequencyJSON,
 		vox.TriggerStrength, vox.LanguagePack, vox.ModulationType,
-		vox.UserResponse, vox.ResponseDelay, vox.ID,
-	)
+		vox.UserResponse, vox.ResponseDelay, vox.ID, vox.Version, vox.Version,
+	).Scan(&vox.Version)
+	if err == sql.ErrNoRows {
+		return missingOrConflict(ctx, r.db, "vox_events", vox.ID)
+	}
 
 	return err
 }
@@ -149,7 +163,8 @@ func (r *SQLiteVOXRepository) Delete(ctx context.Context, id string) error {
 func (r *SQLiteVOXRepository) GetByLanguagePack(ctx context.Context, languagePack string) ([]*domain.VOXEvent, error) {
 	query := `
 		SELECT id, session_id, timestamp, generated_text, phonetic_bank, frequency_data,
-			trigger_strength, language_pack, modulation_type, user_response, response_delay, created_at
+			trigger_strength, language_pack, modulation_type, user_response, response_delay, created_at,
+			COALESCE(investigator_id, ''), COALESCE(device_id, ''), version
 		FROM vox_events WHERE language_pack = ? ORDER BY timestamp DESC`
 
 	rows, err := r.db.QueryContext(ctx, query, languagePack)
@@ -167,7 +182,7 @@ func (r *SQLiteVOXRepository) GetByLanguagePack(ctx context.Context, languagePac
 		err := rows.Scan(
 			&vox.ID, &vox.SessionID, &vox.Timestamp, &vox.GeneratedText, &vox.PhoneticBank,
 			&frequencyJSON, &vox.TriggerStrength, &vox.LanguagePack, &vox.ModulationType,
-			&userResponse, &responseDelay, &vox.CreatedAt,
+			&userResponse, &responseDelay, &vox.CreatedAt, &vox.InvestigatorID, &vox.DeviceID, &vox.Version,
 		)
 		if err != nil {
 			return nil, err
@@ -194,7 +209,8 @@ func (r *SQLiteVOXRepository) GetByLanguagePack(ctx context.Context, languagePac
 func (r *SQLiteVOXRepository) GetByTriggerStrength(ctx context.Context, minStrength float64) ([]*domain.VOXEvent, error) {
 	query := `
 		SELECT id, session_id, timestamp, generated_text, phonetic_bank, frequency_data,
-			trigger_strength, language_pack, modulation_type, user_response, response_delay, created_at
+			trigger_strength, language_pack, modulation_type, user_response, response_delay, created_at,
+			COALESCE(investigator_id, ''), COALESCE(device_id, ''), version
 		FROM vox_events WHERE trigger_strength >= ? ORDER BY trigger_strength DESC`
 
 	rows, err := r.db.QueryContext(ctx, query, minStrength)
@@ -212,7 +228,7 @@ func (r *SQLiteVOXRepository) GetByTriggerStrength(ctx context.Context, minStren
 		err := rows.Scan(
 			&vox.ID, &vox.SessionID, &vox.Timestamp, &vox.GeneratedText, &vox.PhoneticBank,
 			&frequencyJSON, &vox.TriggerStrength, &vox.LanguagePack, &vox.ModulationType,
-			&userResponse, &responseDelay, &vox.CreatedAt,
+			&userResponse, &responseDelay, &vox.CreatedAt, &vox.InvestigatorID, &vox.DeviceID, &vox.Version,
 		)
 		if err != nil {
 			return nil, err
@@ -247,18 +263,24 @@ func NewSQLiteRadarRepository(db *sql.DB) *SQLiteRadarRepository {
 
 // Create creates a new radar event
 func (r *SQLiteRadarRepository) Create(ctx context.Context, radar *domain.RadarEvent) error {
+	return insertRadarEvent(ctx, r.db, radar)
+}
+
+// insertRadarEvent inserts a radar event row through a database or transaction
+func insertRadarEvent(ctx context.Context, exec execer, radar *domain.RadarEvent) error {
 	movementJSON, _ := json.Marshal(radar.MovementTrail)
 
 	query := `
 		INSERT INTO radar_events (
 			id, session_id, timestamp, position_x, position_y, position_z,
-			strength, source_type, emf_reading, audio_anomaly, duration, movement_trail, created_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+			strength, source_type, emf_reading, audio_anomaly, duration, movement_trail, created_at, investigator_id, device_id
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''))`
 
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := exec.ExecContext(ctx, query,
 		radar.ID, radar.SessionID, radar.Timestamp, radar.Position.X, radar.Position.Y, radar.Position.Z,
 		radar.Strength, radar.SourceType, radar.EMFReading, radar.AudioAnomaly, radar.Duration,
 		movementJSON, radar.CreatedAt,
+		radar.InvestigatorID, radar.DeviceID,
 	)
 
 	return err
@@ -268,7 +290,8 @@ func (r *SQLiteRadarRepository) Create(ctx context.Context, radar *domain.RadarE
 func (r *SQLiteRadarRepository) GetByID(ctx context.Context, id string) (*domain.RadarEvent, error) {
 	query := `
 		SELECT id, session_id, timestamp, position_x, position_y, position_z,
-			strength, source_type, emf_reading, audio_anomaly, duration, movement_trail, created_at
+			strength, source_type, emf_reading, audio_anomaly, duration, movement_trail, created_at,
+			COALESCE(investigator_id, ''), COALESCE(device_id, '')
 		FROM radar_events WHERE id = ?`
 
 	var radar domain.RadarEvent
@@ -277,7 +300,7 @@ func (r *SQLiteRadarRepository) GetByID(ctx context.Context, id string) (*domain
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&radar.ID, &radar.SessionID, &radar.Timestamp, &radar.Position.X, &radar.Position.Y, &radar.Position.Z,
 		&radar.Strength, &radar.SourceType, &radar.EMFReading, &radar.AudioAnomaly, &radar.Duration,
-		&movementJSON, &radar.CreatedAt,
+		&movementJSON, &radar.CreatedAt, &radar.InvestigatorID, &radar.DeviceID,
 	)
 
 	if err != nil {
@@ -293,7 +316,8 @@ func (r *SQLiteRadarRepository) GetByID(ctx context.Context, id string) (*domain
 func (r *SQLiteRadarRepository) GetBySessionID(ctx context.Context, sessionID string) ([]*domain.RadarEvent, error) {
 	query := `
 		SELECT id, session_id, timestamp, position_x, position_y, position_z,
-			strength, source_type, emf_reading, audio_anomaly, duration, movement_trail, created_at
+			strength, source_type, emf_reading, audio_anomaly, duration, movement_trail, created_at,
+			COALESCE(investigator_id, ''), COALESCE(device_id, '')
 		FROM radar_events WHERE session_id = ? ORDER BY timestamp DESC`
 
 	rows, err := r.db.QueryContext(ctx, query, sessionID)
@@ -310,7 +334,7 @@ func (r *SQLiteRadarRepository) GetBySessionID(ctx context.Context, sessionID st
 		err := rows.Scan(
 			&radar.ID, &radar.SessionID, &radar.Timestamp, &radar.Position.X, &radar.Position.Y, &radar.Position.Z,
 			&radar.Strength, &radar.SourceType, &radar.EMFReading, &radar.AudioAnomaly, &radar.Duration,
-			&movementJSON, &radar.CreatedAt,
+			&movementJSON, &radar.CreatedAt, &radar.InvestigatorID, &radar.DeviceID,
 		)
 		if err != nil {
 			return nil, err
@@ -355,7 +379,8 @@ func (r *SQLiteRadarRepository) Delete(ctx context.Context, id string) error {
 func (r *SQLiteRadarRepository) GetBySourceType(ctx context.Context, sourceType domain.SourceType) ([]*domain.RadarEvent, error) {
 	query := `
 		SELECT id, session_id, timestamp, position_x, position_y, position_z,
-			strength, source_type, emf_reading, audio_anomaly, duration, movement_trail, created_at
+			strength, source_type, emf_reading, audio_anomaly, duration, movement_trail, created_at,
+			COALESCE(investigator_id, ''), COALESCE(device_id, '')
 		FROM radar_events WHERE source_type = ? ORDER BY timestamp DESC`
 
 	rows, err := r.db.QueryContext(ctx, query, sourceType)
@@ -372,7 +397,7 @@ func (r *SQLiteRadarRepository) GetBySourceType(ctx context.Context, sourceType
 		err := rows.Scan(
 			&radar.ID, &radar.SessionID, &radar.Timestamp, &radar.Position.X, &radar.Position.Y, &radar.Position.Z,
 			&radar.Strength, &radar.SourceType, &radar.EMFReading, &radar.AudioAnomaly, &radar.Duration,
-			&movementJSON, &radar.CreatedAt,
+			&movementJSON, &radar.CreatedAt, &radar.InvestigatorID, &radar.DeviceID,
 		)
 		if err != nil {
 			return nil, err
@@ -390,7 +415,8 @@ func (r *SQLiteRadarRepository) GetBySourceType(ctx context.Context, sourceType
 func (r *SQLiteRadarRepository) GetByStrengthRange(ctx context.Context, minStrength, maxStrength float64) ([]*domain.RadarEvent, error) {
 	query := `
 		SELECT id, session_id, timestamp, position_x, position_y, position_z,
-			strength, source_type, emf_reading, audio_anomaly, duration, movement_trail, created_at
+			strength, source_type, emf_reading, audio_anomaly, duration, movement_trail, created_at,
+			COALESCE(investigator_id, ''), COALESCE(device_id, '')
 		FROM radar_events WHERE strength >= ? AND strength <= ? ORDER BY strength DESC`
 
 	rows, err := r.db.QueryContext(ctx, query, minStrength, maxStrength)
@@ -407,7 +433,7 @@ func (r *SQLiteRadarRepository) GetByStrengthRange(ctx context.Context, minStren
 		err := rows.Scan(
 			&radar.ID, &radar.SessionID, &radar.Timestamp, &radar.Position.X, &radar.Position.Y, &radar.Position.Z,
 			&radar.Strength, &radar.SourceType, &radar.EMFReading, &radar.AudioAnomaly, &radar.Duration,
-			&movementJSON, &radar.CreatedAt,
+			&movementJSON, &radar.CreatedAt, &radar.InvestigatorID, &radar.DeviceID,
 		)
 		if err != nil {
 			return nil, err
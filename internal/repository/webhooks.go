@@ -0,0 +1,308 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+)
+
+// SQLiteWebhookSubscriptionRepository implements WebhookSubscriptionRepository using SQLite
+type SQLiteWebhookSubscriptionRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteWebhookSubscriptionRepository creates a new SQLite webhook subscription repository
+func NewSQLiteWebhookSubscriptionRepository(db *sql.DB) *SQLiteWebhookSubscriptionRepository {
+	return &SQLiteWebhookSubscriptionRepository{db: db}
+}
+
+// Create stores a new webhook subscription
+func (r *SQLiteWebhookSubscriptionRepository) Create(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	eventTypesJSON, err := json.Marshal(nonNilStrings(subscription.EventTypes))
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO webhook_subscriptions (id, url, event_types, description, secret, enabled,
+			created_by, created_at, updated_at)
+		VALUES (?, ?, ?, NULLIF(?, ''), ?, ?, NULLIF(?, ''), ?, ?)`
+
+	_, err = r.db.ExecContext(ctx, query,
+		subscription.ID, subscription.URL, string(eventTypesJSON), subscription.Description, subscription.Secret,
+		subscription.Enabled, subscription.CreatedBy, subscription.CreatedAt, subscription.UpdatedAt,
+	)
+
+	return err
+}
+
+// GetByID retrieves a webhook subscription by ID, including its secret
+func (r *SQLiteWebhookSubscriptionRepository) GetByID(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	query := `
+		SELECT id, url, event_types, COALESCE(description, ''), secret, enabled,
+			COALESCE(created_by, ''), created_at, updated_at
+		FROM webhook_subscriptions WHERE id = ?`
+
+	return scanWebhookSubscription(r.db.QueryRowContext(ctx, query, id))
+}
+
+// GetAll retrieves every webhook subscription, oldest first
+func (r *SQLiteWebhookSubscriptionRepository) GetAll(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	query := `
+		SELECT id, url, event_types, COALESCE(description, ''), secret, enabled,
+			COALESCE(created_by, ''), created_at, updated_at
+		FROM webhook_subscriptions ORDER BY created_at, id`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscriptions []*domain.WebhookSubscription
+	for rows.Next() {
+		subscription, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+// Update replaces a webhook subscription's settings. Its secret and author
+// stay.
+func (r *SQLiteWebhookSubscriptionRepository) Update(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	eventTypesJSON, err := json.Marshal(nonNilStrings(subscription.EventTypes))
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE webhook_subscriptions SET url = ?, event_types = ?, description = NULLIF(?, ''),
+			enabled = ?, updated_at = ?
+		WHERE id = ?`
+
+	result, err := r.db.ExecContext(ctx, query,
+		subscription.URL, string(eventTypesJSON), subscription.Description,
+		subscription.Enabled, subscription.UpdatedAt, subscription.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// Delete removes a webhook subscription along with its deliveries
+func (r *SQLiteWebhookSubscriptionRepository) Delete(ctx context.Context, id string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM webhook_deliveries WHERE subscription_id = ?`, id); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
+
+// scanWebhookSubscription scans one webhook_subscriptions row from a Row or Rows
+func scanWebhookSubscription(row interface{ Scan(...interface{}) error }) (*domain.WebhookSubscription, error) {
+	var subscription domain.WebhookSubscription
+	var eventTypesJSON string
+
+	err := row.Scan(
+		&subscription.ID, &subscription.URL, &eventTypesJSON, &subscription.Description, &subscription.Secret,
+		&subscription.Enabled, &subscription.CreatedBy, &subscription.CreatedAt, &subscription.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(eventTypesJSON), &subscription.EventTypes); err != nil {
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+// SQLiteWebhookDeliveryRepository implements WebhookDeliveryRepository using SQLite
+type SQLiteWebhookDeliveryRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteWebhookDeliveryRepository creates a new SQLite webhook delivery repository
+func NewSQLiteWebhookDeliveryRepository(db *sql.DB) *SQLiteWebhookDeliveryRepository {
+	return &SQLiteWebhookDeliveryRepository{db: db}
+}
+
+// webhookDeliveryColumns are the columns scanWebhookDelivery reads
+const webhookDeliveryColumns = `
+	id, subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at,
+	last_attempt_at, response_status, COALESCE(last_error, ''), COALESCE(replay_of, ''),
+	created_at, delivered_at`
+
+// Create queues a new webhook delivery
+func (r *SQLiteWebhookDeliveryRepository) Create(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (id, subscription_id, event_id, event_type, payload, status,
+			attempts, next_attempt_at, last_attempt_at, response_status, last_error, replay_of,
+			created_at, delivered_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), ?, ?)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		delivery.ID, delivery.SubscriptionID, delivery.EventID, delivery.EventType, string(delivery.Payload),
+		delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.LastAttemptAt,
+		delivery.ResponseStatus, delivery.LastError, delivery.ReplayOf, delivery.CreatedAt, delivery.DeliveredAt,
+	)
+
+	return err
+}
+
+// GetByID retrieves a webhook delivery by ID
+func (r *SQLiteWebhookDeliveryRepository) GetByID(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries WHERE id = ?`
+
+	return scanWebhookDelivery(r.db.QueryRowContext(ctx, query, id))
+}
+
+// GetBySubscriptionID retrieves up to limit deliveries of a subscription,
+// newest first, optionally only those with a status
+func (r *SQLiteWebhookDeliveryRepository) GetBySubscriptionID(ctx context.Context, subscriptionID string, status domain.WebhookDeliveryStatus, limit int) ([]*domain.WebhookDelivery, error) {
+	query := `
+		SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries
+		WHERE subscription_id = ? AND (? = '' OR status = ?)
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?`
+
+	return r.query(ctx, query, subscriptionID, status, status, limit)
+}
+
+// GetDue retrieves up to limit pending deliveries due at a time, earliest first
+func (r *SQLiteWebhookDeliveryRepository) GetDue(ctx context.Context, at time.Time, limit int) ([]*domain.WebhookDelivery, error) {
+	query := `
+		SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries
+		WHERE status = ? AND next_attempt_at <= ?
+		ORDER BY next_attempt_at, id
+		LIMIT ?`
+
+	return r.query(ctx, query, domain.WebhookDeliveryPending, at, limit)
+}
+
+// Update records the outcome of a delivery attempt
+func (r *SQLiteWebhookDeliveryRepository) Update(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries SET status = ?, attempts = ?, next_attempt_at = ?, last_attempt_at = ?,
+			response_status = ?, last_error = NULLIF(?, ''), delivered_at = ?
+		WHERE id = ?`
+
+	result, err := r.db.ExecContext(ctx, query,
+		delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.LastAttemptAt,
+		delivery.ResponseStatus, delivery.LastError, delivery.DeliveredAt, delivery.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// DeleteFinishedBefore removes succeeded and failed deliveries created
+// before a time and returns how many were removed
+func (r *SQLiteWebhookDeliveryRepository) DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM webhook_deliveries WHERE status != ? AND created_at < ?`
+
+	result, err := r.db.ExecContext(ctx, query, domain.WebhookDeliveryPending, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// query runs a delivery query and scans its rows
+func (r *SQLiteWebhookDeliveryRepository) query(ctx context.Context, query string, args ...interface{}) ([]*domain.WebhookDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*domain.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// scanWebhookDelivery scans one webhook_deliveries row from a Row or Rows
+func scanWebhookDelivery(row interface{ Scan(...interface{}) error }) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	var payload string
+	var nextAttemptAt, lastAttemptAt, deliveredAt sql.NullTime
+
+	err := row.Scan(
+		&delivery.ID, &delivery.SubscriptionID, &delivery.EventID, &delivery.EventType, &payload,
+		&delivery.Status, &delivery.Attempts, &nextAttemptAt, &lastAttemptAt, &delivery.ResponseStatus,
+		&delivery.LastError, &delivery.ReplayOf, &delivery.CreatedAt, &deliveredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery.Payload = json.RawMessage(payload)
+	if nextAttemptAt.Valid {
+		delivery.NextAttemptAt = &nextAttemptAt.Time
+	}
+	if lastAttemptAt.Valid {
+		delivery.LastAttemptAt = &lastAttemptAt.Time
+	}
+	if deliveredAt.Valid {
+		delivery.DeliveredAt = &deliveredAt.Time
+	}
+
+	return &delivery, nil
+}
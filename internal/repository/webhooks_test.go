@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteWebhookDeliveryRepository_GetDue_SkipsFutureAndFinished(t *testing.T) {
+	// Arrange
+	db := setupMigratedTestDB(t)
+	defer cleanupTestDB(db)
+	subscriptions := NewSQLiteWebhookSubscriptionRepository(db)
+	repo := NewSQLiteWebhookDeliveryRepository(db)
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Second)
+	require.NoError(t, subscriptions.Create(ctx, &domain.WebhookSubscription{
+		ID: "webhook-1", URL: "http://localhost:9000/hook", EventTypes: []string{"session.completed"},
+		Secret: "whsec_test", Enabled: true, CreatedAt: now, UpdatedAt: now,
+	}))
+
+	delivery := func(id string, status domain.WebhookDeliveryStatus, next time.Time) *domain.WebhookDelivery {
+		return &domain.WebhookDelivery{
+			ID: id, SubscriptionID: "webhook-1", EventID: "event-" + id, EventType: "session.completed",
+			Payload: json.RawMessage(`{"id":"event-` + id + `"}`), Status: status, NextAttemptAt: &next,
+			CreatedAt: now.Add(-time.Hour),
+		}
+	}
+	require.NoError(t, repo.Create(ctx, delivery("late", domain.WebhookDeliveryPending, now.Add(-time.Minute))))
+	require.NoError(t, repo.Create(ctx, delivery("early", domain.WebhookDeliveryPending, now.Add(-2*time.Minute))))
+	require.NoError(t, repo.Create(ctx, delivery("future", domain.WebhookDeliveryPending, now.Add(time.Minute))))
+	require.NoError(t, repo.Create(ctx, delivery("done", domain.WebhookDeliverySucceeded, now.Add(-time.Minute))))
+
+	// Act
+	due, err := repo.GetDue(ctx, now, 10)
+	require.NoError(t, err)
+	purged, err := repo.DeleteFinishedBefore(ctx, now)
+	require.NoError(t, err)
+	remaining, err := repo.GetBySubscriptionID(ctx, "webhook-1", "", 10)
+	require.NoError(t, err)
+
+	// Assert
+	require.Len(t, due, 2)
+	assert.Equal(t, "early", due[0].ID)
+	assert.Equal(t, "late", due[1].ID)
+	assert.JSONEq(t, `{"id":"event-early"}`, string(due[0].Payload))
+	assert.Equal(t, int64(1), purged)
+	assert.Len(t, remaining, 3)
+}
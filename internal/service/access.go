@@ -0,0 +1,502 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+)
+
+// AccessService decides what the caller of a request may do with sessions
+// and exports, and manages teams and session access lists.
+//
+// Sessions are owned by an investigator and optionally shared with a team
+// (read and write) and with guests (read-only). Sessions without an owner
+// are open at team level to their team, or to every investigator when they
+// never had an owner or team, such as those created by devices or before
+// access control. Only admins may delete, archive or share them.
+// Requests without an identity run with authentication disabled and may do
+// anything; devices may read and record to every session.
+type AccessService struct {
+	investigatorRepo domain.InvestigatorRepository
+	teamRepo         domain.TeamRepository
+	aclRepo          domain.SessionACLRepository
+	exportRepo       domain.ExportRecordRepository
+}
+
+// CreateTeamRequest holds the details of a new team
+type CreateTeamRequest struct {
+	Name string `json:"name"`
+}
+
+// SessionOwnershipRequest sets the owner and team of a session
+type SessionOwnershipRequest struct {
+	OwnerID string `json:"owner_id"`
+	TeamID  string `json:"team_id,omitempty"`
+}
+
+// NewAccessService creates a new access service
+func NewAccessService(
+	investigatorRepo domain.InvestigatorRepository,
+	teamRepo domain.TeamRepository,
+	aclRepo domain.SessionACLRepository,
+	exportRepo domain.ExportRecordRepository,
+) *AccessService {
+	return &AccessService{
+		investigatorRepo: investigatorRepo,
+		teamRepo:         teamRepo,
+		aclRepo:          aclRepo,
+		exportRepo:       exportRepo,
+	}
+}
+
+// SessionAccess returns the caller's access level on a session
+func (s *AccessService) SessionAccess(ctx context.Context, sessionID string) (domain.AccessLevel, error) {
+	identity := domain.IdentityFromContext(ctx)
+	if identity == nil {
+		return domain.AccessOwner, nil
+	}
+	if identity.Kind == domain.PrincipalDevice {
+		return domain.AccessTeam, nil
+	}
+
+	acl, err := s.aclRepo.Get(ctx, sessionID)
+	if err != nil {
+		return domain.AccessNone, fmt.Errorf("failed to get session access list: %w", err)
+	}
+
+	var admin bool
+	if acl.OwnerID == "" {
+		if admin, err = s.IsAdmin(ctx); err != nil {
+			return domain.AccessNone, err
+		}
+	}
+
+	return s.investigatorAccess(ctx, identity.ID, admin, acl, nil)
+}
+
+// AuthorizeSession fails with a forbidden error unless the caller has at
+// least the required access to a session
+func (s *AccessService) AuthorizeSession(ctx context.Context, sessionID string, required domain.AccessLevel) error {
+	level, err := s.SessionAccess(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if !level.Allows(required) {
+		return fmt.Errorf("forbidden: session %s requires %s access", sessionID, required)
+	}
+	return nil
+}
+
+// FilterSessions keeps the sessions the caller may read
+func (s *AccessService) FilterSessions(ctx context.Context, sessions []*domain.Session) ([]*domain.Session, error) {
+	identity := domain.IdentityFromContext(ctx)
+	if identity == nil || identity.Kind == domain.PrincipalDevice {
+		return sessions, nil
+	}
+
+	teams, err := s.teamRepo.GetByInvestigator(ctx, identity.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get teams: %w", err)
+	}
+	teamIDs := make(map[string]bool, len(teams))
+	for _, team := range teams {
+		teamIDs[team.ID] = true
+	}
+	admin, err := s.IsAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := make([]*domain.Session, 0, len(sessions))
+	for _, session := range sessions {
+		acl, err := s.aclRepo.Get(ctx, session.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get session access list: %w", err)
+		}
+		level, err := s.investigatorAccess(ctx, identity.ID, admin, acl, teamIDs)
+		if err != nil {
+			return nil, err
+		}
+		if level.Allows(domain.AccessGuest) {
+			visible = append(visible, session)
+		}
+	}
+
+	return visible, nil
+}
+
+// CheckTeam verifies that a new session can be shared with a team: the team
+// must exist and an investigator caller must belong to it
+func (s *AccessService) CheckTeam(ctx context.Context, teamID string) error {
+	if teamID == "" {
+		return nil
+	}
+	_, err := s.GetTeam(ctx, teamID)
+	return err
+}
+
+// ClaimSession makes an investigator caller the owner of a new session and
+// shares it with a team. Sessions created by devices are left without an
+// owner.
+func (s *AccessService) ClaimSession(ctx context.Context, sessionID, teamID string) error {
+	var ownerID string
+	if identity := domain.IdentityFromContext(ctx); identity != nil && identity.Kind == domain.PrincipalInvestigator {
+		ownerID = identity.ID
+	}
+	if ownerID == "" && teamID == "" {
+		return nil
+	}
+
+	if err := s.aclRepo.SetOwnership(ctx, sessionID, ownerID, teamID, time.Now()); err != nil {
+		return fmt.Errorf("failed to set session owner: %w", err)
+	}
+	return nil
+}
+
+// GetSessionACL returns the access list of a session the caller can read
+func (s *AccessService) GetSessionACL(ctx context.Context, sessionID string) (*domain.SessionACL, error) {
+	if err := s.AuthorizeSession(ctx, sessionID, domain.AccessGuest); err != nil {
+		return nil, err
+	}
+	return s.aclRepo.Get(ctx, sessionID)
+}
+
+// SetSessionOwnership hands a session to a new owner and team. Only the
+// owner may do this.
+func (s *AccessService) SetSessionOwnership(ctx context.Context, sessionID string, req SessionOwnershipRequest) (*domain.SessionACL, error) {
+	if err := s.AuthorizeSession(ctx, sessionID, domain.AccessOwner); err != nil {
+		return nil, err
+	}
+
+	ownerID := strings.TrimSpace(req.OwnerID)
+	if ownerID == "" {
+		return nil, fmt.Errorf("invalid session access: owner_id is required")
+	}
+	if _, err := s.investigatorRepo.GetByID(ctx, ownerID); err != nil {
+		return nil, fmt.Errorf("investigator not found: %w", err)
+	}
+
+	teamID := strings.TrimSpace(req.TeamID)
+	if teamID != "" {
+		if _, err := s.teamRepo.GetByID(ctx, teamID); err != nil {
+			return nil, fmt.Errorf("team not found: %w", err)
+		}
+	}
+
+	if err := s.aclRepo.SetOwnership(ctx, sessionID, ownerID, teamID, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to set session owner: %w", err)
+	}
+
+	return s.aclRepo.Get(ctx, sessionID)
+}
+
+// AddSessionGuest gives an investigator read-only access to a session
+func (s *AccessService) AddSessionGuest(ctx context.Context, sessionID, investigatorID string) (*domain.SessionACL, error) {
+	if err := s.AuthorizeSession(ctx, sessionID, domain.AccessOwner); err != nil {
+		return nil, err
+	}
+	if _, err := s.investigatorRepo.GetByID(ctx, investigatorID); err != nil {
+		return nil, fmt.Errorf("investigator not found: %w", err)
+	}
+
+	if err := s.aclRepo.AddGuest(ctx, sessionID, investigatorID, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to add guest: %w", err)
+	}
+
+	return s.aclRepo.Get(ctx, sessionID)
+}
+
+// RemoveSessionGuest withdraws an investigator's read-only access to a session
+func (s *AccessService) RemoveSessionGuest(ctx context.Context, sessionID, investigatorID string) error {
+	if err := s.AuthorizeSession(ctx, sessionID, domain.AccessOwner); err != nil {
+		return err
+	}
+	return s.aclRepo.RemoveGuest(ctx, sessionID, investigatorID)
+}
+
+// CreateTeam creates a team with an investigator caller as its first member
+func (s *AccessService) CreateTeam(ctx context.Context, req CreateTeamRequest) (*domain.Team, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("invalid team: name is required")
+	}
+
+	team := &domain.Team{
+		ID:        generateID(),
+		Name:      name,
+		CreatedAt: time.Now(),
+	}
+	if err := s.teamRepo.Create(ctx, team); err != nil {
+		return nil, fmt.Errorf("failed to create team: %w", err)
+	}
+
+	if identity := domain.IdentityFromContext(ctx); identity != nil && identity.Kind == domain.PrincipalInvestigator {
+		member := &domain.TeamMember{TeamID: team.ID, InvestigatorID: identity.ID, JoinedAt: team.CreatedAt}
+		if err := s.teamRepo.AddMember(ctx, member); err != nil {
+			return nil, fmt.Errorf("failed to add team member: %w", err)
+		}
+	}
+
+	return team, nil
+}
+
+// GetTeam returns a team an investigator caller belongs to
+func (s *AccessService) GetTeam(ctx context.Context, id string) (*domain.Team, error) {
+	team, err := s.teamRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("team not found: %w", err)
+	}
+	if err := s.requireTeamMember(ctx, id); err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+// ListTeams returns the teams an investigator caller belongs to, or every
+// team for other callers
+func (s *AccessService) ListTeams(ctx context.Context) ([]*domain.Team, error) {
+	if identity := domain.IdentityFromContext(ctx); identity != nil && identity.Kind == domain.PrincipalInvestigator {
+		return s.teamRepo.GetByInvestigator(ctx, identity.ID)
+	}
+	return s.teamRepo.GetAll(ctx)
+}
+
+// DeleteTeam deletes a team the caller belongs to. Its sessions stay with
+// their owners.
+func (s *AccessService) DeleteTeam(ctx context.Context, id string) error {
+	if _, err := s.GetTeam(ctx, id); err != nil {
+		return err
+	}
+	return s.teamRepo.Delete(ctx, id)
+}
+
+// ListTeamMembers returns the members of a team the caller belongs to
+func (s *AccessService) ListTeamMembers(ctx context.Context, teamID string) ([]*domain.TeamMember, error) {
+	if _, err := s.GetTeam(ctx, teamID); err != nil {
+		return nil, err
+	}
+	return s.teamRepo.GetMembers(ctx, teamID)
+}
+
+// AddTeamMember adds an investigator to a team the caller belongs to
+func (s *AccessService) AddTeamMember(ctx context.Context, teamID, investigatorID string) error {
+	if _, err := s.GetTeam(ctx, teamID); err != nil {
+		return err
+	}
+	if _, err := s.investigatorRepo.GetByID(ctx, investigatorID); err != nil {
+		return fmt.Errorf("investigator not found: %w", err)
+	}
+
+	member := &domain.TeamMember{TeamID: teamID, InvestigatorID: investigatorID, JoinedAt: time.Now()}
+	if err := s.teamRepo.AddMember(ctx, member); err != nil {
+		return fmt.Errorf("failed to add team member: %w", err)
+	}
+	return nil
+}
+
+// RemoveTeamMember removes an investigator from a team the caller belongs to
+func (s *AccessService) RemoveTeamMember(ctx context.Context, teamID, investigatorID string) error {
+	if _, err := s.GetTeam(ctx, teamID); err != nil {
+		return err
+	}
+	return s.teamRepo.RemoveMember(ctx, teamID, investigatorID)
+}
+
+// RecordExport remembers who created an export file and which sessions it
+// holds. Recording a filename twice fails.
+func (s *AccessService) RecordExport(ctx context.Context, filename string, sessionIDs []string) error {
+	record := &domain.ExportRecord{
+		Filename:   filename,
+		SessionIDs: sessionIDs,
+		CreatedAt:  time.Now(),
+	}
+	if identity := domain.IdentityFromContext(ctx); identity != nil && identity.Kind == domain.PrincipalInvestigator {
+		record.CreatedBy = identity.ID
+	}
+
+	if err := s.exportRepo.Create(ctx, record); err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			return fmt.Errorf("export %s already exists", filename)
+		}
+		return fmt.Errorf("failed to record export: %w", err)
+	}
+	return nil
+}
+
+// ExportAccess returns the caller's access level on an export file: the
+// creator owns it, everyone else gets their lowest access to its sessions.
+// Exports without a record, such as those made before access control, are
+// left to admins.
+func (s *AccessService) ExportAccess(ctx context.Context, filename string) (domain.AccessLevel, error) {
+	identity := domain.IdentityFromContext(ctx)
+	if identity == nil {
+		return domain.AccessOwner, nil
+	}
+
+	record, err := s.exportRepo.GetByFilename(ctx, filename)
+	if errors.Is(err, sql.ErrNoRows) {
+		admin, err := s.IsAdmin(ctx)
+		if err != nil {
+			return domain.AccessNone, err
+		}
+		if admin {
+			return domain.AccessOwner, nil
+		}
+		return domain.AccessNone, nil
+	}
+	if err != nil {
+		return domain.AccessNone, fmt.Errorf("failed to get export record: %w", err)
+	}
+	if identity.Kind == domain.PrincipalInvestigator && record.CreatedBy == identity.ID {
+		return domain.AccessOwner, nil
+	}
+
+	level := domain.AccessOwner
+	for _, sessionID := range record.SessionIDs {
+		sessionLevel, err := s.SessionAccess(ctx, sessionID)
+		if err != nil {
+			return domain.AccessNone, err
+		}
+		if !sessionLevel.Allows(level) {
+			level = sessionLevel
+		}
+	}
+
+	return level, nil
+}
+
+// AuthorizeExport fails with a forbidden error unless the caller has at
+// least the required access to an export file
+func (s *AccessService) AuthorizeExport(ctx context.Context, filename string, required domain.AccessLevel) error {
+	level, err := s.ExportAccess(ctx, filename)
+	if err != nil {
+		return err
+	}
+	if !level.Allows(required) {
+		return fmt.Errorf("forbidden: export %s requires %s access", filename, required)
+	}
+	return nil
+}
+
+// ForgetExport removes the record of a deleted export file
+func (s *AccessService) ForgetExport(ctx context.Context, filename string) error {
+	return s.exportRepo.Delete(ctx, filename)
+}
+
+// IsAdmin reports whether the caller may manage investigators and devices.
+// Requests without an identity run with authentication disabled and are
+// treated as admins.
+func (s *AccessService) IsAdmin(ctx context.Context) (bool, error) {
+	identity := domain.IdentityFromContext(ctx)
+	if identity == nil {
+		return true, nil
+	}
+	if identity.Kind != domain.PrincipalInvestigator {
+		return false, nil
+	}
+
+	investigator, err := s.investigatorRepo.GetByID(ctx, identity.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get investigator: %w", err)
+	}
+	return investigator.IsAdmin, nil
+}
+
+// RequireAdmin fails with a forbidden error unless the caller is an admin
+func (s *AccessService) RequireAdmin(ctx context.Context) error {
+	admin, err := s.IsAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	if !admin {
+		return fmt.Errorf("forbidden: admin access required")
+	}
+	return nil
+}
+
+// AuthorizePrincipal fails with a forbidden error unless the caller is the
+// given investigator or device, or an admin
+func (s *AccessService) AuthorizePrincipal(ctx context.Context, kind domain.PrincipalKind, id string) error {
+	if identity := domain.IdentityFromContext(ctx); identity != nil && identity.Kind == kind && identity.ID == id {
+		return nil
+	}
+	if err := s.RequireAdmin(ctx); err != nil {
+		return fmt.Errorf("forbidden: only %s %s or an admin may do this", kind, id)
+	}
+	return nil
+}
+
+// CheckInvestigatorRemoval fails while an investigator still owns sessions,
+// which must be handed to a new owner before the investigator is deleted
+func (s *AccessService) CheckInvestigatorRemoval(ctx context.Context, investigatorID string) error {
+	sessionIDs, err := s.aclRepo.GetOwnedSessionIDs(ctx, investigatorID)
+	if err != nil {
+		return fmt.Errorf("failed to get owned sessions: %w", err)
+	}
+	if len(sessionIDs) > 0 {
+		return fmt.Errorf("investigator %s still owns sessions %s", investigatorID, strings.Join(sessionIDs, ", "))
+	}
+	return nil
+}
+
+// investigatorAccess resolves an investigator's level from a session's
+// access list. teamIDs, when given, holds the investigator's teams.
+func (s *AccessService) investigatorAccess(ctx context.Context, investigatorID string, admin bool, acl *domain.SessionACL, teamIDs map[string]bool) (domain.AccessLevel, error) {
+	if acl.OwnerID == investigatorID {
+		return domain.AccessOwner, nil
+	}
+	if acl.OwnerID == "" && admin {
+		return domain.AccessOwner, nil
+	}
+	if acl.Open {
+		return domain.AccessTeam, nil
+	}
+
+	if acl.TeamID != "" {
+		member := teamIDs[acl.TeamID]
+		if teamIDs == nil {
+			var err error
+			member, err = s.teamRepo.IsMember(ctx, acl.TeamID, investigatorID)
+			if err != nil {
+				return domain.AccessNone, fmt.Errorf("failed to check team membership: %w", err)
+			}
+		}
+		if member {
+			return domain.AccessTeam, nil
+		}
+	}
+
+	for _, guestID := range acl.GuestIDs {
+		if guestID == investigatorID {
+			return domain.AccessGuest, nil
+		}
+	}
+
+	return domain.AccessNone, nil
+}
+
+// requireTeamMember fails with a forbidden error when an investigator
+// caller does not belong to a team
+func (s *AccessService) requireTeamMember(ctx context.Context, teamID string) error {
+	identity := domain.IdentityFromContext(ctx)
+	if identity == nil || identity.Kind != domain.PrincipalInvestigator {
+		return nil
+	}
+
+	member, err := s.teamRepo.IsMember(ctx, teamID, identity.ID)
+	if err != nil {
+		return fmt.Errorf("failed to check team membership: %w", err)
+	}
+	if !member {
+		return fmt.Errorf("forbidden: not a member of team %s", teamID)
+	}
+	return nil
+}
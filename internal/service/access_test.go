@@ -0,0 +1,377 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/myideascope/otherside/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// accessFixture holds services sharing one migrated in-memory database with
+// investigators "owner", "teammate", "guest", "outsider" and the admin "admin"
+type accessFixture struct {
+	access       *AccessService
+	sessions     *SessionService
+	lifecycle    *SessionLifecycleService
+	participants *ParticipantService
+	manager      *SessionStateManager
+	db           *sql.DB
+}
+
+func setupAccessServices(t *testing.T) *accessFixture {
+	db := setupLifecycleDB(t)
+	ctx := context.Background()
+
+	investigatorRepo := repository.NewSQLiteInvestigatorRepository(db)
+	for _, id := range []string{"owner", "teammate", "guest", "outsider", "admin"} {
+		require.NoError(t, investigatorRepo.Create(ctx, &domain.Investigator{ID: id, Name: id, IsAdmin: id == "admin", CreatedAt: time.Now()}))
+	}
+
+	access := NewAccessService(
+		investigatorRepo,
+		repository.NewSQLiteTeamRepository(db),
+		repository.NewSQLiteSessionACLRepository(db),
+		repository.NewSQLiteExportRecordRepository(db),
+	)
+
+	sm := NewSessionStateManager(repository.NewSQLiteSessionRepository(db))
+	sessions := NewSessionService(sm, nil, nil, nil, nil, repository.NewSQLiteInteractionRepository(db), nil, nil, nil)
+	sessions.SetAccessService(access)
+	lifecycle := NewSessionLifecycleService(sm, repository.NewSQLiteEVPRepository(db), nil)
+	lifecycle.SetAccessService(access)
+	participants := NewParticipantService(
+		repository.NewSQLiteSessionRepository(db),
+		investigatorRepo,
+		repository.NewSQLiteDeviceRepository(db),
+		repository.NewSQLiteSessionParticipantRepository(db),
+	)
+	participants.SetAccessService(access)
+
+	return &accessFixture{access: access, sessions: sessions, lifecycle: lifecycle, participants: participants, manager: sm, db: db}
+}
+
+// as returns a context authenticated as an investigator
+func as(investigatorID string) context.Context {
+	return domain.ContextWithIdentity(context.Background(), &domain.Identity{
+		Kind: domain.PrincipalInvestigator,
+		ID:   investigatorID,
+	})
+}
+
+// sharedSession creates a session owned by "owner", shared with a team
+// holding "teammate" and with "guest" as a read-only guest
+func sharedSession(t *testing.T, f *accessFixture) *domain.Session {
+	team, err := f.access.CreateTeam(as("owner"), CreateTeamRequest{Name: "Night shift"})
+	require.NoError(t, err)
+	require.NoError(t, f.access.AddTeamMember(as("owner"), team.ID, "teammate"))
+
+	session, err := f.sessions.CreateSession(as("owner"), CreateSessionRequest{Title: "Cellar", TeamID: team.ID})
+	require.NoError(t, err)
+	_, err = f.access.AddSessionGuest(as("owner"), session.ID, "guest")
+	require.NoError(t, err)
+
+	return session
+}
+
+func TestAccessService_SessionAccess_SharedSession_ResolvesEachRole(t *testing.T) {
+	// Arrange
+	f := setupAccessServices(t)
+	session := sharedSession(t, f)
+	device := domain.ContextWithIdentity(context.Background(), &domain.Identity{Kind: domain.PrincipalDevice, ID: "radar-1"})
+
+	// Act
+	levels := map[string]domain.AccessLevel{}
+	for _, id := range []string{"owner", "teammate", "guest", "outsider"} {
+		level, err := f.access.SessionAccess(as(id), session.ID)
+		require.NoError(t, err)
+		levels[id] = level
+	}
+	deviceLevel, err := f.access.SessionAccess(device, session.ID)
+	require.NoError(t, err)
+	anonymousLevel, err := f.access.SessionAccess(context.Background(), session.ID)
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, domain.AccessOwner, levels["owner"])
+	assert.Equal(t, domain.AccessTeam, levels["teammate"])
+	assert.Equal(t, domain.AccessGuest, levels["guest"])
+	assert.Equal(t, domain.AccessNone, levels["outsider"])
+	assert.Equal(t, domain.AccessTeam, deviceLevel)
+	assert.Equal(t, domain.AccessOwner, anonymousLevel)
+}
+
+func TestSessionLifecycleService_DeleteSession_DeviceCreatedSession_Forbidden(t *testing.T) {
+	// Arrange
+	f := setupAccessServices(t)
+	device := domain.ContextWithIdentity(context.Background(), &domain.Identity{Kind: domain.PrincipalDevice, ID: "radar-1"})
+	session, err := f.sessions.CreateSession(device, CreateSessionRequest{Title: "Unattended"})
+	require.NoError(t, err)
+	_, err = f.lifecycle.CompleteSession(device, session.ID)
+	require.NoError(t, err)
+
+	// Act
+	level, err := f.access.SessionAccess(as("outsider"), session.ID)
+	require.NoError(t, err)
+	deleteErr := f.lifecycle.DeleteSession(as("outsider"), session.ID)
+	_, ownershipErr := f.access.SetSessionOwnership(as("outsider"), session.ID, SessionOwnershipRequest{OwnerID: "outsider"})
+	_, stillThere := f.manager.GetSession(context.Background(), session.ID)
+
+	// Assert
+	assert.Equal(t, domain.AccessTeam, level)
+	require.Error(t, deleteErr)
+	assert.Contains(t, deleteErr.Error(), "forbidden")
+	require.Error(t, ownershipErr)
+	assert.Contains(t, ownershipErr.Error(), "forbidden")
+	assert.NoError(t, stillThere)
+}
+
+func TestParticipantService_DeleteInvestigator_SessionOwner_KeepsSessionClosed(t *testing.T) {
+	// Arrange
+	f := setupAccessServices(t)
+	session, err := f.sessions.CreateSession(as("owner"), CreateSessionRequest{Title: "Private"})
+	require.NoError(t, err)
+
+	// Act
+	outsiderErr := f.participants.DeleteInvestigator(as("outsider"), "owner")
+	adminErr := f.participants.DeleteInvestigator(as("admin"), "owner")
+	level, err := f.access.SessionAccess(as("outsider"), session.ID)
+	require.NoError(t, err)
+	_, handOverErr := f.access.SetSessionOwnership(as("owner"), session.ID, SessionOwnershipRequest{OwnerID: "teammate"})
+	deleteErr := f.participants.DeleteInvestigator(as("owner"), "owner")
+
+	// Assert
+	require.Error(t, outsiderErr)
+	assert.Contains(t, outsiderErr.Error(), "forbidden")
+	require.Error(t, adminErr)
+	assert.Contains(t, adminErr.Error(), "still owns")
+	assert.Equal(t, domain.AccessNone, level)
+	require.NoError(t, handOverErr)
+	assert.NoError(t, deleteErr)
+}
+
+func TestAccessService_SessionAccess_TeamDeleted_SessionStaysClosed(t *testing.T) {
+	// Arrange
+	f := setupAccessServices(t)
+	team, err := f.access.CreateTeam(as("teammate"), CreateTeamRequest{Name: "Night shift"})
+	require.NoError(t, err)
+	device := domain.ContextWithIdentity(context.Background(), &domain.Identity{Kind: domain.PrincipalDevice, ID: "radar-1"})
+	session, err := f.sessions.CreateSession(device, CreateSessionRequest{Title: "Unattended", TeamID: team.ID})
+	require.NoError(t, err)
+
+	// Act
+	require.NoError(t, f.access.DeleteTeam(as("teammate"), team.ID))
+	outsiderLevel, err := f.access.SessionAccess(as("outsider"), session.ID)
+	require.NoError(t, err)
+	adminLevel, err := f.access.SessionAccess(as("admin"), session.ID)
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, domain.AccessNone, outsiderLevel)
+	assert.Equal(t, domain.AccessOwner, adminLevel)
+}
+
+func TestParticipantService_ManageDevices_NonAdmin_Forbidden(t *testing.T) {
+	// Arrange
+	f := setupAccessServices(t)
+	device, err := f.participants.CreateDevice(as("admin"), CreateDeviceRequest{ID: "radar-1", Name: "Hallway radar"})
+	require.NoError(t, err)
+	deviceCtx := domain.ContextWithIdentity(context.Background(), &domain.Identity{Kind: domain.PrincipalDevice, ID: device.ID})
+
+	// Act
+	_, createErr := f.participants.CreateDevice(as("outsider"), CreateDeviceRequest{Name: "Spirit box"})
+	_, investigatorErr := f.participants.CreateInvestigator(as("outsider"), CreateInvestigatorRequest{Name: "Mallory", IsAdmin: true})
+	deleteErr := f.participants.DeleteDevice(as("outsider"), device.ID)
+	selfDeleteErr := f.participants.DeleteDevice(deviceCtx, device.ID)
+
+	// Assert
+	require.Error(t, createErr)
+	assert.Contains(t, createErr.Error(), "forbidden")
+	require.Error(t, investigatorErr)
+	assert.Contains(t, investigatorErr.Error(), "forbidden")
+	require.Error(t, deleteErr)
+	assert.Contains(t, deleteErr.Error(), "forbidden")
+	assert.NoError(t, selfDeleteErr)
+}
+
+func TestSessionLifecycleService_Guest_CanReadButNotChangeSession(t *testing.T) {
+	// Arrange
+	f := setupAccessServices(t)
+	session := sharedSession(t, f)
+	title := "Attic"
+
+	// Act
+	_, historyErr := f.lifecycle.GetStatusHistory(as("guest"), session.ID)
+	_, updateErr := f.lifecycle.UpdateSession(as("guest"), session.ID, UpdateSessionRequest{Title: &title})
+	_, pauseErr := f.lifecycle.PauseSession(as("teammate"), session.ID)
+	deleteErr := f.lifecycle.DeleteSession(as("teammate"), session.ID)
+
+	// Assert
+	assert.NoError(t, historyErr)
+	require.Error(t, updateErr)
+	assert.Contains(t, updateErr.Error(), "forbidden")
+	assert.NoError(t, pauseErr)
+	require.Error(t, deleteErr)
+	assert.Contains(t, deleteErr.Error(), "forbidden")
+}
+
+func TestSessionService_GetAllSessions_Investigator_ListsOnlyVisibleSessions(t *testing.T) {
+	// Arrange
+	f := setupAccessServices(t)
+	shared := sharedSession(t, f)
+	_, err := f.sessions.CreateSession(as("outsider"), CreateSessionRequest{Title: "Private"})
+	require.NoError(t, err)
+	legacy := &domain.Session{ID: "legacy", Title: "Before access control", StartTime: time.Now()}
+	require.NoError(t, f.manager.CreateSession(context.Background(), legacy))
+
+	// Act
+	guestSessions, err := f.sessions.GetAllSessions(as("guest"), 10, 0)
+	require.NoError(t, err)
+	firstPage, err := f.sessions.GetAllSessions(as("guest"), 1, 0)
+	require.NoError(t, err)
+	secondPage, err := f.sessions.GetAllSessions(as("guest"), 1, 1)
+	require.NoError(t, err)
+
+	// Assert
+	var ids []string
+	for _, session := range guestSessions {
+		ids = append(ids, session.ID)
+	}
+	assert.ElementsMatch(t, []string{shared.ID, legacy.ID}, ids)
+	require.Len(t, firstPage, 1)
+	require.Len(t, secondPage, 1)
+	assert.NotEqual(t, firstPage[0].ID, secondPage[0].ID)
+}
+
+func TestAccessService_AuthorizeExport_NonOwner_CannotDelete(t *testing.T) {
+	// Arrange
+	f := setupAccessServices(t)
+	session := sharedSession(t, f)
+	require.NoError(t, f.access.RecordExport(as("teammate"), "otherside_export_1.json", []string{session.ID}))
+
+	// Act
+	creatorErr := f.access.AuthorizeExport(as("teammate"), "otherside_export_1.json", domain.AccessOwner)
+	ownerErr := f.access.AuthorizeExport(as("owner"), "otherside_export_1.json", domain.AccessOwner)
+	guestDeleteErr := f.access.AuthorizeExport(as("guest"), "otherside_export_1.json", domain.AccessOwner)
+	guestReadErr := f.access.AuthorizeExport(as("guest"), "otherside_export_1.json", domain.AccessGuest)
+	outsiderReadErr := f.access.AuthorizeExport(as("outsider"), "otherside_export_1.json", domain.AccessGuest)
+
+	// Assert
+	assert.NoError(t, creatorErr)
+	assert.NoError(t, ownerErr)
+	require.Error(t, guestDeleteErr)
+	assert.Contains(t, guestDeleteErr.Error(), "forbidden")
+	assert.NoError(t, guestReadErr)
+	require.Error(t, outsiderReadErr)
+	assert.Contains(t, outsiderReadErr.Error(), "forbidden")
+}
+
+func TestAccessService_RecordExport_SameFilename_ReturnsError(t *testing.T) {
+	// Arrange
+	f := setupAccessServices(t)
+	session := sharedSession(t, f)
+	outsiderSession, err := f.sessions.CreateSession(as("outsider"), CreateSessionRequest{Title: "Barn"})
+	require.NoError(t, err)
+	require.NoError(t, f.access.RecordExport(as("owner"), "otherside_export_1.json", []string{session.ID}))
+
+	// Act
+	err = f.access.RecordExport(as("outsider"), "otherside_export_1.json", []string{outsiderSession.ID})
+	readErr := f.access.AuthorizeExport(as("outsider"), "otherside_export_1.json", domain.AccessGuest)
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+	require.Error(t, readErr)
+	assert.Contains(t, readErr.Error(), "forbidden")
+	assert.NotEqual(t, exportFilename("json"), exportFilename("json"))
+}
+
+func TestAccessService_AuthorizeExport_UnrecordedExport_OnlyAdmin(t *testing.T) {
+	// Arrange
+	f := setupAccessServices(t)
+
+	// Act
+	ownerReadErr := f.access.AuthorizeExport(as("owner"), "otherside_export_legacy.json", domain.AccessGuest)
+	adminDeleteErr := f.access.AuthorizeExport(as("admin"), "otherside_export_legacy.json", domain.AccessOwner)
+	anonymousErr := f.access.AuthorizeExport(context.Background(), "otherside_export_legacy.json", domain.AccessOwner)
+
+	// Assert
+	require.Error(t, ownerReadErr)
+	assert.Contains(t, ownerReadErr.Error(), "forbidden")
+	assert.NoError(t, adminDeleteErr)
+	assert.NoError(t, anonymousErr)
+}
+
+func TestAccessService_GetTeam_NonMember_Forbidden(t *testing.T) {
+	// Arrange
+	f := setupAccessServices(t)
+	team, err := f.access.CreateTeam(as("owner"), CreateTeamRequest{Name: "Night shift"})
+	require.NoError(t, err)
+
+	// Act
+	_, getErr := f.access.GetTeam(as("outsider"), team.ID)
+	_, membersErr := f.access.ListTeamMembers(as("outsider"), team.ID)
+	members, memberErr := f.access.ListTeamMembers(as("owner"), team.ID)
+
+	// Assert
+	require.Error(t, getErr)
+	assert.Contains(t, getErr.Error(), "forbidden")
+	require.Error(t, membersErr)
+	assert.Contains(t, membersErr.Error(), "forbidden")
+	require.NoError(t, memberErr)
+	assert.Len(t, members, 1)
+}
+
+func TestEnvironmentalService_UnregisterSensor_OnlyDeviceOrSessionOwner(t *testing.T) {
+	// Arrange
+	f := setupAccessServices(t)
+	environmental := NewEnvironmentalService(
+		repository.NewSQLiteSessionRepository(f.db),
+		repository.NewSQLiteEnvironmentalReadingRepository(f.db),
+		repository.NewSQLiteEnvironmentalAnomalyRepository(f.db),
+		repository.NewSQLiteSensorRegistrationRepository(f.db),
+		EnvironmentalAnomalyConfig{},
+	)
+	environmental.SetAccessService(f.access)
+	session := sharedSession(t, f)
+	device := domain.ContextWithIdentity(context.Background(), &domain.Identity{Kind: domain.PrincipalDevice, ID: "emf-1"})
+	otherDevice := domain.ContextWithIdentity(context.Background(), &domain.Identity{Kind: domain.PrincipalDevice, ID: "emf-2"})
+	for _, id := range []string{"emf-1", "emf-2"} {
+		_, err := environmental.RegisterSensor(as("owner"), session.ID, id, id)
+		require.NoError(t, err)
+	}
+
+	// Act
+	teammateErr := environmental.UnregisterSensor(as("teammate"), "emf-1")
+	otherDeviceErr := environmental.UnregisterSensor(otherDevice, "emf-1")
+	selfErr := environmental.UnregisterSensor(device, "emf-1")
+	ownerErr := environmental.UnregisterSensor(as("owner"), "emf-2")
+
+	// Assert
+	require.Error(t, teammateErr)
+	assert.Contains(t, teammateErr.Error(), "forbidden")
+	require.Error(t, otherDeviceErr)
+	assert.Contains(t, otherDeviceErr.Error(), "forbidden")
+	assert.NoError(t, selfErr)
+	assert.NoError(t, ownerErr)
+}
+
+func TestAccessService_DeleteTeam_NonMember_Forbidden(t *testing.T) {
+	// Arrange
+	f := setupAccessServices(t)
+	team, err := f.access.CreateTeam(as("owner"), CreateTeamRequest{Name: "Night shift"})
+	require.NoError(t, err)
+
+	// Act
+	err = f.access.DeleteTeam(as("outsider"), team.ID)
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "forbidden")
+	_, err = f.access.GetTeam(context.Background(), team.ID)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,866 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+)
+
+// AlertEventEnvironmental is the event name alert conditions use for
+// environmental readings; their field is the metric
+const AlertEventEnvironmental = "environmental"
+
+const (
+	maxAlertRuleNameLength      = 200
+	maxAlertConditions          = 10
+	maxAlertWindowSeconds       = 3600.0
+	maxAlertDebounceSeconds     = 86400.0
+	defaultAlertDebounceSeconds = 30.0
+	defaultAlertListLimit       = 100
+	maxAlertListLimit           = 1000
+
+	// alertWebhookTimeout bounds a webhook delivery
+	alertWebhookTimeout = 5 * time.Second
+)
+
+// alertFields lists the fields alert conditions can test for each event
+// type, and whether each one is a number rather than a string
+var alertFields = map[string]map[string]bool{
+	LiveEventEVP: {
+		"quality": false, "class": false, "detection_level": true, "duration": true,
+	},
+	LiveEventVOX: {
+		"trigger_strength": true, "response_delay": true, "language_pack": false,
+		"phonetic_bank": false, "modulation_type": false,
+	},
+	LiveEventRadar: {
+		"strength": true, "emf_reading": true, "audio_anomaly": true, "duration": true, "source_type": false,
+	},
+	LiveEventSLS: {
+		"confidence": true, "duration": true, "speed": true, "pattern": false,
+	},
+	LiveEventInteraction: {
+		"type": false, "content": false, "response": false, "response_time": true,
+	},
+}
+
+// AlertService evaluates alert rules against every event recorded to a
+// session and every environmental reading, in process. When a rule fires it
+// stores an alert, publishes it to the session's live stream and posts it
+// to the rule's webhook. Enabled rules are cached and reloaded after a
+// change; the partial matches of each rule and session are kept in memory.
+type AlertService struct {
+	ruleRepo       domain.AlertRuleRepository
+	alertRepo      domain.AlertRepository
+	sessionRepo    domain.SessionRepository
+	accessService  *AccessService
+	eventHub       *EventHub
+	webhookService *WebhookService
+	webhookClient  *http.Client
+	now            func() time.Time
+
+	mu         sync.Mutex
+	rules      []*domain.AlertRule
+	generation int64
+	states     map[alertStateKey]*alertState
+	lastSweep  time.Time
+}
+
+// AlertRuleRequest describes an alert rule. Conditions are met by events no
+// more than WindowSeconds apart, which is required with more than one
+// condition. DebounceSeconds defaults to 30 and Enabled to true. A rule's
+// session cannot change once it is created.
+type AlertRuleRequest struct {
+	Name            string                  `json:"name"`
+	SessionID       string                  `json:"session_id,omitempty"`
+	Conditions      []domain.AlertCondition `json:"conditions"`
+	WindowSeconds   float64                 `json:"window_seconds,omitempty"`
+	DebounceSeconds *float64                `json:"debounce_seconds,omitempty"`
+	WebhookURL      string                  `json:"webhook_url,omitempty"`
+	Enabled         *bool                   `json:"enabled,omitempty"`
+}
+
+// alertStateKey identifies the evaluation state of a rule in a session
+type alertStateKey struct {
+	ruleID    string
+	sessionID string
+}
+
+// alertState holds the latest event meeting each condition of a rule in a
+// session, and when the rule last fired there
+type alertState struct {
+	matches   []*domain.AlertMatch
+	lastFired time.Time
+	expires   time.Time
+}
+
+// alertInput is an event being evaluated
+type alertInput struct {
+	event string
+	id    string
+	at    time.Time
+	data  interface{}
+}
+
+// firedAlert is a rule that fired, with the events that met its conditions
+type firedAlert struct {
+	rule    *domain.AlertRule
+	matches []domain.AlertMatch
+}
+
+// NewAlertService creates a new alert service
+func NewAlertService(
+	ruleRepo domain.AlertRuleRepository,
+	alertRepo domain.AlertRepository,
+	sessionRepo domain.SessionRepository,
+) *AlertService {
+	return &AlertService{
+		ruleRepo:      ruleRepo,
+		alertRepo:     alertRepo,
+		sessionRepo:   sessionRepo,
+		webhookClient: newWebhookClient(false),
+		now:           time.Now,
+		states:        make(map[alertStateKey]*alertState),
+	}
+}
+
+// SetAccessService enables per-session access checks on rules and alerts
+func (s *AlertService) SetAccessService(accessService *AccessService) {
+	s.accessService = accessService
+}
+
+// SetWebhookService sends raised alerts to alert.fired webhook subscribers
+// and posts rule webhooks with the webhook client, so that they follow the
+// same address and redirect policy
+func (s *AlertService) SetWebhookService(webhookService *WebhookService) {
+	s.webhookService = webhookService
+	s.webhookClient = webhookService.client
+}
+
+// SetEventHub publishes raised alerts to the live stream of their session
+func (s *AlertService) SetEventHub(eventHub *EventHub) {
+	s.eventHub = eventHub
+}
+
+// CreateRule validates and stores a new alert rule. Rules of one session
+// need team access to it; rules for every session belong to their author.
+func (s *AlertService) CreateRule(ctx context.Context, req AlertRuleRequest) (*domain.AlertRule, error) {
+	now := s.now()
+	rule := &domain.AlertRule{
+		ID:        generateID(),
+		SessionID: strings.TrimSpace(req.SessionID),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := applyAlertRuleRequest(rule, req); err != nil {
+		return nil, err
+	}
+
+	if rule.SessionID != "" {
+		if _, err := s.sessionRepo.GetByID(ctx, rule.SessionID); err != nil {
+			return nil, fmt.Errorf("session not found: %w", err)
+		}
+	}
+	if err := s.authorizeRule(ctx, rule, domain.AccessTeam); err != nil {
+		return nil, err
+	}
+	if identity := domain.IdentityFromContext(ctx); identity != nil && identity.Kind == domain.PrincipalInvestigator {
+		rule.CreatedBy = identity.ID
+	}
+
+	if err := s.ruleRepo.Create(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to create alert rule: %w", err)
+	}
+
+	s.reloadRules(rule.ID)
+	return rule, nil
+}
+
+// GetRule returns an alert rule the caller may read
+func (s *AlertService) GetRule(ctx context.Context, id string) (*domain.AlertRule, error) {
+	rule, err := s.getRule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeRule(ctx, rule, domain.AccessGuest); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// ListRules returns the alert rules the caller may read
+func (s *AlertService) ListRules(ctx context.Context) ([]*domain.AlertRule, error) {
+	rules, err := s.ruleRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert rules: %w", err)
+	}
+
+	visible := make([]*domain.AlertRule, 0, len(rules))
+	for _, rule := range rules {
+		if err := s.authorizeRule(ctx, rule, domain.AccessGuest); err != nil {
+			if strings.Contains(err.Error(), "forbidden") {
+				continue
+			}
+			return nil, err
+		}
+		visible = append(visible, rule)
+	}
+	return visible, nil
+}
+
+// UpdateRule replaces the settings of an alert rule and starts its
+// evaluation over
+func (s *AlertService) UpdateRule(ctx context.Context, id string, req AlertRuleRequest) (*domain.AlertRule, error) {
+	rule, err := s.getRule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeRule(ctx, rule, domain.AccessTeam); err != nil {
+		return nil, err
+	}
+	if sessionID := strings.TrimSpace(req.SessionID); sessionID != "" && sessionID != rule.SessionID {
+		return nil, fmt.Errorf("invalid alert rule: session_id cannot be changed")
+	}
+
+	if err := applyAlertRuleRequest(rule, req); err != nil {
+		return nil, err
+	}
+	rule.UpdatedAt = s.now()
+
+	if err := s.ruleRepo.Update(ctx, rule); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("alert rule not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to update alert rule: %w", err)
+	}
+
+	s.reloadRules(rule.ID)
+	return rule, nil
+}
+
+// DeleteRule removes an alert rule. The alerts it raised are kept.
+func (s *AlertService) DeleteRule(ctx context.Context, id string) error {
+	rule, err := s.getRule(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.authorizeRule(ctx, rule, domain.AccessTeam); err != nil {
+		return err
+	}
+
+	if err := s.ruleRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("alert rule not found: %s", id)
+		}
+		return fmt.Errorf("failed to delete alert rule: %w", err)
+	}
+
+	s.reloadRules(id)
+	return nil
+}
+
+// ListAlerts returns up to limit of the latest alerts raised in a session
+func (s *AlertService) ListAlerts(ctx context.Context, sessionID string, limit int) ([]*domain.Alert, error) {
+	if limit <= 0 {
+		limit = defaultAlertListLimit
+	}
+	if limit > maxAlertListLimit {
+		limit = maxAlertListLimit
+	}
+
+	if _, err := s.sessionRepo.GetByID(ctx, sessionID); err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+	if s.accessService != nil {
+		if err := s.accessService.AuthorizeSession(ctx, sessionID, domain.AccessGuest); err != nil {
+			return nil, err
+		}
+	}
+
+	alerts, err := s.alertRepo.GetBySessionID(ctx, sessionID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alerts: %w", err)
+	}
+	return alerts, nil
+}
+
+// ObserveEvent evaluates the alert rules against an event recorded to a
+// session. Failures are logged rather than failing the recording.
+func (s *AlertService) ObserveEvent(ctx context.Context, sessionID, eventType string, data interface{}) {
+	input, ok := newAlertInput(eventType, data)
+	if !ok {
+		return
+	}
+	s.evaluate(ctx, sessionID, []alertInput{input})
+}
+
+// ObserveReadings evaluates the alert rules against environmental readings
+// stored for a session
+func (s *AlertService) ObserveReadings(ctx context.Context, sessionID string, readings []*domain.EnvironmentalReading) {
+	inputs := make([]alertInput, 0, len(readings))
+	for _, reading := range readings {
+		inputs = append(inputs, alertInput{
+			event: AlertEventEnvironmental,
+			id:    reading.ID,
+			at:    reading.Timestamp,
+			data:  reading,
+		})
+	}
+	s.evaluate(ctx, sessionID, inputs)
+}
+
+// evaluate records the events meeting rule conditions and raises the
+// alerts of rules whose conditions are all met
+func (s *AlertService) evaluate(ctx context.Context, sessionID string, inputs []alertInput) {
+	if len(inputs) == 0 {
+		return
+	}
+
+	rules, err := s.activeRules(ctx)
+	if err != nil {
+		log.Printf("Failed to load alert rules: %v", err)
+		return
+	}
+	if len(rules) == 0 {
+		return
+	}
+
+	var fired []firedAlert
+	s.mu.Lock()
+	now := s.now()
+	for _, input := range inputs {
+		for _, rule := range rules {
+			if rule.SessionID != "" && rule.SessionID != sessionID {
+				continue
+			}
+			if matches := s.matchLocked(rule, sessionID, input, now); matches != nil {
+				fired = append(fired, firedAlert{rule: rule, matches: matches})
+			}
+		}
+	}
+	s.sweepLocked(now)
+	s.mu.Unlock()
+
+	for _, f := range fired {
+		s.raise(ctx, sessionID, f.rule, f.matches, now)
+	}
+}
+
+// matchLocked records an event against the conditions of a rule and
+// returns the matches when the rule fires; the caller holds s.mu
+func (s *AlertService) matchLocked(rule *domain.AlertRule, sessionID string, input alertInput, now time.Time) []domain.AlertMatch {
+	var state *alertState
+	for i, condition := range rule.Conditions {
+		if condition.Event != input.event {
+			continue
+		}
+		value, ok := alertFieldValue(input.data, condition.Field)
+		if !ok || !compareAlertValue(value, condition.Op, condition.Value) {
+			continue
+		}
+
+		if state == nil {
+			state = s.stateLocked(rule, sessionID)
+		}
+		// Keep the latest event for each condition, so that events arriving
+		// out of order do not replace newer ones
+		if previous := state.matches[i]; previous != nil && input.at.Before(previous.Timestamp) {
+			continue
+		}
+		state.matches[i] = &domain.AlertMatch{
+			Event:     input.event,
+			EventID:   input.id,
+			Field:     condition.Field,
+			Value:     value,
+			Timestamp: input.at,
+		}
+	}
+	if state == nil {
+		return nil
+	}
+
+	window := time.Duration(rule.WindowSeconds * float64(time.Second))
+	debounce := time.Duration(rule.DebounceSeconds * float64(time.Second))
+	if expires := now.Add(window); expires.After(state.expires) {
+		state.expires = expires
+	}
+
+	var first, last time.Time
+	for i, match := range state.matches {
+		if match == nil {
+			return nil
+		}
+		if i == 0 || match.Timestamp.Before(first) {
+			first = match.Timestamp
+		}
+		if i == 0 || match.Timestamp.After(last) {
+			last = match.Timestamp
+		}
+	}
+	if last.Sub(first) > window {
+		return nil
+	}
+	if !state.lastFired.IsZero() && now.Sub(state.lastFired) < debounce {
+		return nil
+	}
+
+	matches := make([]domain.AlertMatch, len(state.matches))
+	for i, match := range state.matches {
+		matches[i] = *match
+		state.matches[i] = nil
+	}
+	state.lastFired = now
+	if expires := now.Add(debounce); expires.After(state.expires) {
+		state.expires = expires
+	}
+	return matches
+}
+
+// stateLocked returns the evaluation state of a rule in a session,
+// creating it when needed; the caller holds s.mu
+func (s *AlertService) stateLocked(rule *domain.AlertRule, sessionID string) *alertState {
+	key := alertStateKey{ruleID: rule.ID, sessionID: sessionID}
+	state, exists := s.states[key]
+	if !exists {
+		state = &alertState{matches: make([]*domain.AlertMatch, len(rule.Conditions))}
+		s.states[key] = state
+	}
+	return state
+}
+
+// sweepLocked forgets states that can no longer fire or debounce, at most
+// once a minute; the caller holds s.mu
+func (s *AlertService) sweepLocked(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+
+	for key, state := range s.states {
+		if now.After(state.expires) {
+			delete(s.states, key)
+		}
+	}
+}
+
+// raise stores and announces an alert. A rule for every session only fires
+// in sessions its author can read.
+func (s *AlertService) raise(ctx context.Context, sessionID string, rule *domain.AlertRule, matches []domain.AlertMatch, at time.Time) {
+	if rule.SessionID == "" && rule.CreatedBy != "" && s.accessService != nil {
+		if err := s.accessService.AuthorizeSession(investigatorContext(ctx, rule.CreatedBy), sessionID, domain.AccessGuest); err != nil {
+			return
+		}
+	}
+
+	alert := &domain.Alert{
+		ID:          generateID(),
+		RuleID:      rule.ID,
+		RuleName:    rule.Name,
+		SessionID:   sessionID,
+		Message:     alertMessage(rule, matches),
+		Matches:     matches,
+		TriggeredAt: at,
+	}
+	if err := s.alertRepo.Create(ctx, alert); err != nil {
+		log.Printf("Failed to save alert of rule %s in session %s: %v", rule.ID, sessionID, err)
+		return
+	}
+
+	if s.eventHub != nil {
+		s.eventHub.Publish(sessionID, LiveEventAlert, alert)
+	}
+	if s.webhookService != nil {
+		s.webhookService.Notify(ctx, WebhookEventAlertFired, []string{sessionID}, alert)
+	}
+	if rule.WebhookURL != "" {
+		go s.postWebhook(rule.WebhookURL, alert)
+	}
+}
+
+// postWebhook posts an alert to a rule's webhook, once
+func (s *AlertService) postWebhook(webhookURL string, alert *domain.Alert) {
+	body, err := json.Marshal(map[string]interface{}{
+		"type":  LiveEventAlert,
+		"alert": alert,
+	})
+	if err != nil {
+		log.Printf("Failed to encode alert %s: %v", alert.ID, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), alertWebhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		log.Printf("Failed to post alert %s: %v", alert.ID, err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "OtherSide-Webhooks")
+	req.Header.Set(WebhookHeaderEvent, WebhookEventAlertFired)
+
+	resp, err := s.webhookClient.Do(req)
+	if err != nil {
+		log.Printf("Failed to post alert %s: %v", alert.ID, err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		log.Printf("Webhook for alert %s answered %s", alert.ID, resp.Status)
+	}
+}
+
+// activeRules returns the enabled rules, loading them after a change
+func (s *AlertService) activeRules(ctx context.Context) ([]*domain.AlertRule, error) {
+	s.mu.Lock()
+	rules, generation := s.rules, s.generation
+	s.mu.Unlock()
+	if rules != nil {
+		return rules, nil
+	}
+
+	all, err := s.ruleRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rules = make([]*domain.AlertRule, 0, len(all))
+	for _, rule := range all {
+		if rule.Enabled {
+			rules = append(rules, rule)
+		}
+	}
+
+	s.mu.Lock()
+	// A rule changed while loading; the next evaluation loads again
+	if s.generation == generation {
+		s.rules = rules
+	}
+	s.mu.Unlock()
+	return rules, nil
+}
+
+// reloadRules drops the cached rules and the evaluation state of a changed
+// rule
+func (s *AlertService) reloadRules(changedRuleID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rules = nil
+	s.generation++
+	for key := range s.states {
+		if key.ruleID == changedRuleID {
+			delete(s.states, key)
+		}
+	}
+}
+
+// getRule loads an alert rule
+func (s *AlertService) getRule(ctx context.Context, id string) (*domain.AlertRule, error) {
+	rule, err := s.ruleRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("alert rule not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get alert rule: %w", err)
+	}
+	return rule, nil
+}
+
+// authorizeRule checks the caller's access to a rule: the required access
+// to the session of a session rule, or authorship of a rule for every
+// session
+func (s *AlertService) authorizeRule(ctx context.Context, rule *domain.AlertRule, required domain.AccessLevel) error {
+	identity := domain.IdentityFromContext(ctx)
+	if identity == nil || identity.Kind == domain.PrincipalDevice {
+		return nil
+	}
+
+	if rule.SessionID != "" {
+		if s.accessService == nil {
+			return nil
+		}
+		return s.accessService.AuthorizeSession(ctx, rule.SessionID, required)
+	}
+
+	if rule.CreatedBy != "" && rule.CreatedBy != identity.ID {
+		return fmt.Errorf("forbidden: alert rule %s belongs to another investigator", rule.ID)
+	}
+	return nil
+}
+
+// applyAlertRuleRequest validates a rule request and copies it onto a rule
+func applyAlertRuleRequest(rule *domain.AlertRule, req AlertRuleRequest) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return fmt.Errorf("invalid alert rule: name is required")
+	}
+	if len(name) > maxAlertRuleNameLength {
+		return fmt.Errorf("invalid alert rule: name is longer than %d characters", maxAlertRuleNameLength)
+	}
+
+	if len(req.Conditions) == 0 {
+		return fmt.Errorf("invalid alert rule: at least one condition is required")
+	}
+	if len(req.Conditions) > maxAlertConditions {
+		return fmt.Errorf("invalid alert rule: more than %d conditions", maxAlertConditions)
+	}
+	conditions := make([]domain.AlertCondition, len(req.Conditions))
+	for i, condition := range req.Conditions {
+		normalized, err := normalizeAlertCondition(condition)
+		if err != nil {
+			return fmt.Errorf("invalid alert rule: condition %d: %w", i, err)
+		}
+		conditions[i] = normalized
+	}
+
+	if req.WindowSeconds < 0 || req.WindowSeconds > maxAlertWindowSeconds {
+		return fmt.Errorf("invalid alert rule: window_seconds must be between 0 and %.0f", maxAlertWindowSeconds)
+	}
+	if len(conditions) > 1 && req.WindowSeconds == 0 {
+		return fmt.Errorf("invalid alert rule: window_seconds is required with more than one condition")
+	}
+
+	debounce := defaultAlertDebounceSeconds
+	if req.DebounceSeconds != nil {
+		debounce = *req.DebounceSeconds
+	}
+	if debounce < 0 || debounce > maxAlertDebounceSeconds {
+		return fmt.Errorf("invalid alert rule: debounce_seconds must be between 0 and %.0f", maxAlertDebounceSeconds)
+	}
+
+	webhookURL := strings.TrimSpace(req.WebhookURL)
+	if webhookURL != "" {
+		parsed, err := url.Parse(webhookURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("invalid alert rule: webhook_url must be an http or https URL")
+		}
+	}
+
+	rule.Name = name
+	rule.Conditions = conditions
+	rule.WindowSeconds = req.WindowSeconds
+	rule.DebounceSeconds = debounce
+	rule.WebhookURL = webhookURL
+	rule.Enabled = req.Enabled == nil || *req.Enabled
+	return nil
+}
+
+// normalizeAlertCondition checks a condition against the fields of its
+// event type and stores numeric values as float64
+func normalizeAlertCondition(condition domain.AlertCondition) (domain.AlertCondition, error) {
+	condition.Event = strings.ToLower(strings.TrimSpace(condition.Event))
+	condition.Field = strings.ToLower(strings.TrimSpace(condition.Field))
+	condition.Op = strings.TrimSpace(condition.Op)
+
+	var numeric bool
+	if condition.Event == AlertEventEnvironmental {
+		if !IsEnvironmentalMetric(domain.EnvironmentalMetric(condition.Field)) {
+			return condition, fmt.Errorf("unknown environmental metric %q", condition.Field)
+		}
+		numeric = true
+	} else {
+		fields, known := alertFields[condition.Event]
+		if !known {
+			return condition, fmt.Errorf("unknown event %q", condition.Event)
+		}
+		isNumber, known := fields[condition.Field]
+		if !known {
+			return condition, fmt.Errorf("unknown %s field %q", condition.Event, condition.Field)
+		}
+		numeric = isNumber
+	}
+
+	switch condition.Op {
+	case "==", "!=":
+	case ">", ">=", "<", "<=":
+		if !numeric {
+			return condition, fmt.Errorf("%s %s is not a number and only supports == and !=", condition.Event, condition.Field)
+		}
+	default:
+		return condition, fmt.Errorf("unknown operator %q", condition.Op)
+	}
+
+	switch value := condition.Value.(type) {
+	case float64, string:
+	case int:
+		condition.Value = float64(value)
+	case int64:
+		condition.Value = float64(value)
+	default:
+		return condition, fmt.Errorf("value must be a number or a string")
+	}
+	if _, isNumber := condition.Value.(float64); isNumber != numeric {
+		if numeric {
+			return condition, fmt.Errorf("%s %s compares with a number", condition.Event, condition.Field)
+		}
+		return condition, fmt.Errorf("%s %s compares with a string", condition.Event, condition.Field)
+	}
+
+	return condition, nil
+}
+
+// newAlertInput describes a recorded session event for evaluation
+func newAlertInput(eventType string, data interface{}) (alertInput, bool) {
+	input := alertInput{event: eventType, data: data}
+	switch event := data.(type) {
+	case *domain.EVPRecording:
+		input.id, input.at = event.ID, event.Timestamp
+	case *domain.VOXEvent:
+		input.id, input.at = event.ID, event.Timestamp
+	case *domain.RadarEvent:
+		input.id, input.at = event.ID, event.Timestamp
+	case *domain.SLSDetection:
+		input.id, input.at = event.ID, event.Timestamp
+	case *domain.UserInteraction:
+		input.id, input.at = event.ID, event.Timestamp
+	default:
+		return input, false
+	}
+	return input, true
+}
+
+// alertFieldValue returns a field of an event named as in alertFields
+func alertFieldValue(data interface{}, field string) (interface{}, bool) {
+	switch event := data.(type) {
+	case *domain.EVPRecording:
+		switch field {
+		case "quality":
+			return string(event.Quality), true
+		case "class":
+			return evpClass(event.Quality), true
+		case "detection_level":
+			return event.DetectionLevel, true
+		case "duration":
+			return event.Duration, true
+		}
+	case *domain.VOXEvent:
+		switch field {
+		case "trigger_strength":
+			return event.TriggerStrength, true
+		case "response_delay":
+			return event.ResponseDelay, true
+		case "language_pack":
+			return event.LanguagePack, true
+		case "phonetic_bank":
+			return event.PhoneticBank, true
+		case "modulation_type":
+			return event.ModulationType, true
+		}
+	case *domain.RadarEvent:
+		switch field {
+		case "strength":
+			return event.Strength, true
+		case "emf_reading":
+			return event.EMFReading, true
+		case "audio_anomaly":
+			return event.AudioAnomaly, true
+		case "duration":
+			return event.Duration, true
+		case "source_type":
+			return string(event.SourceType), true
+		}
+	case *domain.SLSDetection:
+		switch field {
+		case "confidence":
+			return event.Confidence, true
+		case "duration":
+			return event.Duration, true
+		case "speed":
+			return event.Movement.Speed, true
+		case "pattern":
+			return event.Movement.Pattern, true
+		}
+	case *domain.UserInteraction:
+		switch field {
+		case "type":
+			return string(event.Type), true
+		case "content":
+			return event.Content, true
+		case "response":
+			return event.Response, true
+		case "response_time":
+			return event.ResponseTime, true
+		}
+	case *domain.EnvironmentalReading:
+		if field == string(event.Metric) {
+			return event.Value, true
+		}
+	}
+	return nil, false
+}
+
+// evpClass grades an EVP recording on the usual A to C scale: A is clear to
+// anyone, C is faint and open to interpretation
+func evpClass(quality domain.EVPQuality) string {
+	switch quality {
+	case domain.EVPQualityExcellent:
+		return "A"
+	case domain.EVPQualityGood:
+		return "B"
+	default:
+		return "C"
+	}
+}
+
+// compareAlertValue applies a condition operator to an event value
+func compareAlertValue(value interface{}, op string, target interface{}) bool {
+	if number, ok := value.(float64); ok {
+		threshold, ok := target.(float64)
+		if !ok {
+			return false
+		}
+		switch op {
+		case ">":
+			return number > threshold
+		case ">=":
+			return number >= threshold
+		case "<":
+			return number < threshold
+		case "<=":
+			return number <= threshold
+		case "==":
+			return number == threshold
+		case "!=":
+			return number != threshold
+		}
+		return false
+	}
+
+	text, ok := value.(string)
+	if !ok {
+		return false
+	}
+	expected, ok := target.(string)
+	if !ok {
+		return false
+	}
+	switch op {
+	case "==":
+		return strings.EqualFold(text, expected)
+	case "!=":
+		return !strings.EqualFold(text, expected)
+	}
+	return false
+}
+
+// alertMessage describes a fired rule and the values that fired it
+func alertMessage(rule *domain.AlertRule, matches []domain.AlertMatch) string {
+	parts := make([]string, len(matches))
+	for i, match := range matches {
+		condition := rule.Conditions[i]
+		parts[i] = fmt.Sprintf("%s %s %v %s %v", match.Event, match.Field, match.Value, condition.Op, condition.Value)
+	}
+	return fmt.Sprintf("%s: %s", rule.Name, strings.Join(parts, ", "))
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/myideascope/otherside/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupAlerts returns an alert service over a migrated in-memory database
+// holding one active session
+func setupAlerts(t *testing.T) *AlertService {
+	db := setupLifecycleDB(t)
+	sessionRepo := repository.NewSQLiteSessionRepository(db)
+	require.NoError(t, sessionRepo.Create(context.Background(), &domain.Session{
+		ID: "session-1", Title: "Cellar", StartTime: time.Now(), Status: domain.SessionStatusActive,
+	}))
+
+	return NewAlertService(
+		repository.NewSQLiteAlertRuleRepository(db), repository.NewSQLiteAlertRepository(db), sessionRepo,
+	)
+}
+
+func TestAlertService_ObserveEvent_ConditionsWithinWindow_RaisesDebouncedAlert(t *testing.T) {
+	// Arrange
+	alerts := setupAlerts(t)
+	hub := NewEventHub(0)
+	alerts.SetEventHub(hub)
+	ctx := context.Background()
+	debounce := 60.0
+	_, err := alerts.CreateRule(ctx, AlertRuleRequest{
+		Name: "EMF and radar",
+		Conditions: []domain.AlertCondition{
+			{Event: "environmental", Field: "emf", Op: ">", Value: 5},
+			{Event: "radar", Field: "strength", Op: ">", Value: 0.7},
+		},
+		WindowSeconds:   3,
+		DebounceSeconds: &debounce,
+	})
+	require.NoError(t, err)
+	sub, _ := hub.Subscribe("session-1", 0, []string{LiveEventAlert})
+	defer sub.Close()
+	start := time.Now()
+
+	// Act
+	alerts.ObserveReadings(ctx, "session-1", []*domain.EnvironmentalReading{
+		{ID: "reading-1", Metric: domain.EnvironmentalMetricEMF, Value: 6.2, Timestamp: start},
+	})
+	alerts.ObserveEvent(ctx, "session-1", LiveEventRadar, &domain.RadarEvent{ID: "radar-late", Strength: 0.9, Timestamp: start.Add(4 * time.Second)})
+	alerts.ObserveReadings(ctx, "session-1", []*domain.EnvironmentalReading{
+		{ID: "reading-2", Metric: domain.EnvironmentalMetricEMF, Value: 5.5, Timestamp: start.Add(8 * time.Second)},
+	})
+	alerts.ObserveEvent(ctx, "session-1", LiveEventRadar, &domain.RadarEvent{ID: "radar-2", Strength: 0.8, Timestamp: start.Add(9 * time.Second)})
+	alerts.ObserveEvent(ctx, "session-1", LiveEventRadar, &domain.RadarEvent{ID: "radar-3", Strength: 0.95, Timestamp: start.Add(10 * time.Second)})
+	raised, err := alerts.ListAlerts(ctx, "session-1", 0)
+	require.NoError(t, err)
+
+	// Assert
+	require.Len(t, raised, 1)
+	assert.Equal(t, "EMF and radar", raised[0].RuleName)
+	require.Len(t, raised[0].Matches, 2)
+	assert.Equal(t, "reading-2", raised[0].Matches[0].EventID)
+	assert.Equal(t, "radar-2", raised[0].Matches[1].EventID)
+	event := <-sub.Events
+	assert.Equal(t, raised[0].ID, event.Data.(*domain.Alert).ID)
+	assert.Empty(t, sub.Events)
+}
+
+func TestAlertService_ObserveEvent_ClassAEVPAfterDebounce_RaisesAgain(t *testing.T) {
+	// Arrange
+	alerts := setupAlerts(t)
+	ctx := context.Background()
+	rule, err := alerts.CreateRule(ctx, AlertRuleRequest{
+		Name:       "Class A EVP candidate",
+		SessionID:  "session-1",
+		Conditions: []domain.AlertCondition{{Event: "evp", Field: "class", Op: "==", Value: "A"}},
+	})
+	require.NoError(t, err)
+	now := time.Now()
+	alerts.now = func() time.Time { return now }
+
+	// Act
+	alerts.ObserveEvent(ctx, "session-1", LiveEventEVP, &domain.EVPRecording{ID: "evp-1", Quality: domain.EVPQualityGood})
+	alerts.ObserveEvent(ctx, "session-1", LiveEventEVP, &domain.EVPRecording{ID: "evp-2", Quality: domain.EVPQualityExcellent})
+	alerts.ObserveEvent(ctx, "session-1", LiveEventEVP, &domain.EVPRecording{ID: "evp-3", Quality: domain.EVPQualityExcellent})
+	now = now.Add(time.Duration(rule.DebounceSeconds+1) * time.Second)
+	alerts.ObserveEvent(ctx, "session-1", LiveEventEVP, &domain.EVPRecording{ID: "evp-4", Quality: domain.EVPQualityExcellent})
+	raised, err := alerts.ListAlerts(ctx, "session-1", 0)
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, defaultAlertDebounceSeconds, rule.DebounceSeconds)
+	require.Len(t, raised, 2)
+	assert.Equal(t, "evp-4", raised[0].Matches[0].EventID)
+	assert.Equal(t, "evp-2", raised[1].Matches[0].EventID)
+}
+
+func TestAlertService_CreateRule_InvalidRule_ReturnsError(t *testing.T) {
+	// Arrange
+	alerts := setupAlerts(t)
+	requests := map[string]AlertRuleRequest{
+		"unknown field": {Name: "Rule", Conditions: []domain.AlertCondition{{Event: "radar", Field: "colour", Op: "==", Value: "red"}}},
+		"order on text": {Name: "Rule", Conditions: []domain.AlertCondition{{Event: "evp", Field: "quality", Op: ">", Value: "good"}}},
+		"wrong value":   {Name: "Rule", Conditions: []domain.AlertCondition{{Event: "environmental", Field: "emf", Op: ">", Value: "high"}}},
+		"no window": {Name: "Rule", Conditions: []domain.AlertCondition{
+			{Event: "environmental", Field: "emf", Op: ">", Value: 5.0},
+			{Event: "radar", Field: "strength", Op: ">", Value: 0.7},
+		}},
+		"bad webhook": {Name: "Rule", WebhookURL: "ftp://example.com", Conditions: []domain.AlertCondition{{Event: "sls", Field: "confidence", Op: ">=", Value: 0.9}}},
+	}
+
+	for name, req := range requests {
+		// Act
+		_, err := alerts.CreateRule(context.Background(), req)
+
+		// Assert
+		require.Error(t, err, name)
+		assert.Contains(t, err.Error(), "invalid alert rule", name)
+	}
+}
+
+func TestAlertFieldValue_KnownFields_AreReadable(t *testing.T) {
+	// Arrange
+	events := map[string]interface{}{
+		LiveEventEVP:         &domain.EVPRecording{},
+		LiveEventVOX:         &domain.VOXEvent{},
+		LiveEventRadar:       &domain.RadarEvent{},
+		LiveEventSLS:         &domain.SLSDetection{},
+		LiveEventInteraction: &domain.UserInteraction{},
+	}
+
+	for eventType, fields := range alertFields {
+		for field, numeric := range fields {
+			// Act
+			value, ok := alertFieldValue(events[eventType], field)
+
+			// Assert
+			require.True(t, ok, "%s %s", eventType, field)
+			_, isNumber := value.(float64)
+			assert.Equal(t, numeric, isNumber, "%s %s", eventType, field)
+		}
+	}
+}
+
+func TestAlertService_PostWebhook_PrivateAddressOrRedirect_NotFollowed(t *testing.T) {
+	// Arrange
+	target := newWebhookStandIn(t, http.StatusOK)
+	redirector := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusFound))
+	t.Cleanup(redirector.Close)
+	guarded := setupAlerts(t)
+	open := setupAlerts(t)
+	webhooks, _, _ := setupWebhooks(t, WebhookDeliveryConfig{AllowPrivateAddresses: true})
+	open.SetWebhookService(webhooks)
+	alert := &domain.Alert{ID: "alert-1", RuleID: "rule-1", SessionID: "session-1"}
+
+	// Act
+	guarded.postWebhook(target.URL, alert)
+	open.postWebhook(redirector.URL, alert)
+	refusedCount := len(target.received())
+	open.postWebhook(target.URL, alert)
+
+	// Assert
+	assert.Zero(t, refusedCount)
+	requests := target.received()
+	require.Len(t, requests, 1)
+	assert.Equal(t, WebhookEventAlertFired, requests[0].header.Get(WebhookHeaderEvent))
+	assert.Contains(t, string(requests[0].body), "alert-1")
+}
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+)
+
+const (
+	// apiKeyPrefix marks OtherSide API keys so they are easy to spot in
+	// configuration and logs
+	apiKeyPrefix = "osk_"
+
+	// lastUsedResolution bounds how often a key's last use is written back
+	lastUsedResolution = time.Minute
+)
+
+// AuthService mints and checks API keys and signed bearer tokens. Keys are
+// stored as SHA-256 hashes; tokens are HS256 JWTs signed with a local
+// secret, so no external identity provider is needed.
+type AuthService struct {
+	keyRepo          domain.APIKeyRepository
+	investigatorRepo domain.InvestigatorRepository
+	deviceRepo       domain.DeviceRepository
+	tokenSecret      []byte
+	tokenTTL         time.Duration
+	now              func() time.Time
+}
+
+// CreateAPIKeyRequest names a new API key and the device or investigator
+// it belongs to. Exactly one of DeviceID and InvestigatorID must be set.
+type CreateAPIKeyRequest struct {
+	Name           string `json:"name"`
+	DeviceID       string `json:"device_id,omitempty"`
+	InvestigatorID string `json:"investigator_id,omitempty"`
+}
+
+// IssuedToken is a bearer token and when it stops being accepted
+type IssuedToken struct {
+	Token     string    `json:"token"`
+	TokenType string    `json:"token_type"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// tokenClaims is the payload of a bearer token
+type tokenClaims struct {
+	Subject   string               `json:"sub"`
+	Kind      domain.PrincipalKind `json:"kind"`
+	KeyID     string               `json:"kid"`
+	IssuedAt  int64                `json:"iat"`
+	ExpiresAt int64                `json:"exp"`
+}
+
+// NewAuthService creates a new auth service. Bearer tokens are signed with
+// tokenSecret and expire after tokenTTL.
+func NewAuthService(
+	keyRepo domain.APIKeyRepository,
+	investigatorRepo domain.InvestigatorRepository,
+	deviceRepo domain.DeviceRepository,
+	tokenSecret []byte,
+	tokenTTL time.Duration,
+) *AuthService {
+	return &AuthService{
+		keyRepo:          keyRepo,
+		investigatorRepo: investigatorRepo,
+		deviceRepo:       deviceRepo,
+		tokenSecret:      tokenSecret,
+		tokenTTL:         tokenTTL,
+		now:              time.Now,
+	}
+}
+
+// CreateAPIKey mints an API key. The returned plaintext key is not stored
+// and cannot be recovered later.
+func (s *AuthService) CreateAPIKey(ctx context.Context, req CreateAPIKeyRequest) (*domain.APIKey, string, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, "", fmt.Errorf("invalid api key: name is required")
+	}
+	if (req.DeviceID == "") == (req.InvestigatorID == "") {
+		return nil, "", fmt.Errorf("invalid api key: exactly one of device_id or investigator_id is required")
+	}
+
+	key := &domain.APIKey{
+		ID:        generateID(),
+		Name:      name,
+		CreatedAt: s.now(),
+	}
+
+	if req.DeviceID != "" {
+		if _, err := s.deviceRepo.GetByID(ctx, req.DeviceID); err != nil {
+			return nil, "", fmt.Errorf("device not found: %w", err)
+		}
+		key.PrincipalKind, key.PrincipalID = domain.PrincipalDevice, req.DeviceID
+	} else {
+		if _, err := s.investigatorRepo.GetByID(ctx, req.InvestigatorID); err != nil {
+			return nil, "", fmt.Errorf("investigator not found: %w", err)
+		}
+		key.PrincipalKind, key.PrincipalID = domain.PrincipalInvestigator, req.InvestigatorID
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+	plaintext := apiKeyPrefix + key.ID + "_" + hex.EncodeToString(secret)
+	key.KeyHash = hashAPIKey(plaintext)
+
+	if err := s.keyRepo.Create(ctx, key); err != nil {
+		return nil, "", fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	return key, plaintext, nil
+}
+
+// ListAPIKeys returns all API keys, including revoked ones
+func (s *AuthService) ListAPIKeys(ctx context.Context) ([]*domain.APIKey, error) {
+	return s.keyRepo.GetAll(ctx)
+}
+
+// RevokeAPIKey revokes an API key and every bearer token issued with it
+func (s *AuthService) RevokeAPIKey(ctx context.Context, id string) error {
+	if err := s.keyRepo.Revoke(ctx, id, s.now()); err != nil {
+		return fmt.Errorf("api key not found: %w", err)
+	}
+	return nil
+}
+
+// AuthenticateAPIKey returns the identity an API key belongs to
+func (s *AuthService) AuthenticateAPIKey(ctx context.Context, plaintext string) (*domain.Identity, error) {
+	id, ok := apiKeyID(plaintext)
+	if !ok {
+		return nil, fmt.Errorf("unauthenticated: malformed api key")
+	}
+
+	key, err := s.activeKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(key.KeyHash), []byte(hashAPIKey(plaintext))) != 1 {
+		return nil, fmt.Errorf("unauthenticated: invalid api key")
+	}
+
+	now := s.now()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedResolution {
+		if err := s.keyRepo.MarkUsed(ctx, key.ID, now); err != nil {
+			log.Printf("Failed to record use of api key %s: %v", key.ID, err)
+		}
+	}
+
+	return &domain.Identity{
+		Kind:   key.PrincipalKind,
+		ID:     key.PrincipalID,
+		KeyID:  key.ID,
+		Method: domain.AuthMethodAPIKey,
+	}, nil
+}
+
+// IssueToken signs a short-lived bearer token for an investigator who
+// authenticated with an API key. Devices use their API keys directly.
+func (s *AuthService) IssueToken(ctx context.Context, identity *domain.Identity) (*IssuedToken, error) {
+	if identity == nil || identity.Method != domain.AuthMethodAPIKey {
+		return nil, fmt.Errorf("forbidden: bearer tokens are issued in exchange for an api key")
+	}
+	if identity.Kind != domain.PrincipalInvestigator {
+		return nil, fmt.Errorf("forbidden: bearer tokens are only issued to investigators")
+	}
+
+	issuedAt := s.now()
+	expiresAt := issuedAt.Add(s.tokenTTL)
+
+	token, err := signToken(s.tokenSecret, tokenClaims{
+		Subject:   identity.ID,
+		Kind:      identity.Kind,
+		KeyID:     identity.KeyID,
+		IssuedAt:  issuedAt.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue token: %w", err)
+	}
+
+	return &IssuedToken{
+		Token:     token,
+		TokenType: "Bearer",
+		ExpiresAt: time.Unix(expiresAt.Unix(), 0),
+	}, nil
+}
+
+// AuthenticateToken verifies a bearer token and returns its identity.
+// Tokens stop working when they expire or when the key they were issued
+// for is revoked.
+func (s *AuthService) AuthenticateToken(ctx context.Context, token string) (*domain.Identity, error) {
+	var claims tokenClaims
+	if err := verifyToken(s.tokenSecret, token, &claims); err != nil {
+		return nil, fmt.Errorf("unauthenticated: %w", err)
+	}
+	if s.now().Unix() >= claims.ExpiresAt {
+		return nil, fmt.Errorf("unauthenticated: token expired")
+	}
+
+	key, err := s.activeKey(ctx, claims.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	if key.PrincipalKind != claims.Kind || key.PrincipalID != claims.Subject {
+		return nil, fmt.Errorf("unauthenticated: invalid token")
+	}
+
+	return &domain.Identity{
+		Kind:   claims.Kind,
+		ID:     claims.Subject,
+		KeyID:  claims.KeyID,
+		Method: domain.AuthMethodBearer,
+	}, nil
+}
+
+// activeKey returns an API key that exists, is not revoked and still
+// belongs to an existing device or investigator
+func (s *AuthService) activeKey(ctx context.Context, id string) (*domain.APIKey, error) {
+	key, err := s.keyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("unauthenticated: unknown api key")
+	}
+	if key.IsRevoked() {
+		return nil, fmt.Errorf("unauthenticated: api key revoked")
+	}
+
+	switch key.PrincipalKind {
+	case domain.PrincipalDevice:
+		_, err = s.deviceRepo.GetByID(ctx, key.PrincipalID)
+	case domain.PrincipalInvestigator:
+		_, err = s.investigatorRepo.GetByID(ctx, key.PrincipalID)
+	default:
+		err = fmt.Errorf("unknown principal kind %q", key.PrincipalKind)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unauthenticated: api key principal no longer exists")
+	}
+	return key, nil
+}
+
+// IsAPIKey reports whether a credential has the shape of an API key
+// rather than a bearer token
+func IsAPIKey(credential string) bool {
+	_, ok := apiKeyID(credential)
+	return ok
+}
+
+// apiKeyID extracts the key ID from a plaintext "osk_<id>_<secret>" key
+func apiKeyID(plaintext string) (string, bool) {
+	rest, ok := strings.CutPrefix(plaintext, apiKeyPrefix)
+	if !ok {
+		return "", false
+	}
+	id, secret, ok := strings.Cut(rest, "_")
+	if !ok || id == "" || secret == "" {
+		return "", false
+	}
+	return id, true
+}
+
+// hashAPIKey returns the hex SHA-256 hash a key is stored under. Keys carry
+// 256 random bits, so a fast hash is enough.
+func hashAPIKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
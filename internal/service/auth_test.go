@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/myideascope/otherside/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupAuthService returns an auth service over a migrated in-memory
+// database holding investigator "inv-1" and device "radar-1"
+func setupAuthService(t *testing.T) (*AuthService, domain.APIKeyRepository) {
+	db := setupLifecycleDB(t)
+	ctx := context.Background()
+
+	investigatorRepo := repository.NewSQLiteInvestigatorRepository(db)
+	deviceRepo := repository.NewSQLiteDeviceRepository(db)
+	require.NoError(t, investigatorRepo.Create(ctx, &domain.Investigator{ID: "inv-1", Name: "Ada", CreatedAt: time.Now()}))
+	require.NoError(t, deviceRepo.Create(ctx, &domain.Device{ID: "radar-1", Name: "Radar", CreatedAt: time.Now()}))
+
+	keyRepo := repository.NewSQLiteAPIKeyRepository(db)
+	return NewAuthService(keyRepo, investigatorRepo, deviceRepo, []byte("test-secret"), time.Hour), keyRepo
+}
+
+func TestAuthService_AuthenticateAPIKey_MintedKey_ReturnsIdentity(t *testing.T) {
+	// Arrange
+	authService, keyRepo := setupAuthService(t)
+	ctx := context.Background()
+
+	key, plaintext, err := authService.CreateAPIKey(ctx, CreateAPIKeyRequest{Name: "hallway radar", DeviceID: "radar-1"})
+	require.NoError(t, err)
+
+	// Act
+	identity, err := authService.AuthenticateAPIKey(ctx, plaintext)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, domain.PrincipalDevice, identity.Kind)
+	assert.Equal(t, "radar-1", identity.ID)
+	assert.Equal(t, key.ID, identity.KeyID)
+	assert.Equal(t, domain.AuthMethodAPIKey, identity.Method)
+
+	stored, err := keyRepo.GetByID(ctx, key.ID)
+	require.NoError(t, err)
+	assert.NotContains(t, stored.KeyHash, strings.TrimPrefix(plaintext, apiKeyPrefix+key.ID+"_"))
+	assert.NotNil(t, stored.LastUsedAt)
+}
+
+func TestAuthService_AuthenticateAPIKey_WrongSecret_ReturnsUnauthenticated(t *testing.T) {
+	// Arrange
+	authService, _ := setupAuthService(t)
+	ctx := context.Background()
+
+	key, _, err := authService.CreateAPIKey(ctx, CreateAPIKeyRequest{Name: "hallway radar", DeviceID: "radar-1"})
+	require.NoError(t, err)
+
+	// Act
+	_, err = authService.AuthenticateAPIKey(ctx, apiKeyPrefix+key.ID+"_guessed")
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unauthenticated")
+}
+
+func TestAuthService_AuthenticateAPIKey_DeletedPrincipal_ReturnsUnauthenticated(t *testing.T) {
+	// Arrange
+	authService, _ := setupAuthService(t)
+	ctx := context.Background()
+
+	_, deviceKey, err := authService.CreateAPIKey(ctx, CreateAPIKeyRequest{Name: "hallway radar", DeviceID: "radar-1"})
+	require.NoError(t, err)
+	_, investigatorKey, err := authService.CreateAPIKey(ctx, CreateAPIKeyRequest{Name: "tablet", InvestigatorID: "inv-1"})
+	require.NoError(t, err)
+	keyIdentity, err := authService.AuthenticateAPIKey(ctx, investigatorKey)
+	require.NoError(t, err)
+	token, err := authService.IssueToken(ctx, keyIdentity)
+	require.NoError(t, err)
+
+	require.NoError(t, authService.deviceRepo.Delete(ctx, "radar-1"))
+	require.NoError(t, authService.investigatorRepo.Delete(ctx, "inv-1"))
+
+	// Act
+	_, deviceErr := authService.AuthenticateAPIKey(ctx, deviceKey)
+	_, investigatorErr := authService.AuthenticateAPIKey(ctx, investigatorKey)
+	_, tokenErr := authService.AuthenticateToken(ctx, token.Token)
+
+	// Assert
+	for _, err := range []error{deviceErr, investigatorErr, tokenErr} {
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unauthenticated")
+	}
+}
+
+func TestAuthService_CreateAPIKey_BothPrincipals_ReturnsError(t *testing.T) {
+	// Arrange
+	authService, _ := setupAuthService(t)
+
+	// Act
+	_, _, err := authService.CreateAPIKey(context.Background(), CreateAPIKeyRequest{
+		Name: "shared", DeviceID: "radar-1", InvestigatorID: "inv-1",
+	})
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid api key")
+}
+
+func TestAuthService_AuthenticateToken_IssuedToken_ReturnsInvestigator(t *testing.T) {
+	// Arrange
+	authService, _ := setupAuthService(t)
+	ctx := context.Background()
+
+	_, plaintext, err := authService.CreateAPIKey(ctx, CreateAPIKeyRequest{Name: "tablet", InvestigatorID: "inv-1"})
+	require.NoError(t, err)
+	keyIdentity, err := authService.AuthenticateAPIKey(ctx, plaintext)
+	require.NoError(t, err)
+	token, err := authService.IssueToken(ctx, keyIdentity)
+	require.NoError(t, err)
+
+	// Act
+	identity, err := authService.AuthenticateToken(ctx, token.Token)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, domain.PrincipalInvestigator, identity.Kind)
+	assert.Equal(t, "inv-1", identity.ID)
+	assert.Equal(t, domain.AuthMethodBearer, identity.Method)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), token.ExpiresAt, time.Minute)
+}
+
+func TestAuthService_AuthenticateToken_ExpiredTamperedOrRevoked_ReturnsUnauthenticated(t *testing.T) {
+	// Arrange
+	authService, _ := setupAuthService(t)
+	ctx := context.Background()
+
+	key, plaintext, err := authService.CreateAPIKey(ctx, CreateAPIKeyRequest{Name: "tablet", InvestigatorID: "inv-1"})
+	require.NoError(t, err)
+	keyIdentity, err := authService.AuthenticateAPIKey(ctx, plaintext)
+	require.NoError(t, err)
+	token, err := authService.IssueToken(ctx, keyIdentity)
+	require.NoError(t, err)
+
+	parts := strings.Split(token.Token, ".")
+	tampered := parts[0] + "." + parts[1] + "." + tokenSignature([]byte("other-secret"), parts[0]+"."+parts[1])
+
+	// Act
+	_, tamperedErr := authService.AuthenticateToken(ctx, tampered)
+
+	authService.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	_, expiredErr := authService.AuthenticateToken(ctx, token.Token)
+
+	authService.now = time.Now
+	require.NoError(t, authService.RevokeAPIKey(ctx, key.ID))
+	_, revokedTokenErr := authService.AuthenticateToken(ctx, token.Token)
+	_, revokedKeyErr := authService.AuthenticateAPIKey(ctx, plaintext)
+
+	// Assert
+	require.Error(t, tamperedErr)
+	assert.Contains(t, tamperedErr.Error(), "invalid token signature")
+	require.Error(t, expiredErr)
+	assert.Contains(t, expiredErr.Error(), "token expired")
+	require.Error(t, revokedTokenErr)
+	assert.Contains(t, revokedTokenErr.Error(), "revoked")
+	require.Error(t, revokedKeyErr)
+	assert.Contains(t, revokedKeyErr.Error(), "revoked")
+}
+
+func TestAuthService_IssueToken_DeviceKey_ReturnsForbidden(t *testing.T) {
+	// Arrange
+	authService, _ := setupAuthService(t)
+	ctx := context.Background()
+
+	_, plaintext, err := authService.CreateAPIKey(ctx, CreateAPIKeyRequest{Name: "hallway radar", DeviceID: "radar-1"})
+	require.NoError(t, err)
+	identity, err := authService.AuthenticateAPIKey(ctx, plaintext)
+	require.NoError(t, err)
+
+	// Act
+	_, err = authService.IssueToken(ctx, identity)
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "forbidden")
+}
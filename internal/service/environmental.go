@@ -0,0 +1,580 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+)
+
+const (
+	maxEnvironmentalBatchSize         = 10000
+	defaultEnvironmentalBucketSeconds = 60
+	maxEnvironmentalBuckets           = 10000
+	defaultBaselineWindowSeconds      = 300.0
+	defaultBaselineMinSamples         = 10
+	defaultAnomalyThreshold           = 3.0
+	defaultAnomalyMinStdDev           = 0.1
+	defaultAnomalyCooldownSeconds     = 30.0
+)
+
+// EnvironmentalService ingests environmental sensor time series and detects
+// readings that deviate from their recent baseline
+type EnvironmentalService struct {
+	sessionRepo   domain.SessionRepository
+	readingRepo   domain.EnvironmentalReadingRepository
+	anomalyRepo   domain.EnvironmentalAnomalyRepository
+	sensorRepo    domain.SensorRegistrationRepository
+	config        EnvironmentalAnomalyConfig
+	alertService  *AlertService
+	accessService *AccessService
+}
+
+// EnvironmentalAnomalyConfig configures baseline-deviation detection. Zero
+// values fall back to the defaults.
+type EnvironmentalAnomalyConfig struct {
+	// BaselineWindowSeconds is how far back the baseline of a reading reaches
+	BaselineWindowSeconds float64 `json:"baseline_window_seconds"`
+	// MinBaselineSamples is the number of earlier readings needed before a
+	// reading can be judged
+	MinBaselineSamples int `json:"min_baseline_samples"`
+	// Threshold is the deviation, in standard deviations, that raises an anomaly
+	Threshold float64 `json:"threshold"`
+	// MinStdDev floors the baseline spread so a flat baseline does not turn
+	// sensor noise into anomalies
+	MinStdDev float64 `json:"min_std_dev"`
+	// CooldownSeconds suppresses further anomalies of the same device and
+	// metric while a deviation persists
+	CooldownSeconds float64 `json:"cooldown_seconds"`
+}
+
+// EnvironmentalSample holds the values one device measured at one instant
+type EnvironmentalSample struct {
+	DeviceID  string                                 `json:"device_id,omitempty"`
+	Timestamp time.Time                              `json:"timestamp"`
+	Values    map[domain.EnvironmentalMetric]float64 `json:"values"`
+}
+
+// EnvironmentalIngestResult reports the outcome of a batch ingest
+type EnvironmentalIngestResult struct {
+	Accepted  int                            `json:"accepted"`
+	Anomalies []*domain.EnvironmentalAnomaly `json:"anomalies"`
+}
+
+// EnvironmentalSeriesQuery selects and downsamples readings. An empty
+// Metrics list selects every metric.
+type EnvironmentalSeriesQuery struct {
+	Metrics       []domain.EnvironmentalMetric `json:"metrics,omitempty"`
+	DeviceID      string                       `json:"device_id,omitempty"`
+	From          *time.Time                   `json:"from,omitempty"`
+	To            *time.Time                   `json:"to,omitempty"`
+	BucketSeconds int                          `json:"bucket_seconds"`
+}
+
+// EnvironmentalBucket aggregates the readings of one time bucket
+type EnvironmentalBucket struct {
+	Start time.Time `json:"start"`
+	Count int       `json:"count"`
+	Min   float64   `json:"min"`
+	Max   float64   `json:"max"`
+	Avg   float64   `json:"avg"`
+}
+
+// EnvironmentalSeries is the downsampled series of one metric. Buckets
+// without readings are omitted.
+type EnvironmentalSeries struct {
+	Metric        domain.EnvironmentalMetric `json:"metric"`
+	BucketSeconds int                        `json:"bucket_seconds"`
+	Buckets       []EnvironmentalBucket      `json:"buckets"`
+}
+
+// NewEnvironmentalService creates a new environmental service
+func NewEnvironmentalService(
+	sessionRepo domain.SessionRepository,
+	readingRepo domain.EnvironmentalReadingRepository,
+	anomalyRepo domain.EnvironmentalAnomalyRepository,
+	sensorRepo domain.SensorRegistrationRepository,
+	config EnvironmentalAnomalyConfig,
+) *EnvironmentalService {
+	if config.BaselineWindowSeconds <= 0 {
+		config.BaselineWindowSeconds = defaultBaselineWindowSeconds
+	}
+	if config.MinBaselineSamples <= 0 {
+		config.MinBaselineSamples = defaultBaselineMinSamples
+	}
+	if config.Threshold <= 0 {
+		config.Threshold = defaultAnomalyThreshold
+	}
+	if config.MinStdDev <= 0 {
+		config.MinStdDev = defaultAnomalyMinStdDev
+	}
+	if config.CooldownSeconds < 0 {
+		config.CooldownSeconds = 0
+	} else if config.CooldownSeconds == 0 {
+		config.CooldownSeconds = defaultAnomalyCooldownSeconds
+	}
+
+	return &EnvironmentalService{
+		sessionRepo: sessionRepo,
+		readingRepo: readingRepo,
+		anomalyRepo: anomalyRepo,
+		sensorRepo:  sensorRepo,
+		config:      config,
+	}
+}
+
+// SetAlertService evaluates alert rules against every stored reading
+func (s *EnvironmentalService) SetAlertService(alertService *AlertService) {
+	s.alertService = alertService
+}
+
+// SetAccessService restricts unregistering a sensor to the device itself,
+// the owner of the session it reports to and admins
+func (s *EnvironmentalService) SetAccessService(accessService *AccessService) {
+	s.accessService = accessService
+}
+
+// IngestReadings stores a batch of samples for an active session and raises
+// anomalies for readings that deviate from their baseline. Samples without a
+// device ID are attributed to deviceID.
+func (s *EnvironmentalService) IngestReadings(ctx context.Context, sessionID, deviceID string, samples []EnvironmentalSample) (*EnvironmentalIngestResult, error) {
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+
+	if !session.Status.AcceptsEvents() {
+		return nil, fmt.Errorf("session is not active: status is %s", session.Status)
+	}
+
+	readings, err := buildEnvironmentalReadings(sessionID, deviceID, samples)
+	if err != nil {
+		return nil, err
+	}
+
+	anomalies, err := s.detectAnomalies(ctx, sessionID, readings)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.readingRepo.CreateBatch(ctx, readings); err != nil {
+		return nil, fmt.Errorf("failed to save environmental readings: %w", err)
+	}
+
+	if len(anomalies) > 0 {
+		if err := s.anomalyRepo.CreateBatch(ctx, anomalies); err != nil {
+			return nil, fmt.Errorf("failed to save environmental anomalies: %w", err)
+		}
+	}
+
+	if s.alertService != nil {
+		s.alertService.ObserveReadings(ctx, sessionID, readings)
+	}
+
+	// Sensor data keeps the session from expiring as inactive
+	if err := s.sessionRepo.Touch(ctx, sessionID, time.Now()); err != nil {
+		log.Printf("Failed to record activity on session %s: %v", sessionID, err)
+	}
+
+	return &EnvironmentalIngestResult{
+		Accepted:  len(readings),
+		Anomalies: anomalies,
+	}, nil
+}
+
+// IngestSensorReadings stores samples pushed by a standalone sensor in the
+// session the sensor is registered with
+func (s *EnvironmentalService) IngestSensorReadings(ctx context.Context, deviceID string, samples []EnvironmentalSample) (*EnvironmentalIngestResult, error) {
+	sessionID, err := s.GetSensorSession(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range samples {
+		if samples[i].DeviceID != "" && samples[i].DeviceID != deviceID {
+			return nil, fmt.Errorf("invalid environmental reading %d: device %s does not match sensor %s", i, samples[i].DeviceID, deviceID)
+		}
+	}
+
+	return s.IngestReadings(ctx, sessionID, deviceID, samples)
+}
+
+// GetSensorSession returns the session a sensor is registered with
+func (s *EnvironmentalService) GetSensorSession(ctx context.Context, deviceID string) (string, error) {
+	registration, err := s.sensorRepo.GetByDeviceID(ctx, deviceID)
+	if err != nil {
+		return "", fmt.Errorf("sensor not registered: %s", deviceID)
+	}
+
+	return registration.SessionID, nil
+}
+
+// RegisterSensor routes a sensor's pushed readings to a session, moving it
+// away from any session it was registered with before
+func (s *EnvironmentalService) RegisterSensor(ctx context.Context, sessionID, deviceID, name string) (*domain.SensorRegistration, error) {
+	if _, err := s.sessionRepo.GetByID(ctx, sessionID); err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+
+	if deviceID == "" {
+		return nil, fmt.Errorf("invalid sensor registration: device ID is required")
+	}
+
+	registration := &domain.SensorRegistration{
+		DeviceID:     deviceID,
+		SessionID:    sessionID,
+		Name:         name,
+		RegisteredAt: time.Now(),
+	}
+
+	if err := s.sensorRepo.Save(ctx, registration); err != nil {
+		return nil, fmt.Errorf("failed to save sensor registration: %w", err)
+	}
+
+	return registration, nil
+}
+
+// GetSensors returns the sensors registered with a session
+func (s *EnvironmentalService) GetSensors(ctx context.Context, sessionID string) ([]*domain.SensorRegistration, error) {
+	if _, err := s.sessionRepo.GetByID(ctx, sessionID); err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+
+	return s.sensorRepo.GetBySessionID(ctx, sessionID)
+}
+
+// UnregisterSensor stops routing a sensor's readings to its session
+func (s *EnvironmentalService) UnregisterSensor(ctx context.Context, deviceID string) error {
+	registration, err := s.sensorRepo.GetByDeviceID(ctx, deviceID)
+	if err != nil {
+		return fmt.Errorf("sensor not found: %w", err)
+	}
+
+	if s.accessService != nil {
+		if err := s.accessService.AuthorizePrincipal(ctx, domain.PrincipalDevice, deviceID); err != nil {
+			if err := s.accessService.AuthorizeSession(ctx, registration.SessionID, domain.AccessOwner); err != nil {
+				return err
+			}
+		}
+	}
+
+	return s.sensorRepo.Delete(ctx, deviceID)
+}
+
+// GetSeries returns min/max/avg per time bucket for each selected metric
+func (s *EnvironmentalService) GetSeries(ctx context.Context, sessionID string, query EnvironmentalSeriesQuery) ([]*EnvironmentalSeries, error) {
+	if _, err := s.sessionRepo.GetByID(ctx, sessionID); err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+
+	query, err := normalizeEnvironmentalSeriesQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var metric domain.EnvironmentalMetric
+	if len(query.Metrics) == 1 {
+		metric = query.Metrics[0]
+	}
+	var from, to time.Time
+	if query.From != nil {
+		from = *query.From
+	}
+	if query.To != nil {
+		to = *query.To
+	}
+
+	readings, err := s.readingRepo.GetBySessionID(ctx, sessionID, metric, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get environmental readings: %w", err)
+	}
+
+	bucketWidth := int64(query.BucketSeconds) * int64(time.Second)
+	bucketsByMetric := make(map[domain.EnvironmentalMetric]map[int64]*EnvironmentalBucket)
+	for _, reading := range readings {
+		if query.DeviceID != "" && reading.DeviceID != query.DeviceID {
+			continue
+		}
+		if len(query.Metrics) > 0 && !containsMetric(query.Metrics, reading.Metric) {
+			continue
+		}
+
+		buckets, ok := bucketsByMetric[reading.Metric]
+		if !ok {
+			buckets = make(map[int64]*EnvironmentalBucket)
+			bucketsByMetric[reading.Metric] = buckets
+		}
+		// Buckets are aligned to multiples of the width since the Unix epoch
+		start := floorDiv(reading.Timestamp.UnixNano(), bucketWidth) * bucketWidth
+		bucket, ok := buckets[start]
+		if !ok {
+			if len(buckets) >= maxEnvironmentalBuckets {
+				return nil, fmt.Errorf("invalid environmental query: more than %d buckets, use a larger bucket", maxEnvironmentalBuckets)
+			}
+			bucket = &EnvironmentalBucket{
+				Start: time.Unix(0, start).UTC(),
+				Min:   reading.Value,
+				Max:   reading.Value,
+			}
+			buckets[start] = bucket
+		}
+		bucket.Count++
+		bucket.Min = math.Min(bucket.Min, reading.Value)
+		bucket.Max = math.Max(bucket.Max, reading.Value)
+		// Avg holds the running sum until the bucket is complete
+		bucket.Avg += reading.Value
+	}
+
+	series := make([]*EnvironmentalSeries, 0, len(bucketsByMetric))
+	for metric, buckets := range bucketsByMetric {
+		entry := &EnvironmentalSeries{
+			Metric:        metric,
+			BucketSeconds: query.BucketSeconds,
+			Buckets:       make([]EnvironmentalBucket, 0, len(buckets)),
+		}
+		for _, bucket := range buckets {
+			bucket.Avg /= float64(bucket.Count)
+			entry.Buckets = append(entry.Buckets, *bucket)
+		}
+		sort.Slice(entry.Buckets, func(i, j int) bool {
+			return entry.Buckets[i].Start.Before(entry.Buckets[j].Start)
+		})
+		series = append(series, entry)
+	}
+	sort.Slice(series, func(i, j int) bool {
+		return series[i].Metric < series[j].Metric
+	})
+
+	return series, nil
+}
+
+// GetAnomalies returns the stored anomalies of a session
+func (s *EnvironmentalService) GetAnomalies(ctx context.Context, sessionID string) ([]*domain.EnvironmentalAnomaly, error) {
+	if _, err := s.sessionRepo.GetByID(ctx, sessionID); err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+
+	return s.anomalyRepo.GetBySessionID(ctx, sessionID)
+}
+
+// detectAnomalies judges each new reading against the mean and standard
+// deviation of the same device and metric over the preceding baseline
+// window, using stored readings as well as earlier readings of the batch
+func (s *EnvironmentalService) detectAnomalies(ctx context.Context, sessionID string, readings []*domain.EnvironmentalReading) ([]*domain.EnvironmentalAnomaly, error) {
+	if len(readings) == 0 {
+		return nil, nil
+	}
+
+	window := time.Duration(s.config.BaselineWindowSeconds * float64(time.Second))
+	cooldown := time.Duration(s.config.CooldownSeconds * float64(time.Second))
+
+	earliest, latest := readings[0].Timestamp, readings[0].Timestamp
+	for _, reading := range readings {
+		if reading.Timestamp.Before(earliest) {
+			earliest = reading.Timestamp
+		}
+		if reading.Timestamp.After(latest) {
+			latest = reading.Timestamp
+		}
+	}
+
+	history, err := s.readingRepo.GetBySessionID(ctx, sessionID, "", earliest.Add(-window), latest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get environmental readings: %w", err)
+	}
+
+	previous, err := s.anomalyRepo.GetBySessionID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get environmental anomalies: %w", err)
+	}
+	lastAnomaly := make(map[string]time.Time)
+	for _, anomaly := range previous {
+		key := anomalyKey(anomaly.DeviceID, anomaly.Metric)
+		if anomaly.Timestamp.After(lastAnomaly[key]) {
+			lastAnomaly[key] = anomaly.Timestamp
+		}
+	}
+
+	type seriesPoint struct {
+		reading *domain.EnvironmentalReading
+		isNew   bool
+	}
+	series := make(map[string][]seriesPoint)
+	for _, reading := range history {
+		key := anomalyKey(reading.DeviceID, reading.Metric)
+		series[key] = append(series[key], seriesPoint{reading: reading})
+	}
+	for _, reading := range readings {
+		key := anomalyKey(reading.DeviceID, reading.Metric)
+		series[key] = append(series[key], seriesPoint{reading: reading, isNew: true})
+	}
+
+	keys := make([]string, 0, len(series))
+	for key := range series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var anomalies []*domain.EnvironmentalAnomaly
+	for _, key := range keys {
+		points := series[key]
+		sort.SliceStable(points, func(i, j int) bool {
+			return points[i].reading.Timestamp.Before(points[j].reading.Timestamp)
+		})
+
+		// A sliding window over the sorted series keeps running sums of the baseline
+		var sum, sumSquares float64
+		first := 0
+		for i, point := range points {
+			for ; first < i && !points[first].reading.Timestamp.After(point.reading.Timestamp.Add(-window)); first++ {
+				sum -= points[first].reading.Value
+				sumSquares -= points[first].reading.Value * points[first].reading.Value
+			}
+
+			count := i - first
+			if point.isNew && count >= s.config.MinBaselineSamples {
+				mean := sum / float64(count)
+				stdDev := math.Sqrt(math.Max(0, sumSquares/float64(count)-mean*mean))
+				deviation := (point.reading.Value - mean) / math.Max(stdDev, s.config.MinStdDev)
+
+				last, seen := lastAnomaly[key]
+				coolingDown := seen && point.reading.Timestamp.Sub(last) < cooldown
+				if math.Abs(deviation) >= s.config.Threshold && !coolingDown {
+					anomalies = append(anomalies, &domain.EnvironmentalAnomaly{
+						ID:        generateID(),
+						SessionID: sessionID,
+						ReadingID: point.reading.ID,
+						DeviceID:  point.reading.DeviceID,
+						Metric:    point.reading.Metric,
+						Timestamp: point.reading.Timestamp,
+						Value:     point.reading.Value,
+						Baseline:  mean,
+						StdDev:    stdDev,
+						Deviation: deviation,
+						CreatedAt: time.Now(),
+					})
+					lastAnomaly[key] = point.reading.Timestamp
+				}
+			}
+
+			sum += point.reading.Value
+			sumSquares += point.reading.Value * point.reading.Value
+		}
+	}
+
+	sort.SliceStable(anomalies, func(i, j int) bool {
+		return anomalies[i].Timestamp.Before(anomalies[j].Timestamp)
+	})
+
+	return anomalies, nil
+}
+
+func buildEnvironmentalReadings(sessionID, deviceID string, samples []EnvironmentalSample) ([]*domain.EnvironmentalReading, error) {
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("invalid environmental reading: batch is empty")
+	}
+	if len(samples) > maxEnvironmentalBatchSize {
+		return nil, fmt.Errorf("invalid environmental reading: batch exceeds %d samples", maxEnvironmentalBatchSize)
+	}
+
+	now := time.Now()
+	var readings []*domain.EnvironmentalReading
+	for i, sample := range samples {
+		sampleDevice := sample.DeviceID
+		if sampleDevice == "" {
+			sampleDevice = deviceID
+		}
+		if sampleDevice == "" {
+			return nil, fmt.Errorf("invalid environmental reading %d: device ID is required", i)
+		}
+		if sample.Timestamp.IsZero() {
+			return nil, fmt.Errorf("invalid environmental reading %d: timestamp is required", i)
+		}
+		if len(sample.Values) == 0 {
+			return nil, fmt.Errorf("invalid environmental reading %d: no values", i)
+		}
+
+		for metric, value := range sample.Values {
+			if !IsEnvironmentalMetric(metric) {
+				return nil, fmt.Errorf("invalid environmental reading %d: unknown metric %s", i, metric)
+			}
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				return nil, fmt.Errorf("invalid environmental reading %d: %s is not a finite number", i, metric)
+			}
+
+			readings = append(readings, &domain.EnvironmentalReading{
+				ID:        generateID(),
+				SessionID: sessionID,
+				DeviceID:  sampleDevice,
+				Timestamp: sample.Timestamp,
+				Metric:    metric,
+				Value:     value,
+				CreatedAt: now,
+			})
+		}
+	}
+
+	return readings, nil
+}
+
+func normalizeEnvironmentalSeriesQuery(query EnvironmentalSeriesQuery) (EnvironmentalSeriesQuery, error) {
+	for _, metric := range query.Metrics {
+		if !IsEnvironmentalMetric(metric) {
+			return query, fmt.Errorf("invalid environmental query: unknown metric %s", metric)
+		}
+	}
+
+	if query.From != nil && query.To != nil && query.To.Before(*query.From) {
+		return query, fmt.Errorf("invalid environmental query: time range ends before it starts")
+	}
+
+	if query.BucketSeconds <= 0 {
+		query.BucketSeconds = defaultEnvironmentalBucketSeconds
+	}
+
+	if query.From != nil && query.To != nil {
+		buckets := query.To.Sub(*query.From) / (time.Duration(query.BucketSeconds) * time.Second)
+		if buckets > maxEnvironmentalBuckets {
+			return query, fmt.Errorf("invalid environmental query: more than %d buckets, use a larger bucket", maxEnvironmentalBuckets)
+		}
+	}
+
+	return query, nil
+}
+
+// IsEnvironmentalMetric reports whether metric is a known environmental metric
+func IsEnvironmentalMetric(metric domain.EnvironmentalMetric) bool {
+	switch metric {
+	case domain.EnvironmentalMetricTemperature, domain.EnvironmentalMetricHumidity,
+		domain.EnvironmentalMetricPressure, domain.EnvironmentalMetricEMF,
+		domain.EnvironmentalMetricLight, domain.EnvironmentalMetricNoise:
+		return true
+	}
+	return false
+}
+
+func containsMetric(metrics []domain.EnvironmentalMetric, metric domain.EnvironmentalMetric) bool {
+	for _, m := range metrics {
+		if m == metric {
+			return true
+		}
+	}
+	return false
+}
+
+func anomalyKey(deviceID string, metric domain.EnvironmentalMetric) string {
+	return deviceID + "\x00" + string(metric)
+}
+
+// floorDiv divides rounding towards negative infinity
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
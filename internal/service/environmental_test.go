@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvironmentalService_IngestReadings_RaisesAnomalyOnDeviation(t *testing.T) {
+	// Arrange
+	mockSessionRepo := &MockSessionRepository{}
+	mockReadingRepo := &MockEnvironmentalReadingRepository{}
+	mockAnomalyRepo := &MockEnvironmentalAnomalyRepository{}
+
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	var history []*domain.EnvironmentalReading
+	for i := 0; i < 20; i++ {
+		history = append(history, &domain.EnvironmentalReading{
+			ID:        generateID(),
+			SessionID: "test-session-123",
+			DeviceID:  "probe-1",
+			Timestamp: base.Add(time.Duration(i) * time.Second),
+			Metric:    domain.EnvironmentalMetricTemperature,
+			Value:     18 + 0.5*float64(i%2), // mean 18.25, std dev 0.25
+		})
+	}
+
+	// A sudden cold spot, a reading while the anomaly cools down, then one after it
+	samples := []EnvironmentalSample{
+		{Timestamp: base.Add(20 * time.Second), Values: map[domain.EnvironmentalMetric]float64{"temperature": 12}},
+		{Timestamp: base.Add(25 * time.Second), Values: map[domain.EnvironmentalMetric]float64{"temperature": 12}},
+		{Timestamp: base.Add(21 * time.Second), Values: map[domain.EnvironmentalMetric]float64{"humidity": 60}},
+	}
+
+	mockSessionRepo.On("GetByID", mock.Anything, "test-session-123").Return(TestSession(), nil)
+	mockSessionRepo.On("Touch", mock.Anything, "test-session-123", mock.Anything).Return(nil)
+	mockReadingRepo.On("GetBySessionID", mock.Anything, "test-session-123", domain.EnvironmentalMetric(""), base.Add(20*time.Second-5*time.Minute), base.Add(25*time.Second)).
+		Return(history, nil)
+	mockAnomalyRepo.On("GetBySessionID", mock.Anything, "test-session-123").Return([]*domain.EnvironmentalAnomaly{}, nil)
+	mockReadingRepo.On("CreateBatch", mock.Anything, mock.AnythingOfType("[]*domain.EnvironmentalReading")).Return(nil)
+	mockAnomalyRepo.On("CreateBatch", mock.Anything, mock.AnythingOfType("[]*domain.EnvironmentalAnomaly")).Return(nil).Once()
+
+	service := NewEnvironmentalService(mockSessionRepo, mockReadingRepo, mockAnomalyRepo, &MockSensorRegistrationRepository{}, EnvironmentalAnomalyConfig{})
+
+	// Act
+	result, err := service.IngestReadings(context.Background(), "test-session-123", "probe-1", samples)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Accepted)
+	require.Len(t, result.Anomalies, 1) // humidity has no baseline yet
+	anomaly := result.Anomalies[0]
+	assert.Equal(t, domain.EnvironmentalMetricTemperature, anomaly.Metric)
+	assert.Equal(t, "probe-1", anomaly.DeviceID)
+	assert.InDelta(t, 18.25, anomaly.Baseline, 1e-9)
+	assert.InDelta(t, 0.25, anomaly.StdDev, 1e-9)
+	assert.InDelta(t, -25, anomaly.Deviation, 1e-9)
+	assert.True(t, anomaly.Timestamp.Equal(base.Add(20*time.Second)))
+	mockAnomalyRepo.AssertExpectations(t)
+}
+
+func TestEnvironmentalService_IngestReadings_InvalidMetric_ReturnsError(t *testing.T) {
+	// Arrange
+	mockSessionRepo := &MockSessionRepository{}
+	mockReadingRepo := &MockEnvironmentalReadingRepository{}
+	mockSessionRepo.On("GetByID", mock.Anything, "test-session-123").Return(TestSession(), nil)
+	mockSessionRepo.On("Touch", mock.Anything, "test-session-123", mock.Anything).Return(nil)
+
+	service := NewEnvironmentalService(mockSessionRepo, mockReadingRepo, &MockEnvironmentalAnomalyRepository{}, &MockSensorRegistrationRepository{}, EnvironmentalAnomalyConfig{})
+
+	// Act
+	result, err := service.IngestReadings(context.Background(), "test-session-123", "probe-1", []EnvironmentalSample{
+		{Timestamp: time.Now(), Values: map[domain.EnvironmentalMetric]float64{"ectoplasm": 1}},
+	})
+
+	// Assert
+	require.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "invalid environmental reading")
+	mockReadingRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+}
+
+func TestEnvironmentalService_GetSeries_DownsamplesPerBucket(t *testing.T) {
+	// Arrange
+	mockSessionRepo := &MockSessionRepository{}
+	mockReadingRepo := &MockEnvironmentalReadingRepository{}
+
+	base := time.Date(2026, 10, 31, 22, 0, 0, 0, time.UTC)
+	reading := func(offset time.Duration, device string, value float64) *domain.EnvironmentalReading {
+		return &domain.EnvironmentalReading{
+			ID:        generateID(),
+			DeviceID:  device,
+			Timestamp: base.Add(offset),
+			Metric:    domain.EnvironmentalMetricEMF,
+			Value:     value,
+		}
+	}
+	readings := []*domain.EnvironmentalReading{
+		reading(5*time.Second, "probe-1", 1),
+		reading(30*time.Second, "probe-1", 3),
+		reading(50*time.Second, "probe-2", 100), // other device
+		reading(130*time.Second, "probe-1", 4),
+	}
+
+	mockSessionRepo.On("GetByID", mock.Anything, "test-session-123").Return(TestSession(), nil)
+	mockSessionRepo.On("Touch", mock.Anything, "test-session-123", mock.Anything).Return(nil)
+	mockReadingRepo.On("GetBySessionID", mock.Anything, "test-session-123", domain.EnvironmentalMetricEMF, time.Time{}, time.Time{}).
+		Return(readings, nil)
+
+	service := NewEnvironmentalService(mockSessionRepo, mockReadingRepo, &MockEnvironmentalAnomalyRepository{}, &MockSensorRegistrationRepository{}, EnvironmentalAnomalyConfig{})
+
+	// Act
+	series, err := service.GetSeries(context.Background(), "test-session-123", EnvironmentalSeriesQuery{
+		Metrics:  []domain.EnvironmentalMetric{domain.EnvironmentalMetricEMF},
+		DeviceID: "probe-1",
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, series, 1)
+	assert.Equal(t, defaultEnvironmentalBucketSeconds, series[0].BucketSeconds)
+	require.Len(t, series[0].Buckets, 2) // the empty minute in between is omitted
+	assert.Equal(t, EnvironmentalBucket{Start: base, Count: 2, Min: 1, Max: 3, Avg: 2}, series[0].Buckets[0])
+	assert.True(t, series[0].Buckets[1].Start.Equal(base.Add(2*time.Minute)))
+	assert.Equal(t, 4.0, series[0].Buckets[1].Avg)
+}
+
+func TestEnvironmentalService_IngestSensorReadings_RoutesToRegisteredSession(t *testing.T) {
+	// Arrange
+	mockSessionRepo := &MockSessionRepository{}
+	mockReadingRepo := &MockEnvironmentalReadingRepository{}
+	mockAnomalyRepo := &MockEnvironmentalAnomalyRepository{}
+	mockSensorRepo := &MockSensorRegistrationRepository{}
+
+	mockSensorRepo.On("GetByDeviceID", mock.Anything, "emf-1").
+		Return(&domain.SensorRegistration{DeviceID: "emf-1", SessionID: "test-session-123"}, nil)
+	mockSensorRepo.On("GetByDeviceID", mock.Anything, "stray").
+		Return((*domain.SensorRegistration)(nil), sql.ErrNoRows)
+	mockSessionRepo.On("GetByID", mock.Anything, "test-session-123").Return(TestSession(), nil)
+	mockSessionRepo.On("Touch", mock.Anything, "test-session-123", mock.Anything).Return(nil)
+	mockReadingRepo.On("GetBySessionID", mock.Anything, "test-session-123", mock.Anything, mock.Anything, mock.Anything).
+		Return([]*domain.EnvironmentalReading{}, nil)
+	mockAnomalyRepo.On("GetBySessionID", mock.Anything, "test-session-123").Return([]*domain.EnvironmentalAnomaly{}, nil)
+	mockReadingRepo.On("CreateBatch", mock.Anything, mock.MatchedBy(func(readings []*domain.EnvironmentalReading) bool {
+		return len(readings) == 1 && readings[0].SessionID == "test-session-123" && readings[0].DeviceID == "emf-1"
+	})).Return(nil).Once()
+
+	service := NewEnvironmentalService(mockSessionRepo, mockReadingRepo, mockAnomalyRepo, mockSensorRepo, EnvironmentalAnomalyConfig{})
+	samples := []EnvironmentalSample{{Timestamp: time.Now(), Values: map[domain.EnvironmentalMetric]float64{"emf": 0.4}}}
+
+	// Act
+	result, err := service.IngestSensorReadings(context.Background(), "emf-1", samples)
+	_, strayErr := service.IngestSensorReadings(context.Background(), "stray", samples)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Accepted)
+	require.Error(t, strayErr)
+	assert.Contains(t, strayErr.Error(), "sensor not registered")
+	mockReadingRepo.AssertExpectations(t)
+}
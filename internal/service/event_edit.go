@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/myideascope/otherside/internal/domain"
+)
+
+// maxEditAttempts bounds how often an edit without a version is applied
+// again to a record that changed while it was being written
+const maxEditAttempts = 3
+
+// UpdateEVPRequest holds the changes to an EVP recording. Annotations
+// replaces the whole list, while AddAnnotations and RemoveAnnotations are
+// merged into whatever list is stored, so investigators annotating the same
+// recording at once keep each other's notes. A non-zero Version is the
+// version the changes were made to; the update fails with a
+// VersionConflictError when it is outdated.
+type UpdateEVPRequest struct {
+	Annotations       *[]string          `json:"annotations,omitempty"`
+	AddAnnotations    []string           `json:"add_annotations,omitempty"`
+	RemoveAnnotations []string           `json:"remove_annotations,omitempty"`
+	Quality           *domain.EVPQuality `json:"quality,omitempty"`
+	Version           int64              `json:"version,omitempty"`
+}
+
+// UpdateVOXRequest holds the changes to a VOX event. Nil fields are left as
+// they are; Version works as in UpdateEVPRequest.
+type UpdateVOXRequest struct {
+	UserResponse  *string  `json:"user_response,omitempty"`
+	ResponseDelay *float64 `json:"response_delay,omitempty"`
+	Version       int64    `json:"version,omitempty"`
+}
+
+// UpdateEVP edits the annotations or quality rating of an EVP recording
+func (s *SessionService) UpdateEVP(ctx context.Context, sessionID, evpID string, req UpdateEVPRequest) (*domain.EVPRecording, error) {
+	if req.Quality != nil && !req.Quality.IsValid() {
+		return nil, fmt.Errorf("invalid evp update: unknown quality %q", *req.Quality)
+	}
+	if err := s.editableSession(ctx, sessionID); err != nil {
+		return nil, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		evp, err := s.evpRepo.GetByID(ctx, evpID)
+		if err != nil || evp.SessionID != sessionID {
+			return nil, fmt.Errorf("evp recording not found: %s", evpID)
+		}
+		if req.Version != 0 && req.Version != evp.Version {
+			return nil, evpConflict(evp)
+		}
+
+		annotations := evp.Annotations
+		if req.Annotations != nil {
+			annotations = *req.Annotations
+		}
+		evp.Annotations = mergeList(annotations, req.AddAnnotations, req.RemoveAnnotations)
+		if req.Quality != nil {
+			evp.Quality = *req.Quality
+		}
+
+		err = s.evpRepo.Update(ctx, evp)
+		if err == nil {
+			return evp, nil
+		}
+		if !errors.Is(err, domain.ErrVersionConflict) {
+			return nil, fmt.Errorf("failed to update evp recording: %w", err)
+		}
+		if req.Version != 0 || attempt == maxEditAttempts {
+			current, getErr := s.evpRepo.GetByID(ctx, evpID)
+			if getErr != nil {
+				return nil, fmt.Errorf("evp recording not found: %s", evpID)
+			}
+			return nil, evpConflict(current)
+		}
+	}
+}
+
+// UpdateVOX records the investigator's response to a VOX event
+func (s *SessionService) UpdateVOX(ctx context.Context, sessionID, voxID string, req UpdateVOXRequest) (*domain.VOXEvent, error) {
+	if req.ResponseDelay != nil && *req.ResponseDelay < 0 {
+		return nil, fmt.Errorf("invalid vox update: response delay must not be negative")
+	}
+	if err := s.editableSession(ctx, sessionID); err != nil {
+		return nil, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		vox, err := s.voxRepo.GetByID(ctx, voxID)
+		if err != nil || vox.SessionID != sessionID {
+			return nil, fmt.Errorf("vox event not found: %s", voxID)
+		}
+		if req.Version != 0 && req.Version != vox.Version {
+			return nil, voxConflict(vox)
+		}
+
+		if req.UserResponse != nil {
+			vox.UserResponse = *req.UserResponse
+		}
+		if req.ResponseDelay != nil {
+			vox.ResponseDelay = *req.ResponseDelay
+		}
+
+		err = s.voxRepo.Update(ctx, vox)
+		if err == nil {
+			return vox, nil
+		}
+		if !errors.Is(err, domain.ErrVersionConflict) {
+			return nil, fmt.Errorf("failed to update vox event: %w", err)
+		}
+		if req.Version != 0 || attempt == maxEditAttempts {
+			current, getErr := s.voxRepo.GetByID(ctx, voxID)
+			if getErr != nil {
+				return nil, fmt.Errorf("vox event not found: %s", voxID)
+			}
+			return nil, voxConflict(current)
+		}
+	}
+}
+
+// editableSession checks that the caller may edit a session's events and
+// that the session is not archived
+func (s *SessionService) editableSession(ctx context.Context, sessionID string) error {
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("session not found: %w", err)
+	}
+	if s.accessService != nil {
+		if err := s.accessService.AuthorizeSession(ctx, sessionID, domain.AccessTeam); err != nil {
+			return err
+		}
+	}
+	if session.Status == domain.SessionStatusArchived {
+		return fmt.Errorf("session %s is archived and read-only", sessionID)
+	}
+	return nil
+}
+
+// mergeList adds and removes items from a list, keeping the order of what
+// stays and dropping blanks and duplicates. Applying the additions and
+// removals of concurrent edits to the latest list, rather than writing each
+// edit's whole list, keeps every edit.
+func mergeList(current, add, remove []string) []string {
+	removed := make(map[string]bool, len(remove))
+	for _, item := range remove {
+		removed[strings.TrimSpace(item)] = true
+	}
+
+	merged := make([]string, 0, len(current)+len(add))
+	seen := make(map[string]bool, len(current)+len(add))
+	for _, list := range [][]string{current, add} {
+		for _, item := range list {
+			item = strings.TrimSpace(item)
+			if item == "" || removed[item] || seen[item] {
+				continue
+			}
+			seen[item] = true
+			merged = append(merged, item)
+		}
+	}
+
+	return merged
+}
+
+// evpConflict reports an update to an outdated copy of an EVP recording
+func evpConflict(current *domain.EVPRecording) error {
+	return &domain.VersionConflictError{Entity: "evp recording", ID: current.ID, Version: current.Version, Current: current}
+}
+
+// voxConflict reports an update to an outdated copy of a VOX event
+func voxConflict(current *domain.VOXEvent) error {
+	return &domain.VersionConflictError{Entity: "vox event", ID: current.ID, Version: current.Version, Current: current}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/myideascope/otherside/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupEventEdit returns a session service over a migrated in-memory
+// database holding one session with one annotated EVP recording
+func setupEventEdit(t *testing.T) (*SessionService, *domain.EVPRecording) {
+	db := setupLifecycleDB(t)
+	sm := NewSessionStateManager(repository.NewSQLiteSessionRepository(db))
+	evpRepo := repository.NewSQLiteEVPRepository(db)
+	sessions := NewSessionService(sm, evpRepo, repository.NewSQLiteVOXRepository(db), nil, nil, nil, nil, nil, nil)
+
+	ctx := context.Background()
+	require.NoError(t, sm.CreateSession(ctx, &domain.Session{ID: "session-1", Title: "Cellar", StartTime: time.Now()}))
+	evp := &domain.EVPRecording{
+		ID:          "evp-1",
+		SessionID:   "session-1",
+		FilePath:    "evp/evp-1.wav",
+		Timestamp:   time.Now(),
+		Annotations: []string{"Possible voice"},
+		Quality:     domain.EVPQualityFair,
+		CreatedAt:   time.Now(),
+	}
+	require.NoError(t, evpRepo.Create(ctx, evp))
+
+	return sessions, evp
+}
+
+func TestSessionService_UpdateEVP_AnnotationsAddedFromOutdatedCopies_KeepsBoth(t *testing.T) {
+	// Arrange
+	sessions, evp := setupEventEdit(t)
+	ctx := context.Background()
+
+	// Act
+	_, err := sessions.UpdateEVP(ctx, "session-1", evp.ID, UpdateEVPRequest{AddAnnotations: []string{"Whisper at 0:12"}})
+	require.NoError(t, err)
+	updated, err := sessions.UpdateEVP(ctx, "session-1", evp.ID, UpdateEVPRequest{
+		AddAnnotations:    []string{"Knock at 0:20"},
+		RemoveAnnotations: []string{"Possible voice"},
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Whisper at 0:12", "Knock at 0:20"}, updated.Annotations)
+	assert.Equal(t, evp.Version+2, updated.Version)
+}
+
+func TestSessionService_UpdateEVP_StaleVersion_ReturnsCurrentCopy(t *testing.T) {
+	// Arrange
+	sessions, evp := setupEventEdit(t)
+	ctx := context.Background()
+	good := domain.EVPQualityGood
+	poor := domain.EVPQualityPoor
+	_, err := sessions.UpdateEVP(ctx, "session-1", evp.ID, UpdateEVPRequest{Quality: &good, Version: evp.Version})
+	require.NoError(t, err)
+
+	// Act
+	updated, err := sessions.UpdateEVP(ctx, "session-1", evp.ID, UpdateEVPRequest{Quality: &poor, Version: evp.Version})
+
+	// Assert
+	assert.Nil(t, updated)
+	var conflict *domain.VersionConflictError
+	require.ErrorAs(t, err, &conflict)
+	current, ok := conflict.Current.(*domain.EVPRecording)
+	require.True(t, ok)
+	assert.Equal(t, domain.EVPQualityGood, current.Quality)
+	assert.Equal(t, evp.Version+1, conflict.Version)
+}
+
+func TestMergeList_AddAndRemove_KeepsOrderWithoutDuplicates(t *testing.T) {
+	// Arrange
+	current := []string{"Possible voice", "Low frequency anomaly", "Static"}
+
+	// Act
+	merged := mergeList(current, []string{" Whisper ", "Static", ""}, []string{"Low frequency anomaly"})
+
+	// Assert
+	assert.Equal(t, []string{"Possible voice", "Static", "Whisper"}, merged)
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// Live event types
+const (
+	LiveEventEVP         = "evp"
+	LiveEventVOX         = "vox"
+	LiveEventRadar       = "radar"
+	LiveEventSLS         = "sls"
+	LiveEventInteraction = "interaction"
+	LiveEventAlert       = "alert"
+)
+
+// defaultLiveReplay is how many recent events of each session are kept for
+// subscribers resuming after a dropped connection
+const defaultLiveReplay = 256
+
+// liveSubscriberBuffer is how many events may wait for a subscriber before
+// it is dropped as too slow
+const liveSubscriberBuffer = 64
+
+// liveStreamIdle is how long the recent events of a session nobody is
+// watching are kept after its last event
+const liveStreamIdle = 10 * time.Minute
+
+// LiveEvent is an event published to the subscribers of a session
+type LiveEvent struct {
+	ID        int64
+	SessionID string
+	Type      string
+	Data      interface{}
+	At        time.Time
+}
+
+// EventHub passes events recorded to a session on to everyone watching
+// that session, in process. It keeps each session's latest events so that a
+// subscriber that lost its connection can resume where it left off. Event
+// IDs start from the clock in microseconds, so they keep increasing across
+// restarts.
+type EventHub struct {
+	mu        sync.Mutex
+	replay    int
+	lastID    int64
+	streams   map[string]*liveStream
+	lastSweep time.Time
+	closed    bool
+	now       func() time.Time
+}
+
+// liveStream holds the recent events and subscribers of one session
+type liveStream struct {
+	recent      []LiveEvent
+	subscribers map[*EventSubscription]struct{}
+	lastEvent   time.Time
+}
+
+// EventSubscription receives the events of one session. Events is closed
+// when the subscription ends: on Close, when the hub shuts down or when the
+// subscriber falls too far behind, in which case it should subscribe again
+// from the last event it received.
+type EventSubscription struct {
+	Events <-chan LiveEvent
+
+	events    chan LiveEvent
+	sessionID string
+	types     map[string]bool
+	hub       *EventHub
+}
+
+// NewEventHub creates a new event hub keeping up to replay recent events
+// per session, or defaultLiveReplay when replay is not positive
+func NewEventHub(replay int) *EventHub {
+	if replay <= 0 {
+		replay = defaultLiveReplay
+	}
+	return &EventHub{
+		replay:  replay,
+		lastID:  time.Now().UnixMicro(),
+		streams: make(map[string]*liveStream),
+		now:     time.Now,
+	}
+}
+
+// Publish sends an event to the subscribers of a session and returns it.
+// Subscribers that cannot keep up are dropped rather than slowing down the
+// caller.
+func (h *EventHub) Publish(sessionID, eventType string, data interface{}) LiveEvent {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := h.now()
+	h.lastID++
+	event := LiveEvent{ID: h.lastID, SessionID: sessionID, Type: eventType, Data: data, At: now}
+	if h.closed {
+		return event
+	}
+
+	stream := h.streamLocked(sessionID)
+	stream.lastEvent = now
+	stream.recent = append(stream.recent, event)
+	if len(stream.recent) > h.replay {
+		stream.recent = stream.recent[len(stream.recent)-h.replay:]
+	}
+
+	for sub := range stream.subscribers {
+		if !sub.wants(eventType) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			h.unsubscribeLocked(sub)
+		}
+	}
+
+	h.sweepLocked(now)
+	return event
+}
+
+// Subscribe starts receiving the events of a session whose type is in
+// types, or of every type when types is empty. When lastEventID is set, the
+// kept events after it are returned to be sent first.
+func (h *EventHub) Subscribe(sessionID string, lastEventID int64, types []string) (*EventSubscription, []LiveEvent) {
+	events := make(chan LiveEvent, liveSubscriberBuffer)
+	sub := &EventSubscription{Events: events, events: events, sessionID: sessionID, hub: h}
+	if len(types) > 0 {
+		sub.types = make(map[string]bool, len(types))
+		for _, eventType := range types {
+			sub.types[eventType] = true
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		close(events)
+		return sub, nil
+	}
+
+	stream := h.streamLocked(sessionID)
+	stream.subscribers[sub] = struct{}{}
+
+	var missed []LiveEvent
+	if lastEventID > 0 {
+		for _, event := range stream.recent {
+			if event.ID > lastEventID && sub.wants(event.Type) {
+				missed = append(missed, event)
+			}
+		}
+	}
+
+	return sub, missed
+}
+
+// Close stops the subscription and closes its channel
+func (s *EventSubscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.unsubscribeLocked(s)
+}
+
+// Close ends every subscription. Later subscriptions end at once, so that
+// open streams let the server shut down.
+func (h *EventHub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for _, stream := range h.streams {
+		for sub := range stream.subscribers {
+			h.unsubscribeLocked(sub)
+		}
+	}
+	h.streams = make(map[string]*liveStream)
+}
+
+// wants reports whether the subscriber asked for events of a type
+func (s *EventSubscription) wants(eventType string) bool {
+	return s.types == nil || s.types[eventType]
+}
+
+// streamLocked returns the stream of a session, creating it when needed;
+// the caller holds h.mu
+func (h *EventHub) streamLocked(sessionID string) *liveStream {
+	stream, exists := h.streams[sessionID]
+	if !exists {
+		stream = &liveStream{subscribers: make(map[*EventSubscription]struct{}), lastEvent: h.now()}
+		h.streams[sessionID] = stream
+	}
+	return stream
+}
+
+// unsubscribeLocked removes a subscriber and closes its channel once; the
+// caller holds h.mu
+func (h *EventHub) unsubscribeLocked(sub *EventSubscription) {
+	stream, exists := h.streams[sub.sessionID]
+	if !exists {
+		return
+	}
+	if _, subscribed := stream.subscribers[sub]; !subscribed {
+		return
+	}
+	delete(stream.subscribers, sub)
+	close(sub.events)
+}
+
+// sweepLocked forgets sessions nobody has watched or published to for
+// liveStreamIdle, at most once a minute; the caller holds h.mu
+func (h *EventHub) sweepLocked(now time.Time) {
+	if now.Sub(h.lastSweep) < time.Minute {
+		return
+	}
+	h.lastSweep = now
+
+	for sessionID, stream := range h.streams {
+		if len(stream.subscribers) == 0 && now.Sub(stream.lastEvent) > liveStreamIdle {
+			delete(h.streams, sessionID)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/myideascope/otherside/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventHub_Subscribe_LastEventID_ReplaysMissedEventsOfWantedTypes(t *testing.T) {
+	// Arrange
+	hub := NewEventHub(0)
+	first := hub.Publish("session-1", LiveEventRadar, "radar-1")
+	hub.Publish("session-1", LiveEventVOX, "vox-1")
+	hub.Publish("session-2", LiveEventRadar, "other session")
+	hub.Publish("session-1", LiveEventRadar, "radar-2")
+
+	// Act
+	sub, missed := hub.Subscribe("session-1", first.ID, []string{LiveEventRadar})
+	defer sub.Close()
+	hub.Publish("session-1", LiveEventVOX, "vox-2")
+	live := hub.Publish("session-1", LiveEventRadar, "radar-3")
+
+	// Assert
+	require.Len(t, missed, 1)
+	assert.Equal(t, "radar-2", missed[0].Data)
+	received := <-sub.Events
+	assert.Equal(t, live.ID, received.ID)
+	assert.Equal(t, "radar-3", received.Data)
+	assert.Empty(t, sub.Events)
+}
+
+func TestEventHub_Publish_SlowSubscriber_IsDropped(t *testing.T) {
+	// Arrange
+	hub := NewEventHub(0)
+	sub, _ := hub.Subscribe("session-1", 0, nil)
+
+	// Act
+	for i := 0; i <= liveSubscriberBuffer; i++ {
+		hub.Publish("session-1", LiveEventInteraction, i)
+	}
+
+	// Assert
+	received := 0
+	for range sub.Events {
+		received++
+	}
+	assert.Equal(t, liveSubscriberBuffer, received)
+	sub.Close()
+}
+
+func TestSessionService_RecordUserInteraction_WithEventHub_PublishesToWatchers(t *testing.T) {
+	// Arrange
+	db := setupLifecycleDB(t)
+	sm := NewSessionStateManager(repository.NewSQLiteSessionRepository(db))
+	sessions := NewSessionService(sm, nil, nil, nil, nil, repository.NewSQLiteInteractionRepository(db), nil, nil, nil)
+	sessions.SetEventHub(NewEventHub(0))
+	ctx := context.Background()
+	require.NoError(t, sm.CreateSession(ctx, &domain.Session{ID: "session-1", Title: "Cellar", StartTime: time.Now()}))
+	sub, _, err := sessions.SubscribeEvents(ctx, "session-1", 0, nil)
+	require.NoError(t, err)
+	defer sub.Close()
+
+	// Act
+	interaction, err := sessions.RecordUserInteraction(ctx, "session-1", UserInteractionData{Type: domain.InteractionTypeText, Content: "Who is here?"})
+	require.NoError(t, err)
+	_, _, typeErr := sessions.SubscribeEvents(ctx, "session-1", 0, []string{"seance"})
+
+	// Assert
+	event := <-sub.Events
+	assert.Equal(t, LiveEventInteraction, event.Type)
+	assert.Equal(t, interaction, event.Data)
+	require.Error(t, typeErr)
+	assert.Contains(t, typeErr.Error(), "invalid event type")
+}
@@ -23,6 +23,9 @@ type ExportService struct {
 	slsRepo         domain.SLSRepository
 	interactionRepo domain.InteractionRepository
 	fileRepo        domain.FileRepository
+	voxAnalysis     *VOXAnalysisService
+	accessService   *AccessService
+	webhookService  *WebhookService
 }
 
 // ExportFormat represents different export formats
@@ -36,17 +39,21 @@ const (
 
 // ExportRequest contains export parameters
 type ExportRequest struct {
-	SessionIDs   []string     `json:"session_ids"`
-	Format       ExportFormat `json:"format"`
-	IncludeAudio bool         `json:"include_audio"`
-	IncludeVideo bool         `json:"include_video"`
-	DateFrom     *time.Time   `json:"date_from,omitempty"`
-	DateTo       *time.Time   `json:"date_to,omitempty"`
-	IncludeEVPs  bool         `json:"include_evps"`
-	IncludeVOX   bool         `json:"include_vox"`
-	IncludeRadar bool         `json:"include_radar"`
-	IncludeSLS   bool         `json:"include_sls"`
-	IncludeNotes bool         `json:"include_notes"`
+	SessionIDs         []string            `json:"session_ids"`
+	Format             ExportFormat        `json:"format"`
+	IncludeAudio       bool                `json:"include_audio"`
+	IncludeVideo       bool                `json:"include_video"`
+	DateFrom           *time.Time          `json:"date_from,omitempty"`
+	DateTo             *time.Time          `json:"date_to,omitempty"`
+	IncludeEVPs        bool                `json:"include_evps"`
+	IncludeVOX         bool                `json:"include_vox"`
+	IncludeRadar       bool                `json:"include_radar"`
+	IncludeSLS         bool                `json:"include_sls"`
+	IncludeNotes       bool                `json:"include_notes"`
+	IncludeVOXBaseline bool                `json:"include_vox_baseline"`
+	VOXBaseline        *VOXBaselineOptions `json:"vox_baseline,omitempty"`
+	InvestigatorID     string              `json:"investigator_id,omitempty"`
+	DeviceID           string              `json:"device_id,omitempty"`
 }
 
 // ExportResult contains export results and metadata
@@ -92,6 +99,11 @@ func (s *ExportService) ExportSessions(ctx context.Context, req ExportRequest) (
 	sessionData := make(map[string]*SessionExportData)
 
 	for _, sessionID := range req.SessionIDs {
+		if s.accessService != nil {
+			if err := s.accessService.AuthorizeSession(ctx, sessionID, domain.AccessGuest); err != nil {
+				return nil, err
+			}
+		}
 		data, err := s.collectSessionData(ctx, sessionID, req)
 		if err != nil {
 			return nil, fmt.Errorf("failed to collect data for session %s: %w", sessionID, err)
@@ -119,9 +131,20 @@ func (s *ExportService) ExportSessions(ctx context.Context, req ExportRequest) (
 		return nil, fmt.Errorf("export generation failed: %w", err)
 	}
 
+	// Record the export before saving it, so that a filename that is
+	// already taken fails instead of overwriting someone else's file
+	if s.accessService != nil {
+		if err := s.accessService.RecordExport(ctx, filename, req.SessionIDs); err != nil {
+			return nil, err
+		}
+	}
+
 	// Save export file
 	filePath := filepath.Join("exports", filename)
 	if err := s.fileRepo.SaveFile(ctx, filePath, exportData); err != nil {
+		if s.accessService != nil {
+			s.accessService.ForgetExport(ctx, filename)
+		}
 		return nil, fmt.Errorf("failed to save export file: %w", err)
 	}
 
@@ -132,7 +155,7 @@ func (s *ExportService) ExportSessions(ctx context.Context, req ExportRequest) (
 			len(data.SLSDetections) + len(data.Interactions)
 	}
 
-	return &ExportResult{
+	result := &ExportResult{
 		Filename:     filename,
 		Size:         int64(len(exportData)),
 		Format:       string(req.Format),
@@ -140,7 +163,13 @@ func (s *ExportService) ExportSessions(ctx context.Context, req ExportRequest) (
 		ItemCount:    totalItems,
 		GeneratedAt:  time.Now(),
 		FilePath:     filePath,
-	}, nil
+	}
+
+	if s.webhookService != nil {
+		s.webhookService.Notify(ctx, WebhookEventExportReady, req.SessionIDs, result)
+	}
+
+	return result, nil
 }
 
 // SessionExportData contains all data for a session export
@@ -151,6 +180,7 @@ type SessionExportData struct {
 	RadarEvents   []*domain.RadarEvent      `json:"radar_events,omitempty"`
 	SLSDetections []*domain.SLSDetection    `json:"sls_detections,omitempty"`
 	Interactions  []*domain.UserInteraction `json:"interactions,omitempty"`
+	VOXBaseline   *VOXBaselineReport        `json:"vox_baseline,omitempty"`
 }
 
 func (s *ExportService) collectSessionData(ctx context.Context, sessionID string, req ExportRequest) (*SessionExportData, error) {
@@ -167,7 +197,7 @@ func (s *ExportService) collectSessionData(ctx context.Context, sessionID string
 	if req.IncludeEVPs {
 		evps, err := s.evpRepo.GetBySessionID(ctx, sessionID)
 		if err == nil {
-			data.EVPs = evps
+			data.EVPs = filterAttributed(evps, req, func(e *domain.EVPRecording) (string, string) { return e.InvestigatorID, e.DeviceID })
 		}
 	}
 
@@ -175,7 +205,7 @@ func (s *ExportService) collectSessionData(ctx context.Context, sessionID string
 	if req.IncludeVOX {
 		voxEvents, err := s.voxRepo.GetBySessionID(ctx, sessionID)
 		if err == nil {
-			data.VOXEvents = voxEvents
+			data.VOXEvents = filterAttributed(voxEvents, req, func(e *domain.VOXEvent) (string, string) { return e.InvestigatorID, e.DeviceID })
 		}
 	}
 
@@ -183,7 +213,7 @@ func (s *ExportService) collectSessionData(ctx context.Context, sessionID string
 	if req.IncludeRadar {
 		radarEvents, err := s.radarRepo.GetBySessionID(ctx, sessionID)
 		if err == nil {
-			data.RadarEvents = radarEvents
+			data.RadarEvents = filterAttributed(radarEvents, req, func(e *domain.RadarEvent) (string, string) { return e.InvestigatorID, e.DeviceID })
 		}
 	}
 
@@ -191,7 +221,7 @@ func (s *ExportService) collectSessionData(ctx context.Context, sessionID string
 	if req.IncludeSLS {
 		slsDetections, err := s.slsRepo.GetBySessionID(ctx, sessionID)
 		if err == nil {
-			data.SLSDetections = slsDetections
+			data.SLSDetections = filterAttributed(slsDetections, req, func(e *domain.SLSDetection) (string, string) { return e.InvestigatorID, e.DeviceID })
 		}
 	}
 
@@ -199,13 +229,47 @@ func (s *ExportService) collectSessionData(ctx context.Context, sessionID string
 	if req.IncludeNotes {
 		interactions, err := s.interactionRepo.GetBySessionID(ctx, sessionID)
 		if err == nil {
-			data.Interactions = interactions
+			data.Interactions = filterAttributed(interactions, req, func(e *domain.UserInteraction) (string, string) { return e.InvestigatorID, e.DeviceID })
+		}
+	}
+
+	// Collect VOX chance baseline
+	if req.IncludeVOXBaseline && s.voxAnalysis != nil {
+		var opts VOXBaselineOptions
+		if req.VOXBaseline != nil {
+			opts = *req.VOXBaseline
+		}
+		report, err := s.voxAnalysis.AnalyzeChanceBaseline(ctx, sessionID, opts)
+		if err != nil {
+			return nil, fmt.Errorf("VOX baseline analysis failed: %w", err)
 		}
+		data.VOXBaseline = report
 	}
 
 	return data, nil
 }
 
+// filterAttributed keeps the events matching the request's investigator and
+// device filters
+func filterAttributed[T any](events []T, req ExportRequest, attribution func(T) (string, string)) []T {
+	if req.InvestigatorID == "" && req.DeviceID == "" {
+		return events
+	}
+
+	filtered := make([]T, 0, len(events))
+	for _, event := range events {
+		investigatorID, deviceID := attribution(event)
+		if req.InvestigatorID != "" && investigatorID != req.InvestigatorID {
+			continue
+		}
+		if req.DeviceID != "" && deviceID != req.DeviceID {
+			continue
+		}
+		filtered = append(filtered, event)
+	}
+	return filtered
+}
+
 func (s *ExportService) exportAsJSON(sessionData map[string]*SessionExportData) ([]byte, string, error) {
 	// Create export structure
 	export := struct {
@@ -230,8 +294,7 @@ func (s *ExportService) exportAsJSON(sessionData map[string]*SessionExportData)
 		return nil, "", fmt.Errorf("JSON marshaling failed: %w", err)
 	}
 
-	timestamp := time.Now().Format("20060102_150405")
-	filename := fmt.Sprintf("otherside_export_%s.json", timestamp)
+	filename := exportFilename("json")
 
 	return data, filename, nil
 }
@@ -291,8 +354,16 @@ func (s *ExportService) exportAsCSV(sessionData map[string]*SessionExportData, r
 		}
 	}
 
-	timestamp := time.Now().Format("20060102_150405")
-	filename := fmt.Sprintf("otherside_export_%s.csv", timestamp)
+	// Write VOX baseline CSV if requested
+	if req.IncludeVOXBaseline {
+		writer.Write([]string{}) // Empty line
+		writer.Write([]string{"VOX CHANCE BASELINE"})
+		if err := s.writeVOXBaselineCSV(writer, sessionData); err != nil {
+			return nil, "", fmt.Errorf("failed to write VOX baseline CSV: %w", err)
+		}
+	}
+
+	filename := exportFilename("csv")
 
 	return buf.Bytes(), filename, nil
 }
@@ -346,8 +417,7 @@ func (s *ExportService) exportAsZIP(ctx context.Context, sessionData map[string]
 	readmeFile, _ := zipWriter.Create("README.txt")
 	readmeFile.Write([]byte(readmeContent))
 
-	timestamp := time.Now().Format("20060102_150405")
-	filename := fmt.Sprintf("otherside_export_%s.zip", timestamp)
+	filename := exportFilename("zip")
 
 	return buf.Bytes(), filename, nil
 }
@@ -388,7 +458,7 @@ func (s *ExportService) writeEVPsCSV(writer *csv.Writer, sessionData map[string]
 	// Write header
 	header := []string{
 		"Session ID", "EVP ID", "Timestamp", "Duration", "Quality",
-		"Detection Level", "File Path", "Annotations",
+		"Detection Level", "File Path", "Annotations", "Investigator ID", "Device ID",
 	}
 	writer.Write(header)
 
@@ -410,6 +480,8 @@ func (s *ExportService) writeEVPsCSV(writer *csv.Writer, sessionData map[string]
 				fmt.Sprintf("%.3f", evp.DetectionLevel),
 				evp.FilePath,
 				annotations,
+				evp.InvestigatorID,
+				evp.DeviceID,
 			}
 			writer.Write(record)
 		}
@@ -423,6 +495,7 @@ func (s *ExportService) writeVOXCSV(writer *csv.Writer, sessionData map[string]*
 	header := []string{
 		"Session ID", "VOX ID", "Timestamp", "Generated Text", "Phonetic Bank",
 		"Trigger Strength", "Language Pack", "User Response", "Response Delay",
+		"Investigator ID", "Device ID",
 	}
 	writer.Write(header)
 
@@ -439,6 +512,8 @@ func (s *ExportService) writeVOXCSV(writer *csv.Writer, sessionData map[string]*
 				vox.LanguagePack,
 				vox.UserResponse,
 				fmt.Sprintf("%.2f", vox.ResponseDelay),
+				vox.InvestigatorID,
+				vox.DeviceID,
 			}
 			writer.Write(record)
 		}
@@ -452,6 +527,7 @@ func (s *ExportService) writeRadarCSV(writer *csv.Writer, sessionData map[string
 	header := []string{
 		"Session ID", "Radar ID", "Timestamp", "Position X", "Position Y",
 		"Strength", "Source Type", "EMF Reading", "Audio Anomaly", "Duration",
+		"Investigator ID", "Device ID",
 	}
 	writer.Write(header)
 
@@ -469,6 +545,8 @@ func (s *ExportService) writeRadarCSV(writer *csv.Writer, sessionData map[string
 				fmt.Sprintf("%.2f", radar.EMFReading),
 				fmt.Sprintf("%.3f", radar.AudioAnomaly),
 				fmt.Sprintf("%.2f", radar.Duration),
+				radar.InvestigatorID,
+				radar.DeviceID,
 			}
 			writer.Write(record)
 		}
@@ -482,6 +560,7 @@ func (s *ExportService) writeSLSCSV(writer *csv.Writer, sessionData map[string]*
 	header := []string{
 		"Session ID", "SLS ID", "Timestamp", "Confidence", "Skeletal Points Count",
 		"Bounding Box Width", "Bounding Box Height", "Duration", "Movement Pattern",
+		"Investigator ID", "Device ID",
 	}
 	writer.Write(header)
 
@@ -498,6 +577,8 @@ func (s *ExportService) writeSLSCSV(writer *csv.Writer, sessionData map[string]*
 				fmt.Sprintf("%.1f", sls.BoundingBox.Height),
 				fmt.Sprintf("%.2f", sls.Duration),
 				sls.Movement.Pattern,
+				sls.InvestigatorID,
+				sls.DeviceID,
 			}
 			writer.Write(record)
 		}
@@ -510,6 +591,7 @@ func (s *ExportService) writeInteractionsCSV(writer *csv.Writer, sessionData map
 	// Write header
 	header := []string{
 		"Session ID", "Interaction ID", "Timestamp", "Type", "Content", "Response", "Response Time",
+		"Investigator ID", "Device ID",
 	}
 	writer.Write(header)
 
@@ -524,6 +606,52 @@ func (s *ExportService) writeInteractionsCSV(writer *csv.Writer, sessionData map
 				interaction.Content,
 				interaction.Response,
 				fmt.Sprintf("%.2f", interaction.ResponseTime),
+				interaction.InvestigatorID,
+				interaction.DeviceID,
+			}
+			writer.Write(record)
+		}
+	}
+
+	return nil
+}
+
+func (s *ExportService) writeVOXBaselineCSV(writer *csv.Writer, sessionData map[string]*SessionExportData) error {
+	// Write header
+	header := []string{
+		"Session ID", "Word", "Observed", "Expected", "P-Value",
+	}
+	writer.Write(header)
+
+	// Write data, starting each session with its overall hit statistics
+	for sessionID, data := range sessionData {
+		report := data.VOXBaseline
+		if report == nil {
+			continue
+		}
+
+		writer.Write([]string{
+			sessionID,
+			"(word hits)",
+			fmt.Sprintf("%.0f", report.Observed.WordHits),
+			fmt.Sprintf("%.3f", report.Expected.WordHits),
+			fmt.Sprintf("%.4f", report.HitRatePValue),
+		})
+		writer.Write([]string{
+			sessionID,
+			"(relevant hits)",
+			fmt.Sprintf("%.0f", report.Observed.RelevantHits),
+			fmt.Sprintf("%.3f", report.Expected.RelevantHits),
+			fmt.Sprintf("%.4f", report.RelevancePValue),
+		})
+
+		for _, word := range report.Words {
+			record := []string{
+				sessionID,
+				word.Word,
+				strconv.Itoa(word.Observed),
+				fmt.Sprintf("%.3f", word.Expected),
+				fmt.Sprintf("%.4f", word.PValue),
 			}
 			writer.Write(record)
 		}
@@ -585,6 +713,7 @@ Export Configuration:
 - Include Radar: %t
 - Include SLS: %t
 - Include Notes: %t
+- Include VOX Baseline: %t
 - Include Audio Files: %t
 - Include Video Files: %t
 
@@ -598,7 +727,48 @@ For questions or support, visit: https://github.com/myideascope/otherside
 Generated by OtherSide Paranormal Investigation App v1.0.0
 `, time.Now().Format(time.RFC3339),
 		req.IncludeEVPs, req.IncludeVOX, req.IncludeRadar,
-		req.IncludeSLS, req.IncludeNotes, req.IncludeAudio, req.IncludeVideo)
+		req.IncludeSLS, req.IncludeNotes, req.IncludeVOXBaseline, req.IncludeAudio, req.IncludeVideo)
+}
+
+// SetVOXAnalysisService enables VOX chance baselines in exports
+func (s *ExportService) SetVOXAnalysisService(voxAnalysis *VOXAnalysisService) {
+	s.voxAnalysis = voxAnalysis
+}
+
+// SetAccessService enables access control on exports: exporting needs read
+// access to every session, and only the creator of an export, or a caller
+// who owns all of its sessions, may delete it
+func (s *ExportService) SetAccessService(accessService *AccessService) {
+	s.accessService = accessService
+}
+
+// SetWebhookService sends finished exports to export.ready webhook
+// subscribers
+func (s *ExportService) SetWebhookService(webhookService *WebhookService) {
+	s.webhookService = webhookService
+}
+
+// exportFilename names a new export file. The random part keeps exports
+// made in the same second apart.
+func exportFilename(ext string) string {
+	return fmt.Sprintf("otherside_export_%s_%s.%s", time.Now().Format("20060102_150405"), generateID()[:8], ext)
+}
+
+// AuthorizeExport fails with a forbidden error unless the caller has at
+// least the required access to an export file
+func (s *ExportService) AuthorizeExport(ctx context.Context, filename string, required domain.AccessLevel) error {
+	if s.accessService == nil {
+		return nil
+	}
+	return s.accessService.AuthorizeExport(ctx, filename, required)
+}
+
+// ForgetExport drops the access record of a deleted export file
+func (s *ExportService) ForgetExport(ctx context.Context, filename string) error {
+	if s.accessService == nil {
+		return nil
+	}
+	return s.accessService.ForgetExport(ctx, filename)
 }
 
 // GetFileRepository returns the file repository for direct file operations
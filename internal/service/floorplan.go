@@ -0,0 +1,437 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"math"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+)
+
+// Event types that can be located on a floor plan
+const (
+	LocatedEventRadar = "radar"
+	LocatedEventSLS   = "sls"
+	LocatedEventEVP   = "evp"
+)
+
+// FloorPlanService manages floor plans and places session events in building
+// coordinates
+type FloorPlanService struct {
+	sessionRepo   domain.SessionRepository
+	floorPlanRepo domain.FloorPlanRepository
+	placementRepo domain.DevicePlacementRepository
+	radarRepo     domain.RadarRepository
+	slsRepo       domain.SLSRepository
+	evpRepo       domain.EVPRepository
+	fileRepo      domain.FileRepository
+}
+
+// CreateFloorPlanRequest represents a request to create a floor plan
+type CreateFloorPlanRequest struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Width       float64       `json:"width"`
+	Height      float64       `json:"height"`
+	Rooms       []RoomRequest `json:"rooms"`
+}
+
+// RoomRequest describes a room outline in metres
+type RoomRequest struct {
+	Name    string               `json:"name"`
+	Polygon []domain.Coordinates `json:"polygon"`
+}
+
+// PlaceDeviceRequest represents a request to place a device on a floor plan.
+// PlacedAt defaults to now; a later placement of the same session supersedes
+// earlier ones from its PlacedAt onwards.
+type PlaceDeviceRequest struct {
+	FloorPlanID string             `json:"floor_plan_id"`
+	DeviceID    string             `json:"device_id"`
+	Origin      domain.Coordinates `json:"origin"`
+	Rotation    float64            `json:"rotation"`
+	PlacedAt    *time.Time         `json:"placed_at,omitempty"`
+}
+
+// LocateEventsOptions filters located events
+type LocateEventsOptions struct {
+	RoomID     string   `json:"room_id,omitempty"`
+	EventTypes []string `json:"event_types,omitempty"`
+}
+
+// LocatedEvent is a session event transformed into building coordinates
+type LocatedEvent struct {
+	EventType     string             `json:"event_type"`
+	EventID       string             `json:"event_id"`
+	Timestamp     time.Time          `json:"timestamp"`
+	PlacementID   string             `json:"placement_id"`
+	DeviceID      string             `json:"device_id"`
+	FloorPlanID   string             `json:"floor_plan_id"`
+	LocalPosition domain.Coordinates `json:"local_position"`
+	Position      domain.Coordinates `json:"position"`
+	RoomID        string             `json:"room_id,omitempty"`
+	RoomName      string             `json:"room_name,omitempty"`
+}
+
+// NewFloorPlanService creates a new floor plan service
+func NewFloorPlanService(
+	sessionRepo domain.SessionRepository,
+	floorPlanRepo domain.FloorPlanRepository,
+	placementRepo domain.DevicePlacementRepository,
+	radarRepo domain.RadarRepository,
+	slsRepo domain.SLSRepository,
+	evpRepo domain.EVPRepository,
+	fileRepo domain.FileRepository,
+) *FloorPlanService {
+	return &FloorPlanService{
+		sessionRepo:   sessionRepo,
+		floorPlanRepo: floorPlanRepo,
+		placementRepo: placementRepo,
+		radarRepo:     radarRepo,
+		slsRepo:       slsRepo,
+		evpRepo:       evpRepo,
+		fileRepo:      fileRepo,
+	}
+}
+
+// CreateFloorPlan creates a floor plan with its rooms
+func (s *FloorPlanService) CreateFloorPlan(ctx context.Context, req CreateFloorPlanRequest) (*domain.FloorPlan, error) {
+	if req.Name == "" {
+		return nil, fmt.Errorf("invalid floor plan: name is required")
+	}
+
+	plan := &domain.FloorPlan{
+		ID:          generateID(),
+		Name:        req.Name,
+		Description: req.Description,
+		Width:       req.Width,
+		Height:      req.Height,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	for _, roomReq := range req.Rooms {
+		if roomReq.Name == "" {
+			return nil, fmt.Errorf("invalid floor plan: room name is required")
+		}
+		if len(roomReq.Polygon) < 3 {
+			return nil, fmt.Errorf("invalid floor plan: room %s needs at least 3 vertices", roomReq.Name)
+		}
+
+		plan.Rooms = append(plan.Rooms, domain.Room{
+			ID:          generateID(),
+			FloorPlanID: plan.ID,
+			Name:        roomReq.Name,
+			Polygon:     roomReq.Polygon,
+		})
+	}
+
+	if err := s.floorPlanRepo.Create(ctx, plan); err != nil {
+		return nil, fmt.Errorf("failed to create floor plan: %w", err)
+	}
+
+	return plan, nil
+}
+
+// GetFloorPlan retrieves a floor plan with its rooms
+func (s *FloorPlanService) GetFloorPlan(ctx context.Context, id string) (*domain.FloorPlan, error) {
+	plan, err := s.floorPlanRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("floor plan not found: %w", err)
+	}
+	return plan, nil
+}
+
+// ListFloorPlans retrieves all floor plans
+func (s *FloorPlanService) ListFloorPlans(ctx context.Context) ([]*domain.FloorPlan, error) {
+	return s.floorPlanRepo.GetAll(ctx)
+}
+
+// DeleteFloorPlan deletes a floor plan and its stored image
+func (s *FloorPlanService) DeleteFloorPlan(ctx context.Context, id string) error {
+	plan, err := s.floorPlanRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("floor plan not found: %w", err)
+	}
+
+	if err := s.floorPlanRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete floor plan: %w", err)
+	}
+
+	if plan.ImagePath != "" {
+		// The plan is already gone; a leftover image is picked up by cleanup
+		s.fileRepo.DeleteFile(ctx, plan.ImagePath)
+	}
+
+	return nil
+}
+
+// UploadFloorPlanImage stores a PNG or JPEG image of a floor plan
+func (s *FloorPlanService) UploadFloorPlanImage(ctx context.Context, id string, data []byte) (*domain.FloorPlan, error) {
+	plan, err := s.floorPlanRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("floor plan not found: %w", err)
+	}
+
+	_, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid floor plan image: %w", err)
+	}
+
+	imagePath := path.Join("floorplans", plan.ID, "plan."+format)
+	if err := s.fileRepo.SaveFile(ctx, imagePath, data); err != nil {
+		return nil, fmt.Errorf("failed to save floor plan image: %w", err)
+	}
+
+	plan.ImagePath = imagePath
+	plan.UpdatedAt = time.Now()
+
+	if err := s.floorPlanRepo.Update(ctx, plan); err != nil {
+		return nil, fmt.Errorf("failed to update floor plan: %w", err)
+	}
+
+	return plan, nil
+}
+
+// GetFloorPlanImage returns the stored image of a floor plan and its format
+func (s *FloorPlanService) GetFloorPlanImage(ctx context.Context, id string) ([]byte, string, error) {
+	plan, err := s.floorPlanRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, "", fmt.Errorf("floor plan not found: %w", err)
+	}
+
+	if plan.ImagePath == "" {
+		return nil, "", fmt.Errorf("floor plan image not found")
+	}
+
+	data, err := s.fileRepo.GetFile(ctx, plan.ImagePath)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read floor plan image: %w", err)
+	}
+
+	return data, path.Ext(plan.ImagePath)[1:], nil
+}
+
+// PlaceDevice records where a device sits on a floor plan during a session
+func (s *FloorPlanService) PlaceDevice(ctx context.Context, sessionID string, req PlaceDeviceRequest) (*domain.DevicePlacement, error) {
+	if _, err := s.sessionRepo.GetByID(ctx, sessionID); err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+
+	if _, err := s.floorPlanRepo.GetByID(ctx, req.FloorPlanID); err != nil {
+		return nil, fmt.Errorf("floor plan not found: %w", err)
+	}
+
+	placedAt := time.Now()
+	if req.PlacedAt != nil {
+		placedAt = *req.PlacedAt
+	}
+
+	placement := &domain.DevicePlacement{
+		ID:          generateID(),
+		SessionID:   sessionID,
+		FloorPlanID: req.FloorPlanID,
+		DeviceID:    req.DeviceID,
+		Origin:      req.Origin,
+		Rotation:    req.Rotation,
+		PlacedAt:    placedAt,
+		CreatedAt:   time.Now(),
+	}
+
+	if err := s.placementRepo.Create(ctx, placement); err != nil {
+		return nil, fmt.Errorf("failed to save device placement: %w", err)
+	}
+
+	return placement, nil
+}
+
+// GetPlacements returns the device placements of a session, oldest first
+func (s *FloorPlanService) GetPlacements(ctx context.Context, sessionID string) ([]*domain.DevicePlacement, error) {
+	if _, err := s.sessionRepo.GetByID(ctx, sessionID); err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+
+	return s.placementRepo.GetBySessionID(ctx, sessionID)
+}
+
+// LocateEvents transforms a session's radar, SLS and EVP events into building
+// coordinates and resolves the room each one falls in. Radar positions are
+// rotated and offset by the placement of the event's device active at the
+// event time. SLS and EVP events carry no range, so they are located at the
+// device origin. Events of a device that was never placed are left out, and
+// events without a device ID use the placements of every device.
+func (s *FloorPlanService) LocateEvents(ctx context.Context, sessionID string, opts LocateEventsOptions) ([]*LocatedEvent, error) {
+	if _, err := s.sessionRepo.GetByID(ctx, sessionID); err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+
+	placements, err := s.placementRepo.GetBySessionID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get device placements: %w", err)
+	}
+	if len(placements) == 0 {
+		return []*LocatedEvent{}, nil
+	}
+	sort.SliceStable(placements, func(i, j int) bool {
+		return placements[i].PlacedAt.Before(placements[j].PlacedAt)
+	})
+
+	devicePlacements := make(map[string][]*domain.DevicePlacement)
+	for _, placement := range placements {
+		devicePlacements[placement.DeviceID] = append(devicePlacements[placement.DeviceID], placement)
+	}
+
+	plans := make(map[string]*domain.FloorPlan)
+	for _, placement := range placements {
+		if _, ok := plans[placement.FloorPlanID]; ok {
+			continue
+		}
+		plan, err := s.floorPlanRepo.GetByID(ctx, placement.FloorPlanID)
+		if err != nil {
+			return nil, fmt.Errorf("floor plan not found: %w", err)
+		}
+		plans[plan.ID] = plan
+	}
+
+	include := func(eventType string) bool {
+		if len(opts.EventTypes) == 0 {
+			return true
+		}
+		for _, t := range opts.EventTypes {
+			if t == eventType {
+				return true
+			}
+		}
+		return false
+	}
+
+	var located []*LocatedEvent
+	locate := func(eventType, eventID, deviceID string, timestamp time.Time, local domain.Coordinates, ranged bool) {
+		candidates := placements
+		if deviceID != "" {
+			candidates = devicePlacements[deviceID]
+		}
+		if len(candidates) == 0 {
+			return
+		}
+		placement := placementAt(candidates, timestamp)
+
+		event := &LocatedEvent{
+			EventType:     eventType,
+			EventID:       eventID,
+			Timestamp:     timestamp,
+			PlacementID:   placement.ID,
+			DeviceID:      placement.DeviceID,
+			FloorPlanID:   placement.FloorPlanID,
+			LocalPosition: local,
+			Position:      placement.Origin,
+		}
+		if ranged {
+			event.Position = toBuildingCoordinates(placement, local)
+		}
+
+		if room := roomContaining(plans[placement.FloorPlanID], event.Position); room != nil {
+			event.RoomID = room.ID
+			event.RoomName = room.Name
+		}
+
+		if opts.RoomID != "" && event.RoomID != opts.RoomID {
+			return
+		}
+		located = append(located, event)
+	}
+
+	if include(LocatedEventRadar) {
+		radarEvents, err := s.radarRepo.GetBySessionID(ctx, sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get radar events: %w", err)
+		}
+		for _, event := range radarEvents {
+			locate(LocatedEventRadar, event.ID, event.DeviceID, event.Timestamp, event.Position, true)
+		}
+	}
+
+	if include(LocatedEventSLS) {
+		slsDetections, err := s.slsRepo.GetBySessionID(ctx, sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get SLS detections: %w", err)
+		}
+		for _, detection := range slsDetections {
+			locate(LocatedEventSLS, detection.ID, detection.DeviceID, detection.Timestamp, domain.Coordinates{}, false)
+		}
+	}
+
+	if include(LocatedEventEVP) {
+		evpRecordings, err := s.evpRepo.GetBySessionID(ctx, sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get EVP recordings: %w", err)
+		}
+		for _, recording := range evpRecordings {
+			locate(LocatedEventEVP, recording.ID, recording.DeviceID, recording.Timestamp, domain.Coordinates{}, false)
+		}
+	}
+
+	sort.SliceStable(located, func(i, j int) bool {
+		return located[i].Timestamp.Before(located[j].Timestamp)
+	})
+
+	return located, nil
+}
+
+// placementAt returns the latest placement made at or before the timestamp.
+// Events recorded before the first placement use the first placement.
+func placementAt(placements []*domain.DevicePlacement, timestamp time.Time) *domain.DevicePlacement {
+	active := placements[0]
+	for _, placement := range placements[1:] {
+		if placement.PlacedAt.After(timestamp) {
+			break
+		}
+		active = placement
+	}
+	return active
+}
+
+// toBuildingCoordinates rotates a device-relative position by the placement
+// rotation and offsets it by the placement origin
+func toBuildingCoordinates(placement *domain.DevicePlacement, local domain.Coordinates) domain.Coordinates {
+	sin, cos := math.Sincos(placement.Rotation)
+
+	return domain.Coordinates{
+		X: placement.Origin.X + local.X*cos - local.Y*sin,
+		Y: placement.Origin.Y + local.X*sin + local.Y*cos,
+		Z: placement.Origin.Z + local.Z,
+	}
+}
+
+// roomContaining returns the first room whose polygon contains the point
+func roomContaining(plan *domain.FloorPlan, point domain.Coordinates) *domain.Room {
+	if plan == nil {
+		return nil
+	}
+	for i := range plan.Rooms {
+		if polygonContains(plan.Rooms[i].Polygon, point) {
+			return &plan.Rooms[i]
+		}
+	}
+	return nil
+}
+
+// polygonContains tests a point against a polygon with the even-odd rule
+func polygonContains(polygon []domain.Coordinates, point domain.Coordinates) bool {
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[i], polygon[j]
+		if (a.Y > point.Y) != (b.Y > point.Y) &&
+			point.X < (b.X-a.X)*(point.Y-a.Y)/(b.Y-a.Y)+a.X {
+			inside = !inside
+		}
+	}
+	return inside
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testFloorPlan() *domain.FloorPlan {
+	return &domain.FloorPlan{
+		ID:   "plan-1",
+		Name: "Ground floor",
+		Rooms: []domain.Room{
+			{ID: "hall", FloorPlanID: "plan-1", Name: "Hall", Polygon: []domain.Coordinates{{X: 0, Y: 0}, {X: 5, Y: 0}, {X: 5, Y: 5}, {X: 0, Y: 5}}},
+			{ID: "kitchen", FloorPlanID: "plan-1", Name: "Kitchen", Polygon: []domain.Coordinates{{X: 5, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 5}, {X: 5, Y: 5}}},
+		},
+	}
+}
+
+func TestFloorPlanService_LocateEvents_TransformsIntoRooms(t *testing.T) {
+	// Arrange
+	mockSessionRepo := &MockSessionRepository{}
+	mockFloorPlanRepo := &MockFloorPlanRepository{}
+	mockPlacementRepo := &MockDevicePlacementRepository{}
+	mockRadarRepo := &MockRadarRepository{}
+	mockSLSRepo := &MockSLSRepository{}
+	mockEVPRepo := &MockEVPRepository{}
+
+	base := time.Now().Add(-time.Hour)
+	// The device starts in the hall facing +Y, then moves into the kitchen
+	placements := []*domain.DevicePlacement{
+		{ID: "p1", SessionID: "test-session-123", FloorPlanID: "plan-1", DeviceID: "phone", Origin: domain.Coordinates{X: 2, Y: 2}, Rotation: math.Pi / 2, PlacedAt: base},
+		{ID: "p2", SessionID: "test-session-123", FloorPlanID: "plan-1", DeviceID: "phone", Origin: domain.Coordinates{X: 7, Y: 2}, PlacedAt: base.Add(10 * time.Minute)},
+	}
+	radarEvents := []*domain.RadarEvent{
+		testRadarEventAt("r1", base.Add(time.Minute), 1, 0),      // -> (2, 3) in the hall
+		testRadarEventAt("r2", base.Add(15*time.Minute), 1, 1),   // -> (8, 3) in the kitchen
+		testRadarEventAt("r3", base.Add(16*time.Minute), 10, 10), // -> outside every room
+		testRadarEventAt("early", base.Add(-time.Minute), 0, -1), // before first placement -> (3, 2)
+	}
+	slsDetection := TestSLSDetection()
+	slsDetection.Timestamp = base.Add(20 * time.Minute)
+
+	mockSessionRepo.On("GetByID", mock.Anything, "test-session-123").Return(TestSession(), nil)
+	mockPlacementRepo.On("GetBySessionID", mock.Anything, "test-session-123").Return(placements, nil)
+	mockFloorPlanRepo.On("GetByID", mock.Anything, "plan-1").Return(testFloorPlan(), nil).Once()
+	mockRadarRepo.On("GetBySessionID", mock.Anything, "test-session-123").Return(radarEvents, nil)
+	mockSLSRepo.On("GetBySessionID", mock.Anything, "test-session-123").Return([]*domain.SLSDetection{slsDetection}, nil)
+
+	service := NewFloorPlanService(mockSessionRepo, mockFloorPlanRepo, mockPlacementRepo, mockRadarRepo, mockSLSRepo, mockEVPRepo, &MockFileRepository{})
+
+	// Act
+	events, err := service.LocateEvents(context.Background(), "test-session-123", LocateEventsOptions{
+		EventTypes: []string{LocatedEventRadar, LocatedEventSLS},
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, events, 5)
+
+	assert.Equal(t, "early", events[0].EventID)
+	assert.Equal(t, "p1", events[0].PlacementID)
+	assert.InDelta(t, 3.0, events[0].Position.X, 1e-9)
+	assert.InDelta(t, 2.0, events[0].Position.Y, 1e-9)
+
+	assert.Equal(t, "r1", events[1].EventID)
+	assert.InDelta(t, 2.0, events[1].Position.X, 1e-9)
+	assert.InDelta(t, 3.0, events[1].Position.Y, 1e-9)
+	assert.Equal(t, "Hall", events[1].RoomName)
+
+	assert.Equal(t, "r2", events[2].EventID)
+	assert.Equal(t, "p2", events[2].PlacementID)
+	assert.Equal(t, "kitchen", events[2].RoomID)
+
+	assert.Equal(t, "r3", events[3].EventID)
+	assert.Empty(t, events[3].RoomID)
+
+	// SLS detections sit at the device origin
+	assert.Equal(t, LocatedEventSLS, events[4].EventType)
+	assert.Equal(t, domain.Coordinates{X: 7, Y: 2}, events[4].Position)
+	assert.Equal(t, "kitchen", events[4].RoomID)
+
+	mockEVPRepo.AssertNotCalled(t, "GetBySessionID", mock.Anything, mock.Anything)
+	mockFloorPlanRepo.AssertExpectations(t)
+}
+
+func TestFloorPlanService_LocateEvents_FiltersByRoom(t *testing.T) {
+	// Arrange
+	mockSessionRepo := &MockSessionRepository{}
+	mockFloorPlanRepo := &MockFloorPlanRepository{}
+	mockPlacementRepo := &MockDevicePlacementRepository{}
+	mockRadarRepo := &MockRadarRepository{}
+
+	base := time.Now().Add(-time.Hour)
+	placements := []*domain.DevicePlacement{
+		{ID: "p1", FloorPlanID: "plan-1", Origin: domain.Coordinates{X: 5, Y: 2.5}, PlacedAt: base},
+	}
+	radarEvents := []*domain.RadarEvent{
+		testRadarEventAt("west", base.Add(time.Second), -1, 0),
+		testRadarEventAt("east", base.Add(2*time.Second), 1, 0),
+	}
+
+	mockSessionRepo.On("GetByID", mock.Anything, "test-session-123").Return(TestSession(), nil)
+	mockPlacementRepo.On("GetBySessionID", mock.Anything, "test-session-123").Return(placements, nil)
+	mockFloorPlanRepo.On("GetByID", mock.Anything, "plan-1").Return(testFloorPlan(), nil)
+	mockRadarRepo.On("GetBySessionID", mock.Anything, "test-session-123").Return(radarEvents, nil)
+
+	service := NewFloorPlanService(mockSessionRepo, mockFloorPlanRepo, mockPlacementRepo, mockRadarRepo, &MockSLSRepository{}, &MockEVPRepository{}, &MockFileRepository{})
+
+	// Act
+	events, err := service.LocateEvents(context.Background(), "test-session-123", LocateEventsOptions{
+		RoomID:     "kitchen",
+		EventTypes: []string{LocatedEventRadar},
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "east", events[0].EventID)
+}
+
+func TestFloorPlanService_LocateEvents_TwoDevices_UsesEachDevicePlacement(t *testing.T) {
+	// Arrange
+	mockSessionRepo := &MockSessionRepository{}
+	mockFloorPlanRepo := &MockFloorPlanRepository{}
+	mockPlacementRepo := &MockDevicePlacementRepository{}
+	mockRadarRepo := &MockRadarRepository{}
+
+	base := time.Now().Add(-time.Hour)
+	// The hall radar is placed last, so it is the latest placement for both events
+	placements := []*domain.DevicePlacement{
+		{ID: "kitchen-placement", FloorPlanID: "plan-1", DeviceID: "radar-kitchen", Origin: domain.Coordinates{X: 7, Y: 2}, PlacedAt: base},
+		{ID: "hall-placement", FloorPlanID: "plan-1", DeviceID: "radar-hall", Origin: domain.Coordinates{X: 2, Y: 2}, PlacedAt: base.Add(time.Minute)},
+	}
+	kitchenEvent := testRadarEventAt("kitchen-event", base.Add(2*time.Minute), 1, 0)
+	kitchenEvent.DeviceID = "radar-kitchen"
+	hallEvent := testRadarEventAt("hall-event", base.Add(3*time.Minute), 1, 0)
+	hallEvent.DeviceID = "radar-hall"
+	unplacedEvent := testRadarEventAt("unplaced-event", base.Add(4*time.Minute), 1, 0)
+	unplacedEvent.DeviceID = "radar-attic"
+
+	mockSessionRepo.On("GetByID", mock.Anything, "test-session-123").Return(TestSession(), nil)
+	mockPlacementRepo.On("GetBySessionID", mock.Anything, "test-session-123").Return(placements, nil)
+	mockFloorPlanRepo.On("GetByID", mock.Anything, "plan-1").Return(testFloorPlan(), nil)
+	mockRadarRepo.On("GetBySessionID", mock.Anything, "test-session-123").Return([]*domain.RadarEvent{kitchenEvent, hallEvent, unplacedEvent}, nil)
+
+	service := NewFloorPlanService(mockSessionRepo, mockFloorPlanRepo, mockPlacementRepo, mockRadarRepo, &MockSLSRepository{}, &MockEVPRepository{}, &MockFileRepository{})
+
+	// Act
+	events, err := service.LocateEvents(context.Background(), "test-session-123", LocateEventsOptions{
+		EventTypes: []string{LocatedEventRadar},
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	assert.Equal(t, "kitchen-event", events[0].EventID)
+	assert.Equal(t, "kitchen-placement", events[0].PlacementID)
+	assert.Equal(t, "radar-kitchen", events[0].DeviceID)
+	assert.Equal(t, "kitchen", events[0].RoomID)
+
+	assert.Equal(t, "hall-event", events[1].EventID)
+	assert.Equal(t, "hall-placement", events[1].PlacementID)
+	assert.Equal(t, "hall", events[1].RoomID)
+}
+
+func TestFloorPlanService_CreateFloorPlan_DegenerateRoom_ReturnsError(t *testing.T) {
+	// Arrange
+	mockFloorPlanRepo := &MockFloorPlanRepository{}
+	service := NewFloorPlanService(&MockSessionRepository{}, mockFloorPlanRepo, &MockDevicePlacementRepository{}, &MockRadarRepository{}, &MockSLSRepository{}, &MockEVPRepository{}, &MockFileRepository{})
+
+	// Act
+	plan, err := service.CreateFloorPlan(context.Background(), CreateFloorPlanRequest{
+		Name:  "Attic",
+		Rooms: []RoomRequest{{Name: "Crawlspace", Polygon: []domain.Coordinates{{X: 0, Y: 0}, {X: 1, Y: 0}}}},
+	})
+
+	// Assert
+	require.Error(t, err)
+	assert.Nil(t, plan)
+	assert.Contains(t, err.Error(), "at least 3 vertices")
+	mockFloorPlanRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestFloorPlanService_UploadFloorPlanImage_ValidPNG_StoresImage(t *testing.T) {
+	// Arrange
+	mockFloorPlanRepo := &MockFloorPlanRepository{}
+	mockFileRepo := &MockFileRepository{}
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 4))))
+
+	mockFloorPlanRepo.On("GetByID", mock.Anything, "plan-1").Return(testFloorPlan(), nil)
+	mockFileRepo.On("SaveFile", mock.Anything, "floorplans/plan-1/plan.png", buf.Bytes()).Return(nil).Once()
+	mockFloorPlanRepo.On("Update", mock.Anything, mock.MatchedBy(func(plan *domain.FloorPlan) bool {
+		return plan.ImagePath == "floorplans/plan-1/plan.png"
+	})).Return(nil).Once()
+
+	service := NewFloorPlanService(&MockSessionRepository{}, mockFloorPlanRepo, &MockDevicePlacementRepository{}, &MockRadarRepository{}, &MockSLSRepository{}, &MockEVPRepository{}, mockFileRepo)
+
+	// Act
+	plan, err := service.UploadFloorPlanImage(context.Background(), "plan-1", buf.Bytes())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "floorplans/plan-1/plan.png", plan.ImagePath)
+	mockFileRepo.AssertExpectations(t)
+	mockFloorPlanRepo.AssertExpectations(t)
+}
+
+func TestFloorPlanService_UploadFloorPlanImage_NotAnImage_ReturnsError(t *testing.T) {
+	// Arrange
+	mockFloorPlanRepo := &MockFloorPlanRepository{}
+	mockFloorPlanRepo.On("GetByID", mock.Anything, "plan-1").Return(testFloorPlan(), nil)
+
+	service := NewFloorPlanService(&MockSessionRepository{}, mockFloorPlanRepo, &MockDevicePlacementRepository{}, &MockRadarRepository{}, &MockSLSRepository{}, &MockEVPRepository{}, &MockFileRepository{})
+
+	// Act
+	plan, err := service.UploadFloorPlanImage(context.Background(), "plan-1", []byte("not an image"))
+
+	// Assert
+	require.Error(t, err)
+	assert.Nil(t, plan)
+	assert.Contains(t, err.Error(), "invalid floor plan image")
+}
@@ -0,0 +1,350 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+)
+
+// Modalities that fusion rules can correlate
+const (
+	FusionModalityEVP   = "evp"
+	FusionModalityRadar = "radar"
+	FusionModalitySLS   = "sls"
+	FusionModalityEMF   = "emf"
+)
+
+const (
+	defaultFusionWindowSeconds = 2.0
+	defaultEMFSpikeFactor      = 3.0
+	defaultEMFSpikeMinimum     = 1.0
+)
+
+// FusionService correlates events from separate sensor modalities
+type FusionService struct {
+	sessionRepo domain.SessionRepository
+	evpRepo     domain.EVPRepository
+	radarRepo   domain.RadarRepository
+	slsRepo     domain.SLSRepository
+	fusionRepo  domain.FusionEventRepository
+}
+
+// FusionRule emits a fusion event when every listed modality has an event
+// inside the window
+type FusionRule struct {
+	Name          string   `json:"name"`
+	Modalities    []string `json:"modalities"`
+	WindowSeconds float64  `json:"window_seconds"`
+	MinConfidence float64  `json:"min_confidence"`
+}
+
+// FusionConfig configures the correlation pass. Rules are evaluated in order
+// and an event contributes to at most one fusion event, so more specific
+// rules should come first.
+type FusionConfig struct {
+	Rules []FusionRule `json:"rules,omitempty"`
+	// EMFSpikeFactor marks a radar EMF reading as a spike when it exceeds
+	// this multiple of the session's baseline EMF level
+	EMFSpikeFactor float64 `json:"emf_spike_factor"`
+	// EMFSpikeMinimum is the lowest EMF reading treated as a spike
+	EMFSpikeMinimum float64 `json:"emf_spike_minimum"`
+}
+
+// fusionSignal is one event from one modality on the session timeline. The
+// key identifies the stored event, which EMF spikes share with their radar event.
+type fusionSignal struct {
+	modality   string
+	key        string
+	eventID    string
+	start      time.Time
+	end        time.Time
+	confidence float64
+}
+
+// NewFusionService creates a new fusion service
+func NewFusionService(
+	sessionRepo domain.SessionRepository,
+	evpRepo domain.EVPRepository,
+	radarRepo domain.RadarRepository,
+	slsRepo domain.SLSRepository,
+	fusionRepo domain.FusionEventRepository,
+) *FusionService {
+	return &FusionService{
+		sessionRepo: sessionRepo,
+		evpRepo:     evpRepo,
+		radarRepo:   radarRepo,
+		slsRepo:     slsRepo,
+		fusionRepo:  fusionRepo,
+	}
+}
+
+// DefaultFusionRules returns the built-in correlation rules, most specific
+// first. EMF rules precede the plain radar pairs because a spike would
+// otherwise always be claimed through its own radar event.
+func DefaultFusionRules() []FusionRule {
+	return []FusionRule{
+		{Name: "evp_radar_sls", Modalities: []string{FusionModalityEVP, FusionModalityRadar, FusionModalitySLS}},
+		{Name: "emf_evp", Modalities: []string{FusionModalityEMF, FusionModalityEVP}},
+		{Name: "emf_sls", Modalities: []string{FusionModalityEMF, FusionModalitySLS}},
+		{Name: "radar_sls", Modalities: []string{FusionModalityRadar, FusionModalitySLS}},
+		{Name: "evp_radar", Modalities: []string{FusionModalityEVP, FusionModalityRadar}},
+	}
+}
+
+// Correlate scans a session's events, then rebuilds and persists its fusion events
+func (s *FusionService) Correlate(ctx context.Context, sessionID string, config FusionConfig) ([]*domain.FusionEvent, error) {
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+
+	config, err = normalizeFusionConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	signals, err := s.collectSignals(ctx, session, config)
+	if err != nil {
+		return nil, err
+	}
+
+	events := correlateSignals(sessionID, signals, config.Rules)
+
+	if err := s.fusionRepo.ReplaceBySessionID(ctx, sessionID, events); err != nil {
+		return nil, fmt.Errorf("failed to save fusion events: %w", err)
+	}
+
+	return events, nil
+}
+
+// GetFusionEvents returns the stored fusion events of a session
+func (s *FusionService) GetFusionEvents(ctx context.Context, sessionID string) ([]*domain.FusionEvent, error) {
+	if _, err := s.sessionRepo.GetByID(ctx, sessionID); err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+
+	return s.fusionRepo.GetBySessionID(ctx, sessionID)
+}
+
+// collectSignals loads every modality of a session as timeline signals with
+// a per-event confidence in [0, 1]
+func (s *FusionService) collectSignals(ctx context.Context, session *domain.Session, config FusionConfig) ([]fusionSignal, error) {
+	var signals []fusionSignal
+
+	evpRecordings, err := s.evpRepo.GetBySessionID(ctx, session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get EVP recordings: %w", err)
+	}
+	for _, evp := range evpRecordings {
+		signals = append(signals, newFusionSignal(FusionModalityEVP, "evp:"+evp.ID, evp.ID, evp.Timestamp, evp.Duration, evp.DetectionLevel))
+	}
+
+	radarEvents, err := s.radarRepo.GetBySessionID(ctx, session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get radar events: %w", err)
+	}
+
+	emfThreshold := math.Max(config.EMFSpikeMinimum, config.EMFSpikeFactor*session.Environmental.EMFLevel)
+	for _, radar := range radarEvents {
+		signals = append(signals, newFusionSignal(FusionModalityRadar, "radar:"+radar.ID, radar.ID, radar.Timestamp, radar.Duration, radar.Strength))
+
+		if radar.EMFReading >= emfThreshold {
+			// Confidence grows from 0 at the threshold towards 1 for large spikes
+			confidence := 1 - emfThreshold/radar.EMFReading
+			signals = append(signals, newFusionSignal(FusionModalityEMF, "radar:"+radar.ID, radar.ID, radar.Timestamp, radar.Duration, confidence))
+		}
+	}
+
+	slsDetections, err := s.slsRepo.GetBySessionID(ctx, session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SLS detections: %w", err)
+	}
+	for _, sls := range slsDetections {
+		signals = append(signals, newFusionSignal(FusionModalitySLS, "sls:"+sls.ID, sls.ID, sls.Timestamp, sls.Duration, sls.Confidence))
+	}
+
+	sort.SliceStable(signals, func(i, j int) bool {
+		return signals[i].start.Before(signals[j].start)
+	})
+
+	return signals, nil
+}
+
+func newFusionSignal(modality, key, eventID string, start time.Time, durationSeconds, confidence float64) fusionSignal {
+	return fusionSignal{
+		modality:   modality,
+		key:        key,
+		eventID:    eventID,
+		start:      start,
+		end:        start.Add(time.Duration(math.Max(0, durationSeconds) * float64(time.Second))),
+		confidence: math.Max(0, math.Min(1, confidence)),
+	}
+}
+
+// correlateSignals applies the rules to start-ordered signals. Each unused
+// signal anchors a window starting at its start time; the strongest unused
+// signal of every other required modality overlapping the window joins it.
+// A stored event is used by at most one fusion event.
+func correlateSignals(sessionID string, signals []fusionSignal, rules []FusionRule) []*domain.FusionEvent {
+	var maxDuration time.Duration
+	for _, signal := range signals {
+		maxDuration = max(maxDuration, signal.end.Sub(signal.start))
+	}
+
+	used := make(map[string]bool)
+	var events []*domain.FusionEvent
+
+	for _, rule := range rules {
+		window := time.Duration(rule.WindowSeconds * float64(time.Second))
+
+		for i, anchor := range signals {
+			if used[anchor.key] || !containsString(rule.Modalities, anchor.modality) {
+				continue
+			}
+
+			windowStart := anchor.start
+			windowEnd := anchor.start.Add(window)
+
+			chosen := map[string]int{anchor.modality: i}
+			// Earlier signals can still overlap the window if they are long enough
+			first := sort.Search(len(signals), func(j int) bool {
+				return !signals[j].start.Before(windowStart.Add(-maxDuration))
+			})
+			for j := first; j < len(signals) && !signals[j].start.After(windowEnd); j++ {
+				candidate := signals[j]
+				if used[candidate.key] || candidate.end.Before(windowStart) ||
+					candidate.modality == anchor.modality || candidate.key == anchor.key ||
+					!containsString(rule.Modalities, candidate.modality) {
+					continue
+				}
+				if current, ok := chosen[candidate.modality]; !ok || candidate.confidence > signals[current].confidence {
+					chosen[candidate.modality] = j
+				}
+			}
+
+			if len(chosen) < len(rule.Modalities) || !distinctEvents(signals, chosen) {
+				continue
+			}
+
+			event := buildFusionEvent(sessionID, rule, signals, chosen)
+			if event.Confidence < rule.MinConfidence {
+				continue
+			}
+
+			for _, j := range chosen {
+				used[signals[j].key] = true
+			}
+			events = append(events, event)
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].StartTime.Before(events[j].StartTime)
+	})
+
+	return events
+}
+
+// buildFusionEvent combines contributor confidences with a noisy-OR, so each
+// additional independent modality raises the combined score
+func buildFusionEvent(sessionID string, rule FusionRule, signals []fusionSignal, chosen map[string]int) *domain.FusionEvent {
+	event := &domain.FusionEvent{
+		ID:        generateID(),
+		SessionID: sessionID,
+		Rule:      rule.Name,
+		CreatedAt: time.Now(),
+	}
+
+	miss := 1.0
+	for _, modality := range rule.Modalities {
+		signal := signals[chosen[modality]]
+
+		event.Contributors = append(event.Contributors, domain.FusionContributor{
+			EventType:  signal.modality,
+			EventID:    signal.eventID,
+			Timestamp:  signal.start,
+			Confidence: signal.confidence,
+		})
+		miss *= 1 - signal.confidence
+
+		if event.StartTime.IsZero() || signal.start.Before(event.StartTime) {
+			event.StartTime = signal.start
+		}
+		if signal.end.After(event.EndTime) {
+			event.EndTime = signal.end
+		}
+	}
+	event.Confidence = 1 - miss
+
+	return event
+}
+
+// distinctEvents rejects matches where one stored event fills two
+// modalities, such as a radar event and its own EMF spike
+func distinctEvents(signals []fusionSignal, chosen map[string]int) bool {
+	seen := make(map[string]bool, len(chosen))
+	for _, j := range chosen {
+		if seen[signals[j].key] {
+			return false
+		}
+		seen[signals[j].key] = true
+	}
+	return true
+}
+
+func normalizeFusionConfig(config FusionConfig) (FusionConfig, error) {
+	if len(config.Rules) == 0 {
+		config.Rules = DefaultFusionRules()
+	}
+
+	rules := make([]FusionRule, len(config.Rules))
+	for i, rule := range config.Rules {
+		if rule.Name == "" {
+			return config, fmt.Errorf("invalid fusion rule %d: name is required", i)
+		}
+		if len(rule.Modalities) < 2 {
+			return config, fmt.Errorf("invalid fusion rule %s: at least two modalities are required", rule.Name)
+		}
+
+		seen := make(map[string]bool)
+		for _, modality := range rule.Modalities {
+			switch modality {
+			case FusionModalityEVP, FusionModalityRadar, FusionModalitySLS, FusionModalityEMF:
+			default:
+				return config, fmt.Errorf("invalid fusion rule %s: unknown modality %s", rule.Name, modality)
+			}
+			if seen[modality] {
+				return config, fmt.Errorf("invalid fusion rule %s: duplicate modality %s", rule.Name, modality)
+			}
+			seen[modality] = true
+		}
+
+		if rule.WindowSeconds <= 0 {
+			rule.WindowSeconds = defaultFusionWindowSeconds
+		}
+		rules[i] = rule
+	}
+	config.Rules = rules
+
+	if config.EMFSpikeFactor <= 0 {
+		config.EMFSpikeFactor = defaultEMFSpikeFactor
+	}
+	if config.EMFSpikeMinimum <= 0 {
+		config.EMFSpikeMinimum = defaultEMFSpikeMinimum
+	}
+
+	return config, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFusionService_Correlate_DefaultRules_GroupsCoincidentEvents(t *testing.T) {
+	// Arrange
+	mockSessionRepo := &MockSessionRepository{}
+	mockEVPRepo := &MockEVPRepository{}
+	mockRadarRepo := &MockRadarRepository{}
+	mockSLSRepo := &MockSLSRepository{}
+	mockFusionRepo := &MockFusionEventRepository{}
+
+	base := time.Now().Add(-time.Hour)
+	session := TestSession()
+	session.Environmental.EMFLevel = 1.0
+
+	evp := TestEVPRecording()
+	evp.ID = "evp-1"
+	evp.Timestamp = base
+	evp.Duration = 3.0
+	evp.DetectionLevel = 0.5
+
+	radarNearEVP := testRadarEventAt("radar-1", base.Add(1500*time.Millisecond), 1, 1)
+	radarNearEVP.Strength = 0.5
+	slsNearEVP := TestSLSDetection()
+	slsNearEVP.ID = "sls-1"
+	slsNearEVP.Timestamp = base.Add(2500 * time.Millisecond) // overlaps the EVP segment
+	slsNearEVP.Confidence = 0.5
+
+	// An EMF spike next to an SLS detection a minute later
+	radarSpike := testRadarEventAt("radar-2", base.Add(time.Minute), 2, 2)
+	radarSpike.EMFReading = 6.0
+	slsLater := TestSLSDetection()
+	slsLater.ID = "sls-2"
+	slsLater.Timestamp = base.Add(time.Minute + time.Second)
+	slsLater.Confidence = 0.8
+
+	// A radar blip with nothing around it
+	radarAlone := testRadarEventAt("radar-3", base.Add(10*time.Minute), 3, 3)
+
+	mockSessionRepo.On("GetByID", mock.Anything, "test-session-123").Return(session, nil)
+	mockEVPRepo.On("GetBySessionID", mock.Anything, "test-session-123").Return([]*domain.EVPRecording{evp}, nil)
+	mockRadarRepo.On("GetBySessionID", mock.Anything, "test-session-123").
+		Return([]*domain.RadarEvent{radarAlone, radarSpike, radarNearEVP}, nil)
+	mockSLSRepo.On("GetBySessionID", mock.Anything, "test-session-123").
+		Return([]*domain.SLSDetection{slsLater, slsNearEVP}, nil)
+	mockFusionRepo.On("ReplaceBySessionID", mock.Anything, "test-session-123", mock.AnythingOfType("[]*domain.FusionEvent")).
+		Return(nil).
+		Once()
+
+	service := NewFusionService(mockSessionRepo, mockEVPRepo, mockRadarRepo, mockSLSRepo, mockFusionRepo)
+
+	// Act
+	events, err := service.Correlate(context.Background(), "test-session-123", FusionConfig{})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	assert.Equal(t, "evp_radar_sls", events[0].Rule)
+	require.Len(t, events[0].Contributors, 3)
+	assert.Equal(t, "evp-1", events[0].Contributors[0].EventID)
+	assert.Equal(t, "radar-1", events[0].Contributors[1].EventID)
+	assert.Equal(t, "sls-1", events[0].Contributors[2].EventID)
+	assert.InDelta(t, 1-0.5*0.5*0.5, events[0].Confidence, 1e-9)
+	assert.True(t, events[0].StartTime.Equal(base))
+
+	// Spike threshold is 3x the 1.0 baseline, so a 6.0 reading scores 0.5
+	assert.Equal(t, "emf_sls", events[1].Rule)
+	assert.Equal(t, []string{FusionModalityEMF, FusionModalitySLS},
+		[]string{events[1].Contributors[0].EventType, events[1].Contributors[1].EventType})
+	assert.InDelta(t, 1-0.5*0.2, events[1].Confidence, 1e-9)
+
+	mockFusionRepo.AssertExpectations(t)
+}
+
+func TestFusionService_Correlate_CustomRule_AppliesWindowAndMinConfidence(t *testing.T) {
+	// Arrange
+	mockSessionRepo := &MockSessionRepository{}
+	mockEVPRepo := &MockEVPRepository{}
+	mockRadarRepo := &MockRadarRepository{}
+	mockSLSRepo := &MockSLSRepository{}
+	mockFusionRepo := &MockFusionEventRepository{}
+
+	base := time.Now().Add(-time.Hour)
+	weak := testRadarEventAt("weak", base, 1, 1)
+	weak.Strength = 0.1
+	weak.Duration = 0
+	strong := testRadarEventAt("strong", base.Add(10*time.Second), 1, 1)
+	strong.Duration = 0
+
+	weakSLS := TestSLSDetection()
+	weakSLS.ID = "weak-sls"
+	weakSLS.Timestamp = base.Add(time.Second)
+	weakSLS.Duration = 0
+	weakSLS.Confidence = 0.1
+	farSLS := TestSLSDetection()
+	farSLS.ID = "far-sls"
+	farSLS.Timestamp = base.Add(15 * time.Second)
+	farSLS.Duration = 0
+
+	mockSessionRepo.On("GetByID", mock.Anything, "test-session-123").Return(TestSession(), nil)
+	mockEVPRepo.On("GetBySessionID", mock.Anything, "test-session-123").Return([]*domain.EVPRecording{}, nil)
+	mockRadarRepo.On("GetBySessionID", mock.Anything, "test-session-123").Return([]*domain.RadarEvent{weak, strong}, nil)
+	mockSLSRepo.On("GetBySessionID", mock.Anything, "test-session-123").Return([]*domain.SLSDetection{weakSLS, farSLS}, nil)
+	mockFusionRepo.On("ReplaceBySessionID", mock.Anything, "test-session-123", mock.Anything).Return(nil)
+
+	service := NewFusionService(mockSessionRepo, mockEVPRepo, mockRadarRepo, mockSLSRepo, mockFusionRepo)
+
+	// Act
+	events, err := service.Correlate(context.Background(), "test-session-123", FusionConfig{
+		Rules: []FusionRule{{
+			Name:          "presence",
+			Modalities:    []string{FusionModalityRadar, FusionModalitySLS},
+			WindowSeconds: 2,
+			MinConfidence: 0.5,
+		}},
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Empty(t, events) // weak pair scores 0.19, strong radar has no SLS within 2s
+}
+
+func TestFusionService_Correlate_InvalidRule_ReturnsError(t *testing.T) {
+	// Arrange
+	mockSessionRepo := &MockSessionRepository{}
+	mockSessionRepo.On("GetByID", mock.Anything, "test-session-123").Return(TestSession(), nil)
+
+	service := NewFusionService(mockSessionRepo, &MockEVPRepository{}, &MockRadarRepository{}, &MockSLSRepository{}, &MockFusionEventRepository{})
+
+	// Act
+	events, err := service.Correlate(context.Background(), "test-session-123", FusionConfig{
+		Rules: []FusionRule{{Name: "solo", Modalities: []string{FusionModalityRadar}}},
+	})
+
+	// Assert
+	require.Error(t, err)
+	assert.Nil(t, events)
+	assert.Contains(t, err.Error(), "at least two modalities")
+}
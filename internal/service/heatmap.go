@@ -0,0 +1,380 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+)
+
+const (
+	defaultHeatmapColumns    = 32
+	defaultHeatmapRows       = 32
+	maxHeatmapCells          = 256
+	defaultHeatmapCellPixels = 16
+	maxHeatmapCellPixels     = 64
+
+	// maxHeatmapBandwidthExtent caps the bandwidth at this multiple of the
+	// grid's smaller extent; wider kernels only flatten the grid further
+	maxHeatmapBandwidthExtent = 1
+)
+
+// HeatmapMetric selects the value each event contributes to the grid
+type HeatmapMetric string
+
+const (
+	HeatmapMetricCount    HeatmapMetric = "count"
+	HeatmapMetricStrength HeatmapMetric = "strength"
+	HeatmapMetricEMF      HeatmapMetric = "emf"
+)
+
+// HeatmapService bins radar activity into spatial heatmaps
+type HeatmapService struct {
+	sessionRepo domain.SessionRepository
+	radarRepo   domain.RadarRepository
+}
+
+// HeatmapOptions configures the grid, smoothing and event filters
+type HeatmapOptions struct {
+	Metric      HeatmapMetric       `json:"metric"`
+	Columns     int                 `json:"columns"`
+	Rows        int                 `json:"rows"`
+	Bounds      *HeatmapBounds      `json:"bounds,omitempty"`
+	Bandwidth   float64             `json:"bandwidth"`
+	From        *time.Time          `json:"from,omitempty"`
+	To          *time.Time          `json:"to,omitempty"`
+	SourceTypes []domain.SourceType `json:"source_types,omitempty"`
+}
+
+// HeatmapBounds is the area covered by the grid in radar coordinates
+type HeatmapBounds struct {
+	MinX float64 `json:"min_x"`
+	MaxX float64 `json:"max_x"`
+	MinY float64 `json:"min_y"`
+	MaxY float64 `json:"max_y"`
+}
+
+// Heatmap is a smoothed activity grid. Values are indexed [row][column],
+// with row 0 at MinY and column 0 at MinX.
+type Heatmap struct {
+	SessionID   string              `json:"session_id"`
+	Metric      HeatmapMetric       `json:"metric"`
+	Bounds      HeatmapBounds       `json:"bounds"`
+	Columns     int                 `json:"columns"`
+	Rows        int                 `json:"rows"`
+	CellWidth   float64             `json:"cell_width"`
+	CellHeight  float64             `json:"cell_height"`
+	Bandwidth   float64             `json:"bandwidth"`
+	From        *time.Time          `json:"from,omitempty"`
+	To          *time.Time          `json:"to,omitempty"`
+	SourceTypes []domain.SourceType `json:"source_types,omitempty"`
+	EventCount  int                 `json:"event_count"`
+	MaxValue    float64             `json:"max_value"`
+	Values      [][]float64         `json:"values"`
+	GeneratedAt time.Time           `json:"generated_at"`
+}
+
+// NewHeatmapService creates a new heatmap service
+func NewHeatmapService(sessionRepo domain.SessionRepository, radarRepo domain.RadarRepository) *HeatmapService {
+	return &HeatmapService{
+		sessionRepo: sessionRepo,
+		radarRepo:   radarRepo,
+	}
+}
+
+// GenerateHeatmap bins a session's radar events into a smoothed grid
+func (s *HeatmapService) GenerateHeatmap(ctx context.Context, sessionID string, opts HeatmapOptions) (*Heatmap, error) {
+	if _, err := s.sessionRepo.GetByID(ctx, sessionID); err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+
+	opts, err := normalizeHeatmapOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	radarEvents, err := s.radarRepo.GetBySessionID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get radar events: %w", err)
+	}
+
+	events := filterHeatmapEvents(radarEvents, opts)
+
+	bounds := heatmapBoundsFor(events)
+	if opts.Bounds != nil {
+		bounds = *opts.Bounds
+	}
+
+	width, height := bounds.MaxX-bounds.MinX, bounds.MaxY-bounds.MinY
+	bandwidth := math.Min(opts.Bandwidth, maxHeatmapBandwidthExtent*math.Min(width, height))
+
+	heatmap := &Heatmap{
+		SessionID:   sessionID,
+		Metric:      opts.Metric,
+		Bounds:      bounds,
+		Columns:     opts.Columns,
+		Rows:        opts.Rows,
+		CellWidth:   width / float64(opts.Columns),
+		CellHeight:  height / float64(opts.Rows),
+		Bandwidth:   bandwidth,
+		From:        opts.From,
+		To:          opts.To,
+		SourceTypes: opts.SourceTypes,
+		EventCount:  len(events),
+		Values:      make([][]float64, opts.Rows),
+		GeneratedAt: time.Now(),
+	}
+	for row := range heatmap.Values {
+		heatmap.Values[row] = make([]float64, opts.Columns)
+	}
+
+	// Bin events into grid cells
+	for _, event := range events {
+		if event.Position.X < bounds.MinX || event.Position.X > bounds.MaxX ||
+			event.Position.Y < bounds.MinY || event.Position.Y > bounds.MaxY {
+			continue
+		}
+		// Events on the max edge belong to the last cell
+		col := min(int((event.Position.X-bounds.MinX)/heatmap.CellWidth), opts.Columns-1)
+		row := min(int((event.Position.Y-bounds.MinY)/heatmap.CellHeight), opts.Rows-1)
+
+		heatmap.Values[row][col] += heatmapWeight(event, opts.Metric)
+	}
+
+	// Kernel density smoothing with a separable Gaussian
+	if bandwidth > 0 {
+		heatmap.Values = smoothGrid(heatmap.Values,
+			gaussianKernel(bandwidth/heatmap.CellWidth),
+			gaussianKernel(bandwidth/heatmap.CellHeight))
+	}
+
+	for _, row := range heatmap.Values {
+		for _, value := range row {
+			heatmap.MaxValue = math.Max(heatmap.MaxValue, value)
+		}
+	}
+
+	return heatmap, nil
+}
+
+// RenderHeatmapPNG renders a heatmap as a PNG image, drawing the MaxY row at
+// the top so the image matches the radar's orientation
+func RenderHeatmapPNG(heatmap *Heatmap, cellPixels int) ([]byte, error) {
+	if cellPixels <= 0 {
+		cellPixels = defaultHeatmapCellPixels
+	}
+	cellPixels = min(cellPixels, maxHeatmapCellPixels)
+
+	img := image.NewRGBA(image.Rect(0, 0, heatmap.Columns*cellPixels, heatmap.Rows*cellPixels))
+
+	for row := 0; row < heatmap.Rows; row++ {
+		for col := 0; col < heatmap.Columns; col++ {
+			intensity := 0.0
+			if heatmap.MaxValue > 0 {
+				intensity = heatmap.Values[row][col] / heatmap.MaxValue
+			}
+			c := heatColor(intensity)
+
+			top := (heatmap.Rows - 1 - row) * cellPixels
+			left := col * cellPixels
+			for y := top; y < top+cellPixels; y++ {
+				for x := left; x < left+cellPixels; x++ {
+					img.SetRGBA(x, y, c)
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("PNG encoding failed: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func normalizeHeatmapOptions(opts HeatmapOptions) (HeatmapOptions, error) {
+	switch opts.Metric {
+	case "":
+		opts.Metric = HeatmapMetricStrength
+	case HeatmapMetricCount, HeatmapMetricStrength, HeatmapMetricEMF:
+	default:
+		return opts, fmt.Errorf("unsupported heatmap metric: %s", opts.Metric)
+	}
+
+	if opts.Columns <= 0 {
+		opts.Columns = defaultHeatmapColumns
+	}
+	if opts.Rows <= 0 {
+		opts.Rows = defaultHeatmapRows
+	}
+	if opts.Columns > maxHeatmapCells || opts.Rows > maxHeatmapCells {
+		return opts, fmt.Errorf("heatmap grid exceeds %d cells per side", maxHeatmapCells)
+	}
+
+	if opts.Bounds != nil {
+		b := opts.Bounds
+		if !isFinite(b.MinX, b.MaxX, b.MinY, b.MaxY, b.MaxX-b.MinX, b.MaxY-b.MinY) ||
+			b.MaxX <= b.MinX || b.MaxY <= b.MinY {
+			return opts, fmt.Errorf("invalid heatmap bounds")
+		}
+	}
+
+	if !isFinite(opts.Bandwidth) || opts.Bandwidth < 0 {
+		return opts, fmt.Errorf("invalid heatmap bandwidth")
+	}
+
+	return opts, nil
+}
+
+func filterHeatmapEvents(radarEvents []*domain.RadarEvent, opts HeatmapOptions) []*domain.RadarEvent {
+	sourceTypes := make(map[domain.SourceType]bool)
+	for _, sourceType := range opts.SourceTypes {
+		sourceTypes[sourceType] = true
+	}
+
+	var events []*domain.RadarEvent
+	for _, event := range radarEvents {
+		if opts.From != nil && event.Timestamp.Before(*opts.From) {
+			continue
+		}
+		if opts.To != nil && event.Timestamp.After(*opts.To) {
+			continue
+		}
+		if len(sourceTypes) > 0 && !sourceTypes[event.SourceType] {
+			continue
+		}
+		// A position that is not a number cannot be placed on the grid
+		if !isFinite(event.Position.X, event.Position.Y) {
+			continue
+		}
+		events = append(events, event)
+	}
+
+	return events
+}
+
+// heatmapBoundsFor covers every event with a one unit margin
+func heatmapBoundsFor(events []*domain.RadarEvent) HeatmapBounds {
+	if len(events) == 0 {
+		return HeatmapBounds{MinX: -1, MaxX: 1, MinY: -1, MaxY: 1}
+	}
+
+	bounds := HeatmapBounds{
+		MinX: math.Inf(1), MaxX: math.Inf(-1),
+		MinY: math.Inf(1), MaxY: math.Inf(-1),
+	}
+	for _, event := range events {
+		bounds.MinX = math.Min(bounds.MinX, event.Position.X)
+		bounds.MaxX = math.Max(bounds.MaxX, event.Position.X)
+		bounds.MinY = math.Min(bounds.MinY, event.Position.Y)
+		bounds.MaxY = math.Max(bounds.MaxY, event.Position.Y)
+	}
+
+	bounds.MinX--
+	bounds.MaxX++
+	bounds.MinY--
+	bounds.MaxY++
+
+	return bounds
+}
+
+// isFinite reports whether every value is neither infinite nor NaN
+func isFinite(values ...float64) bool {
+	for _, value := range values {
+		if math.IsInf(value, 0) || math.IsNaN(value) {
+			return false
+		}
+	}
+	return true
+}
+
+func heatmapWeight(event *domain.RadarEvent, metric HeatmapMetric) float64 {
+	switch metric {
+	case HeatmapMetricCount:
+		return 1
+	case HeatmapMetricEMF:
+		return event.EMFReading
+	default:
+		return event.Strength
+	}
+}
+
+// gaussianKernel returns a normalized 1D Gaussian truncated at three sigma,
+// with sigma expressed in cells
+func gaussianKernel(sigma float64) []float64 {
+	if sigma <= 0 {
+		return []float64{1}
+	}
+
+	radius := int(math.Ceil(3 * sigma))
+	kernel := make([]float64, 2*radius+1)
+
+	var sum float64
+	for i := -radius; i <= radius; i++ {
+		value := math.Exp(-float64(i*i) / (2 * sigma * sigma))
+		kernel[i+radius] = value
+		sum += value
+	}
+	for i := range kernel {
+		kernel[i] /= sum
+	}
+
+	return kernel
+}
+
+// smoothGrid convolves the grid with a separable kernel. Mass falling off the
+// grid edges is discarded.
+func smoothGrid(grid [][]float64, kernelX, kernelY []float64) [][]float64 {
+	rows := len(grid)
+	if rows == 0 {
+		return grid
+	}
+	cols := len(grid[0])
+
+	convolve := func(get func(i int) float64, n, i int, kernel []float64) float64 {
+		radius := len(kernel) / 2
+		var value float64
+		for k := -radius; k <= radius; k++ {
+			if j := i + k; j >= 0 && j < n {
+				value += get(j) * kernel[k+radius]
+			}
+		}
+		return value
+	}
+
+	horizontal := make([][]float64, rows)
+	for row := 0; row < rows; row++ {
+		horizontal[row] = make([]float64, cols)
+		for col := 0; col < cols; col++ {
+			horizontal[row][col] = convolve(func(j int) float64 { return grid[row][j] }, cols, col, kernelX)
+		}
+	}
+
+	smoothed := make([][]float64, rows)
+	for row := 0; row < rows; row++ {
+		smoothed[row] = make([]float64, cols)
+		for col := 0; col < cols; col++ {
+			smoothed[row][col] = convolve(func(j int) float64 { return horizontal[j][col] }, rows, row, kernelY)
+		}
+	}
+
+	return smoothed
+}
+
+// heatColor maps an intensity in [0, 1] onto a black-red-yellow-white ramp
+func heatColor(intensity float64) color.RGBA {
+	intensity = math.Max(0, math.Min(1, intensity))
+
+	channel := func(start float64) uint8 {
+		return uint8(255 * math.Max(0, math.Min(1, (intensity-start)*3)))
+	}
+
+	return color.RGBA{R: channel(0), G: channel(1.0 / 3), B: channel(2.0 / 3), A: 255}
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"image/png"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeatmapService_GenerateHeatmap_BinsAndFiltersEvents(t *testing.T) {
+	// Arrange
+	mockSessionRepo := &MockSessionRepository{}
+	mockRadarRepo := &MockRadarRepository{}
+
+	base := time.Now().Add(-time.Hour)
+	audio := testRadarEventAt("audio", base, 0.5, 0.5)
+	audio.SourceType = domain.SourceTypeAudio
+	late := testRadarEventAt("late", base.Add(time.Hour), 0.5, 0.5)
+	events := []*domain.RadarEvent{
+		testRadarEventAt("a", base, 0.5, 0.5),
+		testRadarEventAt("b", base, 0.6, 0.4),
+		testRadarEventAt("c", base, 3.5, 3.5),
+		testRadarEventAt("edge", base, 4, 4),
+		testRadarEventAt("outside", base, 9, 9),
+		audio,
+		late,
+	}
+
+	mockSessionRepo.On("GetByID", mock.Anything, "test-session-123").Return(TestSession(), nil)
+	mockRadarRepo.On("GetBySessionID", mock.Anything, "test-session-123").Return(events, nil)
+
+	service := NewHeatmapService(mockSessionRepo, mockRadarRepo)
+	to := base.Add(time.Minute)
+
+	// Act
+	heatmap, err := service.GenerateHeatmap(context.Background(), "test-session-123", HeatmapOptions{
+		Metric:      HeatmapMetricCount,
+		Columns:     4,
+		Rows:        4,
+		Bounds:      &HeatmapBounds{MinX: 0, MaxX: 4, MinY: 0, MaxY: 4},
+		To:          &to,
+		SourceTypes: []domain.SourceType{domain.SourceTypeEMF},
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 5, heatmap.EventCount) // audio and late events are filtered out
+	assert.Equal(t, 1.0, heatmap.CellWidth)
+	assert.Equal(t, 2.0, heatmap.Values[0][0])
+	assert.Equal(t, 2.0, heatmap.Values[3][3]) // includes the event on the max edge
+	assert.Equal(t, 2.0, heatmap.MaxValue)
+}
+
+func TestHeatmapService_GenerateHeatmap_SmoothingPreservesInteriorMass(t *testing.T) {
+	// Arrange
+	mockSessionRepo := &MockSessionRepository{}
+	mockRadarRepo := &MockRadarRepository{}
+
+	events := []*domain.RadarEvent{testRadarEventAt("a", time.Now(), 10.5, 10.5)}
+	events[0].EMFReading = 4.0
+
+	mockSessionRepo.On("GetByID", mock.Anything, "test-session-123").Return(TestSession(), nil)
+	mockRadarRepo.On("GetBySessionID", mock.Anything, "test-session-123").Return(events, nil)
+
+	service := NewHeatmapService(mockSessionRepo, mockRadarRepo)
+
+	// Act
+	heatmap, err := service.GenerateHeatmap(context.Background(), "test-session-123", HeatmapOptions{
+		Metric:    HeatmapMetricEMF,
+		Columns:   21,
+		Rows:      21,
+		Bounds:    &HeatmapBounds{MinX: 0, MaxX: 21, MinY: 0, MaxY: 21},
+		Bandwidth: 1.5,
+	})
+
+	// Assert
+	require.NoError(t, err)
+
+	var total float64
+	for _, row := range heatmap.Values {
+		for _, value := range row {
+			total += value
+		}
+	}
+	assert.InDelta(t, 4.0, total, 1e-9)
+	assert.Equal(t, heatmap.MaxValue, heatmap.Values[10][10])
+	assert.Less(t, heatmap.Values[10][10], 4.0)
+	assert.InDelta(t, heatmap.Values[10][9], heatmap.Values[10][11], 1e-12)
+	assert.InDelta(t, heatmap.Values[9][10], heatmap.Values[10][9], 1e-12)
+}
+
+func TestHeatmapService_GenerateHeatmap_InvalidMetric_ReturnsError(t *testing.T) {
+	// Arrange
+	mockSessionRepo := &MockSessionRepository{}
+	mockSessionRepo.On("GetByID", mock.Anything, "test-session-123").Return(TestSession(), nil)
+
+	service := NewHeatmapService(mockSessionRepo, &MockRadarRepository{})
+
+	// Act
+	heatmap, err := service.GenerateHeatmap(context.Background(), "test-session-123", HeatmapOptions{Metric: "temperature"})
+
+	// Assert
+	require.Error(t, err)
+	assert.Nil(t, heatmap)
+	assert.Contains(t, err.Error(), "unsupported heatmap metric")
+}
+
+func TestHeatmapService_GenerateHeatmap_NonFiniteOptions_ReturnsError(t *testing.T) {
+	// Arrange
+	mockSessionRepo := &MockSessionRepository{}
+	mockSessionRepo.On("GetByID", mock.Anything, "test-session-123").Return(TestSession(), nil)
+
+	service := NewHeatmapService(mockSessionRepo, &MockRadarRepository{})
+	invalid := []HeatmapOptions{
+		{Bounds: &HeatmapBounds{MinX: 0, MinY: 0, MaxX: math.Inf(1), MaxY: math.Inf(1)}},
+		{Bounds: &HeatmapBounds{MinX: math.NaN(), MinY: 0, MaxX: 4, MaxY: 4}},
+		{Bounds: &HeatmapBounds{MinX: -math.MaxFloat64, MinY: 0, MaxX: math.MaxFloat64, MaxY: 4}},
+		{Bandwidth: math.NaN()},
+		{Bandwidth: math.Inf(1)},
+	}
+
+	// Act
+	var errs []error
+	for _, opts := range invalid {
+		_, err := service.GenerateHeatmap(context.Background(), "test-session-123", opts)
+		errs = append(errs, err)
+	}
+
+	// Assert
+	for i, err := range errs {
+		require.Error(t, err, "options %d", i)
+		assert.Contains(t, err.Error(), "invalid heatmap")
+	}
+}
+
+func TestHeatmapService_GenerateHeatmap_HugeBandwidth_CappedAtGridExtent(t *testing.T) {
+	// Arrange
+	mockSessionRepo := &MockSessionRepository{}
+	mockRadarRepo := &MockRadarRepository{}
+
+	events := []*domain.RadarEvent{
+		testRadarEventAt("a", time.Now(), 1.5, 1.5),
+		testRadarEventAt("nan", time.Now(), math.NaN(), 1.5),
+	}
+
+	mockSessionRepo.On("GetByID", mock.Anything, "test-session-123").Return(TestSession(), nil)
+	mockRadarRepo.On("GetBySessionID", mock.Anything, "test-session-123").Return(events, nil)
+
+	service := NewHeatmapService(mockSessionRepo, mockRadarRepo)
+
+	// Act
+	heatmap, err := service.GenerateHeatmap(context.Background(), "test-session-123", HeatmapOptions{
+		Metric:    HeatmapMetricCount,
+		Columns:   8,
+		Rows:      4,
+		Bounds:    &HeatmapBounds{MinX: 0, MaxX: 8, MinY: 0, MaxY: 4},
+		Bandwidth: 1e9,
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, heatmap.EventCount) // the event without a position is left out
+	assert.Equal(t, 4.0, heatmap.Bandwidth)
+	assert.Greater(t, heatmap.MaxValue, 0.0)
+	assert.Less(t, heatmap.MaxValue, 1.0)
+}
+
+func TestRenderHeatmapPNG_ValidHeatmap_EncodesImage(t *testing.T) {
+	// Arrange
+	heatmap := &Heatmap{
+		Columns:  2,
+		Rows:     2,
+		MaxValue: 1,
+		Values:   [][]float64{{1, 0}, {0, 0}},
+	}
+
+	// Act
+	data, err := RenderHeatmapPNG(heatmap, 4)
+
+	// Assert
+	require.NoError(t, err)
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, 8, img.Bounds().Dx())
+	assert.Equal(t, 8, img.Bounds().Dy())
+
+	// Row 0 sits at the bottom of the image
+	r, g, b, _ := img.At(0, 7).RGBA()
+	assert.Equal(t, [3]uint32{0xffff, 0xffff, 0xffff}, [3]uint32{r, g, b})
+	r, g, b, _ = img.At(0, 0).RGBA()
+	assert.Equal(t, [3]uint32{0, 0, 0}, [3]uint32{r, g, b})
+}
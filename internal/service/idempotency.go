@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+)
+
+// defaultIdempotencyTTL is how long a stored response answers retries of
+// its request
+const defaultIdempotencyTTL = 24 * time.Hour
+
+// idempotencyLockTimeout is how long a request may hold its key before a
+// retry treats it as abandoned, as when the server stopped mid-request
+const idempotencyLockTimeout = 2 * time.Minute
+
+// maxIdempotencyKeyLength bounds the keys clients may send
+const maxIdempotencyKeyLength = 255
+
+// uuidPattern matches a UUID in its canonical textual form
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// IdempotencyService stores the responses to requests sent with an
+// Idempotency-Key so that retries get the original response instead of
+// repeating the request. Keys are scoped to the caller's identity.
+type IdempotencyService struct {
+	repo domain.IdempotencyRepository
+	ttl  time.Duration
+	now  func() time.Time
+}
+
+// NewIdempotencyService creates a new idempotency service. Stored responses
+// are kept for ttl, or a day when ttl is not positive.
+func NewIdempotencyService(repo domain.IdempotencyRepository, ttl time.Duration) *IdempotencyService {
+	if ttl <= 0 {
+		ttl = defaultIdempotencyTTL
+	}
+	return &IdempotencyService{
+		repo: repo,
+		ttl:  ttl,
+		now:  time.Now,
+	}
+}
+
+// IdempotentRequest is a request sent with an Idempotency-Key
+type IdempotentRequest struct {
+	Key    string
+	Method string
+	Path   string
+	Body   []byte
+}
+
+// Begin claims the key of a request for the caller. It returns the stored
+// response when the request was already answered, and nil when the caller
+// should handle the request and then call Complete or Release. Reusing a
+// key for a different request, or while its first use is still being
+// handled, is an error.
+func (s *IdempotencyService) Begin(ctx context.Context, req IdempotentRequest) (*domain.IdempotencyRecord, error) {
+	if err := validateIdempotencyKey(req.Key); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	record := &domain.IdempotencyRecord{
+		Principal:   idempotencyPrincipal(ctx),
+		Key:         req.Key,
+		Method:      req.Method,
+		Path:        req.Path,
+		RequestHash: hashIdempotentRequest(req),
+		CreatedAt:   now,
+	}
+
+	// A second attempt follows releasing an expired or abandoned record
+	for attempt := 0; attempt < 2; attempt++ {
+		claimed, err := s.repo.Claim(ctx, record)
+		if err != nil {
+			return nil, fmt.Errorf("failed to claim idempotency key: %w", err)
+		}
+		if claimed {
+			return nil, nil
+		}
+
+		existing, err := s.repo.Get(ctx, record.Principal, record.Key)
+		if err != nil {
+			// Released between the claim and the read; claim it again
+			continue
+		}
+
+		abandoned := !existing.IsComplete() && now.Sub(existing.CreatedAt) > idempotencyLockTimeout
+		if now.Sub(existing.CreatedAt) > s.ttl || abandoned {
+			if err := s.repo.Release(ctx, record.Principal, record.Key); err != nil {
+				return nil, fmt.Errorf("failed to release idempotency key: %w", err)
+			}
+			continue
+		}
+
+		if existing.Method != record.Method || existing.Path != record.Path || existing.RequestHash != record.RequestHash {
+			return nil, fmt.Errorf("idempotency key reused: key %q was sent with a different request", req.Key)
+		}
+		if !existing.IsComplete() {
+			return nil, fmt.Errorf("idempotency key in progress: the first request with key %q has not finished", req.Key)
+		}
+
+		return existing, nil
+	}
+
+	return nil, fmt.Errorf("failed to claim idempotency key %q", req.Key)
+}
+
+// Complete stores the response to a request claimed with Begin. Server
+// errors are not stored, so that the request can be retried.
+func (s *IdempotencyService) Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	if statusCode >= 500 {
+		return s.Release(ctx, key)
+	}
+
+	completedAt := s.now()
+	return s.repo.Complete(ctx, &domain.IdempotencyRecord{
+		Principal:    idempotencyPrincipal(ctx),
+		Key:          key,
+		StatusCode:   statusCode,
+		ContentType:  contentType,
+		ResponseBody: body,
+		CompletedAt:  &completedAt,
+	})
+}
+
+// Release gives up a key claimed with Begin without storing a response
+func (s *IdempotencyService) Release(ctx context.Context, key string) error {
+	return s.repo.Release(ctx, idempotencyPrincipal(ctx), key)
+}
+
+// PurgeExpired removes stored responses older than the TTL
+func (s *IdempotencyService) PurgeExpired(ctx context.Context) (int64, error) {
+	return s.repo.DeleteBefore(ctx, s.now().Add(-s.ttl))
+}
+
+// validateIdempotencyKey accepts up to maxIdempotencyKeyLength printable
+// ASCII characters
+func validateIdempotencyKey(key string) error {
+	if key == "" || len(key) > maxIdempotencyKeyLength {
+		return fmt.Errorf("invalid idempotency key: must be 1 to %d characters", maxIdempotencyKeyLength)
+	}
+	for _, c := range key {
+		if c < 0x21 || c > 0x7e {
+			return fmt.Errorf("invalid idempotency key: only printable ASCII characters are allowed")
+		}
+	}
+	return nil
+}
+
+// idempotencyPrincipal scopes keys to the caller, so that clients cannot
+// read each other's responses by guessing keys. Without authentication all
+// callers share one scope.
+func idempotencyPrincipal(ctx context.Context) string {
+	identity := domain.IdentityFromContext(ctx)
+	if identity == nil {
+		return ""
+	}
+	return string(identity.Kind) + ":" + identity.ID
+}
+
+// hashIdempotentRequest fingerprints a request so that a key cannot be
+// replayed for a different one
+func hashIdempotentRequest(req IdempotentRequest) string {
+	hash := sha256.New()
+	hash.Write([]byte(req.Method + " " + req.Path + "\n"))
+	hash.Write(req.Body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// resolveClientID returns the ID for a new record: the client-supplied ID
+// in canonical lower-case form, or a generated one when the client sent
+// none. Clients working offline pick their own IDs so that records keep
+// them once synced.
+func resolveClientID(id string) (string, error) {
+	if id == "" {
+		return generateID(), nil
+	}
+	if !uuidPattern.MatchString(id) {
+		return "", fmt.Errorf("invalid id: %q is not a UUID", id)
+	}
+	return strings.ToLower(id), nil
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/myideascope/otherside/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupIdempotencyService(t *testing.T) *IdempotencyService {
+	return NewIdempotencyService(repository.NewSQLiteIdempotencyRepository(setupLifecycleDB(t)), time.Hour)
+}
+
+func TestIdempotencyService_Begin_CompletedKey_ReturnsStoredResponse(t *testing.T) {
+	// Arrange
+	idempotency := setupIdempotencyService(t)
+	ctx := as("owner")
+	req := IdempotentRequest{Key: "sync-1", Method: http.MethodPost, Path: "/api/v1/sessions", Body: []byte(`{"title":"Cellar"}`)}
+	first, err := idempotency.Begin(ctx, req)
+	require.NoError(t, err)
+	require.Nil(t, first)
+	require.NoError(t, idempotency.Complete(ctx, "sync-1", http.StatusCreated, "application/json", []byte(`{"id":"1"}`)))
+
+	// Act
+	replay, err := idempotency.Begin(ctx, req)
+	otherCaller, otherErr := idempotency.Begin(as("teammate"), req)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, replay)
+	assert.Equal(t, http.StatusCreated, replay.StatusCode)
+	assert.Equal(t, "application/json", replay.ContentType)
+	assert.Equal(t, []byte(`{"id":"1"}`), replay.ResponseBody)
+	assert.NoError(t, otherErr)
+	assert.Nil(t, otherCaller)
+}
+
+func TestIdempotencyService_Begin_ReusedOrPendingKey_ReturnsError(t *testing.T) {
+	// Arrange
+	idempotency := setupIdempotencyService(t)
+	ctx := context.Background()
+	req := IdempotentRequest{Key: "sync-1", Method: http.MethodPost, Path: "/api/v1/sessions", Body: []byte(`{"title":"Cellar"}`)}
+	_, err := idempotency.Begin(ctx, req)
+	require.NoError(t, err)
+
+	// Act
+	_, pendingErr := idempotency.Begin(ctx, req)
+	different := req
+	different.Body = []byte(`{"title":"Attic"}`)
+	_, reusedErr := idempotency.Begin(ctx, different)
+	_, invalidErr := idempotency.Begin(ctx, IdempotentRequest{Key: "has space", Method: http.MethodPost, Path: "/api/v1/sessions"})
+
+	// Assert
+	require.Error(t, pendingErr)
+	assert.Contains(t, pendingErr.Error(), "idempotency key in progress")
+	require.Error(t, reusedErr)
+	assert.Contains(t, reusedErr.Error(), "idempotency key reused")
+	require.Error(t, invalidErr)
+	assert.Contains(t, invalidErr.Error(), "invalid idempotency key")
+}
+
+func TestIdempotencyService_Complete_ServerError_ReleasesKey(t *testing.T) {
+	// Arrange
+	idempotency := setupIdempotencyService(t)
+	ctx := context.Background()
+	req := IdempotentRequest{Key: "sync-1", Method: http.MethodDelete, Path: "/api/v1/sessions/1"}
+	_, err := idempotency.Begin(ctx, req)
+	require.NoError(t, err)
+
+	// Act
+	require.NoError(t, idempotency.Complete(ctx, "sync-1", http.StatusInternalServerError, "text/plain", []byte("boom")))
+	retry, err := idempotency.Begin(ctx, req)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Nil(t, retry)
+}
+
+func TestSessionService_RecordUserInteraction_ClientID_ReplayReturnsExisting(t *testing.T) {
+	// Arrange
+	db := setupLifecycleDB(t)
+	ctx := context.Background()
+	sm := NewSessionStateManager(repository.NewSQLiteSessionRepository(db))
+	sessions := NewSessionService(sm, nil, nil, nil, nil, repository.NewSQLiteInteractionRepository(db), nil, nil, nil)
+	session, err := sessions.CreateSession(ctx, CreateSessionRequest{ID: "6F1C2A4E-0B3D-4E5F-8A9B-0C1D2E3F4A5B", Title: "Cellar"})
+	require.NoError(t, err)
+	other, err := sessions.CreateSession(ctx, CreateSessionRequest{Title: "Attic"})
+	require.NoError(t, err)
+	data := UserInteractionData{ID: "0d9c8b7a-6f5e-4d3c-9b2a-1f0e9d8c7b6a", Type: domain.InteractionTypeText, Content: "Is anyone here?"}
+
+	// Act
+	first, err := sessions.RecordUserInteraction(ctx, session.ID, data)
+	require.NoError(t, err)
+	replay, err := sessions.RecordUserInteraction(ctx, session.ID, data)
+	require.NoError(t, err)
+	sessionReplay, err := sessions.CreateSession(ctx, CreateSessionRequest{ID: session.ID, Title: "Cellar"})
+	require.NoError(t, err)
+	_, conflictErr := sessions.RecordUserInteraction(ctx, other.ID, data)
+	_, invalidErr := sessions.RecordUserInteraction(ctx, session.ID, UserInteractionData{ID: "interaction-1", Type: domain.InteractionTypeText})
+
+	// Assert
+	assert.Equal(t, "6f1c2a4e-0b3d-4e5f-8a9b-0c1d2e3f4a5b", session.ID)
+	assert.Equal(t, session.ID, sessionReplay.ID)
+	assert.Equal(t, data.ID, first.ID)
+	assert.Equal(t, first.ID, replay.ID)
+	interactions, err := repository.NewSQLiteInteractionRepository(db).GetBySessionID(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Len(t, interactions, 1)
+	require.Error(t, conflictErr)
+	assert.Contains(t, conflictErr.Error(), "id conflict")
+	require.Error(t, invalidErr)
+	assert.Contains(t, invalidErr.Error(), "invalid id:")
+}
@@ -49,6 +49,21 @@ func (m *MockSessionRepository) GetByDateRange(ctx context.Context, start, end t
 	return args.Get(0).([]*domain.Session), args.Error(1)
 }
 
+func (m *MockSessionRepository) UpdateStatus(ctx context.Context, session *domain.Session, change *domain.SessionStatusChange) error {
+	args := m.Called(ctx, session, change)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) GetStatusHistory(ctx context.Context, sessionID string) ([]*domain.SessionStatusChange, error) {
+	args := m.Called(ctx, sessionID)
+	return args.Get(0).([]*domain.SessionStatusChange), args.Error(1)
+}
+
+func (m *MockSessionRepository) Touch(ctx context.Context, id string, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
 // MockEVPRepository mocks EVPRepository interface
 type MockEVPRepository struct {
 	mock.Mock
@@ -169,6 +184,176 @@ func (m *MockRadarRepository) GetByStrengthRange(ctx context.Context, minStrengt
 	return args.Get(0).([]*domain.RadarEvent), args.Error(1)
 }
 
+// MockRadarTrackRepository mocks RadarTrackRepository interface
+type MockRadarTrackRepository struct {
+	mock.Mock
+}
+
+func (m *MockRadarTrackRepository) ReplaceBySessionID(ctx context.Context, sessionID string, tracks []*domain.RadarTrack) error {
+	args := m.Called(ctx, sessionID, tracks)
+	return args.Error(0)
+}
+
+func (m *MockRadarTrackRepository) GetByID(ctx context.Context, id string) (*domain.RadarTrack, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*domain.RadarTrack), args.Error(1)
+}
+
+func (m *MockRadarTrackRepository) GetBySessionID(ctx context.Context, sessionID string) ([]*domain.RadarTrack, error) {
+	args := m.Called(ctx, sessionID)
+	return args.Get(0).([]*domain.RadarTrack), args.Error(1)
+}
+
+func (m *MockRadarTrackRepository) DeleteBySessionID(ctx context.Context, sessionID string) error {
+	args := m.Called(ctx, sessionID)
+	return args.Error(0)
+}
+
+// MockFusionEventRepository mocks FusionEventRepository interface
+type MockFusionEventRepository struct {
+	mock.Mock
+}
+
+func (m *MockFusionEventRepository) ReplaceBySessionID(ctx context.Context, sessionID string, events []*domain.FusionEvent) error {
+	args := m.Called(ctx, sessionID, events)
+	return args.Error(0)
+}
+
+func (m *MockFusionEventRepository) GetByID(ctx context.Context, id string) (*domain.FusionEvent, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*domain.FusionEvent), args.Error(1)
+}
+
+func (m *MockFusionEventRepository) GetBySessionID(ctx context.Context, sessionID string) ([]*domain.FusionEvent, error) {
+	args := m.Called(ctx, sessionID)
+	return args.Get(0).([]*domain.FusionEvent), args.Error(1)
+}
+
+func (m *MockFusionEventRepository) DeleteBySessionID(ctx context.Context, sessionID string) error {
+	args := m.Called(ctx, sessionID)
+	return args.Error(0)
+}
+
+// MockFloorPlanRepository mocks FloorPlanRepository interface
+type MockFloorPlanRepository struct {
+	mock.Mock
+}
+
+func (m *MockFloorPlanRepository) Create(ctx context.Context, plan *domain.FloorPlan) error {
+	args := m.Called(ctx, plan)
+	return args.Error(0)
+}
+
+func (m *MockFloorPlanRepository) GetByID(ctx context.Context, id string) (*domain.FloorPlan, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*domain.FloorPlan), args.Error(1)
+}
+
+func (m *MockFloorPlanRepository) GetAll(ctx context.Context) ([]*domain.FloorPlan, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*domain.FloorPlan), args.Error(1)
+}
+
+func (m *MockFloorPlanRepository) Update(ctx context.Context, plan *domain.FloorPlan) error {
+	args := m.Called(ctx, plan)
+	return args.Error(0)
+}
+
+func (m *MockFloorPlanRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MockDevicePlacementRepository mocks DevicePlacementRepository interface
+type MockDevicePlacementRepository struct {
+	mock.Mock
+}
+
+func (m *MockDevicePlacementRepository) Create(ctx context.Context, placement *domain.DevicePlacement) error {
+	args := m.Called(ctx, placement)
+	return args.Error(0)
+}
+
+func (m *MockDevicePlacementRepository) GetByID(ctx context.Context, id string) (*domain.DevicePlacement, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*domain.DevicePlacement), args.Error(1)
+}
+
+func (m *MockDevicePlacementRepository) GetBySessionID(ctx context.Context, sessionID string) ([]*domain.DevicePlacement, error) {
+	args := m.Called(ctx, sessionID)
+	return args.Get(0).([]*domain.DevicePlacement), args.Error(1)
+}
+
+func (m *MockDevicePlacementRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MockEnvironmentalReadingRepository mocks EnvironmentalReadingRepository interface
+type MockEnvironmentalReadingRepository struct {
+	mock.Mock
+}
+
+func (m *MockEnvironmentalReadingRepository) CreateBatch(ctx context.Context, readings []*domain.EnvironmentalReading) error {
+	args := m.Called(ctx, readings)
+	return args.Error(0)
+}
+
+func (m *MockEnvironmentalReadingRepository) GetByID(ctx context.Context, id string) (*domain.EnvironmentalReading, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*domain.EnvironmentalReading), args.Error(1)
+}
+
+func (m *MockEnvironmentalReadingRepository) GetBySessionID(ctx context.Context, sessionID string, metric domain.EnvironmentalMetric, start, end time.Time) ([]*domain.EnvironmentalReading, error) {
+	args := m.Called(ctx, sessionID, metric, start, end)
+	return args.Get(0).([]*domain.EnvironmentalReading), args.Error(1)
+}
+
+func (m *MockEnvironmentalReadingRepository) DeleteBySessionID(ctx context.Context, sessionID string) error {
+	args := m.Called(ctx, sessionID)
+	return args.Error(0)
+}
+
+// MockEnvironmentalAnomalyRepository mocks EnvironmentalAnomalyRepository interface
+type MockEnvironmentalAnomalyRepository struct {
+	mock.Mock
+}
+
+func (m *MockEnvironmentalAnomalyRepository) CreateBatch(ctx context.Context, anomalies []*domain.EnvironmentalAnomaly) error {
+	args := m.Called(ctx, anomalies)
+	return args.Error(0)
+}
+
+func (m *MockEnvironmentalAnomalyRepository) GetBySessionID(ctx context.Context, sessionID string) ([]*domain.EnvironmentalAnomaly, error) {
+	args := m.Called(ctx, sessionID)
+	return args.Get(0).([]*domain.EnvironmentalAnomaly), args.Error(1)
+}
+
+// MockSensorRegistrationRepository mocks SensorRegistrationRepository interface
+type MockSensorRegistrationRepository struct {
+	mock.Mock
+}
+
+func (m *MockSensorRegistrationRepository) Save(ctx context.Context, registration *domain.SensorRegistration) error {
+	args := m.Called(ctx, registration)
+	return args.Error(0)
+}
+
+func (m *MockSensorRegistrationRepository) GetByDeviceID(ctx context.Context, deviceID string) (*domain.SensorRegistration, error) {
+	args := m.Called(ctx, deviceID)
+	return args.Get(0).(*domain.SensorRegistration), args.Error(1)
+}
+
+func (m *MockSensorRegistrationRepository) GetBySessionID(ctx context.Context, sessionID string) ([]*domain.SensorRegistration, error) {
+	args := m.Called(ctx, sessionID)
+	return args.Get(0).([]*domain.SensorRegistration), args.Error(1)
+}
+
+func (m *MockSensorRegistrationRepository) Delete(ctx context.Context, deviceID string) error {
+	args := m.Called(ctx, deviceID)
+	return args.Error(0)
+}
+
 // MockSLSRepository mocks SLSRepository interface
 type MockSLSRepository struct {
 	mock.Mock
@@ -279,6 +464,11 @@ func (m *MockFileRepository) ListFiles(ctx context.Context, directory string) ([
 	return args.Get(0).([]string), args.Error(1)
 }
 
+func (m *MockFileRepository) ListSessionFiles(ctx context.Context, sessionID string) ([]string, error) {
+	args := m.Called(ctx, sessionID)
+	return args.Get(0).([]string), args.Error(1)
+}
+
 // MockAudioProcessor mocks the audio.Processor
 type MockAudioProcessor struct {
 	mock.Mock
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+)
+
+// ParticipantService manages investigators, devices and the investigators
+// taking part in each session
+type ParticipantService struct {
+	sessionRepo      domain.SessionRepository
+	investigatorRepo domain.InvestigatorRepository
+	deviceRepo       domain.DeviceRepository
+	participantRepo  domain.SessionParticipantRepository
+	accessService    *AccessService
+}
+
+// CreateInvestigatorRequest holds the details of a new investigator
+type CreateInvestigatorRequest struct {
+	Name    string `json:"name"`
+	Email   string `json:"email,omitempty"`
+	IsAdmin bool   `json:"is_admin,omitempty"`
+}
+
+// CreateDeviceRequest holds the details of a new device. An empty ID is
+// generated; sensors that report their own ID should be registered with it.
+type CreateDeviceRequest struct {
+	ID           string `json:"id,omitempty"`
+	Name         string `json:"name"`
+	Kind         string `json:"kind,omitempty"`
+	SerialNumber string `json:"serial_number,omitempty"`
+}
+
+// NewParticipantService creates a new participant service
+func NewParticipantService(
+	sessionRepo domain.SessionRepository,
+	investigatorRepo domain.InvestigatorRepository,
+	deviceRepo domain.DeviceRepository,
+	participantRepo domain.SessionParticipantRepository,
+) *ParticipantService {
+	return &ParticipantService{
+		sessionRepo:      sessionRepo,
+		investigatorRepo: investigatorRepo,
+		deviceRepo:       deviceRepo,
+		participantRepo:  participantRepo,
+	}
+}
+
+// SetAccessService restricts creating investigators and devices to admins,
+// and deleting them to admins and the investigator or device itself
+func (s *ParticipantService) SetAccessService(accessService *AccessService) {
+	s.accessService = accessService
+}
+
+// CreateInvestigator adds a new investigator
+func (s *ParticipantService) CreateInvestigator(ctx context.Context, req CreateInvestigatorRequest) (*domain.Investigator, error) {
+	if s.accessService != nil {
+		if err := s.accessService.RequireAdmin(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("invalid investigator: name is required")
+	}
+
+	investigator := &domain.Investigator{
+		ID:        generateID(),
+		Name:      name,
+		Email:     strings.TrimSpace(req.Email),
+		IsAdmin:   req.IsAdmin,
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.investigatorRepo.Create(ctx, investigator); err != nil {
+		return nil, fmt.Errorf("failed to create investigator: %w", err)
+	}
+
+	return investigator, nil
+}
+
+// GetInvestigator returns an investigator
+func (s *ParticipantService) GetInvestigator(ctx context.Context, id string) (*domain.Investigator, error) {
+	investigator, err := s.investigatorRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("investigator not found: %w", err)
+	}
+	return investigator, nil
+}
+
+// ListInvestigators returns all investigators
+func (s *ParticipantService) ListInvestigators(ctx context.Context) ([]*domain.Investigator, error) {
+	return s.investigatorRepo.GetAll(ctx)
+}
+
+// DeleteInvestigator removes an investigator from every session and deletes
+// them. Investigators who own sessions must hand them over first.
+func (s *ParticipantService) DeleteInvestigator(ctx context.Context, id string) error {
+	if _, err := s.GetInvestigator(ctx, id); err != nil {
+		return err
+	}
+	if s.accessService != nil {
+		if err := s.accessService.AuthorizePrincipal(ctx, domain.PrincipalInvestigator, id); err != nil {
+			return err
+		}
+		if err := s.accessService.CheckInvestigatorRemoval(ctx, id); err != nil {
+			return err
+		}
+	}
+	return s.investigatorRepo.Delete(ctx, id)
+}
+
+// CreateDevice adds a new device
+func (s *ParticipantService) CreateDevice(ctx context.Context, req CreateDeviceRequest) (*domain.Device, error) {
+	if s.accessService != nil {
+		if err := s.accessService.RequireAdmin(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("invalid device: name is required")
+	}
+
+	id := strings.TrimSpace(req.ID)
+	if id == "" {
+		id = generateID()
+	} else if _, err := s.deviceRepo.GetByID(ctx, id); err == nil {
+		return nil, fmt.Errorf("invalid device: device %s already exists", id)
+	}
+
+	device := &domain.Device{
+		ID:           id,
+		Name:         name,
+		Kind:         strings.TrimSpace(req.Kind),
+		SerialNumber: strings.TrimSpace(req.SerialNumber),
+		CreatedAt:    time.Now(),
+	}
+
+	if err := s.deviceRepo.Create(ctx, device); err != nil {
+		return nil, fmt.Errorf("failed to create device: %w", err)
+	}
+
+	return device, nil
+}
+
+// GetDevice returns a device
+func (s *ParticipantService) GetDevice(ctx context.Context, id string) (*domain.Device, error) {
+	device, err := s.deviceRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("device not found: %w", err)
+	}
+	return device, nil
+}
+
+// ListDevices returns all devices
+func (s *ParticipantService) ListDevices(ctx context.Context) ([]*domain.Device, error) {
+	return s.deviceRepo.GetAll(ctx)
+}
+
+// DeleteDevice deletes a device
+func (s *ParticipantService) DeleteDevice(ctx context.Context, id string) error {
+	if _, err := s.GetDevice(ctx, id); err != nil {
+		return err
+	}
+	if s.accessService != nil {
+		if err := s.accessService.AuthorizePrincipal(ctx, domain.PrincipalDevice, id); err != nil {
+			return err
+		}
+	}
+	return s.deviceRepo.Delete(ctx, id)
+}
+
+// AddParticipant adds an investigator to a session with a role, or changes
+// the role of an investigator already taking part. Archived sessions are
+// read-only.
+func (s *ParticipantService) AddParticipant(ctx context.Context, sessionID, investigatorID string, role domain.ParticipantRole) (*domain.SessionParticipant, error) {
+	if !role.IsValid() {
+		return nil, fmt.Errorf("invalid participant: unknown role %q", role)
+	}
+
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+	if session.Status == domain.SessionStatusArchived {
+		return nil, fmt.Errorf("session %s is archived and read-only", sessionID)
+	}
+
+	if _, err := s.GetInvestigator(ctx, investigatorID); err != nil {
+		return nil, err
+	}
+
+	participant := &domain.SessionParticipant{
+		SessionID:      sessionID,
+		InvestigatorID: investigatorID,
+		Role:           role,
+		JoinedAt:       time.Now(),
+	}
+
+	if err := s.participantRepo.Save(ctx, participant); err != nil {
+		return nil, fmt.Errorf("failed to save participant: %w", err)
+	}
+
+	// Return the stored row, which keeps the original join time on a role change
+	return s.participantRepo.Get(ctx, sessionID, investigatorID)
+}
+
+// ListParticipants returns the investigators taking part in a session
+func (s *ParticipantService) ListParticipants(ctx context.Context, sessionID string) ([]*domain.SessionParticipant, error) {
+	if _, err := s.sessionRepo.GetByID(ctx, sessionID); err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+	return s.participantRepo.GetBySessionID(ctx, sessionID)
+}
+
+// RemoveParticipant removes an investigator from a session. Events they
+// recorded keep their attribution.
+func (s *ParticipantService) RemoveParticipant(ctx context.Context, sessionID, investigatorID string) error {
+	if _, err := s.participantRepo.Get(ctx, sessionID, investigatorID); err != nil {
+		return fmt.Errorf("participant not found: %w", err)
+	}
+	return s.participantRepo.Delete(ctx, sessionID, investigatorID)
+}
+
+// resolveAttribution returns the investigator and device an event recorded
+// in a session is attributed to. Authenticated callers may only record as
+// themselves: an investigator may name no other investigator, and a device
+// may name no other device and no investigator. A device's events carry its
+// ID. Attributed investigators must take part in the session with a role
+// that records events.
+func resolveAttribution(ctx context.Context, participantRepo domain.SessionParticipantRepository, sessionID, investigatorID, deviceID string) (string, string, error) {
+	if identity := domain.IdentityFromContext(ctx); identity != nil {
+		switch identity.Kind {
+		case domain.PrincipalInvestigator:
+			if investigatorID != "" && investigatorID != identity.ID {
+				return "", "", fmt.Errorf("invalid attribution: investigator %s cannot record events as investigator %s", identity.ID, investigatorID)
+			}
+		case domain.PrincipalDevice:
+			if deviceID != "" && deviceID != identity.ID {
+				return "", "", fmt.Errorf("invalid attribution: device %s cannot record events as device %s", identity.ID, deviceID)
+			}
+			if investigatorID != "" {
+				return "", "", fmt.Errorf("invalid attribution: device %s cannot attribute events to investigator %s", identity.ID, investigatorID)
+			}
+			deviceID = identity.ID
+		}
+	}
+
+	if err := checkAttribution(ctx, participantRepo, sessionID, investigatorID); err != nil {
+		return "", "", err
+	}
+	return investigatorID, deviceID, nil
+}
+
+// checkAttribution verifies that events in a session may be attributed to
+// an investigator: they must take part in it with a role that records
+// events. An empty investigator ID is always allowed.
+func checkAttribution(ctx context.Context, participantRepo domain.SessionParticipantRepository, sessionID, investigatorID string) error {
+	if investigatorID == "" || participantRepo == nil {
+		return nil
+	}
+
+	participant, err := participantRepo.Get(ctx, sessionID, investigatorID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("invalid attribution: investigator %s is not a participant of session %s", investigatorID, sessionID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check attribution: %w", err)
+	}
+
+	if !participant.Role.CanRecordEvents() {
+		return fmt.Errorf("invalid attribution: investigator %s is an %s of session %s", investigatorID, participant.Role, sessionID)
+	}
+
+	return nil
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/myideascope/otherside/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupParticipantServices returns session and participant services over a
+// migrated in-memory database holding one active session
+func setupParticipantServices(t *testing.T) (*SessionService, *ParticipantService, *SessionStateManager, *domain.Session) {
+	db := setupLifecycleDB(t)
+	sm := NewSessionStateManager(repository.NewSQLiteSessionRepository(db))
+	session := &domain.Session{ID: "session-1", Title: "Cellar", StartTime: time.Now()}
+	require.NoError(t, sm.CreateSession(context.Background(), session))
+
+	participantRepo := repository.NewSQLiteSessionParticipantRepository(db)
+	participantService := NewParticipantService(sm,
+		repository.NewSQLiteInvestigatorRepository(db),
+		repository.NewSQLiteDeviceRepository(db),
+		participantRepo,
+	)
+	sessionService := NewSessionService(sm, nil, nil, nil, nil, repository.NewSQLiteInteractionRepository(db), nil, nil, nil)
+	sessionService.SetParticipantRepository(participantRepo)
+
+	return sessionService, participantService, sm, session
+}
+
+func TestSessionService_RecordUserInteraction_AttributedToInvestigator_StoresAttribution(t *testing.T) {
+	// Arrange
+	sessionService, participantService, _, session := setupParticipantServices(t)
+	ctx := context.Background()
+
+	investigator, err := participantService.CreateInvestigator(ctx, CreateInvestigatorRequest{Name: "Ada"})
+	require.NoError(t, err)
+	_, err = participantService.AddParticipant(ctx, session.ID, investigator.ID, domain.ParticipantRoleInvestigator)
+	require.NoError(t, err)
+
+	// Act
+	interaction, err := sessionService.RecordUserInteraction(ctx, session.ID, UserInteractionData{
+		Type:           domain.InteractionTypeNote,
+		Content:        "Cold spot by the stairs",
+		InvestigatorID: investigator.ID,
+		DeviceID:       "tablet-1",
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, investigator.ID, interaction.InvestigatorID)
+	assert.Equal(t, "tablet-1", interaction.DeviceID)
+}
+
+func TestSessionService_RecordUserInteraction_ObserverOrOutsider_ReturnsAttributionError(t *testing.T) {
+	// Arrange
+	sessionService, participantService, _, session := setupParticipantServices(t)
+	ctx := context.Background()
+
+	observer, err := participantService.CreateInvestigator(ctx, CreateInvestigatorRequest{Name: "Grace"})
+	require.NoError(t, err)
+	_, err = participantService.AddParticipant(ctx, session.ID, observer.ID, domain.ParticipantRoleObserver)
+	require.NoError(t, err)
+	outsider, err := participantService.CreateInvestigator(ctx, CreateInvestigatorRequest{Name: "Linus"})
+	require.NoError(t, err)
+
+	for _, investigatorID := range []string{observer.ID, outsider.ID} {
+		// Act
+		_, err := sessionService.RecordUserInteraction(ctx, session.ID, UserInteractionData{
+			Type:           domain.InteractionTypeNote,
+			Content:        "Footsteps",
+			InvestigatorID: investigatorID,
+		})
+
+		// Assert
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid attribution")
+	}
+}
+
+func TestSessionService_RecordUserInteraction_AuthenticatedCaller_AttributedToCaller(t *testing.T) {
+	// Arrange
+	sessionService, participantService, _, session := setupParticipantServices(t)
+	ctx := context.Background()
+
+	ada, err := participantService.CreateInvestigator(ctx, CreateInvestigatorRequest{Name: "Ada"})
+	require.NoError(t, err)
+	grace, err := participantService.CreateInvestigator(ctx, CreateInvestigatorRequest{Name: "Grace"})
+	require.NoError(t, err)
+	for _, id := range []string{ada.ID, grace.ID} {
+		_, err = participantService.AddParticipant(ctx, session.ID, id, domain.ParticipantRoleInvestigator)
+		require.NoError(t, err)
+	}
+	adaCtx := domain.ContextWithIdentity(ctx, &domain.Identity{Kind: domain.PrincipalInvestigator, ID: ada.ID})
+	deviceCtx := domain.ContextWithIdentity(ctx, &domain.Identity{Kind: domain.PrincipalDevice, ID: "radar-1"})
+
+	// Act
+	_, impersonateErr := sessionService.RecordUserInteraction(adaCtx, session.ID, UserInteractionData{
+		Type: domain.InteractionTypeNote, Content: "Footsteps", InvestigatorID: grace.ID,
+	})
+	_, deviceAsInvestigatorErr := sessionService.RecordUserInteraction(deviceCtx, session.ID, UserInteractionData{
+		Type: domain.InteractionTypeNote, Content: "Footsteps", InvestigatorID: ada.ID,
+	})
+	_, otherDeviceErr := sessionService.RecordUserInteraction(deviceCtx, session.ID, UserInteractionData{
+		Type: domain.InteractionTypeNote, Content: "Footsteps", DeviceID: "radar-2",
+	})
+	fromDevice, err := sessionService.RecordUserInteraction(deviceCtx, session.ID, UserInteractionData{
+		Type: domain.InteractionTypeNote, Content: "Footsteps",
+	})
+
+	// Assert
+	for _, err := range []error{impersonateErr, deviceAsInvestigatorErr, otherDeviceErr} {
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid attribution")
+	}
+	require.NoError(t, err)
+	assert.Equal(t, "radar-1", fromDevice.DeviceID)
+	assert.Empty(t, fromDevice.InvestigatorID)
+}
+
+func TestParticipantService_AddParticipant_ChangesRole_KeepsJoinedAt(t *testing.T) {
+	// Arrange
+	_, participantService, _, session := setupParticipantServices(t)
+	ctx := context.Background()
+
+	investigator, err := participantService.CreateInvestigator(ctx, CreateInvestigatorRequest{Name: "Ada"})
+	require.NoError(t, err)
+	first, err := participantService.AddParticipant(ctx, session.ID, investigator.ID, domain.ParticipantRoleObserver)
+	require.NoError(t, err)
+
+	// Act
+	second, err := participantService.AddParticipant(ctx, session.ID, investigator.ID, domain.ParticipantRoleLead)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, domain.ParticipantRoleLead, second.Role)
+	assert.True(t, first.JoinedAt.Equal(second.JoinedAt))
+
+	participants, err := participantService.ListParticipants(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Len(t, participants, 1)
+}
+
+func TestParticipantService_AddParticipant_ArchivedSession_ReturnsReadOnlyError(t *testing.T) {
+	// Arrange
+	_, participantService, sm, session := setupParticipantServices(t)
+	ctx := context.Background()
+
+	investigator, err := participantService.CreateInvestigator(ctx, CreateInvestigatorRequest{Name: "Ada"})
+	require.NoError(t, err)
+	require.NoError(t, sm.CompleteSession(ctx, session.ID))
+	require.NoError(t, sm.ArchiveSession(ctx, session.ID))
+
+	// Act
+	_, err = participantService.AddParticipant(ctx, session.ID, investigator.ID, domain.ParticipantRoleLead)
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "read-only")
+}
+
+func TestParticipantService_AddParticipant_UnknownRole_ReturnsError(t *testing.T) {
+	// Arrange
+	_, participantService, _, session := setupParticipantServices(t)
+
+	// Act
+	_, err := participantService.AddParticipant(context.Background(), session.ID, "inv-1", domain.ParticipantRole("ghost"))
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid participant")
+}
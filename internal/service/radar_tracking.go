@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+)
+
+const (
+	defaultTrackGateDistance  = 1.5
+	defaultTrackMaxGapSeconds = 5.0
+	defaultTrackMinEvents     = 2
+)
+
+// RadarTrackingService associates discrete radar events into tracks
+type RadarTrackingService struct {
+	sessionRepo domain.SessionRepository
+	radarRepo   domain.RadarRepository
+	trackRepo   domain.RadarTrackRepository
+}
+
+// RadarTrackingConfig controls nearest-neighbour track association
+type RadarTrackingConfig struct {
+	// GateDistance is the largest distance between a track's predicted
+	// position and a new event for the event to join the track
+	GateDistance float64 `json:"gate_distance"`
+	// MaxGapSeconds closes a track when no event joined it for this long
+	MaxGapSeconds float64 `json:"max_gap_seconds"`
+	// MinEvents drops tracks built from fewer events
+	MinEvents int `json:"min_events"`
+}
+
+// NewRadarTrackingService creates a new radar tracking service
+func NewRadarTrackingService(
+	sessionRepo domain.SessionRepository,
+	radarRepo domain.RadarRepository,
+	trackRepo domain.RadarTrackRepository,
+) *RadarTrackingService {
+	return &RadarTrackingService{
+		sessionRepo: sessionRepo,
+		radarRepo:   radarRepo,
+		trackRepo:   trackRepo,
+	}
+}
+
+// BuildTracks rebuilds and persists the radar tracks of a session
+func (s *RadarTrackingService) BuildTracks(ctx context.Context, sessionID string, config RadarTrackingConfig) ([]*domain.RadarTrack, error) {
+	if _, err := s.sessionRepo.GetByID(ctx, sessionID); err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+
+	radarEvents, err := s.radarRepo.GetBySessionID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get radar events: %w", err)
+	}
+
+	config = normalizeTrackingConfig(config)
+
+	var tracks []*domain.RadarTrack
+	for _, events := range associateRadarEvents(radarEvents, config) {
+		if len(events) < config.MinEvents {
+			continue
+		}
+		tracks = append(tracks, summarizeRadarTrack(sessionID, events))
+	}
+
+	if err := s.trackRepo.ReplaceBySessionID(ctx, sessionID, tracks); err != nil {
+		return nil, fmt.Errorf("failed to save radar tracks: %w", err)
+	}
+
+	return tracks, nil
+}
+
+// GetTracks returns the stored radar tracks of a session
+func (s *RadarTrackingService) GetTracks(ctx context.Context, sessionID string) ([]*domain.RadarTrack, error) {
+	if _, err := s.sessionRepo.GetByID(ctx, sessionID); err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+
+	return s.trackRepo.GetBySessionID(ctx, sessionID)
+}
+
+// radarTrackState is a track still open for association
+type radarTrackState struct {
+	events    []*domain.RadarEvent
+	velocityX float64
+	velocityY float64
+}
+
+// associateRadarEvents groups events with a greedy nearest-neighbour filter.
+// Each open track predicts its next position with a constant-velocity model
+// and claims the closest event inside its gate.
+func associateRadarEvents(radarEvents []*domain.RadarEvent, config RadarTrackingConfig) [][]*domain.RadarEvent {
+	events := make([]*domain.RadarEvent, len(radarEvents))
+	copy(events, radarEvents)
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+
+	var open []*radarTrackState
+	var closed [][]*domain.RadarEvent
+
+	for _, event := range events {
+		var best *radarTrackState
+		bestDistance := math.Inf(1)
+
+		stillOpen := open[:0]
+		for _, track := range open {
+			last := track.events[len(track.events)-1]
+			gap := event.Timestamp.Sub(last.Timestamp).Seconds()
+			if gap > config.MaxGapSeconds {
+				closed = append(closed, track.events)
+				continue
+			}
+			stillOpen = append(stillOpen, track)
+
+			predictedX := last.Position.X + track.velocityX*gap
+			predictedY := last.Position.Y + track.velocityY*gap
+			distance := math.Hypot(event.Position.X-predictedX, event.Position.Y-predictedY)
+			if distance <= config.GateDistance && distance < bestDistance {
+				best = track
+				bestDistance = distance
+			}
+		}
+		open = stillOpen
+
+		if best == nil {
+			open = append(open, &radarTrackState{events: []*domain.RadarEvent{event}})
+			continue
+		}
+
+		last := best.events[len(best.events)-1]
+		if dt := event.Timestamp.Sub(last.Timestamp).Seconds(); dt > 0 {
+			best.velocityX = (event.Position.X - last.Position.X) / dt
+			best.velocityY = (event.Position.Y - last.Position.Y) / dt
+		}
+		best.events = append(best.events, event)
+	}
+
+	for _, track := range open {
+		closed = append(closed, track.events)
+	}
+
+	sort.SliceStable(closed, func(i, j int) bool {
+		return closed[i][0].Timestamp.Before(closed[j][0].Timestamp)
+	})
+
+	return closed
+}
+
+// summarizeRadarTrack computes the kinematics of an associated event chain
+func summarizeRadarTrack(sessionID string, events []*domain.RadarEvent) *domain.RadarTrack {
+	first := events[0]
+	last := events[len(events)-1]
+
+	track := &domain.RadarTrack{
+		ID:        generateID(),
+		SessionID: sessionID,
+		EventIDs:  make([]string, 0, len(events)),
+		Points:    make([]domain.Coordinates, 0, len(events)),
+		StartTime: first.Timestamp,
+		EndTime:   last.Timestamp.Add(time.Duration(last.Duration * float64(time.Second))),
+		CreatedAt: time.Now(),
+	}
+
+	var totalStrength float64
+	for i, event := range events {
+		track.EventIDs = append(track.EventIDs, event.ID)
+		track.Points = append(track.Points, event.Position)
+		totalStrength += event.Strength
+
+		if i > 0 {
+			prev := events[i-1].Position
+			dx := event.Position.X - prev.X
+			dy := event.Position.Y - prev.Y
+			dz := event.Position.Z - prev.Z
+			track.Distance += math.Sqrt(dx*dx + dy*dy + dz*dz)
+		}
+	}
+
+	track.MeanStrength = totalStrength / float64(len(events))
+	track.DwellTime = track.EndTime.Sub(track.StartTime).Seconds()
+
+	if elapsed := last.Timestamp.Sub(first.Timestamp).Seconds(); elapsed > 0 {
+		track.Velocity = track.Distance / elapsed
+	}
+
+	// Heading uses the same convention as MovementAnalysis.Direction:
+	// radians in [0, 2π) measured from the +X axis
+	heading := math.Atan2(last.Position.Y-first.Position.Y, last.Position.X-first.Position.X)
+	if heading < 0 {
+		heading += 2 * math.Pi
+	}
+	track.Heading = heading
+
+	return track
+}
+
+func normalizeTrackingConfig(config RadarTrackingConfig) RadarTrackingConfig {
+	if config.GateDistance <= 0 {
+		config.GateDistance = defaultTrackGateDistance
+	}
+	if config.MaxGapSeconds <= 0 {
+		config.MaxGapSeconds = defaultTrackMaxGapSeconds
+	}
+	if config.MinEvents <= 0 {
+		config.MinEvents = defaultTrackMinEvents
+	}
+	return config
+}
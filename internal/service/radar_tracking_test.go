@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testRadarEventAt(id string, at time.Time, x, y float64) *domain.RadarEvent {
+	return &domain.RadarEvent{
+		ID:         id,
+		SessionID:  "test-session-123",
+		Timestamp:  at,
+		Position:   domain.Coordinates{X: x, Y: y},
+		Strength:   0.6,
+		SourceType: domain.SourceTypeEMF,
+		Duration:   1.0,
+	}
+}
+
+func TestRadarTrackingService_BuildTracks_SeparatesTwoMovingSources(t *testing.T) {
+	// Arrange
+	mockSessionRepo := &MockSessionRepository{}
+	mockRadarRepo := &MockRadarRepository{}
+	mockTrackRepo := &MockRadarTrackRepository{}
+
+	base := time.Now().Add(-time.Hour)
+	// Repository returns newest first; source A walks along +X, source B along +Y
+	events := []*domain.RadarEvent{
+		testRadarEventAt("b3", base.Add(3*time.Second), -5, 3),
+		testRadarEventAt("a3", base.Add(3*time.Second), 3, 0),
+		testRadarEventAt("b2", base.Add(2*time.Second), -5, 2),
+		testRadarEventAt("a2", base.Add(2*time.Second), 2, 0),
+		testRadarEventAt("b1", base.Add(1*time.Second), -5, 1),
+		testRadarEventAt("a1", base.Add(1*time.Second), 1, 0),
+		testRadarEventAt("lone", base.Add(30*time.Second), 9, 9),
+	}
+
+	mockSessionRepo.On("GetByID", mock.Anything, "test-session-123").Return(TestSession(), nil)
+	mockRadarRepo.On("GetBySessionID", mock.Anything, "test-session-123").Return(events, nil)
+	mockTrackRepo.On("ReplaceBySessionID", mock.Anything, "test-session-123", mock.AnythingOfType("[]*domain.RadarTrack")).
+		Return(nil).
+		Once()
+
+	service := NewRadarTrackingService(mockSessionRepo, mockRadarRepo, mockTrackRepo)
+
+	// Act
+	tracks, err := service.BuildTracks(context.Background(), "test-session-123", RadarTrackingConfig{})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, tracks, 2) // lone event is dropped by MinEvents
+
+	var trackA, trackB *domain.RadarTrack
+	for _, track := range tracks {
+		switch track.EventIDs[0] {
+		case "a1":
+			trackA = track
+		case "b1":
+			trackB = track
+		}
+	}
+	require.NotNil(t, trackA)
+	require.NotNil(t, trackB)
+
+	assert.Equal(t, []string{"a1", "a2", "a3"}, trackA.EventIDs)
+	assert.InDelta(t, 2.0, trackA.Distance, 1e-9)
+	assert.InDelta(t, 1.0, trackA.Velocity, 1e-9)
+	assert.InDelta(t, 0.0, trackA.Heading, 1e-9)
+	assert.InDelta(t, 3.0, trackA.DwellTime, 1e-9) // 2s span plus last event duration
+
+	assert.Equal(t, []string{"b1", "b2", "b3"}, trackB.EventIDs)
+	assert.InDelta(t, math.Pi/2, trackB.Heading, 1e-9)
+
+	mockTrackRepo.AssertExpectations(t)
+}
+
+func TestRadarTrackingService_BuildTracks_GapClosesTrack(t *testing.T) {
+	// Arrange
+	mockSessionRepo := &MockSessionRepository{}
+	mockRadarRepo := &MockRadarRepository{}
+	mockTrackRepo := &MockRadarTrackRepository{}
+
+	base := time.Now().Add(-time.Hour)
+	events := []*domain.RadarEvent{
+		testRadarEventAt("e1", base, 1, 1),
+		testRadarEventAt("e2", base.Add(time.Second), 1.2, 1),
+		testRadarEventAt("e3", base.Add(20*time.Second), 1.4, 1),
+		testRadarEventAt("e4", base.Add(21*time.Second), 1.6, 1),
+	}
+
+	mockSessionRepo.On("GetByID", mock.Anything, "test-session-123").Return(TestSession(), nil)
+	mockRadarRepo.On("GetBySessionID", mock.Anything, "test-session-123").Return(events, nil)
+	mockTrackRepo.On("ReplaceBySessionID", mock.Anything, "test-session-123", mock.Anything).Return(nil)
+
+	service := NewRadarTrackingService(mockSessionRepo, mockRadarRepo, mockTrackRepo)
+
+	// Act
+	tracks, err := service.BuildTracks(context.Background(), "test-session-123", RadarTrackingConfig{MaxGapSeconds: 5})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, tracks, 2)
+	assert.Equal(t, []string{"e1", "e2"}, tracks[0].EventIDs)
+	assert.Equal(t, []string{"e3", "e4"}, tracks[1].EventIDs)
+}
+
+func TestRadarTrackingService_BuildTracks_SessionNotFound_ReturnsError(t *testing.T) {
+	// Arrange
+	mockSessionRepo := &MockSessionRepository{}
+	mockSessionRepo.On("GetByID", mock.Anything, "missing").
+		Return((*domain.Session)(nil), assert.AnError).
+		Once()
+
+	service := NewRadarTrackingService(mockSessionRepo, &MockRadarRepository{}, &MockRadarTrackRepository{})
+
+	// Act
+	tracks, err := service.BuildTracks(context.Background(), "missing", RadarTrackingConfig{})
+
+	// Assert
+	require.Error(t, err)
+	assert.Nil(t, tracks)
+	assert.Contains(t, err.Error(), "session not found")
+}
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/myideascope/otherside/internal/domain"
+)
+
+const (
+	defaultSearchLimit          = 20
+	maxSearchLimit              = 100
+	defaultSearchHitsPerSession = 5
+	maxSearchHitsPerSession     = 50
+	maxSearchQueryLength        = 500
+	maxSearchTerms              = 16
+
+	// maxSearchMatches bounds how many of the best matching fields are
+	// grouped into sessions
+	maxSearchMatches = 5000
+)
+
+// SearchService searches the text of sessions and their events: session
+// titles and notes, EVP annotations, VOX generated text and interaction
+// content and responses. Hits are ranked, highlighted and grouped by
+// session, and only sessions the caller can read are returned.
+type SearchService struct {
+	searchRepo    domain.SearchRepository
+	sessionRepo   domain.SessionRepository
+	accessService *AccessService
+}
+
+// SearchQuery is a full-text search. Every word or "quoted phrase" of Q
+// must appear in the same field; a word ending in * matches as a prefix.
+// Types limits hits to some entity types. Limit is the number of sessions
+// and HitsPerSession the number of hits returned for each.
+type SearchQuery struct {
+	Q              string
+	Types          []string
+	Limit          int
+	HitsPerSession int
+}
+
+// SearchResult holds the sessions matching a search, best first. Total is
+// how many sessions matched, counted over the best maxSearchMatches hits.
+type SearchResult struct {
+	Query    string                 `json:"query"`
+	Sessions []*SessionSearchResult `json:"sessions"`
+	Total    int                    `json:"total"`
+}
+
+// SessionSearchResult is a session with its best hits. Its score is the
+// score of its best hit and HitCount counts all of its hits.
+type SessionSearchResult struct {
+	SessionID string               `json:"session_id"`
+	Title     string               `json:"title"`
+	Status    domain.SessionStatus `json:"status"`
+	StartTime time.Time            `json:"start_time"`
+	Score     float64              `json:"score"`
+	HitCount  int                  `json:"hit_count"`
+	Hits      []*domain.SearchHit  `json:"hits"`
+}
+
+// NewSearchService creates a new search service
+func NewSearchService(searchRepo domain.SearchRepository, sessionRepo domain.SessionRepository) *SearchService {
+	return &SearchService{
+		searchRepo:  searchRepo,
+		sessionRepo: sessionRepo,
+	}
+}
+
+// SetAccessService limits search results to sessions the caller can read
+func (s *SearchService) SetAccessService(accessService *AccessService) {
+	s.accessService = accessService
+}
+
+// Search runs a full-text search. Highlights are HTML with the matched
+// terms in <mark> elements.
+func (s *SearchService) Search(ctx context.Context, query SearchQuery) (*SearchResult, error) {
+	expression, err := searchExpression(query.Q)
+	if err != nil {
+		return nil, err
+	}
+	for _, entityType := range query.Types {
+		switch entityType {
+		case domain.SearchEntitySession, domain.SearchEntityEVP, domain.SearchEntityVOX, domain.SearchEntityInteraction:
+		default:
+			return nil, fmt.Errorf("invalid search query: unknown type %q", entityType)
+		}
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+	hitsPerSession := query.HitsPerSession
+	if hitsPerSession <= 0 {
+		hitsPerSession = defaultSearchHitsPerSession
+	}
+	if hitsPerSession > maxSearchHitsPerSession {
+		hitsPerSession = maxSearchHitsPerSession
+	}
+
+	hits, err := s.searchRepo.Match(ctx, expression, query.Types, maxSearchMatches)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search: %w", err)
+	}
+
+	// Group the hits by session, keeping them best first
+	grouped := make(map[string][]*domain.SearchHit)
+	for _, hit := range hits {
+		grouped[hit.SessionID] = append(grouped[hit.SessionID], hit)
+	}
+
+	sessions, err := s.visibleSessions(ctx, grouped)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*SessionSearchResult, 0, len(sessions))
+	for _, session := range sessions {
+		sessionHits := grouped[session.ID]
+		results = append(results, &SessionSearchResult{
+			SessionID: session.ID,
+			Title:     session.Title,
+			Status:    session.Status,
+			StartTime: session.StartTime,
+			Score:     sessionHits[0].Score,
+			HitCount:  len(sessionHits),
+			Hits:      sessionHits,
+		})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		if results[i].HitCount != results[j].HitCount {
+			return results[i].HitCount > results[j].HitCount
+		}
+		return results[i].StartTime.After(results[j].StartTime)
+	})
+
+	total := len(results)
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	var docIDs []int64
+	for _, result := range results {
+		if len(result.Hits) > hitsPerSession {
+			result.Hits = result.Hits[:hitsPerSession]
+		}
+		for _, hit := range result.Hits {
+			docIDs = append(docIDs, hit.DocID)
+		}
+	}
+
+	highlights, err := s.searchRepo.Highlight(ctx, expression, docIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to highlight search hits: %w", err)
+	}
+	for _, result := range results {
+		for _, hit := range result.Hits {
+			hit.Highlight = markHighlight(highlights[hit.DocID])
+		}
+	}
+
+	return &SearchResult{
+		Query:    query.Q,
+		Sessions: results,
+		Total:    total,
+	}, nil
+}
+
+// visibleSessions loads the sessions with hits and keeps those the caller
+// can read. Sessions deleted since they were matched are left out.
+func (s *SearchService) visibleSessions(ctx context.Context, grouped map[string][]*domain.SearchHit) ([]*domain.Session, error) {
+	sessions := make([]*domain.Session, 0, len(grouped))
+	for sessionID := range grouped {
+		session, err := s.sessionRepo.GetByID(ctx, sessionID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get session: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+
+	if s.accessService == nil {
+		return sessions, nil
+	}
+	return s.accessService.FilterSessions(ctx, sessions)
+}
+
+// searchExpression turns a search into an FTS5 query expression in which
+// every term is a quoted phrase, so that no input is read as query syntax;
+// prefix terms are followed by a *
+func searchExpression(q string) (string, error) {
+	q = strings.TrimSpace(q)
+	if q == "" {
+		return "", fmt.Errorf("invalid search query: q is required")
+	}
+	if len(q) > maxSearchQueryLength {
+		return "", fmt.Errorf("invalid search query: q is longer than %d characters", maxSearchQueryLength)
+	}
+
+	var terms []string
+	for _, term := range splitSearchTerms(q) {
+		prefix := strings.HasSuffix(term, "*")
+		term = strings.TrimRight(term, "*")
+		if !strings.ContainsFunc(term, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) {
+			continue
+		}
+		term = `"` + term + `"`
+		if prefix {
+			term += "*"
+		}
+		terms = append(terms, term)
+	}
+
+	if len(terms) == 0 {
+		return "", fmt.Errorf("invalid search query: q has no words to search for")
+	}
+	if len(terms) > maxSearchTerms {
+		return "", fmt.Errorf("invalid search query: q has more than %d terms", maxSearchTerms)
+	}
+	return strings.Join(terms, " "), nil
+}
+
+// splitSearchTerms splits a search into words and "quoted phrases",
+// dropping the quotes
+func splitSearchTerms(q string) []string {
+	var terms []string
+	var current strings.Builder
+	quoted := false
+
+	flush := func() {
+		if term := strings.TrimSpace(current.String()); term != "" {
+			terms = append(terms, term)
+		}
+		current.Reset()
+	}
+
+	for _, r := range q {
+		switch {
+		case r == '"':
+			flush()
+			quoted = !quoted
+		case unicode.IsSpace(r) && !quoted:
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+
+	return terms
+}
+
+// markHighlight escapes a snippet read from the index for HTML and wraps
+// its matched terms in <mark> elements
+func markHighlight(snippet string) string {
+	marked := html.EscapeString(snippet)
+	marked = strings.ReplaceAll(marked, domain.SearchHighlightStart, "<mark>")
+	return strings.ReplaceAll(marked, domain.SearchHighlightEnd, "</mark>")
+}
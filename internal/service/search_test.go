@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/myideascope/otherside/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchService_Search_GroupsRankedHitsBySessionForReaders(t *testing.T) {
+	// Arrange
+	f := setupAccessServices(t)
+	search := NewSearchService(repository.NewSQLiteSearchRepository(f.db), f.manager)
+	search.SetAccessService(f.access)
+	interactions := repository.NewSQLiteInteractionRepository(f.db)
+	ctx := context.Background()
+
+	cellar := sharedSession(t, f)
+	attic, err := f.sessions.CreateSession(as("owner"), CreateSessionRequest{
+		Title: "Attic", Notes: "Footsteps overhead, then a voice from the <basement>",
+	})
+	require.NoError(t, err)
+	_, err = f.sessions.CreateSession(as("owner"), CreateSessionRequest{Title: "Garden", Notes: "Quiet night"})
+	require.NoError(t, err)
+
+	now := time.Now()
+	for i, content := range []string{"Is anyone in the basement?", "Knock if you are in the basement", "Basement basement"} {
+		require.NoError(t, interactions.Create(ctx, &domain.UserInteraction{
+			ID: generateID(), SessionID: cellar.ID, Timestamp: now.Add(time.Duration(i) * time.Second),
+			Type: domain.InteractionTypeText, Content: content, CreatedAt: now,
+		}))
+	}
+
+	// Act
+	ownerResult, err := search.Search(as("owner"), SearchQuery{Q: "basement", HitsPerSession: 2})
+	require.NoError(t, err)
+	guestResult, err := search.Search(as("guest"), SearchQuery{Q: "basement"})
+	require.NoError(t, err)
+	outsiderResult, err := search.Search(as("outsider"), SearchQuery{Q: "basement"})
+	require.NoError(t, err)
+	sessionOnly, err := search.Search(as("owner"), SearchQuery{Q: "basement", Types: []string{domain.SearchEntitySession}})
+	require.NoError(t, err)
+
+	// Assert
+	require.Len(t, ownerResult.Sessions, 2)
+	assert.Equal(t, 2, ownerResult.Total)
+	best := ownerResult.Sessions[0]
+	assert.Equal(t, cellar.ID, best.SessionID)
+	assert.Equal(t, 3, best.HitCount)
+	require.Len(t, best.Hits, 2)
+	assert.Equal(t, domain.SearchEntityInteraction, best.Hits[0].Type)
+	assert.Equal(t, "content", best.Hits[0].Field)
+	assert.Equal(t, "<mark>Basement</mark> <mark>basement</mark>", best.Hits[0].Highlight)
+	assert.GreaterOrEqual(t, best.Hits[0].Score, best.Hits[1].Score)
+	assert.Equal(t, attic.ID, ownerResult.Sessions[1].SessionID)
+	assert.Contains(t, ownerResult.Sessions[1].Hits[0].Highlight, "from the &lt;<mark>basement</mark>&gt;")
+
+	require.Len(t, guestResult.Sessions, 1)
+	assert.Equal(t, cellar.ID, guestResult.Sessions[0].SessionID)
+	assert.Empty(t, outsiderResult.Sessions)
+	require.Len(t, sessionOnly.Sessions, 1)
+	assert.Equal(t, attic.ID, sessionOnly.Sessions[0].SessionID)
+}
+
+func TestSearchService_Search_InvalidQuery_ReturnsError(t *testing.T) {
+	// Arrange
+	search := NewSearchService(repository.NewSQLiteSearchRepository(setupLifecycleDB(t)), nil)
+	ctx := context.Background()
+
+	// Act
+	_, emptyErr := search.Search(ctx, SearchQuery{Q: "  "})
+	_, symbolsErr := search.Search(ctx, SearchQuery{Q: `"**" -`})
+	_, typeErr := search.Search(ctx, SearchQuery{Q: "help", Types: []string{"radar"}})
+	expression, err := searchExpression(`help OR "cold spot" base* NEAR(`)
+	_, syntaxErr := search.Search(ctx, SearchQuery{Q: `help OR "cold spot" base* NEAR(`})
+
+	// Assert
+	assert.Contains(t, emptyErr.Error(), "invalid search query")
+	assert.Contains(t, symbolsErr.Error(), "invalid search query")
+	assert.Contains(t, typeErr.Error(), "invalid search query")
+	require.NoError(t, err)
+	assert.Equal(t, `"help" "OR" "cold spot" "base"* "NEAR("`, expression)
+	assert.NoError(t, syntaxErr)
+}
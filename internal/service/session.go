@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"log"
 	"math"
 	"time"
 
@@ -23,8 +24,20 @@ type SessionService struct {
 	fileRepo        domain.FileRepository
 	audioProcessor  *audio.Processor
 	voxGenerator    *audio.VOXGenerator
+	participantRepo domain.SessionParticipantRepository
+	accessService   *AccessService
+	eventHub        *EventHub
+	alertService    *AlertService
+	webhookService  *WebhookService
 }
 
+// voxTriggerThreshold is the minimum trigger strength for VOX generation
+const voxTriggerThreshold = 0.3
+
+// visibleSessionBatch is the smallest number of sessions read at a time
+// when filtering listings by access
+const visibleSessionBatch = 100
+
 // SessionServiceConfig holds configuration for session service
 type SessionServiceConfig struct {
 	MaxConcurrentSessions int
@@ -57,10 +70,60 @@ func NewSessionService(
 	}
 }
 
+// SetParticipantRepository enables checking that events are attributed to
+// investigators taking part in their session
+func (s *SessionService) SetParticipantRepository(participantRepo domain.SessionParticipantRepository) {
+	s.participantRepo = participantRepo
+}
+
+// SetAccessService enables per-session access control: new sessions are
+// owned by the investigator who creates them, listings only show sessions
+// the caller can read and recording events needs team access
+func (s *SessionService) SetAccessService(accessService *AccessService) {
+	s.accessService = accessService
+}
+
+// SetEventHub enables live streams: every event recorded to a session is
+// published to the hub for the investigators watching it
+func (s *SessionService) SetEventHub(eventHub *EventHub) {
+	s.eventHub = eventHub
+}
+
+// SetAlertService evaluates alert rules against every event recorded to a
+// session
+func (s *SessionService) SetAlertService(alertService *AlertService) {
+	s.alertService = alertService
+}
+
+// SetWebhookService sends high-confidence EVP recordings to webhook
+// subscribers
+func (s *SessionService) SetWebhookService(webhookService *WebhookService) {
+	s.webhookService = webhookService
+}
+
 // CreateSession creates a new paranormal investigation session
 func (s *SessionService) CreateSession(ctx context.Context, req CreateSessionRequest) (*domain.Session, error) {
+	id, err := resolveClientID(req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if req.ID != "" {
+		if existing, err := s.sessionRepo.GetByID(ctx, id); err == nil {
+			if err := s.authorizeReplay(ctx, existing.ID, existing.ID, id); err != nil {
+				return nil, err
+			}
+			return existing, nil
+		}
+	}
+
+	if s.accessService != nil {
+		if err := s.accessService.CheckTeam(ctx, req.TeamID); err != nil {
+			return nil, err
+		}
+	}
+
 	session := &domain.Session{
-		ID:            generateID(),
+		ID:            id,
 		Title:         req.Title,
 		Location:      req.Location,
 		StartTime:     time.Now(),
@@ -75,19 +138,37 @@ func (s *SessionService) CreateSession(ctx context.Context, req CreateSessionReq
 		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
 
+	if s.accessService != nil {
+		if err := s.accessService.ClaimSession(ctx, session.ID, req.TeamID); err != nil {
+			return nil, err
+		}
+	}
+
 	return session, nil
 }
 
 // ProcessEVPRecording processes an EVP recording for paranormal analysis
 func (s *SessionService) ProcessEVPRecording(ctx context.Context, sessionID string, audioData []float64, metadata EVPMetadata) (*domain.EVPRecording, error) {
-	// Verify session exists and is active
-	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	id, err := resolveClientID(metadata.ID)
 	if err != nil {
-		return nil, fmt.Errorf("session not found: %w", err)
+		return nil, err
+	}
+	if metadata.ID != "" {
+		if existing, err := s.evpRepo.GetByID(ctx, id); err == nil {
+			if err := s.authorizeReplay(ctx, sessionID, existing.SessionID, id); err != nil {
+				return nil, err
+			}
+			return existing, nil
+		}
 	}
 
-	if session.Status != domain.SessionStatusActive {
-		return nil, fmt.Errorf("session is not active")
+	// Verify session exists and is active
+	if _, err := s.activeSession(ctx, sessionID); err != nil {
+		return nil, err
+	}
+	metadata.InvestigatorID, metadata.DeviceID, err = resolveAttribution(ctx, s.participantRepo, sessionID, metadata.InvestigatorID, metadata.DeviceID)
+	if err != nil {
+		return nil, err
 	}
 
 	// Process audio using audio processor
@@ -101,7 +182,7 @@ func (s *SessionService) ProcessEVPRecording(ctx context.Context, sessionID stri
 
 	// Create EVP recording
 	evp := &domain.EVPRecording{
-		ID:             generateID(),
+		ID:             id,
 		SessionID:      sessionID,
 		FilePath:       metadata.FilePath,
 		Duration:       result.Metadata.Duration,
@@ -110,29 +191,167 @@ func (s *SessionService) ProcessEVPRecording(ctx context.Context, sessionID stri
 		Annotations:    metadata.Annotations,
 		Quality:        quality,
 		DetectionLevel: result.AnomalyStrength,
+		InvestigatorID: metadata.InvestigatorID,
+		DeviceID:       metadata.DeviceID,
 		CreatedAt:      time.Now(),
 	}
 
 	if err := s.evpRepo.Create(ctx, evp); err != nil {
 		return nil, fmt.Errorf("failed to save EVP recording: %w", err)
 	}
+	s.publish(ctx, sessionID, LiveEventEVP, evp)
 
 	return evp, nil
 }
 
 // GenerateVOXCommunication generates VOX-based paranormal communication
 func (s *SessionService) GenerateVOXCommunication(ctx context.Context, sessionID string, triggerData VOXTriggerData) (*domain.VOXEvent, error) {
-	// Verify session
-	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	id, err := resolveClientID(triggerData.ID)
 	if err != nil {
-		return nil, fmt.Errorf("session not found: %w", err)
+		return nil, err
+	}
+	if triggerData.ID != "" {
+		if existing, err := s.voxRepo.GetByID(ctx, id); err == nil {
+			if err := s.authorizeReplay(ctx, sessionID, existing.SessionID, id); err != nil {
+				return nil, err
+			}
+			return existing, nil
+		}
+	}
+
+	// Verify session exists and is active
+	if _, err := s.activeSession(ctx, sessionID); err != nil {
+		return nil, err
+	}
+	triggerData.InvestigatorID, triggerData.DeviceID, err = resolveAttribution(ctx, s.participantRepo, sessionID, triggerData.InvestigatorID, triggerData.DeviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	voxEvent, err := s.newVOXEvent(ctx, sessionID, id, triggerData, time.Now())
+	if err != nil || voxEvent == nil {
+		return nil, err
+	}
+
+	if err := s.voxRepo.Create(ctx, voxEvent); err != nil {
+		return nil, fmt.Errorf("failed to save VOX event: %w", err)
+	}
+	s.publish(ctx, sessionID, LiveEventVOX, voxEvent)
+
+	return voxEvent, nil
+}
+
+// ProcessRadarEvent processes radar/presence detection data
+func (s *SessionService) ProcessRadarEvent(ctx context.Context, sessionID string, radarData RadarEventData) (*domain.RadarEvent, error) {
+	id, err := resolveClientID(radarData.ID)
+	if err != nil {
+		return nil, err
 	}
+	if radarData.ID != "" {
+		if existing, err := s.radarRepo.GetByID(ctx, id); err == nil {
+			if err := s.authorizeReplay(ctx, sessionID, existing.SessionID, id); err != nil {
+				return nil, err
+			}
+			return existing, nil
+		}
+	}
+
+	// Verify session exists and is active
+	if _, err := s.activeSession(ctx, sessionID); err != nil {
+		return nil, err
+	}
+	radarData.InvestigatorID, radarData.DeviceID, err = resolveAttribution(ctx, s.participantRepo, sessionID, radarData.InvestigatorID, radarData.DeviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	radarEvent, err := s.newRadarEvent(sessionID, id, radarData, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.radarRepo.Create(ctx, radarEvent); err != nil {
+		return nil, fmt.Errorf("failed to save radar event: %w", err)
+	}
+	s.publish(ctx, sessionID, LiveEventRadar, radarEvent)
 
-	// Ensure session is active
-	if session.Status != domain.SessionStatusActive {
-		return nil, fmt.Errorf("session is not active")
+	return radarEvent, nil
+}
+
+// ProcessSLSDetection processes SLS (Structured Light Sensor) detection
+func (s *SessionService) ProcessSLSDetection(ctx context.Context, sessionID string, slsData SLSDetectionData) (*domain.SLSDetection, error) {
+	id, err := resolveClientID(slsData.ID)
+	if err != nil {
+		return nil, err
+	}
+	if slsData.ID != "" {
+		if existing, err := s.slsRepo.GetByID(ctx, id); err == nil {
+			if err := s.authorizeReplay(ctx, sessionID, existing.SessionID, id); err != nil {
+				return nil, err
+			}
+			return existing, nil
+		}
+	}
+
+	// Verify session exists and is active
+	if _, err := s.activeSession(ctx, sessionID); err != nil {
+		return nil, err
+	}
+	slsData.InvestigatorID, slsData.DeviceID, err = resolveAttribution(ctx, s.participantRepo, sessionID, slsData.InvestigatorID, slsData.DeviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	slsDetection, err := s.newSLSDetection(sessionID, id, slsData, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.slsRepo.Create(ctx, slsDetection); err != nil {
+		return nil, fmt.Errorf("failed to save SLS detection: %w", err)
+	}
+	s.publish(ctx, sessionID, LiveEventSLS, slsDetection)
+
+	return slsDetection, nil
+}
+
+// RecordUserInteraction records user interaction during investigation
+func (s *SessionService) RecordUserInteraction(ctx context.Context, sessionID string, interaction UserInteractionData) (*domain.UserInteraction, error) {
+	id, err := resolveClientID(interaction.ID)
+	if err != nil {
+		return nil, err
+	}
+	if interaction.ID != "" {
+		if existing, err := s.interactionRepo.GetByID(ctx, id); err == nil {
+			if err := s.authorizeReplay(ctx, sessionID, existing.SessionID, id); err != nil {
+				return nil, err
+			}
+			return existing, nil
+		}
+	}
+
+	// Verify session exists and is active
+	if _, err := s.activeSession(ctx, sessionID); err != nil {
+		return nil, err
+	}
+	interaction.InvestigatorID, interaction.DeviceID, err = resolveAttribution(ctx, s.participantRepo, sessionID, interaction.InvestigatorID, interaction.DeviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	userInteraction := s.newUserInteraction(sessionID, id, interaction, time.Now())
+
+	if err := s.interactionRepo.Create(ctx, userInteraction); err != nil {
+		return nil, fmt.Errorf("failed to save user interaction: %w", err)
 	}
+	s.publish(ctx, sessionID, LiveEventInteraction, userInteraction)
 
+	return userInteraction, nil
+}
+
+// newVOXEvent generates the VOX event for trigger data at a time. It
+// returns nil when the triggers are below the generation threshold.
+func (s *SessionService) newVOXEvent(ctx context.Context, sessionID, id string, triggerData VOXTriggerData, at time.Time) (*domain.VOXEvent, error) {
 	// Prepare trigger data for VOX generator
 	triggers := map[string]float64{
 		"emf_anomaly":   triggerData.EMFAnomaly,
@@ -145,7 +364,7 @@ func (s *SessionService) GenerateVOXCommunication(ctx context.Context, sessionID
 	voxConfig := audio.VOXConfig{
 		DefaultLanguage:  triggerData.LanguagePack,
 		PhoneticBankSize: triggerData.PhoneticBankSize,
-		TriggerThreshold: 0.3,
+		TriggerThreshold: voxTriggerThreshold,
 	}
 
 	voxResult, err := s.voxGenerator.GenerateVOX(ctx, triggers, voxConfig)
@@ -158,132 +377,86 @@ func (s *SessionService) GenerateVOXCommunication(ctx context.Context, sessionID
 		return nil, nil
 	}
 
-	// Create VOX event
-	voxEvent := &domain.VOXEvent{
-		ID:              generateID(),
+	return &domain.VOXEvent{
+		ID:              id,
 		SessionID:       sessionID,
-		Timestamp:       time.Now(),
+		Timestamp:       at,
 		GeneratedText:   voxResult.GeneratedText,
 		PhoneticBank:    voxResult.PhoneticBank,
 		FrequencyData:   voxResult.FrequencyData,
 		TriggerStrength: voxResult.TriggerStrength,
 		LanguagePack:    triggerData.LanguagePack,
 		ModulationType:  voxResult.ModulationType,
+		InvestigatorID:  triggerData.InvestigatorID,
+		DeviceID:        triggerData.DeviceID,
 		CreatedAt:       time.Now(),
-	}
-
-	if err := s.voxRepo.Create(ctx, voxEvent); err != nil {
-		return nil, fmt.Errorf("failed to save VOX event: %w", err)
-	}
-
-	return voxEvent, nil
+	}, nil
 }
 
-// ProcessRadarEvent processes radar/presence detection data
-func (s *SessionService) ProcessRadarEvent(ctx context.Context, sessionID string, radarData RadarEventData) (*domain.RadarEvent, error) {
-	// Verify session
-	session, err := s.sessionRepo.GetByID(ctx, sessionID)
-	if err != nil {
-		return nil, fmt.Errorf("session not found: %w", err)
-	}
-
-	// Ensure session is active
-	if session.Status != domain.SessionStatusActive {
-		return nil, fmt.Errorf("session is not active")
-	}
-
+// newRadarEvent validates radar data and builds its event at a time
+func (s *SessionService) newRadarEvent(sessionID, id string, radarData RadarEventData, at time.Time) (*domain.RadarEvent, error) {
 	// Analyze radar data for authenticity (minimize false positives)
 	if !s.validateRadarEvent(radarData) {
 		return nil, fmt.Errorf("radar event failed validation")
 	}
 
-	// Determine source type based on data
-	sourceType := s.determineRadarSourceType(radarData)
-
-	radarEvent := &domain.RadarEvent{
-		ID:            generateID(),
-		SessionID:     sessionID,
-		Timestamp:     time.Now(),
-		Position:      radarData.Position,
-		Strength:      radarData.Strength,
-		SourceType:    sourceType,
-		EMFReading:    radarData.EMFReading,
-		AudioAnomaly:  radarData.AudioAnomaly,
-		Duration:      radarData.Duration,
-		MovementTrail: radarData.MovementTrail,
-		CreatedAt:     time.Now(),
-	}
-
-	if err := s.radarRepo.Create(ctx, radarEvent); err != nil {
-		return nil, fmt.Errorf("failed to save radar event: %w", err)
-	}
-
-	return radarEvent, nil
+	return &domain.RadarEvent{
+		ID:             id,
+		SessionID:      sessionID,
+		Timestamp:      at,
+		Position:       radarData.Position,
+		Strength:       radarData.Strength,
+		SourceType:     s.determineRadarSourceType(radarData),
+		EMFReading:     radarData.EMFReading,
+		AudioAnomaly:   radarData.AudioAnomaly,
+		Duration:       radarData.Duration,
+		MovementTrail:  radarData.MovementTrail,
+		InvestigatorID: radarData.InvestigatorID,
+		DeviceID:       radarData.DeviceID,
+		CreatedAt:      time.Now(),
+	}, nil
 }
 
-// ProcessSLSDetection processes SLS (Structured Light Sensor) detection
-func (s *SessionService) ProcessSLSDetection(ctx context.Context, sessionID string, slsData SLSDetectionData) (*domain.SLSDetection, error) {
-	// Verify session
-	_, err := s.sessionRepo.GetByID(ctx, sessionID)
-	if err != nil {
-		return nil, fmt.Errorf("session not found: %w", err)
-	}
-
+// newSLSDetection validates SLS data and builds its detection at a time
+func (s *SessionService) newSLSDetection(sessionID, id string, slsData SLSDetectionData, at time.Time) (*domain.SLSDetection, error) {
 	// Apply false-positive reduction filters
 	if !s.validateSLSDetection(slsData) {
 		return nil, fmt.Errorf("SLS detection failed validation")
 	}
 
-	// Analyze movement patterns
-	movement := s.analyzeMovementPattern(slsData.SkeletalPoints)
-
-	slsDetection := &domain.SLSDetection{
-		ID:             generateID(),
+	return &domain.SLSDetection{
+		ID:             id,
 		SessionID:      sessionID,
-		Timestamp:      time.Now(),
+		Timestamp:      at,
 		SkeletalPoints: slsData.SkeletalPoints,
 		Confidence:     slsData.Confidence,
 		BoundingBox:    slsData.BoundingBox,
 		VideoFrame:     slsData.VideoFrame,
 		FilterApplied:  slsData.FiltersApplied,
 		Duration:       slsData.Duration,
-		Movement:       movement,
+		Movement:       s.analyzeMovementPattern(slsData.SkeletalPoints),
+		InvestigatorID: slsData.InvestigatorID,
+		DeviceID:       slsData.DeviceID,
 		CreatedAt:      time.Now(),
-	}
-
-	if err := s.slsRepo.Create(ctx, slsDetection); err != nil {
-		return nil, fmt.Errorf("failed to save SLS detection: %w", err)
-	}
-
-	return slsDetection, nil
+	}, nil
 }
 
-// RecordUserInteraction records user interaction during investigation
-func (s *SessionService) RecordUserInteraction(ctx context.Context, sessionID string, interaction UserInteractionData) (*domain.UserInteraction, error) {
-	// Verify session
-	_, err := s.sessionRepo.GetByID(ctx, sessionID)
-	if err != nil {
-		return nil, fmt.Errorf("session not found: %w", err)
-	}
-
-	userInteraction := &domain.UserInteraction{
-		ID:               generateID(),
+// newUserInteraction builds a user interaction at a time
+func (s *SessionService) newUserInteraction(sessionID, id string, interaction UserInteractionData, at time.Time) *domain.UserInteraction {
+	return &domain.UserInteraction{
+		ID:               id,
 		SessionID:        sessionID,
-		Timestamp:        time.Now(),
+		Timestamp:        at,
 		Type:             interaction.Type,
 		Content:          interaction.Content,
 		AudioPath:        interaction.AudioPath,
 		Response:         interaction.Response,
 		ResponseTime:     interaction.ResponseTime,
 		RandomizerResult: interaction.RandomizerResult,
+		InvestigatorID:   interaction.InvestigatorID,
+		DeviceID:         interaction.DeviceID,
 		CreatedAt:        time.Now(),
 	}
-
-	if err := s.interactionRepo.Create(ctx, userInteraction); err != nil {
-		return nil, fmt.Errorf("failed to save user interaction: %w", err)
-	}
-
-	return userInteraction, nil
 }
 
 // GetSessionSummary returns a comprehensive summary of a session
@@ -293,6 +466,11 @@ func (s *SessionService) GetSessionSummary(ctx context.Context, sessionID string
 	if err != nil {
 		return nil, fmt.Errorf("session not found: %w", err)
 	}
+	if s.accessService != nil {
+		if err := s.accessService.AuthorizeSession(ctx, sessionID, domain.AccessGuest); err != nil {
+			return nil, err
+		}
+	}
 
 	// Get all related data
 	evps, _ := s.evpRepo.GetBySessionID(ctx, sessionID)
@@ -317,16 +495,156 @@ func (s *SessionService) GetSessionSummary(ctx context.Context, sessionID string
 
 // GetAllSessions returns all sessions with pagination
 func (s *SessionService) GetAllSessions(ctx context.Context, limit, offset int) ([]*domain.Session, error) {
-	return s.sessionRepo.GetAll(ctx, limit, offset)
+	if s.accessService == nil {
+		return s.sessionRepo.GetAll(ctx, limit, offset)
+	}
+
+	// Page through all sessions so that limit and offset count only the
+	// sessions the caller can see
+	var visible []*domain.Session
+	batchSize := limit
+	if batchSize < visibleSessionBatch {
+		batchSize = visibleSessionBatch
+	}
+	for start := 0; len(visible) < offset+limit; start += batchSize {
+		batch, err := s.sessionRepo.GetAll(ctx, batchSize, start)
+		if err != nil {
+			return nil, err
+		}
+		filtered, err := s.accessService.FilterSessions(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+		visible = append(visible, filtered...)
+		if len(batch) < batchSize {
+			break
+		}
+	}
+
+	return pageSessions(visible, limit, offset), nil
 }
 
 // GetSessionsByStatus returns sessions filtered by status with pagination
 func (s *SessionService) GetSessionsByStatus(ctx context.Context, status domain.SessionStatus, limit, offset int) ([]*domain.Session, error) {
-	return s.sessionRepo.GetByStatus(ctx, status)
+	sessions, err := s.sessionRepo.GetByStatus(ctx, status)
+	if err != nil || s.accessService == nil {
+		return sessions, err
+	}
+	return s.accessService.FilterSessions(ctx, sessions)
+}
+
+// SubscribeEvents starts watching the events recorded to a session, of
+// the given types or of every type when types is empty. The events kept
+// since lastEventID are returned to be sent first. It fails when live
+// streams are not enabled.
+func (s *SessionService) SubscribeEvents(ctx context.Context, sessionID string, lastEventID int64, types []string) (*EventSubscription, []LiveEvent, error) {
+	if s.eventHub == nil {
+		return nil, nil, fmt.Errorf("live streams are not enabled")
+	}
+	for _, eventType := range types {
+		switch eventType {
+		case LiveEventEVP, LiveEventVOX, LiveEventRadar, LiveEventSLS, LiveEventInteraction, LiveEventAlert:
+		default:
+			return nil, nil, fmt.Errorf("invalid event type: %q", eventType)
+		}
+	}
+
+	if _, err := s.sessionRepo.GetByID(ctx, sessionID); err != nil {
+		return nil, nil, fmt.Errorf("session not found: %w", err)
+	}
+	if s.accessService != nil {
+		if err := s.accessService.AuthorizeSession(ctx, sessionID, domain.AccessGuest); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	sub, missed := s.eventHub.Subscribe(sessionID, lastEventID, types)
+	return sub, missed, nil
 }
 
 // Helper methods
 
+// publish sends a recorded event to the session's live stream, alert rules
+// and webhooks, when enabled
+func (s *SessionService) publish(ctx context.Context, sessionID, eventType string, data interface{}) {
+	if s.eventHub != nil {
+		s.eventHub.Publish(sessionID, eventType, data)
+	}
+	if s.alertService != nil {
+		s.alertService.ObserveEvent(ctx, sessionID, eventType, data)
+	}
+	if s.webhookService != nil {
+		s.webhookService.ObserveEvent(ctx, sessionID, eventType, data)
+	}
+}
+
+// publishBatch sends the events of a stored batch to their live streams
+// and alert rules
+func (s *SessionService) publishBatch(ctx context.Context, batch *domain.EventBatch) {
+	for _, vox := range batch.VOXEvents {
+		s.publish(ctx, vox.SessionID, LiveEventVOX, vox)
+	}
+	for _, radar := range batch.RadarEvents {
+		s.publish(ctx, radar.SessionID, LiveEventRadar, radar)
+	}
+	for _, sls := range batch.SLSDetections {
+		s.publish(ctx, sls.SessionID, LiveEventSLS, sls)
+	}
+	for _, interaction := range batch.Interactions {
+		s.publish(ctx, interaction.SessionID, LiveEventInteraction, interaction)
+	}
+}
+
+// activeSession loads a session, checks that it accepts new events and
+// records the activity so the session does not expire while in use
+func (s *SessionService) activeSession(ctx context.Context, sessionID string) (*domain.Session, error) {
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+
+	if s.accessService != nil {
+		if err := s.accessService.AuthorizeSession(ctx, sessionID, domain.AccessTeam); err != nil {
+			return nil, err
+		}
+	}
+
+	if !session.Status.AcceptsEvents() {
+		return nil, fmt.Errorf("session is not active: status is %s", session.Status)
+	}
+
+	if err := s.sessionRepo.Touch(ctx, sessionID, time.Now()); err != nil {
+		log.Printf("Failed to record activity on session %s: %v", sessionID, err)
+	}
+
+	return session, nil
+}
+
+// authorizeReplay checks that a create request whose client-supplied ID
+// is already taken repeats an earlier request, in which case the existing
+// record is returned, rather than reusing the ID for a different session
+func (s *SessionService) authorizeReplay(ctx context.Context, sessionID, existingSessionID, id string) error {
+	if existingSessionID != sessionID {
+		return fmt.Errorf("id conflict: %s is already used in another session", id)
+	}
+	if s.accessService != nil {
+		return s.accessService.AuthorizeSession(ctx, sessionID, domain.AccessTeam)
+	}
+	return nil
+}
+
+// pageSessions returns one page of an already filtered session list
+func pageSessions(sessions []*domain.Session, limit, offset int) []*domain.Session {
+	if offset >= len(sessions) {
+		return []*domain.Session{}
+	}
+	end := offset + limit
+	if end > len(sessions) {
+		end = len(sessions)
+	}
+	return sessions[offset:end]
+}
+
 func (s *SessionService) determineEVPQuality(result *audio.ProcessingResult) domain.EVPQuality {
 	if result.AnomalyStrength >= 0.8 && result.NoiseLevel < 0.1 {
 		return domain.EVPQualityExcellent
@@ -525,51 +843,68 @@ func generateID() string {
 // Request/Response types
 
 type CreateSessionRequest struct {
+	ID            string               `json:"id,omitempty"`
 	Title         string               `json:"title"`
 	Location      domain.Location      `json:"location"`
 	Notes         string               `json:"notes"`
 	Environmental domain.Environmental `json:"environmental"`
+	TeamID        string               `json:"team_id,omitempty"`
 }
 
 type EVPMetadata struct {
-	FilePath    string   `json:"file_path"`
-	Annotations []string `json:"annotations"`
+	ID             string   `json:"id,omitempty"`
+	FilePath       string   `json:"file_path"`
+	Annotations    []string `json:"annotations"`
+	InvestigatorID string   `json:"investigator_id,omitempty"`
+	DeviceID       string   `json:"device_id,omitempty"`
 }
 
 type VOXTriggerData struct {
+	ID                     string  `json:"id,omitempty"`
 	EMFAnomaly             float64 `json:"emf_anomaly"`
 	AudioAnomaly           float64 `json:"audio_anomaly"`
 	TemperatureFluctuation float64 `json:"temperature_fluctuation"`
 	Interference           float64 `json:"interference"`
 	LanguagePack           string  `json:"language_pack"`
 	PhoneticBankSize       int     `json:"phonetic_bank_size"`
+	InvestigatorID         string  `json:"investigator_id,omitempty"`
+	DeviceID               string  `json:"device_id,omitempty"`
 }
 
 type RadarEventData struct {
-	Position      domain.Coordinates   `json:"position"`
-	Strength      float64              `json:"strength"`
-	EMFReading    float64              `json:"emf_reading"`
-	AudioAnomaly  float64              `json:"audio_anomaly"`
-	Duration      float64              `json:"duration"`
-	MovementTrail []domain.Coordinates `json:"movement_trail"`
+	ID             string               `json:"id,omitempty"`
+	Position       domain.Coordinates   `json:"position"`
+	Strength       float64              `json:"strength"`
+	EMFReading     float64              `json:"emf_reading"`
+	AudioAnomaly   float64              `json:"audio_anomaly"`
+	Duration       float64              `json:"duration"`
+	MovementTrail  []domain.Coordinates `json:"movement_trail"`
+	InvestigatorID string               `json:"investigator_id,omitempty"`
+	DeviceID       string               `json:"device_id,omitempty"`
 }
 
 type SLSDetectionData struct {
+	ID             string                 `json:"id,omitempty"`
 	SkeletalPoints []domain.SkeletalPoint `json:"skeletal_points"`
 	Confidence     float64                `json:"confidence"`
 	BoundingBox    domain.BoundingBox     `json:"bounding_box"`
 	VideoFrame     string                 `json:"video_frame"`
 	FiltersApplied []string               `json:"filters_applied"`
 	Duration       float64                `json:"duration"`
+	InvestigatorID string                 `json:"investigator_id,omitempty"`
+	DeviceID       string                 `json:"device_id,omitempty"`
 }
 
 type UserInteractionData struct {
+	ID               string                   `json:"id,omitempty"`
 	Type             domain.InteractionType   `json:"type"`
 	Content          string                   `json:"content"`
 	AudioPath        string                   `json:"audio_path,omitempty"`
 	Response         string                   `json:"response,omitempty"`
 	ResponseTime     float64                  `json:"response_time,omitempty"`
 	RandomizerResult *domain.RandomizerResult `json:"randomizer_result,omitempty"`
+	InvestigatorID   string                   `json:"investigator_id,omitempty"`
+	DeviceID         string                   `json:"device_id,omitempty"`
 }
 
 type SessionSummary struct {
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/myideascope/otherside/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionStateManager_GetSession_ReturnsCopies(t *testing.T) {
+	// Arrange
+	sm, session := setupLifecycleManager(t)
+	ctx := context.Background()
+
+	// Act
+	first, err := sm.GetSession(ctx, session.ID)
+	require.NoError(t, err)
+	first.Title = "Changed by a handler"
+	first.Status = domain.SessionStatusArchived
+
+	// Assert
+	second, err := sm.GetSession(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Cellar", second.Title)
+	assert.Equal(t, domain.SessionStatusActive, second.Status)
+	assert.Equal(t, domain.SessionStatusActive, sm.GetActiveSessions()[0].Status)
+}
+
+func TestSessionService_ThroughStateManager_SeesTransitions(t *testing.T) {
+	// Arrange
+	sm, _ := setupLifecycleManager(t)
+	ctx := context.Background()
+	sessionService := NewSessionService(sm, nil, nil, nil, nil, new(MockInteractionRepository), nil, nil, nil)
+
+	created, err := sessionService.CreateSession(ctx, CreateSessionRequest{Title: "Attic"})
+	require.NoError(t, err)
+
+	// Act
+	require.NoError(t, sm.PauseSession(ctx, created.ID))
+	_, err = sessionService.RecordUserInteraction(ctx, created.ID, UserInteractionData{Type: domain.InteractionTypeVoice})
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not active")
+	assert.Len(t, sm.GetActiveSessionsByStatus(domain.SessionStatusPaused), 1)
+}
+
+func TestSessionStateManager_Transition_StaleCache_EvictsAndReturnsTransitionError(t *testing.T) {
+	// Arrange
+	db := setupLifecycleDB(t)
+	sm := NewSessionStateManager(repository.NewSQLiteSessionRepository(db))
+	ctx := context.Background()
+
+	session := &domain.Session{ID: "session-1", Title: "Cellar", StartTime: time.Now()}
+	require.NoError(t, sm.CreateSession(ctx, session))
+
+	// A second manager over the same database completes the session behind
+	// the first one's cache
+	other := NewSessionStateManager(repository.NewSQLiteSessionRepository(db))
+	require.NoError(t, other.CompleteSession(ctx, session.ID))
+
+	// Act
+	err := sm.PauseSession(ctx, session.ID)
+
+	// Assert
+	var transitionErr *domain.SessionTransitionError
+	require.True(t, errors.As(err, &transitionErr))
+	assert.Equal(t, domain.SessionStatusComplete, transitionErr.From)
+
+	stored, err := sm.GetSession(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.SessionStatusComplete, stored.Status)
+}
+
+func TestSessionStateManager_ConcurrentReadsAndTransitions_Consistent(t *testing.T) {
+	// Arrange
+	sm, session := setupLifecycleManager(t)
+	ctx := context.Background()
+
+	// Act
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 25; j++ {
+				sm.PauseSession(ctx, session.ID)
+				sm.ResumeSession(ctx, session.ID)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				current, err := sm.GetSession(ctx, session.ID)
+				if assert.NoError(t, err) {
+					current.Title = "scribbled"
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	cached, err := sm.GetSession(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Cellar", cached.Title)
+
+	stored, err := sm.sessionRepo.GetByID(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, stored.Status, cached.Status)
+
+	history, err := sm.GetStatusHistory(ctx, session.ID)
+	require.NoError(t, err)
+	for i := 1; i < len(history); i++ {
+		assert.Equal(t, history[i-1].ToStatus, history[i].FromStatus)
+	}
+}
+
+func TestSessionStateManager_Touch_PersistedBySaveSessionState(t *testing.T) {
+	// Arrange
+	db := setupLifecycleDB(t)
+	sm := NewSessionStateManager(repository.NewSQLiteSessionRepository(db))
+	ctx := context.Background()
+	session := &domain.Session{ID: "session-1", Title: "Cellar", StartTime: time.Now()}
+	require.NoError(t, sm.CreateSession(ctx, session))
+
+	lastActivity := time.Now().Add(time.Minute)
+	require.NoError(t, sm.Touch(ctx, session.ID, lastActivity))
+	require.NoError(t, sm.Touch(ctx, session.ID, time.Now())) // never moves back
+
+	stored, err := repository.NewSQLiteSessionRepository(db).GetByID(ctx, session.ID)
+	require.NoError(t, err)
+	require.True(t, stored.UpdatedAt.Before(lastActivity), "touch must not write through")
+
+	// Act
+	err = sm.SaveSessionState(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	restarted := NewSessionStateManager(repository.NewSQLiteSessionRepository(db))
+	require.NoError(t, restarted.Initialize(ctx))
+	reloaded, err := restarted.GetSession(ctx, session.ID)
+	require.NoError(t, err)
+	assert.WithinDuration(t, lastActivity, reloaded.UpdatedAt, time.Millisecond)
+}
+
+func TestSessionService_Ingest_RecordsActivityOnSession(t *testing.T) {
+	// Arrange
+	sm, session := setupLifecycleManager(t)
+	ctx := context.Background()
+	before, err := sm.GetSession(ctx, session.ID)
+	require.NoError(t, err)
+	service := NewSessionService(sm, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	// Act
+	_, err = service.activeSession(ctx, session.ID)
+
+	// Assert
+	require.NoError(t, err)
+	after, err := sm.GetSession(ctx, session.ID)
+	require.NoError(t, err)
+	assert.True(t, after.UpdatedAt.After(before.UpdatedAt))
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/myideascope/otherside/internal/domain"
+)
+
+// SessionLifecycleService moves sessions through their lifecycle, edits
+// their details and deletes them together with their stored files
+type SessionLifecycleService struct {
+	stateManager  *SessionStateManager
+	evpRepo       domain.EVPRepository
+	fileRepo      domain.FileRepository
+	accessService *AccessService
+}
+
+// UpdateSessionRequest holds the session fields to change. Nil fields are
+// left as they are. A non-zero Version is the version the changes were made
+// to; the update fails with a VersionConflictError when it is outdated.
+type UpdateSessionRequest struct {
+	Title         *string               `json:"title,omitempty"`
+	Notes         *string               `json:"notes,omitempty"`
+	Location      *domain.Location      `json:"location,omitempty"`
+	Environmental *domain.Environmental `json:"environmental,omitempty"`
+	Version       int64                 `json:"version,omitempty"`
+}
+
+// NewSessionLifecycleService creates a new session lifecycle service
+func NewSessionLifecycleService(
+	stateManager *SessionStateManager,
+	evpRepo domain.EVPRepository,
+	fileRepo domain.FileRepository,
+) *SessionLifecycleService {
+	return &SessionLifecycleService{
+		stateManager: stateManager,
+		evpRepo:      evpRepo,
+		fileRepo:     fileRepo,
+	}
+}
+
+// SetAccessService enables per-session access control: the owner may
+// archive and delete a session, its team may change it and guests may
+// read its history
+func (s *SessionLifecycleService) SetAccessService(accessService *AccessService) {
+	s.accessService = accessService
+}
+
+// PauseSession pauses an active session
+func (s *SessionLifecycleService) PauseSession(ctx context.Context, sessionID string) (*domain.Session, error) {
+	return s.apply(ctx, sessionID, domain.AccessTeam, s.stateManager.PauseSession)
+}
+
+// ResumeSession resumes a paused session
+func (s *SessionLifecycleService) ResumeSession(ctx context.Context, sessionID string) (*domain.Session, error) {
+	return s.apply(ctx, sessionID, domain.AccessTeam, s.stateManager.ResumeSession)
+}
+
+// CompleteSession ends an active or paused session
+func (s *SessionLifecycleService) CompleteSession(ctx context.Context, sessionID string) (*domain.Session, error) {
+	return s.apply(ctx, sessionID, domain.AccessTeam, s.stateManager.CompleteSession)
+}
+
+// ArchiveSession archives a complete session
+func (s *SessionLifecycleService) ArchiveSession(ctx context.Context, sessionID string) (*domain.Session, error) {
+	return s.apply(ctx, sessionID, domain.AccessOwner, s.stateManager.ArchiveSession)
+}
+
+// GetStatusHistory returns the lifecycle transitions of a session
+func (s *SessionLifecycleService) GetStatusHistory(ctx context.Context, sessionID string) ([]*domain.SessionStatusChange, error) {
+	if err := s.authorize(ctx, sessionID, domain.AccessGuest); err != nil {
+		return nil, err
+	}
+	return s.stateManager.GetStatusHistory(ctx, sessionID)
+}
+
+// UpdateSession changes the title, notes, location or environmental
+// conditions of a session. Archived sessions are read-only. Without a
+// version the changes apply to the latest copy.
+func (s *SessionLifecycleService) UpdateSession(ctx context.Context, sessionID string, req UpdateSessionRequest) (*domain.Session, error) {
+	if req.Title != nil && strings.TrimSpace(*req.Title) == "" {
+		return nil, fmt.Errorf("invalid session update: title must not be empty")
+	}
+
+	session, err := s.stateManager.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, sessionID, domain.AccessTeam); err != nil {
+		return nil, err
+	}
+
+	if session.Status == domain.SessionStatusArchived {
+		return nil, fmt.Errorf("session %s is archived and read-only", sessionID)
+	}
+	if req.Version != 0 && req.Version != session.Version {
+		return nil, sessionConflict(session)
+	}
+
+	updated := *session
+	if req.Title != nil {
+		updated.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.Notes != nil {
+		updated.Notes = *req.Notes
+	}
+	if req.Location != nil {
+		updated.Location = *req.Location
+	}
+	if req.Environmental != nil {
+		updated.Environmental = *req.Environmental
+	}
+
+	if err := s.stateManager.UpdateSession(ctx, &updated); err != nil {
+		if errors.Is(err, domain.ErrVersionConflict) {
+			if current, getErr := s.stateManager.GetSession(ctx, sessionID); getErr == nil {
+				return nil, sessionConflict(current)
+			}
+		}
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// sessionConflict reports an update to an outdated copy of a session
+func sessionConflict(current *domain.Session) error {
+	return &domain.VersionConflictError{Entity: "session", ID: current.ID, Version: current.Version, Current: current}
+}
+
+// DeleteSession deletes a session that is not active, along with its
+// events, the EVP audio files they reference and the other files stored
+// for it
+func (s *SessionLifecycleService) DeleteSession(ctx context.Context, sessionID string) error {
+	session, err := s.stateManager.GetSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if err := s.authorize(ctx, sessionID, domain.AccessOwner); err != nil {
+		return err
+	}
+
+	if session.Status == domain.SessionStatusActive {
+		return fmt.Errorf("session %s is still active; pause or complete it before deleting", sessionID)
+	}
+
+	// Collect file paths first; deleting the session cascades to the EVPs
+	// and to the session's rows in files
+	evps, err := s.evpRepo.GetBySessionID(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to get EVP recordings: %w", err)
+	}
+	sessionFiles, err := s.fileRepo.ListSessionFiles(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to get session files: %w", err)
+	}
+
+	var paths []string
+	for _, evp := range evps {
+		paths = append(paths, evp.FilePath, evp.ProcessedPath)
+	}
+	paths = append(paths, sessionFiles...)
+
+	if err := s.stateManager.DeleteSession(ctx, sessionID); err != nil {
+		return err
+	}
+
+	deleted := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		if path == "" || deleted[path] {
+			continue
+		}
+		deleted[path] = true
+		// The session is already gone; a leftover file is picked up by cleanup
+		if exists, _ := s.fileRepo.FileExists(ctx, path); exists {
+			if err := s.fileRepo.DeleteFile(ctx, path); err != nil {
+				log.Printf("Failed to delete file %s of session %s: %v", path, sessionID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// apply runs a lifecycle transition and returns the updated session
+func (s *SessionLifecycleService) apply(ctx context.Context, sessionID string, required domain.AccessLevel, transition func(context.Context, string) error) (*domain.Session, error) {
+	if err := s.authorize(ctx, sessionID, required); err != nil {
+		return nil, err
+	}
+	if err := transition(ctx, sessionID); err != nil {
+		return nil, err
+	}
+
+	return s.stateManager.GetSession(ctx, sessionID)
+}
+
+// authorize checks the caller's access to a session when access control is enabled
+func (s *SessionLifecycleService) authorize(ctx context.Context, sessionID string, required domain.AccessLevel) error {
+	if s.accessService == nil {
+		return nil
+	}
+	return s.accessService.AuthorizeSession(ctx, sessionID, required)
+}
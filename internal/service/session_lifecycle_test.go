@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3" // SQLite driver
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/myideascope/otherside/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// setupLifecycleDB returns a migrated in-memory database enforcing foreign
+// keys, as the server's database does
+func setupLifecycleDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", ":memory:?_foreign_keys=on")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	migrator := repository.NewMigrator(db, "../repository/migrations")
+	require.NoError(t, migrator.Initialize(context.Background()))
+	require.NoError(t, migrator.Up(context.Background()))
+
+	return db
+}
+
+// setupLifecycleManager returns a state manager over a migrated in-memory
+// database holding one active session
+func setupLifecycleManager(t *testing.T) (*SessionStateManager, *domain.Session) {
+	sm := NewSessionStateManager(repository.NewSQLiteSessionRepository(setupLifecycleDB(t)))
+	session := &domain.Session{
+		ID:        "session-1",
+		Title:     "Cellar",
+		StartTime: time.Now(),
+	}
+	require.NoError(t, sm.CreateSession(context.Background(), session))
+
+	return sm, session
+}
+
+func TestSessionStateManager_Lifecycle_RecordsEachTransition(t *testing.T) {
+	// Arrange
+	sm, session := setupLifecycleManager(t)
+	ctx := context.Background()
+
+	// Act
+	require.NoError(t, sm.PauseSession(ctx, session.ID))
+	require.NoError(t, sm.ResumeSession(ctx, session.ID))
+	require.NoError(t, sm.CompleteSession(ctx, session.ID))
+	require.NoError(t, sm.ArchiveSession(ctx, session.ID))
+
+	// Assert
+	stored, err := sm.GetSession(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.SessionStatusArchived, stored.Status)
+	assert.NotNil(t, stored.EndTime)
+	assert.Empty(t, sm.GetActiveSessions())
+
+	history, err := sm.GetStatusHistory(ctx, session.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 4)
+	assert.Equal(t, domain.SessionStatusActive, history[0].FromStatus)
+	assert.Equal(t, domain.SessionStatusPaused, history[0].ToStatus)
+	assert.Equal(t, domain.SessionStatusComplete, history[3].FromStatus)
+	assert.Equal(t, domain.SessionStatusArchived, history[3].ToStatus)
+}
+
+func TestSessionStateManager_ResumeSession_ArchivedSession_ReturnsTransitionError(t *testing.T) {
+	// Arrange
+	sm, session := setupLifecycleManager(t)
+	ctx := context.Background()
+	require.NoError(t, sm.CompleteSession(ctx, session.ID))
+	require.NoError(t, sm.ArchiveSession(ctx, session.ID))
+
+	// Act
+	err := sm.ResumeSession(ctx, session.ID)
+
+	// Assert
+	var transitionErr *domain.SessionTransitionError
+	require.True(t, errors.As(err, &transitionErr))
+	assert.Equal(t, domain.SessionStatusArchived, transitionErr.From)
+	assert.Equal(t, domain.SessionStatusActive, transitionErr.To)
+
+	stored, err := sm.GetSession(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.SessionStatusArchived, stored.Status)
+}
+
+func TestSessionStateManager_PauseSession_AlreadyPaused_NoOp(t *testing.T) {
+	// Arrange
+	sm, session := setupLifecycleManager(t)
+	ctx := context.Background()
+	require.NoError(t, sm.PauseSession(ctx, session.ID))
+
+	// Act
+	err := sm.PauseSession(ctx, session.ID)
+
+	// Assert
+	require.NoError(t, err)
+	history, err := sm.GetStatusHistory(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Len(t, history, 1)
+}
+
+func TestSessionStateManager_UpdateSession_StatusChange_Ignored(t *testing.T) {
+	// Arrange
+	sm, session := setupLifecycleManager(t)
+	ctx := context.Background()
+
+	// Act
+	update := *session
+	update.Title = "Attic"
+	update.Status = domain.SessionStatusArchived
+	err := sm.UpdateSession(ctx, &update)
+
+	// Assert
+	require.NoError(t, err)
+	stored, err := sm.GetSession(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Attic", stored.Title)
+	assert.Equal(t, domain.SessionStatusActive, stored.Status)
+}
+
+func TestSessionStateManager_CleanupExpiredSessions_ArchivesThroughComplete(t *testing.T) {
+	// Arrange
+	sm, session := setupLifecycleManager(t)
+	ctx := context.Background()
+
+	// Act
+	err := sm.CleanupExpiredSessions(ctx, -time.Second)
+
+	// Assert
+	require.NoError(t, err)
+	history, err := sm.GetStatusHistory(ctx, session.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, domain.SessionStatusComplete, history[0].ToStatus)
+	assert.Equal(t, domain.SessionStatusArchived, history[1].ToStatus)
+	assert.Equal(t, "expired", history[1].Reason)
+}
+
+func TestSessionService_IngestMethods_PausedSession_ReturnNotActive(t *testing.T) {
+	// Arrange
+	mockSessionRepo := new(MockSessionRepository)
+	service := NewSessionService(mockSessionRepo, nil, nil, nil, nil, nil, nil, nil, nil)
+	ctx := context.Background()
+
+	paused := &domain.Session{ID: "session-1", Status: domain.SessionStatusPaused}
+	mockSessionRepo.On("GetByID", ctx, "session-1").Return(paused, nil)
+
+	// Act
+	_, evpErr := service.ProcessEVPRecording(ctx, "session-1", []float64{0.1}, EVPMetadata{})
+	_, voxErr := service.GenerateVOXCommunication(ctx, "session-1", VOXTriggerData{})
+	_, radarErr := service.ProcessRadarEvent(ctx, "session-1", RadarEventData{})
+	_, slsErr := service.ProcessSLSDetection(ctx, "session-1", SLSDetectionData{})
+	_, interactionErr := service.RecordUserInteraction(ctx, "session-1", UserInteractionData{})
+
+	// Assert
+	for _, err := range []error{evpErr, voxErr, radarErr, slsErr, interactionErr} {
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "session is not active")
+	}
+	mockSessionRepo.AssertNumberOfCalls(t, "GetByID", 5)
+	mockSessionRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestSessionLifecycleService_DeleteSession_CompleteSession_RemovesFiles(t *testing.T) {
+	// Arrange
+	sm, session := setupLifecycleManager(t)
+	ctx := context.Background()
+	require.NoError(t, sm.CompleteSession(ctx, session.ID))
+
+	mockEVPRepo := new(MockEVPRepository)
+	mockFileRepo := new(MockFileRepository)
+	service := NewSessionLifecycleService(sm, mockEVPRepo, mockFileRepo)
+
+	mockEVPRepo.On("GetBySessionID", ctx, session.ID).Return([]*domain.EVPRecording{
+		{ID: "evp-1", FilePath: "evp/one.wav", ProcessedPath: "evp/one-clean.wav"},
+	}, nil)
+	mockFileRepo.On("ListSessionFiles", ctx, session.ID).Return([]string{"evp/one.wav", "sessions/session-1/photo.jpg"}, nil)
+	mockFileRepo.On("FileExists", ctx, "evp/one.wav").Return(true, nil).Once()
+	mockFileRepo.On("FileExists", ctx, "evp/one-clean.wav").Return(false, nil)
+	mockFileRepo.On("FileExists", ctx, "sessions/session-1/photo.jpg").Return(true, nil)
+	mockFileRepo.On("DeleteFile", ctx, "evp/one.wav").Return(nil).Once()
+	mockFileRepo.On("DeleteFile", ctx, "sessions/session-1/photo.jpg").Return(nil)
+
+	// Act
+	err := service.DeleteSession(ctx, session.ID)
+
+	// Assert
+	require.NoError(t, err)
+	_, err = sm.GetSession(ctx, session.ID)
+	assert.Contains(t, err.Error(), "not found")
+	mockFileRepo.AssertExpectations(t)
+	mockFileRepo.AssertNotCalled(t, "DeleteFile", ctx, "evp/one-clean.wav")
+}
+
+func TestSessionLifecycleService_DeleteSession_ActiveSession_ReturnsError(t *testing.T) {
+	// Arrange
+	sm, session := setupLifecycleManager(t)
+	service := NewSessionLifecycleService(sm, new(MockEVPRepository), new(MockFileRepository))
+
+	// Act
+	err := service.DeleteSession(context.Background(), session.ID)
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "still active")
+	_, err = sm.GetSession(context.Background(), session.ID)
+	assert.NoError(t, err)
+}
+
+func TestSessionLifecycleService_UpdateSession_ArchivedSession_ReturnsError(t *testing.T) {
+	// Arrange
+	sm, session := setupLifecycleManager(t)
+	ctx := context.Background()
+	require.NoError(t, sm.CompleteSession(ctx, session.ID))
+	require.NoError(t, sm.ArchiveSession(ctx, session.ID))
+	service := NewSessionLifecycleService(sm, new(MockEVPRepository), new(MockFileRepository))
+
+	title := "Attic"
+
+	// Act
+	updated, err := service.UpdateSession(ctx, session.ID, UpdateSessionRequest{Title: &title})
+
+	// Assert
+	require.Error(t, err)
+	assert.Nil(t, updated)
+	assert.Contains(t, err.Error(), "read-only")
+}
+
+func TestSessionLifecycleService_UpdateSession_PartialUpdate_KeepsOtherFields(t *testing.T) {
+	// Arrange
+	sm, session := setupLifecycleManager(t)
+	ctx := context.Background()
+	service := NewSessionLifecycleService(sm, new(MockEVPRepository), new(MockFileRepository))
+
+	notes := "Cold spot near the stairs"
+
+	// Act
+	updated, err := service.UpdateSession(ctx, session.ID, UpdateSessionRequest{Notes: &notes})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "Cellar", updated.Title)
+	assert.Equal(t, notes, updated.Notes)
+
+	stored, err := sm.GetSession(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, notes, stored.Notes)
+	assert.Equal(t, domain.SessionStatusActive, stored.Status)
+}
+
+func TestSessionLifecycleService_UpdateSession_StaleVersion_ReturnsCurrentCopy(t *testing.T) {
+	// Arrange
+	sm, session := setupLifecycleManager(t)
+	ctx := context.Background()
+	service := NewSessionLifecycleService(sm, new(MockEVPRepository), new(MockFileRepository))
+
+	first := "Cold spot near the stairs"
+	second := "Nothing on the stairs"
+	edited, err := service.UpdateSession(ctx, session.ID, UpdateSessionRequest{Notes: &first, Version: session.Version})
+	require.NoError(t, err)
+
+	// Act
+	updated, err := service.UpdateSession(ctx, session.ID, UpdateSessionRequest{Notes: &second, Version: session.Version})
+
+	// Assert
+	assert.Nil(t, updated)
+	var conflict *domain.VersionConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, edited.Version, conflict.Version)
+	current, ok := conflict.Current.(*domain.Session)
+	require.True(t, ok)
+	assert.Equal(t, first, current.Notes)
+	assert.Equal(t, session.Version+1, edited.Version)
+}
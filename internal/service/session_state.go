@@ -3,32 +3,40 @@ package service
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"sync"
 	"time"
 
 	"github.com/myideascope/otherside/internal/domain"
-	"github.com/myideascope/otherside/internal/repository"
 )
 
-// SessionStateManager manages session state persistence across restarts
+// SessionStateManager caches active and paused sessions in front of the
+// session repository and owns their lifecycle transitions. It implements
+// domain.SessionRepository, so services read and write sessions through it
+// and see status changes as soon as they are made. Sessions are copied on
+// the way in and out; callers never share the cached values.
 type SessionStateManager struct {
-	sessionRepo    *repository.SQLiteSessionRepository
+	sessionRepo    domain.SessionRepository
 	activeSessions map[string]*domain.Session
+	webhookService *WebhookService
 	mu             sync.RWMutex
-	db             *sql.DB
 }
 
 // NewSessionStateManager creates a new session state manager
-func NewSessionStateManager(db *sql.DB) *SessionStateManager {
+func NewSessionStateManager(sessionRepo domain.SessionRepository) *SessionStateManager {
 	return &SessionStateManager{
-		sessionRepo:    repository.NewSQLiteSessionRepository(db),
+		sessionRepo:    sessionRepo,
 		activeSessions: make(map[string]*domain.Session),
-		db:             db,
 	}
 }
 
+// SetWebhookService sends completed sessions to webhook subscribers
+func (sm *SessionStateManager) SetWebhookService(webhookService *WebhookService) {
+	sm.webhookService = webhookService
+}
+
 // Initialize loads active sessions from database
 func (sm *SessionStateManager) Initialize(ctx context.Context) error {
 	log.Println("Loading active sessions from database...")
@@ -59,159 +67,344 @@ func (sm *SessionStateManager) Initialize(ctx context.Context) error {
 	return nil
 }
 
-// CreateSession creates and persists a new session
+// CreateSession creates and persists a new active session
 func (sm *SessionStateManager) CreateSession(ctx context.Context, session *domain.Session) error {
-	sm.mu.Lock()
-	defer sm.mu.Unlock()
-
 	// Set timestamps
 	now := time.Now()
 	session.CreatedAt = now
 	session.UpdatedAt = now
 	session.Status = domain.SessionStatusActive
 
-	// Persist to database
-	if err := sm.sessionRepo.Create(ctx, session); err != nil {
+	if err := sm.Create(ctx, session); err != nil {
 		return fmt.Errorf("failed to create session in database: %w", err)
 	}
 
-	// Add to active sessions
-	sm.activeSessions[session.ID] = session
 	log.Printf("Created new session: %s", session.ID)
-
 	return nil
 }
 
-// GetSession retrieves a session by ID (first from memory, then from database)
+// GetSession retrieves a copy of a session, from memory when cached
 func (sm *SessionStateManager) GetSession(ctx context.Context, id string) (*domain.Session, error) {
-	// Try memory first
+	session, err := sm.GetByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session from database: %w", err)
+	}
+
+	return session, nil
+}
+
+// UpdateSession persists the descriptive fields of a session. Status and
+// end time keep their stored values; they only change through the
+// lifecycle methods below.
+func (sm *SessionStateManager) UpdateSession(ctx context.Context, session *domain.Session) error {
+	if _, err := sm.GetSession(ctx, session.ID); err != nil {
+		return err
+	}
+
+	if err := sm.Update(ctx, session); err != nil {
+		return fmt.Errorf("failed to update session in database: %w", err)
+	}
+
+	log.Printf("Updated session: %s", session.ID)
+	return nil
+}
+
+// Create persists a session and caches it when active or paused
+func (sm *SessionStateManager) Create(ctx context.Context, session *domain.Session) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if err := sm.sessionRepo.Create(ctx, session); err != nil {
+		return err
+	}
+
+	sm.cacheLocked(session)
+	return nil
+}
+
+// GetByID returns a copy of a session. Cache misses are loaded under the
+// write lock so a concurrent transition cannot be overwritten by a stale read.
+func (sm *SessionStateManager) GetByID(ctx context.Context, id string) (*domain.Session, error) {
 	sm.mu.RLock()
 	if session, exists := sm.activeSessions[id]; exists {
 		sm.mu.RUnlock()
-		return session, nil
+		return copySession(session), nil
 	}
 	sm.mu.RUnlock()
 
-	// Load from database
-	session, err := sm.sessionRepo.GetByID(ctx, id)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get session from database: %w", err)
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if session, exists := sm.activeSessions[id]; exists {
+		return copySession(session), nil
 	}
 
-	// Cache if active or paused
-	if session.Status == domain.SessionStatusActive || session.Status == domain.SessionStatusPaused {
-		sm.mu.Lock()
-		sm.activeSessions[id] = session
-		sm.mu.Unlock()
+	session, err := sm.sessionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
 	}
 
+	sm.cacheLocked(session)
 	return session, nil
 }
 
-// UpdateSession updates and persists a session
-func (sm *SessionStateManager) UpdateSession(ctx context.Context, session *domain.Session) error {
+// GetAll lists sessions from the repository, which the cache writes through to
+func (sm *SessionStateManager) GetAll(ctx context.Context, limit, offset int) ([]*domain.Session, error) {
+	return sm.sessionRepo.GetAll(ctx, limit, offset)
+}
+
+// GetByStatus lists sessions in a status from the repository
+func (sm *SessionStateManager) GetByStatus(ctx context.Context, status domain.SessionStatus) ([]*domain.Session, error) {
+	return sm.sessionRepo.GetByStatus(ctx, status)
+}
+
+// GetByDateRange lists sessions started within a range from the repository
+func (sm *SessionStateManager) GetByDateRange(ctx context.Context, start, end time.Time) ([]*domain.Session, error) {
+	return sm.sessionRepo.GetByDateRange(ctx, start, end)
+}
+
+// Update persists the descriptive fields of a session, keeping its stored
+// status and end time. A lost version check evicts the cached copy.
+func (sm *SessionStateManager) Update(ctx context.Context, session *domain.Session) error {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
-	// Update timestamp
-	session.UpdatedAt = time.Now()
+	current, exists := sm.activeSessions[session.ID]
+	if !exists {
+		stored, err := sm.sessionRepo.GetByID(ctx, session.ID)
+		if err != nil {
+			return err
+		}
+		current = stored
+	}
+	session.Status = current.Status
+	session.EndTime = current.EndTime
 
-	// Persist to database
 	if err := sm.sessionRepo.Update(ctx, session); err != nil {
-		return fmt.Errorf("failed to update session in database: %w", err)
+		if errors.Is(err, domain.ErrVersionConflict) {
+			delete(sm.activeSessions, session.ID)
+		}
+		return err
 	}
 
-	// Update in memory
-	if _, exists := sm.activeSessions[session.ID]; exists {
-		sm.activeSessions[session.ID] = session
-	}
+	sm.cacheLocked(session)
+	return nil
+}
 
-	// If session is completed or archived, remove from active sessions
-	if session.Status == domain.SessionStatusComplete || session.Status == domain.SessionStatusArchived {
-		delete(sm.activeSessions, session.ID)
+// Delete removes a session from the repository and the cache
+func (sm *SessionStateManager) Delete(ctx context.Context, id string) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if err := sm.sessionRepo.Delete(ctx, id); err != nil {
+		return err
 	}
 
-	log.Printf("Updated session: %s (status: %s)", session.ID, session.Status)
+	delete(sm.activeSessions, id)
 	return nil
 }
 
-// PauseSession pauses a session
-func (sm *SessionStateManager) PauseSession(ctx context.Context, sessionID string) error {
-	session, err := sm.GetSession(ctx, sessionID)
-	if err != nil {
-		return err
+// UpdateStatus writes a status change through to the repository and
+// refreshes the cache. A lost compare-and-set evicts the cached copy.
+func (sm *SessionStateManager) UpdateStatus(ctx context.Context, session *domain.Session, change *domain.SessionStatusChange) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	return sm.updateStatusLocked(ctx, session, change)
+}
+
+// Touch records activity on a session. Cached sessions only have their
+// in-memory updated time moved forward; SaveSessionState persists it.
+func (sm *SessionStateManager) Touch(ctx context.Context, id string, at time.Time) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if session, exists := sm.activeSessions[id]; exists {
+		if at.After(session.UpdatedAt) {
+			session.UpdatedAt = at
+		}
+		return nil
 	}
 
-	session.Status = domain.SessionStatusPaused
-	return sm.UpdateSession(ctx, session)
+	return sm.sessionRepo.Touch(ctx, id, at)
+}
+
+// PauseSession pauses an active session
+func (sm *SessionStateManager) PauseSession(ctx context.Context, sessionID string) error {
+	return sm.transition(ctx, sessionID, domain.SessionStatusPaused, "paused")
 }
 
 // ResumeSession resumes a paused session
 func (sm *SessionStateManager) ResumeSession(ctx context.Context, sessionID string) error {
-	session, err := sm.GetSession(ctx, sessionID)
-	if err != nil {
-		return err
+	return sm.transition(ctx, sessionID, domain.SessionStatusActive, "resumed")
+}
+
+// CompleteSession marks an active or paused session as complete
+func (sm *SessionStateManager) CompleteSession(ctx context.Context, sessionID string) error {
+	return sm.transition(ctx, sessionID, domain.SessionStatusComplete, "completed")
+}
+
+// ArchiveSession archives a complete session
+func (sm *SessionStateManager) ArchiveSession(ctx context.Context, sessionID string) error {
+	return sm.transition(ctx, sessionID, domain.SessionStatusArchived, "archived")
+}
+
+// GetStatusHistory returns the lifecycle transitions of a session, oldest first
+func (sm *SessionStateManager) GetStatusHistory(ctx context.Context, sessionID string) ([]*domain.SessionStatusChange, error) {
+	if _, err := sm.GetSession(ctx, sessionID); err != nil {
+		return nil, err
 	}
 
-	session.Status = domain.SessionStatusActive
-	return sm.UpdateSession(ctx, session)
+	return sm.sessionRepo.GetStatusHistory(ctx, sessionID)
 }
 
-// CompleteSession marks a session as complete
-func (sm *SessionStateManager) CompleteSession(ctx context.Context, sessionID string) error {
+// transition moves a session along the lifecycle. Moving to the status the
+// session already has is a no-op, so retried requests succeed.
+func (sm *SessionStateManager) transition(ctx context.Context, sessionID string, to domain.SessionStatus, reason string) error {
 	session, err := sm.GetSession(ctx, sessionID)
 	if err != nil {
 		return err
 	}
 
+	// Webhooks check access through the manager, so they go out once the
+	// lock is released
+	var updated *domain.Session
+	defer func() {
+		if updated != nil && updated.Status == domain.SessionStatusComplete && session.Status != domain.SessionStatusComplete {
+			sm.notifyCompleted(ctx, updated)
+		}
+	}()
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	// Another transition may have won the lock first
+	if cached, exists := sm.activeSessions[sessionID]; exists {
+		session = copySession(cached)
+	}
+
+	updated, err = sm.transitionLocked(ctx, session, to, reason)
+	return err
+}
+
+// notifyCompleted sends completed sessions to webhook subscribers
+func (sm *SessionStateManager) notifyCompleted(ctx context.Context, sessions ...*domain.Session) {
+	if sm.webhookService == nil {
+		return
+	}
+	for _, session := range sessions {
+		sm.webhookService.Notify(ctx, WebhookEventSessionCompleted, []string{session.ID}, session)
+	}
+}
+
+// transitionLocked applies one transition and returns the updated session;
+// the caller holds sm.mu
+func (sm *SessionStateManager) transitionLocked(ctx context.Context, session *domain.Session, to domain.SessionStatus, reason string) (*domain.Session, error) {
+	from := session.Status
+	if from == to {
+		return session, nil
+	}
+	if !from.CanTransitionTo(to) {
+		return nil, &domain.SessionTransitionError{SessionID: session.ID, From: from, To: to}
+	}
+
 	now := time.Now()
-	session.EndTime = &now
-	session.Status = domain.SessionStatusComplete
-	return sm.UpdateSession(ctx, session)
+	updated := *session
+	updated.Status = to
+	updated.UpdatedAt = now
+	if to == domain.SessionStatusComplete {
+		updated.EndTime = &now
+	}
+
+	change := &domain.SessionStatusChange{
+		ID:         generateID(),
+		SessionID:  session.ID,
+		FromStatus: from,
+		ToStatus:   to,
+		Reason:     reason,
+		ChangedAt:  now,
+	}
+
+	if err := sm.updateStatusLocked(ctx, &updated, change); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to update session status: %w", err)
+		}
+
+		// The stored status moved on underneath the cache
+		current, getErr := sm.sessionRepo.GetByID(ctx, session.ID)
+		if getErr != nil {
+			return nil, fmt.Errorf("session not found: %w", getErr)
+		}
+		return nil, &domain.SessionTransitionError{SessionID: session.ID, From: current.Status, To: to}
+	}
+
+	log.Printf("Session %s moved from %s to %s", session.ID, from, to)
+	return &updated, nil
 }
 
-// ArchiveSession archives a session
-func (sm *SessionStateManager) ArchiveSession(ctx context.Context, sessionID string) error {
-	session, err := sm.GetSession(ctx, sessionID)
-	if err != nil {
+// updateStatusLocked writes a status change and refreshes the cache; the
+// caller holds sm.mu
+func (sm *SessionStateManager) updateStatusLocked(ctx context.Context, session *domain.Session, change *domain.SessionStatusChange) error {
+	if err := sm.sessionRepo.UpdateStatus(ctx, session, change); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			delete(sm.activeSessions, session.ID)
+		}
 		return err
 	}
 
-	session.Status = domain.SessionStatusArchived
-	return sm.UpdateSession(ctx, session)
+	stored := copySession(session)
+	stored.Status = change.ToStatus
+	sm.cacheLocked(stored)
+	return nil
+}
+
+// cacheLocked stores a copy of an active or paused session and evicts any
+// other; the caller holds sm.mu
+func (sm *SessionStateManager) cacheLocked(session *domain.Session) {
+	if session.Status == domain.SessionStatusActive || session.Status == domain.SessionStatusPaused {
+		sm.activeSessions[session.ID] = copySession(session)
+	} else {
+		delete(sm.activeSessions, session.ID)
+	}
+}
+
+// copySession copies a session so cached values are never shared
+func copySession(session *domain.Session) *domain.Session {
+	copied := *session
+	if session.EndTime != nil {
+		endTime := *session.EndTime
+		copied.EndTime = &endTime
+	}
+	return &copied
 }
 
 // DeleteSession deletes a session completely
 func (sm *SessionStateManager) DeleteSession(ctx context.Context, sessionID string) error {
-	sm.mu.Lock()
-	defer sm.mu.Unlock()
-
-	// Delete from database
-	if err := sm.sessionRepo.Delete(ctx, sessionID); err != nil {
+	if err := sm.Delete(ctx, sessionID); err != nil {
 		return fmt.Errorf("failed to delete session from database: %w", err)
 	}
 
-	// Remove from memory
-	delete(sm.activeSessions, sessionID)
 	log.Printf("Deleted session: %s", sessionID)
-
 	return nil
 }
 
-// GetActiveSessions returns all active sessions
+// GetActiveSessions returns copies of all cached sessions
 func (sm *SessionStateManager) GetActiveSessions() []*domain.Session {
 	sm.mu.RLock()
 	defer sm.mu.RUnlock()
 
 	sessions := make([]*domain.Session, 0, len(sm.activeSessions))
 	for _, session := range sm.activeSessions {
-		sessions = append(sessions, session)
+		sessions = append(sessions, copySession(session))
 	}
 
 	return sessions
 }
 
-// GetActiveSessionsByStatus returns sessions by status
+// GetActiveSessionsByStatus returns copies of the cached sessions in a status
 func (sm *SessionStateManager) GetActiveSessionsByStatus(status domain.SessionStatus) []*domain.Session {
 	sm.mu.RLock()
 	defer sm.mu.RUnlock()
@@ -219,14 +412,16 @@ func (sm *SessionStateManager) GetActiveSessionsByStatus(status domain.SessionSt
 	var sessions []*domain.Session
 	for _, session := range sm.activeSessions {
 		if session.Status == status {
-			sessions = append(sessions, session)
+			sessions = append(sessions, copySession(session))
 		}
 	}
 
 	return sessions
 }
 
-// SaveSessionState saves the current state of all active sessions
+// SaveSessionState persists the last activity time of every cached session.
+// Everything else is written through as it changes, so this is all that a
+// restart would otherwise lose; inactivity expiry picks up from it.
 func (sm *SessionStateManager) SaveSessionState(ctx context.Context) error {
 	sm.mu.RLock()
 	defer sm.mu.RUnlock()
@@ -234,7 +429,7 @@ func (sm *SessionStateManager) SaveSessionState(ctx context.Context) error {
 	log.Printf("Saving state for %d active sessions", len(sm.activeSessions))
 
 	for _, session := range sm.activeSessions {
-		if err := sm.sessionRepo.Update(ctx, session); err != nil {
+		if err := sm.sessionRepo.Touch(ctx, session.ID, session.UpdatedAt); err != nil {
 			log.Printf("Failed to save session %s: %v", session.ID, err)
 			return fmt.Errorf("failed to save session %s: %w", session.ID, err)
 		}
@@ -244,41 +439,49 @@ func (sm *SessionStateManager) SaveSessionState(ctx context.Context) error {
 	return nil
 }
 
-// CleanupExpiredSessions cleans up sessions that have been inactive for too long
+// CleanupExpiredSessions completes and archives sessions that have seen no
+// activity for longer than maxInactiveDuration
 func (sm *SessionStateManager) CleanupExpiredSessions(ctx context.Context, maxInactiveDuration time.Duration) error {
+	var completedSessions []*domain.Session
+	defer func() { sm.notifyCompleted(ctx, completedSessions...) }()
+
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
 	now := time.Now()
-	var expiredSessions []string
+	var expiredSessions []*domain.Session
 
-	for id, session := range sm.activeSessions {
+	for _, session := range sm.activeSessions {
 		inactiveDuration := now.Sub(session.UpdatedAt)
 
 		// Consider sessions expired if inactive for too long and not already completed
 		if inactiveDuration > maxInactiveDuration &&
 			(session.Status == domain.SessionStatusActive || session.Status == domain.SessionStatusPaused) {
-			expiredSessions = append(expiredSessions, id)
+			expiredSessions = append(expiredSessions, session)
 		}
 	}
 
-	// Mark expired sessions as archived
-	for _, id := range expiredSessions {
-		session := sm.activeSessions[id]
-		session.Status = domain.SessionStatusArchived
-		session.UpdatedAt = now
+	// Expired sessions pass through complete on their way to archived
+	archived := 0
+	for _, session := range expiredSessions {
+		completed, err := sm.transitionLocked(ctx, copySession(session), domain.SessionStatusComplete, "expired")
+		if err != nil {
+			log.Printf("Failed to complete expired session %s: %v", session.ID, err)
+			continue
+		}
+		completedSessions = append(completedSessions, completed)
 
-		if err := sm.sessionRepo.Update(ctx, session); err != nil {
-			log.Printf("Failed to archive expired session %s: %v", id, err)
+		if _, err := sm.transitionLocked(ctx, completed, domain.SessionStatusArchived, "expired"); err != nil {
+			log.Printf("Failed to archive expired session %s: %v", session.ID, err)
 			continue
 		}
 
-		delete(sm.activeSessions, id)
-		log.Printf("Archived expired session: %s (inactive for %v)", id, maxInactiveDuration)
+		archived++
+		log.Printf("Archived expired session: %s (inactive for %v)", session.ID, maxInactiveDuration)
 	}
 
-	if len(expiredSessions) > 0 {
-		log.Printf("Cleaned up %d expired sessions", len(expiredSessions))
+	if archived > 0 {
+		log.Printf("Cleaned up %d expired sessions", archived)
 	}
 
 	return nil
@@ -8,6 +8,7 @@ import (
 
 	_ "github.com/mattn/go-sqlite3" // SQLite driver
 	"github.com/myideascope/otherside/internal/domain"
+	"github.com/myideascope/otherside/internal/repository"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -45,7 +46,7 @@ func TestSessionStateManager_Initialize_LoadsActiveAndPausedSessions_Success(t *
 	`)
 	require.NoError(t, err)
 
-	sm := NewSessionStateManager(db)
+	sm := NewSessionStateManager(repository.NewSQLiteSessionRepository(db))
 
 	// Act
 	err = sm.Initialize(context.Background())
@@ -81,7 +82,7 @@ func TestSessionStateManager_CreateSession_ValidSession_Success(t *testing.T) {
 	`)
 	require.NoError(t, err)
 
-	sm := NewSessionStateManager(db)
+	sm := NewSessionStateManager(repository.NewSQLiteSessionRepository(db))
 	err = sm.Initialize(context.Background())
 	require.NoError(t, err)
 
@@ -125,7 +126,7 @@ func TestSessionStateManager_GetSession_InMemory_Success(t *testing.T) {
 	`)
 	require.NoError(t, err)
 
-	sm := NewSessionStateManager(db)
+	sm := NewSessionStateManager(repository.NewSQLiteSessionRepository(db))
 	err = sm.Initialize(context.Background())
 	require.NoError(t, err)
 
@@ -173,7 +174,7 @@ func TestSessionStateManager_GetSession_FromDatabase_Success(t *testing.T) {
 	`)
 	require.NoError(t, err)
 
-	sm := NewSessionStateManager(db)
+	sm := NewSessionStateManager(repository.NewSQLiteSessionRepository(db))
 	err = sm.Initialize(context.Background())
 	require.NoError(t, err)
 
@@ -215,7 +216,7 @@ func TestSessionStateManager_UpdateSession_ValidSession_Success(t *testing.T) {
 	`)
 	require.NoError(t, err)
 
-	sm := NewSessionStateManager(db)
+	sm := NewSessionStateManager(repository.NewSQLiteSessionRepository(db))
 	err = sm.Initialize(context.Background())
 	require.NoError(t, err)
 
@@ -269,7 +270,7 @@ func TestSessionStateManager_PauseSession_ActiveSession_Success(t *testing.T) {
 	`)
 	require.NoError(t, err)
 
-	sm := NewSessionStateManager(db)
+	sm := NewSessionStateManager(repository.NewSQLiteSessionRepository(db))
 	err = sm.Initialize(context.Background())
 	require.NoError(t, err)
 
@@ -312,7 +313,7 @@ func TestSessionStateManager_ResumeSession_PausedSession_Success(t *testing.T) {
 	`)
 	require.NoError(t, err)
 
-	sm := NewSessionStateManager(db)
+	sm := NewSessionStateManager(repository.NewSQLiteSessionRepository(db))
 	err = sm.Initialize(context.Background())
 	require.NoError(t, err)
 
@@ -359,7 +360,7 @@ func TestSessionStateManager_CompleteSession_ActiveSession_Success(t *testing.T)
 	`)
 	require.NoError(t, err)
 
-	sm := NewSessionStateManager(db)
+	sm := NewSessionStateManager(repository.NewSQLiteSessionRepository(db))
 	err = sm.Initialize(context.Background())
 	require.NoError(t, err)
 
@@ -403,7 +404,7 @@ func TestSessionStateManager_ArchiveSession_ActiveSession_Success(t *testing.T)
 	`)
 	require.NoError(t, err)
 
-	sm := NewSessionStateManager(db)
+	sm := NewSessionStateManager(repository.NewSQLiteSessionRepository(db))
 	err = sm.Initialize(context.Background())
 	require.NoError(t, err)
 
@@ -446,7 +447,7 @@ func TestSessionStateManager_DeleteSession_ActiveSession_Success(t *testing.T) {
 	`)
 	require.NoError(t, err)
 
-	sm := NewSessionStateManager(db)
+	sm := NewSessionStateManager(repository.NewSQLiteSessionRepository(db))
 	err = sm.Initialize(context.Background())
 	require.NoError(t, err)
 
@@ -488,7 +489,7 @@ func TestSessionStateManager_GetActiveSessionsByStatus_MixedStatuses_ReturnsFilt
 	`)
 	require.NoError(t, err)
 
-	sm := NewSessionStateManager(db)
+	sm := NewSessionStateManager(repository.NewSQLiteSessionRepository(db))
 	err = sm.Initialize(context.Background())
 	require.NoError(t, err)
 
@@ -542,7 +543,7 @@ func TestSessionStateManager_SaveSessionState_ActiveSessions_Success(t *testing.
 	`)
 	require.NoError(t, err)
 
-	sm := NewSessionStateManager(db)
+	sm := NewSessionStateManager(repository.NewSQLiteSessionRepository(db))
 	err = sm.Initialize(context.Background())
 	require.NoError(t, err)
 
@@ -588,7 +589,7 @@ func TestSessionStateManager_CleanupExpiredSessions_ExpiredSessions_Archived(t *
 	`)
 	require.NoError(t, err)
 
-	sm := NewSessionStateManager(db)
+	sm := NewSessionStateManager(repository.NewSQLiteSessionRepository(db))
 	err = sm.Initialize(context.Background())
 	require.NoError(t, err)
 
@@ -650,7 +651,7 @@ func TestSessionStateManager_Shutdown_SaveState_Success(t *testing.T) {
 	`)
 	require.NoError(t, err)
 
-	sm := NewSessionStateManager(db)
+	sm := NewSessionStateManager(repository.NewSQLiteSessionRepository(db))
 	err = sm.Initialize(context.Background())
 	require.NoError(t, err)
 
@@ -0,0 +1,418 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+)
+
+const (
+	// defaultShareLinkTTL is how long a share link works when no expiry is given
+	defaultShareLinkTTL = 7 * 24 * time.Hour
+
+	// maxShareLinkTTL bounds how long a share link can work
+	maxShareLinkTTL = 90 * 24 * time.Hour
+
+	// shareTokenAudience keeps share tokens and bearer tokens apart
+	shareTokenAudience = "share"
+)
+
+// ShareService creates revocable, expiring read-only links to a session and
+// serves the shared view to whoever holds the link. Link tokens are signed
+// like bearer tokens, so they need no account to use.
+type ShareService struct {
+	shareRepo       domain.ShareLinkRepository
+	sessionRepo     domain.SessionRepository
+	evpRepo         domain.EVPRepository
+	fileRepo        domain.FileRepository
+	timelineService *TimelineService
+	accessService   *AccessService
+	tokenSecret     []byte
+	now             func() time.Time
+}
+
+// CreateShareLinkRequest describes a new share link. EventTypes limits the
+// link to some timeline event types and EVPIDs to some EVP recordings;
+// ExpiresIn is in seconds and defaults to seven days.
+type CreateShareLinkRequest struct {
+	Name       string   `json:"name"`
+	EventTypes []string `json:"event_types,omitempty"`
+	EVPIDs     []string `json:"evp_ids,omitempty"`
+	ExpiresIn  int      `json:"expires_in,omitempty"`
+}
+
+// SharedSession is the part of a session shown through a share link.
+// Investigator notes are left out.
+type SharedSession struct {
+	ID            string               `json:"id"`
+	Title         string               `json:"title"`
+	Location      domain.Location      `json:"location"`
+	StartTime     time.Time            `json:"start_time"`
+	EndTime       *time.Time           `json:"end_time,omitempty"`
+	Status        domain.SessionStatus `json:"status"`
+	Environmental domain.Environmental `json:"environmental"`
+}
+
+// SharedView describes what a share link shows
+type SharedView struct {
+	Session    *SharedSession `json:"session"`
+	Name       string         `json:"name"`
+	EventTypes []string       `json:"event_types"`
+	ExpiresAt  time.Time      `json:"expires_at"`
+}
+
+// SharedEVP is an EVP recording shown through a share link
+type SharedEVP struct {
+	ID             string            `json:"id"`
+	Timestamp      time.Time         `json:"timestamp"`
+	Duration       float64           `json:"duration"`
+	Quality        domain.EVPQuality `json:"quality"`
+	DetectionLevel float64           `json:"detection_level"`
+	Annotations    []string          `json:"annotations,omitempty"`
+	HasAudio       bool              `json:"has_audio"`
+}
+
+// SharedReport summarises a session for the holder of a share link
+type SharedReport struct {
+	Session     *SharedSession `json:"session"`
+	EventCounts map[string]int `json:"event_counts"`
+	EVPs        []*SharedEVP   `json:"evps"`
+	GeneratedAt time.Time      `json:"generated_at"`
+	ExpiresAt   time.Time      `json:"expires_at"`
+}
+
+// shareClaims is the payload of a share link token
+type shareClaims struct {
+	LinkID    string `json:"lid"`
+	SessionID string `json:"sid"`
+	Audience  string `json:"aud"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// NewShareService creates a new share service. Link tokens are signed with
+// tokenSecret.
+func NewShareService(
+	shareRepo domain.ShareLinkRepository,
+	sessionRepo domain.SessionRepository,
+	evpRepo domain.EVPRepository,
+	fileRepo domain.FileRepository,
+	timelineService *TimelineService,
+	tokenSecret []byte,
+) *ShareService {
+	return &ShareService{
+		shareRepo:       shareRepo,
+		sessionRepo:     sessionRepo,
+		evpRepo:         evpRepo,
+		fileRepo:        fileRepo,
+		timelineService: timelineService,
+		tokenSecret:     tokenSecret,
+		now:             time.Now,
+	}
+}
+
+// SetAccessService restricts creating, listing and revoking share links to
+// the session owner
+func (s *ShareService) SetAccessService(accessService *AccessService) {
+	s.accessService = accessService
+}
+
+// CreateShareLink creates a share link to a session. The returned token is
+// not stored and cannot be recovered later.
+func (s *ShareService) CreateShareLink(ctx context.Context, sessionID string, req CreateShareLinkRequest) (*domain.ShareLink, string, error) {
+	if _, err := s.sessionRepo.GetByID(ctx, sessionID); err != nil {
+		return nil, "", fmt.Errorf("session not found: %w", err)
+	}
+	if err := s.authorize(ctx, sessionID); err != nil {
+		return nil, "", err
+	}
+
+	for _, eventType := range req.EventTypes {
+		if !isTimelineEventType(eventType) {
+			return nil, "", fmt.Errorf("invalid share link: unknown event type %s", eventType)
+		}
+	}
+	if len(req.EVPIDs) > 0 {
+		if len(req.EventTypes) > 0 && !containsString(req.EventTypes, TimelineEventEVP) {
+			return nil, "", fmt.Errorf("invalid share link: evp_ids need the evp event type")
+		}
+		for _, evpID := range req.EVPIDs {
+			evp, err := s.evpRepo.GetByID(ctx, evpID)
+			if err != nil || evp.SessionID != sessionID {
+				return nil, "", fmt.Errorf("invalid share link: EVP %s is not part of session %s", evpID, sessionID)
+			}
+		}
+	}
+
+	ttl := time.Duration(req.ExpiresIn) * time.Second
+	switch {
+	case req.ExpiresIn < 0 || ttl > maxShareLinkTTL:
+		return nil, "", fmt.Errorf("invalid share link: expires_in must be between 1 second and %d days", int(maxShareLinkTTL.Hours()/24))
+	case ttl == 0:
+		ttl = defaultShareLinkTTL
+	}
+
+	now := s.now()
+	link := &domain.ShareLink{
+		ID:         generateID(),
+		SessionID:  sessionID,
+		Name:       strings.TrimSpace(req.Name),
+		EventTypes: nonNilStrings(req.EventTypes),
+		EVPIDs:     nonNilStrings(req.EVPIDs),
+		CreatedAt:  now,
+		ExpiresAt:  time.Unix(now.Add(ttl).Unix(), 0),
+	}
+	if identity := domain.IdentityFromContext(ctx); identity != nil && identity.Kind == domain.PrincipalInvestigator {
+		link.CreatedBy = identity.ID
+	}
+
+	token, err := signToken(s.tokenSecret, shareClaims{
+		LinkID:    link.ID,
+		SessionID: sessionID,
+		Audience:  shareTokenAudience,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: link.ExpiresAt.Unix(),
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to sign share link: %w", err)
+	}
+
+	if err := s.shareRepo.Create(ctx, link); err != nil {
+		return nil, "", fmt.Errorf("failed to create share link: %w", err)
+	}
+
+	return link, token, nil
+}
+
+// ListShareLinks returns the share links of a session, including revoked
+// and expired ones
+func (s *ShareService) ListShareLinks(ctx context.Context, sessionID string) ([]*domain.ShareLink, error) {
+	if err := s.authorize(ctx, sessionID); err != nil {
+		return nil, err
+	}
+	return s.shareRepo.GetBySessionID(ctx, sessionID)
+}
+
+// RevokeShareLink stops a share link from working
+func (s *ShareService) RevokeShareLink(ctx context.Context, sessionID, linkID string) error {
+	link, err := s.shareRepo.GetByID(ctx, linkID)
+	if err != nil || link.SessionID != sessionID {
+		return fmt.Errorf("share link not found: %s", linkID)
+	}
+	if err := s.authorize(ctx, sessionID); err != nil {
+		return err
+	}
+
+	if err := s.shareRepo.Revoke(ctx, linkID, s.now()); err != nil {
+		return fmt.Errorf("failed to revoke share link: %w", err)
+	}
+	return nil
+}
+
+// ResolveShareLink returns the link a token belongs to, as long as the
+// token is genuine and the link has not expired or been revoked
+func (s *ShareService) ResolveShareLink(ctx context.Context, token string) (*domain.ShareLink, error) {
+	var claims shareClaims
+	if err := verifyToken(s.tokenSecret, token, &claims); err != nil || claims.Audience != shareTokenAudience {
+		return nil, fmt.Errorf("share link not found")
+	}
+
+	link, err := s.shareRepo.GetByID(ctx, claims.LinkID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && link.SessionID != claims.SessionID) {
+		return nil, fmt.Errorf("share link not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get share link: %w", err)
+	}
+
+	if link.RevokedAt != nil {
+		return nil, fmt.Errorf("share link revoked")
+	}
+	if !link.IsActive(s.now()) {
+		return nil, fmt.Errorf("share link expired")
+	}
+
+	return link, nil
+}
+
+// GetSharedView returns the session and scope behind a share link
+func (s *ShareService) GetSharedView(ctx context.Context, token string) (*SharedView, error) {
+	link, err := s.ResolveShareLink(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.sharedSession(ctx, link.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SharedView{
+		Session:    session,
+		Name:       link.Name,
+		EventTypes: sharedEventTypes(link),
+		ExpiresAt:  link.ExpiresAt,
+	}, nil
+}
+
+// GetSharedTimeline returns the part of a session timeline a share link
+// shows. Requested event types outside the link's scope are rejected.
+func (s *ShareService) GetSharedTimeline(ctx context.Context, token string, query TimelineQuery) (*TimelinePage, error) {
+	link, err := s.ResolveShareLink(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := sharedEventTypes(link)
+	if len(query.Types) == 0 {
+		query.Types = allowed
+	}
+	for _, eventType := range query.Types {
+		if !containsString(allowed, eventType) {
+			return nil, fmt.Errorf("invalid timeline query: event type %s is not shared", eventType)
+		}
+	}
+	query.EVPIDs = link.EVPIDs
+
+	return s.timelineService.GetTimeline(ctx, link.SessionID, query)
+}
+
+// GetSharedEVPAudio returns the audio of an EVP recording shared by a link
+// and a filename to serve it under
+func (s *ShareService) GetSharedEVPAudio(ctx context.Context, token, evpID string) ([]byte, string, error) {
+	link, err := s.ResolveShareLink(ctx, token)
+	if err != nil {
+		return nil, "", err
+	}
+
+	evp, err := s.evpRepo.GetByID(ctx, evpID)
+	if err != nil || evp.SessionID != link.SessionID || !sharesEVP(link, evpID) {
+		return nil, "", fmt.Errorf("EVP not found: %s", evpID)
+	}
+	if evp.FilePath == "" {
+		return nil, "", fmt.Errorf("EVP audio not found: %s", evpID)
+	}
+
+	data, err := s.fileRepo.GetFile(ctx, evp.FilePath)
+	if err != nil {
+		return nil, "", fmt.Errorf("EVP audio not found: %w", err)
+	}
+
+	return data, fmt.Sprintf("evp_%s%s", evp.ID, filepath.Ext(evp.FilePath)), nil
+}
+
+// GetSharedReport summarises what a share link shows: the session, how
+// many events of each shared type it holds and the shared EVP recordings
+func (s *ShareService) GetSharedReport(ctx context.Context, token string) (*SharedReport, error) {
+	link, err := s.ResolveShareLink(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.sharedSession(ctx, link.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SharedReport{
+		Session:     session,
+		EventCounts: make(map[string]int),
+		EVPs:        []*SharedEVP{},
+		GeneratedAt: s.now(),
+		ExpiresAt:   link.ExpiresAt,
+	}
+
+	for _, eventType := range sharedEventTypes(link) {
+		page, err := s.timelineService.GetTimeline(ctx, link.SessionID, TimelineQuery{
+			Types:  []string{eventType},
+			EVPIDs: link.EVPIDs,
+			Limit:  1,
+		})
+		if err != nil {
+			return nil, err
+		}
+		report.EventCounts[eventType] = page.Total
+	}
+
+	if containsString(sharedEventTypes(link), TimelineEventEVP) {
+		evps, err := s.evpRepo.GetBySessionID(ctx, link.SessionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get EVP recordings: %w", err)
+		}
+		for _, evp := range evps {
+			if !sharesEVP(link, evp.ID) {
+				continue
+			}
+			report.EVPs = append(report.EVPs, &SharedEVP{
+				ID:             evp.ID,
+				Timestamp:      evp.Timestamp,
+				Duration:       evp.Duration,
+				Quality:        evp.Quality,
+				DetectionLevel: evp.DetectionLevel,
+				Annotations:    evp.Annotations,
+				HasAudio:       evp.FilePath != "",
+			})
+		}
+	}
+
+	return report, nil
+}
+
+// authorize checks that the caller owns a session when access control is enabled
+func (s *ShareService) authorize(ctx context.Context, sessionID string) error {
+	if s.accessService == nil {
+		return nil
+	}
+	return s.accessService.AuthorizeSession(ctx, sessionID, domain.AccessOwner)
+}
+
+// sharedSession loads the shareable part of a session
+func (s *ShareService) sharedSession(ctx context.Context, sessionID string) (*SharedSession, error) {
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+
+	return &SharedSession{
+		ID:            session.ID,
+		Title:         session.Title,
+		Location:      session.Location,
+		StartTime:     session.StartTime,
+		EndTime:       session.EndTime,
+		Status:        session.Status,
+		Environmental: session.Environmental,
+	}, nil
+}
+
+// sharedEventTypes returns the timeline event types a link shows
+func sharedEventTypes(link *domain.ShareLink) []string {
+	if len(link.EventTypes) > 0 {
+		return link.EventTypes
+	}
+	return []string{
+		TimelineEventEVP, TimelineEventVOX, TimelineEventRadar, TimelineEventSLS,
+		TimelineEventInteraction, TimelineEventEnvironmental,
+	}
+}
+
+// sharesEVP reports whether a link shows an EVP recording
+func sharesEVP(link *domain.ShareLink, evpID string) bool {
+	if !containsString(sharedEventTypes(link), TimelineEventEVP) {
+		return false
+	}
+	return len(link.EVPIDs) == 0 || containsString(link.EVPIDs, evpID)
+}
+
+// nonNilStrings returns an empty list instead of nil
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
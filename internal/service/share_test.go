@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/myideascope/otherside/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupShareService returns a share service over a migrated in-memory
+// database holding session "session-1" with EVPs "evp-1" and "evp-2", both
+// with audio, and one interaction
+func setupShareService(t *testing.T) *ShareService {
+	db := setupLifecycleDB(t)
+	ctx := context.Background()
+
+	sm := NewSessionStateManager(repository.NewSQLiteSessionRepository(db))
+	require.NoError(t, sm.CreateSession(ctx, &domain.Session{ID: "session-1", Title: "Cellar", Notes: "private", StartTime: time.Now()}))
+
+	fileRepo := repository.NewSQLiteFileRepository(db, t.TempDir())
+	evpRepo := repository.NewSQLiteEVPRepository(db)
+	interactionRepo := repository.NewSQLiteInteractionRepository(db)
+	now := time.Now()
+	for i, id := range []string{"evp-1", "evp-2"} {
+		path := "audio/" + id + ".wav"
+		// File rows are keyed by the "timestamp" context value
+		fileCtx := context.WithValue(ctx, "timestamp", int64(i+1))
+		require.NoError(t, fileRepo.SaveFile(fileCtx, path, []byte("RIFF"+id)))
+		require.NoError(t, evpRepo.Create(ctx, &domain.EVPRecording{
+			ID: id, SessionID: "session-1", FilePath: path, Timestamp: now.Add(time.Duration(i) * time.Second),
+			Quality: domain.EVPQualityGood, CreatedAt: now,
+		}))
+	}
+	require.NoError(t, interactionRepo.Create(ctx, &domain.UserInteraction{
+		ID: "interaction-1", SessionID: "session-1", Type: domain.InteractionTypeText,
+		Content: "Is anyone here?", Timestamp: now, CreatedAt: now,
+	}))
+
+	timelineService := NewTimelineService(sm, evpRepo,
+		repository.NewSQLiteVOXRepository(db),
+		repository.NewSQLiteRadarRepository(db),
+		repository.NewSQLiteSLSRepository(db),
+		interactionRepo,
+		repository.NewSQLiteEnvironmentalAnomalyRepository(db),
+	)
+
+	return NewShareService(repository.NewSQLiteShareLinkRepository(db), sm, evpRepo, fileRepo, timelineService, []byte("test-secret"))
+}
+
+func TestShareService_GetSharedTimeline_ScopedLink_ShowsOnlySharedEvents(t *testing.T) {
+	// Arrange
+	shareService := setupShareService(t)
+	ctx := context.Background()
+	_, token, err := shareService.CreateShareLink(ctx, "session-1", CreateShareLinkRequest{
+		Name: "For the owners", EventTypes: []string{TimelineEventEVP}, EVPIDs: []string{"evp-2"},
+	})
+	require.NoError(t, err)
+
+	// Act
+	page, err := shareService.GetSharedTimeline(ctx, token, TimelineQuery{})
+	require.NoError(t, err)
+	_, outOfScopeErr := shareService.GetSharedTimeline(ctx, token, TimelineQuery{Types: []string{TimelineEventInteraction}})
+	report, err := shareService.GetSharedReport(ctx, token)
+	require.NoError(t, err)
+
+	// Assert
+	require.Len(t, page.Events, 1)
+	assert.Equal(t, "evp-2", page.Events[0].RefID)
+	require.Error(t, outOfScopeErr)
+	assert.Contains(t, outOfScopeErr.Error(), "invalid timeline query")
+	assert.Equal(t, map[string]int{TimelineEventEVP: 1}, report.EventCounts)
+	require.Len(t, report.EVPs, 1)
+	assert.Equal(t, "evp-2", report.EVPs[0].ID)
+	assert.Equal(t, "Cellar", report.Session.Title)
+}
+
+func TestShareService_GetSharedEVPAudio_OnlyServesSharedClips(t *testing.T) {
+	// Arrange
+	shareService := setupShareService(t)
+	ctx := context.Background()
+	_, token, err := shareService.CreateShareLink(ctx, "session-1", CreateShareLinkRequest{EVPIDs: []string{"evp-1"}})
+	require.NoError(t, err)
+
+	// Act
+	data, filename, err := shareService.GetSharedEVPAudio(ctx, token, "evp-1")
+	_, _, unsharedErr := shareService.GetSharedEVPAudio(ctx, token, "evp-2")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []byte("RIFFevp-1"), data)
+	assert.Equal(t, "evp_evp-1.wav", filename)
+	require.Error(t, unsharedErr)
+	assert.Contains(t, unsharedErr.Error(), "not found")
+}
+
+func TestShareService_ResolveShareLink_RevokedExpiredOrForged_Rejected(t *testing.T) {
+	// Arrange
+	shareService := setupShareService(t)
+	ctx := context.Background()
+	revoked, revokedToken, err := shareService.CreateShareLink(ctx, "session-1", CreateShareLinkRequest{})
+	require.NoError(t, err)
+	require.NoError(t, shareService.RevokeShareLink(ctx, "session-1", revoked.ID))
+	_, expiringToken, err := shareService.CreateShareLink(ctx, "session-1", CreateShareLinkRequest{ExpiresIn: 60})
+	require.NoError(t, err)
+
+	parts := strings.Split(expiringToken, ".")
+	forgedToken := parts[0] + "." + parts[1] + "." + tokenSignature([]byte("other-secret"), parts[0]+"."+parts[1])
+
+	// Act
+	_, revokedErr := shareService.ResolveShareLink(ctx, revokedToken)
+	_, forgedErr := shareService.ResolveShareLink(ctx, forgedToken)
+	shareService.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	_, expiredErr := shareService.ResolveShareLink(ctx, expiringToken)
+
+	// Assert
+	require.Error(t, revokedErr)
+	assert.Contains(t, revokedErr.Error(), "share link revoked")
+	require.Error(t, forgedErr)
+	assert.Contains(t, forgedErr.Error(), "share link not found")
+	require.Error(t, expiredErr)
+	assert.Contains(t, expiredErr.Error(), "share link expired")
+}
+
+func TestShareService_CreateShareLink_InvalidScope_ReturnsError(t *testing.T) {
+	// Arrange
+	shareService := setupShareService(t)
+	ctx := context.Background()
+
+	// Act
+	_, _, unknownTypeErr := shareService.CreateShareLink(ctx, "session-1", CreateShareLinkRequest{EventTypes: []string{"ectoplasm"}})
+	_, _, evpWithoutTypeErr := shareService.CreateShareLink(ctx, "session-1", CreateShareLinkRequest{
+		EventTypes: []string{TimelineEventVOX}, EVPIDs: []string{"evp-1"},
+	})
+	_, _, foreignEVPErr := shareService.CreateShareLink(ctx, "session-1", CreateShareLinkRequest{EVPIDs: []string{"evp-9"}})
+	_, _, tooLongErr := shareService.CreateShareLink(ctx, "session-1", CreateShareLinkRequest{ExpiresIn: int((100 * 24 * time.Hour).Seconds())})
+
+	// Assert
+	for _, err := range []error{unknownTypeErr, evpWithoutTypeErr, foreignEVPErr, tooLongErr} {
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid share link")
+	}
+}
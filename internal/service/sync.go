@@ -0,0 +1,606 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+)
+
+// maxSyncBatchOperations bounds the number of operations in one sync batch
+const maxSyncBatchOperations = 5000
+
+// syncClockSkew is how far in the future an operation's timestamp may be,
+// to allow for clients whose clocks run ahead
+const syncClockSkew = 5 * time.Minute
+
+// defaultChangesLimit and maxChangesLimit bound a page of the change feed
+const (
+	defaultChangesLimit = 500
+	maxChangesLimit     = 1000
+)
+
+// Sync operation types
+const (
+	SyncOperationSession     = "session"
+	SyncOperationEVP         = "evp"
+	SyncOperationVOX         = "vox"
+	SyncOperationRadar       = "radar"
+	SyncOperationSLS         = "sls"
+	SyncOperationInteraction = "interaction"
+)
+
+// Change feed types of records other than events. Floor plan entries have
+// the ID 'session_id/floor_plan_id' and participants
+// 'session_id/investigator_id'; an access list has its session's ID.
+const (
+	ChangeTypeEnvironmental = "environmental"
+	ChangeTypeRadarTrack    = "radar_track"
+	ChangeTypeFusion        = "fusion"
+	ChangeTypeFloorPlan     = "floor_plan"
+	ChangeTypePlacement     = "placement"
+	ChangeTypeParticipant   = "participant"
+	ChangeTypeACL           = "acl"
+)
+
+// Sync operation outcomes. Created and duplicate operations are done, and
+// so are rejected ones since sending them again cannot succeed. Failed
+// operations hit a server error and should be sent again later.
+const (
+	SyncStatusCreated   = "created"
+	SyncStatusDuplicate = "duplicate"
+	SyncStatusRejected  = "rejected"
+	SyncStatusFailed    = "failed"
+)
+
+// SyncService applies batches of operations queued by clients while they
+// were offline and keeps devices up to date through a feed of changes
+type SyncService struct {
+	sessions   *SessionService
+	batchRepo  domain.EventBatchRepository
+	changeRepo domain.ChangeRepository
+	records    SyncRecordRepositories
+	now        func() time.Time
+}
+
+// SyncRecordRepositories load the records other than sessions and their
+// events that the change feed carries. Changes of a type without a
+// repository are left out.
+type SyncRecordRepositories struct {
+	Readings     domain.EnvironmentalReadingRepository
+	Tracks       domain.RadarTrackRepository
+	Fusion       domain.FusionEventRepository
+	FloorPlans   domain.FloorPlanRepository
+	Placements   domain.DevicePlacementRepository
+	Participants domain.SessionParticipantRepository
+	ACLs         domain.SessionACLRepository
+}
+
+// NewSyncService creates a new sync service recording events through a
+// session service
+func NewSyncService(sessions *SessionService, batchRepo domain.EventBatchRepository, changeRepo domain.ChangeRepository) *SyncService {
+	return &SyncService{
+		sessions:   sessions,
+		batchRepo:  batchRepo,
+		changeRepo: changeRepo,
+		now:        time.Now,
+	}
+}
+
+// SetRecordRepositories lets the change feed carry environmental readings,
+// radar tracks, fusion events, floor plans, device placements,
+// participants and access lists
+func (s *SyncService) SetRecordRepositories(records SyncRecordRepositories) {
+	s.records = records
+}
+
+// ApplyBatch applies a batch of operations and reports the outcome of each.
+// Session operations are applied first, so a batch may create a session
+// and its events. Events are then grouped by session and each session's
+// accepted events are stored in one transaction. Events keep their
+// operation's timestamp when it has one.
+func (s *SyncService) ApplyBatch(ctx context.Context, req SyncBatchRequest) (*SyncBatchResponse, error) {
+	if len(req.Operations) == 0 {
+		return nil, fmt.Errorf("invalid sync batch: no operations")
+	}
+	if len(req.Operations) > maxSyncBatchOperations {
+		return nil, fmt.Errorf("invalid sync batch: at most %d operations are allowed", maxSyncBatchOperations)
+	}
+
+	results := make([]SyncResult, len(req.Operations))
+	var sessionOrder []string
+	groups := make(map[string][]int)
+
+	for i, op := range req.Operations {
+		results[i] = SyncResult{Index: i, Type: op.Type, SessionID: op.SessionID}
+
+		switch op.Type {
+		case SyncOperationSession:
+			s.applySession(ctx, op, &results[i])
+		case SyncOperationVOX, SyncOperationRadar, SyncOperationSLS, SyncOperationInteraction:
+			if op.SessionID == "" {
+				results[i].reject("session_id is required")
+				continue
+			}
+			if _, ok := groups[op.SessionID]; !ok {
+				sessionOrder = append(sessionOrder, op.SessionID)
+			}
+			groups[op.SessionID] = append(groups[op.SessionID], i)
+		case SyncOperationEVP:
+			results[i].reject("EVP recordings carry audio; upload them to /api/v1/sessions/{sessionId}/evp")
+		default:
+			results[i].reject(fmt.Sprintf("unknown operation type %q", op.Type))
+		}
+	}
+
+	for _, sessionID := range sessionOrder {
+		s.applySessionEvents(ctx, sessionID, req.Operations, groups[sessionID], results)
+	}
+
+	response := &SyncBatchResponse{Results: results}
+	for _, result := range results {
+		switch result.Status {
+		case SyncStatusCreated:
+			response.Created++
+		case SyncStatusDuplicate:
+			response.Duplicates++
+		case SyncStatusRejected:
+			response.Rejected++
+		case SyncStatusFailed:
+			response.Failed++
+		}
+	}
+
+	return response, nil
+}
+
+// applySession creates the session of a session operation, or reports it
+// as a duplicate when a session with its ID exists
+func (s *SyncService) applySession(ctx context.Context, op SyncOperation, result *SyncResult) {
+	var req CreateSessionRequest
+	if err := json.Unmarshal(op.Data, &req); err != nil {
+		result.reject(fmt.Sprintf("invalid data: %v", err))
+		return
+	}
+	if req.Title == "" {
+		result.reject("title is required")
+		return
+	}
+
+	id, err := resolveClientID(req.ID)
+	if err != nil {
+		result.reject(err.Error())
+		return
+	}
+	existed := false
+	if req.ID != "" {
+		_, err := s.sessions.sessionRepo.GetByID(ctx, id)
+		existed = err == nil
+	}
+
+	session, err := s.sessions.CreateSession(ctx, req)
+	if err != nil {
+		if strings.Contains(err.Error(), "failed to create session") {
+			result.fail(err.Error())
+		} else {
+			result.reject(err.Error())
+		}
+		return
+	}
+
+	result.ID = session.ID
+	result.SessionID = session.ID
+	if existed {
+		result.Status = SyncStatusDuplicate
+	} else {
+		result.Status = SyncStatusCreated
+	}
+}
+
+// applySessionEvents applies the event operations of one session. Events
+// already recorded are duplicates, the others are checked like single
+// events and stored together.
+func (s *SyncService) applySessionEvents(ctx context.Context, sessionID string, ops []SyncOperation, indexes []int, results []SyncResult) {
+	rejectAll := func(indexes []int, reason string) {
+		for _, i := range indexes {
+			results[i].reject(reason)
+		}
+	}
+
+	if _, err := s.sessions.sessionRepo.GetByID(ctx, sessionID); err != nil {
+		rejectAll(indexes, fmt.Sprintf("session not found: %s", sessionID))
+		return
+	}
+	if s.sessions.accessService != nil {
+		if err := s.sessions.accessService.AuthorizeSession(ctx, sessionID, domain.AccessTeam); err != nil {
+			rejectAll(indexes, err.Error())
+			return
+		}
+	}
+
+	// Settle duplicates first: they are done even if the session has
+	// stopped accepting events since they were recorded
+	var pending []int
+	ids := make(map[int]string)
+	seen := make(map[string]bool)
+	for _, i := range indexes {
+		clientID, err := syncOperationID(ops[i])
+		if err != nil {
+			results[i].reject(err.Error())
+			continue
+		}
+		id, err := resolveClientID(clientID)
+		if err != nil {
+			results[i].reject(err.Error())
+			continue
+		}
+		results[i].ID = id
+
+		if seen[id] {
+			results[i].Status = SyncStatusDuplicate
+			continue
+		}
+		if clientID != "" {
+			if existingSessionID, ok := s.existingEventSession(ctx, ops[i].Type, id); ok {
+				if existingSessionID != sessionID {
+					results[i].reject(fmt.Sprintf("id conflict: %s is already used in another session", id))
+				} else {
+					results[i].Status = SyncStatusDuplicate
+				}
+				continue
+			}
+		}
+		seen[id] = true
+		ids[i] = id
+		pending = append(pending, i)
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	if _, err := s.sessions.activeSession(ctx, sessionID); err != nil {
+		rejectAll(pending, err.Error())
+		return
+	}
+
+	batch := &domain.EventBatch{}
+	var accepted []int
+	for _, i := range pending {
+		if err := s.addEvent(ctx, batch, sessionID, ids[i], ops[i]); err != nil {
+			results[i].reject(err.Error())
+			continue
+		}
+		accepted = append(accepted, i)
+	}
+	if len(accepted) == 0 {
+		return
+	}
+
+	if err := s.batchRepo.CreateEvents(ctx, batch); err != nil {
+		for _, i := range accepted {
+			results[i].fail(fmt.Sprintf("failed to store events: %v", err))
+		}
+		return
+	}
+	for _, i := range accepted {
+		results[i].Status = SyncStatusCreated
+	}
+	s.sessions.publishBatch(ctx, batch)
+}
+
+// addEvent checks one event operation and adds its event to a batch
+func (s *SyncService) addEvent(ctx context.Context, batch *domain.EventBatch, sessionID, id string, op SyncOperation) error {
+	at := s.now()
+	if op.Timestamp != nil {
+		if op.Timestamp.After(at.Add(syncClockSkew)) {
+			return fmt.Errorf("timestamp is in the future")
+		}
+		at = *op.Timestamp
+	}
+
+	switch op.Type {
+	case SyncOperationVOX:
+		var data VOXTriggerData
+		if err := json.Unmarshal(op.Data, &data); err != nil {
+			return fmt.Errorf("invalid data: %v", err)
+		}
+		var err error
+		data.InvestigatorID, data.DeviceID, err = resolveAttribution(ctx, s.sessions.participantRepo, sessionID, data.InvestigatorID, data.DeviceID)
+		if err != nil {
+			return err
+		}
+		voxEvent, err := s.sessions.newVOXEvent(ctx, sessionID, id, data, at)
+		if err != nil {
+			return err
+		}
+		if voxEvent == nil {
+			return fmt.Errorf("VOX triggers are below the generation threshold")
+		}
+		batch.VOXEvents = append(batch.VOXEvents, voxEvent)
+
+	case SyncOperationRadar:
+		var data RadarEventData
+		if err := json.Unmarshal(op.Data, &data); err != nil {
+			return fmt.Errorf("invalid data: %v", err)
+		}
+		var err error
+		data.InvestigatorID, data.DeviceID, err = resolveAttribution(ctx, s.sessions.participantRepo, sessionID, data.InvestigatorID, data.DeviceID)
+		if err != nil {
+			return err
+		}
+		radarEvent, err := s.sessions.newRadarEvent(sessionID, id, data, at)
+		if err != nil {
+			return err
+		}
+		batch.RadarEvents = append(batch.RadarEvents, radarEvent)
+
+	case SyncOperationSLS:
+		var data SLSDetectionData
+		if err := json.Unmarshal(op.Data, &data); err != nil {
+			return fmt.Errorf("invalid data: %v", err)
+		}
+		var err error
+		data.InvestigatorID, data.DeviceID, err = resolveAttribution(ctx, s.sessions.participantRepo, sessionID, data.InvestigatorID, data.DeviceID)
+		if err != nil {
+			return err
+		}
+		slsDetection, err := s.sessions.newSLSDetection(sessionID, id, data, at)
+		if err != nil {
+			return err
+		}
+		batch.SLSDetections = append(batch.SLSDetections, slsDetection)
+
+	case SyncOperationInteraction:
+		var data UserInteractionData
+		if err := json.Unmarshal(op.Data, &data); err != nil {
+			return fmt.Errorf("invalid data: %v", err)
+		}
+		var err error
+		data.InvestigatorID, data.DeviceID, err = resolveAttribution(ctx, s.sessions.participantRepo, sessionID, data.InvestigatorID, data.DeviceID)
+		if err != nil {
+			return err
+		}
+		batch.Interactions = append(batch.Interactions, s.sessions.newUserInteraction(sessionID, id, data, at))
+	}
+
+	return nil
+}
+
+// existingEventSession returns the session of an already recorded event
+func (s *SyncService) existingEventSession(ctx context.Context, opType, id string) (string, bool) {
+	switch opType {
+	case SyncOperationVOX:
+		if existing, err := s.sessions.voxRepo.GetByID(ctx, id); err == nil {
+			return existing.SessionID, true
+		}
+	case SyncOperationRadar:
+		if existing, err := s.sessions.radarRepo.GetByID(ctx, id); err == nil {
+			return existing.SessionID, true
+		}
+	case SyncOperationSLS:
+		if existing, err := s.sessions.slsRepo.GetByID(ctx, id); err == nil {
+			return existing.SessionID, true
+		}
+	case SyncOperationInteraction:
+		if existing, err := s.sessions.interactionRepo.GetByID(ctx, id); err == nil {
+			return existing.SessionID, true
+		}
+	}
+	return "", false
+}
+
+// GetChanges returns the changes after a cursor to sessions the caller can
+// read, with the current copy of each written record. Deleted records come
+// as tombstones without a record. When the cursor is older than the oldest
+// kept tombstone the page starts over from the beginning and Reset is set,
+// so the client knows to drop its local copies first.
+func (s *SyncService) GetChanges(ctx context.Context, query ChangesQuery) (*ChangesPage, error) {
+	if query.Since < 0 {
+		return nil, fmt.Errorf("invalid cursor: must not be negative")
+	}
+	if query.Limit <= 0 {
+		query.Limit = defaultChangesLimit
+	}
+	if query.Limit > maxChangesLimit {
+		query.Limit = maxChangesLimit
+	}
+
+	accessService := s.sessions.accessService
+	if query.SessionID != "" && accessService != nil {
+		if err := accessService.AuthorizeSession(ctx, query.SessionID, domain.AccessGuest); err != nil {
+			return nil, err
+		}
+	}
+
+	page := &ChangesPage{Changes: []*ChangeEntry{}}
+	purgedThrough, err := s.changeRepo.PurgedThrough(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read change feed: %w", err)
+	}
+	if query.Since > 0 && query.Since < purgedThrough {
+		page.Reset = true
+		query.Since = 0
+	}
+
+	changes, err := s.changeRepo.GetSince(ctx, query.Since, query.SessionID, query.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read change feed: %w", err)
+	}
+
+	cursor := query.Since
+	visible := make(map[string]bool)
+	for _, change := range changes {
+		// The cursor passes changes the caller cannot see, so that they are
+		// not read again
+		cursor = change.Seq
+
+		if query.SessionID == "" && accessService != nil {
+			canRead, checked := visible[change.SessionID]
+			if !checked {
+				level, err := accessService.SessionAccess(ctx, change.SessionID)
+				if err != nil {
+					return nil, err
+				}
+				canRead = level.Allows(domain.AccessGuest)
+				visible[change.SessionID] = canRead
+			}
+			if !canRead {
+				continue
+			}
+		}
+
+		entry := &ChangeEntry{Change: change}
+		if change.Operation == domain.ChangeUpsert {
+			record, err := s.changedRecord(ctx, change)
+			if err != nil {
+				// Deleted since; its tombstone follows later in the feed
+				continue
+			}
+			entry.Record = record
+		}
+		page.Changes = append(page.Changes, entry)
+	}
+
+	page.Cursor = strconv.FormatInt(cursor, 10)
+	page.HasMore = len(changes) == query.Limit
+
+	return page, nil
+}
+
+// PurgeTombstones forgets deleted records older than a retention period.
+// Clients whose cursor is older than the purged tombstones start over.
+func (s *SyncService) PurgeTombstones(ctx context.Context, retention time.Duration) (int64, error) {
+	return s.changeRepo.PurgeTombstones(ctx, s.now().Add(-retention))
+}
+
+// changedRecord loads the current copy of a changed record
+func (s *SyncService) changedRecord(ctx context.Context, change *domain.Change) (interface{}, error) {
+	switch change.EntityType {
+	case SyncOperationSession:
+		return s.sessions.sessionRepo.GetByID(ctx, change.EntityID)
+	case SyncOperationEVP:
+		return s.sessions.evpRepo.GetByID(ctx, change.EntityID)
+	case SyncOperationVOX:
+		return s.sessions.voxRepo.GetByID(ctx, change.EntityID)
+	case SyncOperationRadar:
+		return s.sessions.radarRepo.GetByID(ctx, change.EntityID)
+	case SyncOperationSLS:
+		return s.sessions.slsRepo.GetByID(ctx, change.EntityID)
+	case SyncOperationInteraction:
+		return s.sessions.interactionRepo.GetByID(ctx, change.EntityID)
+	case ChangeTypeEnvironmental:
+		if s.records.Readings != nil {
+			return s.records.Readings.GetByID(ctx, change.EntityID)
+		}
+	case ChangeTypeRadarTrack:
+		if s.records.Tracks != nil {
+			return s.records.Tracks.GetByID(ctx, change.EntityID)
+		}
+	case ChangeTypeFusion:
+		if s.records.Fusion != nil {
+			return s.records.Fusion.GetByID(ctx, change.EntityID)
+		}
+	case ChangeTypeFloorPlan:
+		if s.records.FloorPlans != nil {
+			return s.records.FloorPlans.GetByID(ctx, strings.TrimPrefix(change.EntityID, change.SessionID+"/"))
+		}
+	case ChangeTypePlacement:
+		if s.records.Placements != nil {
+			return s.records.Placements.GetByID(ctx, change.EntityID)
+		}
+	case ChangeTypeParticipant:
+		if s.records.Participants != nil {
+			return s.records.Participants.Get(ctx, change.SessionID, strings.TrimPrefix(change.EntityID, change.SessionID+"/"))
+		}
+	case ChangeTypeACL:
+		if s.records.ACLs != nil {
+			return s.records.ACLs.Get(ctx, change.SessionID)
+		}
+	default:
+		return nil, fmt.Errorf("unknown change type %q", change.EntityType)
+	}
+	return nil, fmt.Errorf("change type %q is not served", change.EntityType)
+}
+
+// syncOperationID reads the client-supplied ID of an operation's data
+func syncOperationID(op SyncOperation) (string, error) {
+	var data struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(op.Data, &data); err != nil {
+		return "", fmt.Errorf("invalid data: %v", err)
+	}
+	return data.ID, nil
+}
+
+// SyncBatchRequest is an ordered list of operations queued offline
+type SyncBatchRequest struct {
+	Operations []SyncOperation `json:"operations"`
+}
+
+// SyncOperation creates a session or records an event. Data holds the body
+// the matching single-item endpoint takes, including the optional client
+// ID, and Timestamp is when the event happened.
+type SyncOperation struct {
+	Type      string          `json:"type"`
+	SessionID string          `json:"session_id,omitempty"`
+	Timestamp *time.Time      `json:"timestamp,omitempty"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// SyncResult is the outcome of one operation, at its index in the batch
+type SyncResult struct {
+	Index     int    `json:"index"`
+	Type      string `json:"type"`
+	ID        string `json:"id,omitempty"`
+	SessionID string `json:"session_id,omitempty"`
+	Status    string `json:"status"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+func (r *SyncResult) reject(reason string) {
+	r.Status = SyncStatusRejected
+	r.Reason = reason
+}
+
+func (r *SyncResult) fail(reason string) {
+	r.Status = SyncStatusFailed
+	r.Reason = reason
+}
+
+// ChangesQuery selects a page of the change feed. Since is the cursor
+// returned with the previous page, or zero to start from the beginning.
+type ChangesQuery struct {
+	Since     int64
+	SessionID string
+	Limit     int
+}
+
+// ChangeEntry is a change with the current copy of the written record
+type ChangeEntry struct {
+	*domain.Change
+	Record interface{} `json:"record,omitempty"`
+}
+
+// ChangesPage is a page of the change feed. Cursor is passed as since to
+// get the next page, and HasMore says whether there may be one already.
+type ChangesPage struct {
+	Changes []*ChangeEntry `json:"changes"`
+	Cursor  string         `json:"cursor"`
+	HasMore bool           `json:"has_more"`
+	Reset   bool           `json:"reset"`
+}
+
+// SyncBatchResponse holds the per-operation results of a batch and how
+// many operations ended in each status
+type SyncBatchResponse struct {
+	Results    []SyncResult `json:"results"`
+	Created    int          `json:"created"`
+	Duplicates int          `json:"duplicates"`
+	Rejected   int          `json:"rejected"`
+	Failed     int          `json:"failed"`
+}
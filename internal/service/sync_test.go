@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/myideascope/otherside/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const syncSessionID = "6f1c2a4e-0b3d-4e5f-8a9b-0c1d2e3f4a5b"
+
+// failingEventBatchRepository fails every batch, as a full disk would
+type failingEventBatchRepository struct{}
+
+func (failingEventBatchRepository) CreateEvents(ctx context.Context, batch *domain.EventBatch) error {
+	return errors.New("disk I/O error")
+}
+
+// setupSyncService returns a sync service over a migrated in-memory
+// database, and the session service it records through
+func setupSyncService(t *testing.T, batchRepo domain.EventBatchRepository) (*SyncService, *SessionService) {
+	db := setupLifecycleDB(t)
+	sm := NewSessionStateManager(repository.NewSQLiteSessionRepository(db))
+	sessions := NewSessionService(sm, nil, nil,
+		repository.NewSQLiteRadarRepository(db), nil,
+		repository.NewSQLiteInteractionRepository(db), nil, nil, nil,
+	)
+	if batchRepo == nil {
+		batchRepo = repository.NewSQLiteEventBatchRepository(db)
+	}
+	return NewSyncService(sessions, batchRepo, repository.NewSQLiteChangeRepository(db)), sessions
+}
+
+// syncOp builds a sync operation with data marshalled to JSON
+func syncOp(t *testing.T, opType, sessionID string, data interface{}) SyncOperation {
+	raw, err := json.Marshal(data)
+	require.NoError(t, err)
+	return SyncOperation{Type: opType, SessionID: sessionID, Data: raw}
+}
+
+func TestSyncService_ApplyBatch_MixedOperations_ReportsEachOutcome(t *testing.T) {
+	// Arrange
+	syncService, sessions := setupSyncService(t, nil)
+	ctx := context.Background()
+	at := time.Now().Add(-8 * time.Hour).Truncate(time.Second)
+	interaction := UserInteractionData{ID: "0d9c8b7a-6f5e-4d3c-9b2a-1f0e9d8c7b6a", Type: domain.InteractionTypeText, Content: "Is anyone here?"}
+	timed := syncOp(t, SyncOperationInteraction, syncSessionID, interaction)
+	timed.Timestamp = &at
+	req := SyncBatchRequest{Operations: []SyncOperation{
+		timed,
+		syncOp(t, SyncOperationSession, "", CreateSessionRequest{ID: syncSessionID, Title: "Cellar"}),
+		syncOp(t, SyncOperationInteraction, syncSessionID, interaction),
+		syncOp(t, SyncOperationRadar, syncSessionID, RadarEventData{Strength: 0.1}),
+		syncOp(t, SyncOperationEVP, syncSessionID, EVPMetadata{}),
+		syncOp(t, SyncOperationRadar, "", RadarEventData{}),
+	}}
+
+	// Act
+	response, err := syncService.ApplyBatch(ctx, req)
+	require.NoError(t, err)
+	replay, err := syncService.ApplyBatch(ctx, SyncBatchRequest{Operations: req.Operations[:3]})
+	require.NoError(t, err)
+
+	// Assert
+	statuses := func(results []SyncResult) []string {
+		var out []string
+		for _, result := range results {
+			out = append(out, result.Status)
+		}
+		return out
+	}
+	assert.Equal(t, []string{
+		SyncStatusCreated, SyncStatusCreated, SyncStatusDuplicate,
+		SyncStatusRejected, SyncStatusRejected, SyncStatusRejected,
+	}, statuses(response.Results))
+	assert.Equal(t, "radar event failed validation", response.Results[3].Reason)
+	assert.Equal(t, 2, response.Created)
+	assert.Equal(t, 3, response.Rejected)
+	assert.Equal(t, []string{SyncStatusDuplicate, SyncStatusDuplicate, SyncStatusDuplicate}, statuses(replay.Results))
+
+	recorded, err := sessions.interactionRepo.GetByID(ctx, interaction.ID)
+	require.NoError(t, err)
+	assert.True(t, at.Equal(recorded.Timestamp))
+}
+
+func TestSyncService_ApplyBatch_InactiveSession_RejectsNewEventsOnly(t *testing.T) {
+	// Arrange
+	syncService, sessions := setupSyncService(t, nil)
+	ctx := context.Background()
+	_, err := sessions.CreateSession(ctx, CreateSessionRequest{ID: syncSessionID, Title: "Cellar"})
+	require.NoError(t, err)
+	recorded := UserInteractionData{ID: "0d9c8b7a-6f5e-4d3c-9b2a-1f0e9d8c7b6a", Type: domain.InteractionTypeText}
+	_, err = sessions.RecordUserInteraction(ctx, syncSessionID, recorded)
+	require.NoError(t, err)
+	lifecycle := NewSessionLifecycleService(sessions.sessionRepo.(*SessionStateManager), nil, nil)
+	_, err = lifecycle.CompleteSession(ctx, syncSessionID)
+	require.NoError(t, err)
+
+	// Act
+	response, err := syncService.ApplyBatch(ctx, SyncBatchRequest{Operations: []SyncOperation{
+		syncOp(t, SyncOperationInteraction, syncSessionID, recorded),
+		syncOp(t, SyncOperationInteraction, syncSessionID, UserInteractionData{Type: domain.InteractionTypeText}),
+	}})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, SyncStatusDuplicate, response.Results[0].Status)
+	assert.Equal(t, SyncStatusRejected, response.Results[1].Status)
+	assert.Contains(t, response.Results[1].Reason, "not active")
+}
+
+func TestSyncService_ApplyBatch_StorageError_FailsWholeSession(t *testing.T) {
+	// Arrange
+	syncService, sessions := setupSyncService(t, failingEventBatchRepository{})
+	ctx := context.Background()
+	_, err := sessions.CreateSession(ctx, CreateSessionRequest{ID: syncSessionID, Title: "Cellar"})
+	require.NoError(t, err)
+
+	// Act
+	response, err := syncService.ApplyBatch(ctx, SyncBatchRequest{Operations: []SyncOperation{
+		syncOp(t, SyncOperationInteraction, syncSessionID, UserInteractionData{Type: domain.InteractionTypeText}),
+		syncOp(t, SyncOperationInteraction, syncSessionID, UserInteractionData{Type: domain.InteractionTypeText}),
+	}})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 2, response.Failed)
+	for _, result := range response.Results {
+		assert.Equal(t, SyncStatusFailed, result.Status)
+		assert.Contains(t, result.Reason, "disk I/O error")
+	}
+}
+
+func TestSyncService_GetChanges_SinceCursor_ReturnsLaterChangesAndTombstones(t *testing.T) {
+	// Arrange
+	syncService, sessions := setupSyncService(t, nil)
+	ctx := context.Background()
+	_, err := sessions.CreateSession(ctx, CreateSessionRequest{ID: syncSessionID, Title: "Cellar"})
+	require.NoError(t, err)
+	first, err := sessions.RecordUserInteraction(ctx, syncSessionID, UserInteractionData{Type: domain.InteractionTypeText, Content: "Hello?"})
+	require.NoError(t, err)
+	initial, err := syncService.GetChanges(ctx, ChangesQuery{})
+	require.NoError(t, err)
+	cursor, err := strconv.ParseInt(initial.Cursor, 10, 64)
+	require.NoError(t, err)
+
+	second, err := sessions.RecordUserInteraction(ctx, syncSessionID, UserInteractionData{Type: domain.InteractionTypeText, Content: "Knock twice"})
+	require.NoError(t, err)
+	require.NoError(t, sessions.interactionRepo.Delete(ctx, first.ID))
+
+	// Act
+	page, err := syncService.GetChanges(ctx, ChangesQuery{Since: cursor})
+	require.NoError(t, err)
+	end, err := syncService.GetChanges(ctx, ChangesQuery{Since: mustParseCursor(t, page.Cursor)})
+	require.NoError(t, err)
+
+	// Assert
+	operations := map[string]domain.ChangeOperation{}
+	for _, change := range initial.Changes {
+		operations[change.EntityID] = change.Operation
+	}
+	assert.Equal(t, domain.ChangeUpsert, operations[syncSessionID])
+	assert.Equal(t, domain.ChangeUpsert, operations[first.ID])
+
+	operations = map[string]domain.ChangeOperation{}
+	for _, change := range page.Changes {
+		operations[change.EntityID] = change.Operation
+		if change.EntityID == second.ID {
+			record, ok := change.Record.(*domain.UserInteraction)
+			require.True(t, ok)
+			assert.Equal(t, "Knock twice", record.Content)
+		}
+		if change.Operation == domain.ChangeDelete {
+			assert.Nil(t, change.Record)
+		}
+	}
+	assert.Equal(t, domain.ChangeUpsert, operations[second.ID])
+	assert.Equal(t, domain.ChangeDelete, operations[first.ID])
+	assert.False(t, page.HasMore)
+	assert.Empty(t, end.Changes)
+	assert.Equal(t, page.Cursor, end.Cursor)
+}
+
+func TestSyncService_GetChanges_CursorBeforePurgedTombstones_Resets(t *testing.T) {
+	// Arrange
+	syncService, sessions := setupSyncService(t, nil)
+	ctx := context.Background()
+	_, err := sessions.CreateSession(ctx, CreateSessionRequest{ID: syncSessionID, Title: "Cellar"})
+	require.NoError(t, err)
+	start, err := syncService.GetChanges(ctx, ChangesQuery{})
+	require.NoError(t, err)
+	interaction, err := sessions.RecordUserInteraction(ctx, syncSessionID, UserInteractionData{Type: domain.InteractionTypeText})
+	require.NoError(t, err)
+	require.NoError(t, sessions.interactionRepo.Delete(ctx, interaction.ID))
+	syncService.now = func() time.Time { return time.Now().Add(time.Hour) }
+	purged, err := syncService.PurgeTombstones(ctx, 0)
+	require.NoError(t, err)
+
+	// Act
+	page, err := syncService.GetChanges(ctx, ChangesQuery{Since: mustParseCursor(t, start.Cursor)})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+	assert.True(t, page.Reset)
+	require.Len(t, page.Changes, 1)
+	assert.Equal(t, syncSessionID, page.Changes[0].EntityID)
+}
+
+func mustParseCursor(t *testing.T, cursor string) int64 {
+	seq, err := strconv.ParseInt(cursor, 10, 64)
+	require.NoError(t, err)
+	return seq
+}
+
+func TestSyncService_GetChanges_GuestAdded_ReceivesEarlierRecords(t *testing.T) {
+	// Arrange
+	f := setupAccessServices(t)
+	ctx := context.Background()
+	syncService := NewSyncService(f.sessions, repository.NewSQLiteEventBatchRepository(f.db), repository.NewSQLiteChangeRepository(f.db))
+	syncService.SetRecordRepositories(SyncRecordRepositories{
+		Readings: repository.NewSQLiteEnvironmentalReadingRepository(f.db),
+		ACLs:     repository.NewSQLiteSessionACLRepository(f.db),
+	})
+
+	session, err := f.sessions.CreateSession(as("owner"), CreateSessionRequest{Title: "Cellar"})
+	require.NoError(t, err)
+	now := time.Now().Truncate(time.Second)
+	require.NoError(t, repository.NewSQLiteEnvironmentalReadingRepository(f.db).CreateBatch(ctx, []*domain.EnvironmentalReading{{
+		ID: "reading-1", SessionID: session.ID, DeviceID: "sensor-1", Timestamp: now,
+		Metric: domain.EnvironmentalMetricTemperature, Value: 12.5, CreatedAt: now,
+	}}))
+	hidden, err := syncService.GetChanges(as("guest"), ChangesQuery{})
+	require.NoError(t, err)
+	_, err = f.access.AddSessionGuest(as("owner"), session.ID, "guest")
+	require.NoError(t, err)
+
+	// Act
+	page, err := syncService.GetChanges(as("guest"), ChangesQuery{Since: mustParseCursor(t, hidden.Cursor)})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Empty(t, hidden.Changes)
+	records := map[string]interface{}{}
+	for _, change := range page.Changes {
+		records[change.EntityType] = change.Record
+	}
+	reading, ok := records[ChangeTypeEnvironmental].(*domain.EnvironmentalReading)
+	require.True(t, ok)
+	assert.Equal(t, 12.5, reading.Value)
+	acl, ok := records[ChangeTypeACL].(*domain.SessionACL)
+	require.True(t, ok)
+	assert.Equal(t, []string{"guest"}, acl.GuestIDs)
+	assert.Contains(t, records, SyncOperationSession)
+}
+
+func TestSyncService_GetChanges_SessionWithGuestDeleted_TombstonesAccessList(t *testing.T) {
+	// Arrange
+	f := setupAccessServices(t)
+	syncService := NewSyncService(f.sessions, repository.NewSQLiteEventBatchRepository(f.db), repository.NewSQLiteChangeRepository(f.db))
+	session := sharedSession(t, f)
+	start, err := syncService.GetChanges(as("owner"), ChangesQuery{})
+	require.NoError(t, err)
+
+	// Act
+	_, err = f.db.Exec(`DELETE FROM sessions WHERE id = ?`, session.ID)
+	require.NoError(t, err)
+	page, err := syncService.GetChanges(context.Background(), ChangesQuery{Since: mustParseCursor(t, start.Cursor)})
+
+	// Assert
+	require.NoError(t, err)
+	operations := map[string]domain.ChangeOperation{}
+	for _, change := range page.Changes {
+		operations[change.EntityType] = change.Operation
+	}
+	assert.Equal(t, domain.ChangeDelete, operations[SyncOperationSession])
+	assert.Equal(t, domain.ChangeDelete, operations[ChangeTypeACL])
+}
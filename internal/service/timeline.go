@@ -0,0 +1,337 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+)
+
+// Timeline event types
+const (
+	TimelineEventEVP           = "evp"
+	TimelineEventVOX           = "vox"
+	TimelineEventRadar         = "radar"
+	TimelineEventSLS           = "sls"
+	TimelineEventInteraction   = "interaction"
+	TimelineEventEnvironmental = "environmental"
+)
+
+const (
+	defaultTimelineLimit    = 100
+	maxTimelineLimit        = 1000
+	maxTimelineSummaryRunes = 80
+)
+
+// TimelineService merges a session's events into one chronological stream
+type TimelineService struct {
+	sessionRepo     domain.SessionRepository
+	evpRepo         domain.EVPRepository
+	voxRepo         domain.VOXRepository
+	radarRepo       domain.RadarRepository
+	slsRepo         domain.SLSRepository
+	interactionRepo domain.InteractionRepository
+	anomalyRepo     domain.EnvironmentalAnomalyRepository
+}
+
+// TimelineEvent is the common envelope of every timeline entry. Confidence is
+// omitted for events that carry no detection score, and the investigator and
+// device for events nobody was credited with.
+type TimelineEvent struct {
+	Type           string    `json:"type"`
+	RefID          string    `json:"ref_id"`
+	Timestamp      time.Time `json:"timestamp"`
+	Duration       float64   `json:"duration"`
+	Summary        string    `json:"summary"`
+	Confidence     *float64  `json:"confidence,omitempty"`
+	InvestigatorID string    `json:"investigator_id,omitempty"`
+	DeviceID       string    `json:"device_id,omitempty"`
+}
+
+// TimelineQuery filters and paginates a timeline. Events without a
+// confidence score are not removed by MinConfidence. An investigator or
+// device filter keeps only the events attributed to it, and EVPIDs keeps
+// only the listed EVP recordings.
+type TimelineQuery struct {
+	Types          []string   `json:"types,omitempty"`
+	From           *time.Time `json:"from,omitempty"`
+	To             *time.Time `json:"to,omitempty"`
+	MinConfidence  float64    `json:"min_confidence"`
+	InvestigatorID string     `json:"investigator_id,omitempty"`
+	DeviceID       string     `json:"device_id,omitempty"`
+	EVPIDs         []string   `json:"evp_ids,omitempty"`
+	Limit          int        `json:"limit"`
+	Offset         int        `json:"offset"`
+}
+
+// TimelinePage is one page of a session timeline
+type TimelinePage struct {
+	SessionID string           `json:"session_id"`
+	Events    []*TimelineEvent `json:"events"`
+	Total     int              `json:"total"`
+	Limit     int              `json:"limit"`
+	Offset    int              `json:"offset"`
+}
+
+// NewTimelineService creates a new timeline service
+func NewTimelineService(
+	sessionRepo domain.SessionRepository,
+	evpRepo domain.EVPRepository,
+	voxRepo domain.VOXRepository,
+	radarRepo domain.RadarRepository,
+	slsRepo domain.SLSRepository,
+	interactionRepo domain.InteractionRepository,
+	anomalyRepo domain.EnvironmentalAnomalyRepository,
+) *TimelineService {
+	return &TimelineService{
+		sessionRepo:     sessionRepo,
+		evpRepo:         evpRepo,
+		voxRepo:         voxRepo,
+		radarRepo:       radarRepo,
+		slsRepo:         slsRepo,
+		interactionRepo: interactionRepo,
+		anomalyRepo:     anomalyRepo,
+	}
+}
+
+// GetTimeline returns a filtered page of a session's events in chronological order
+func (s *TimelineService) GetTimeline(ctx context.Context, sessionID string, query TimelineQuery) (*TimelinePage, error) {
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+
+	query, err = normalizeTimelineQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	include := func(eventType string) bool {
+		return len(query.Types) == 0 || containsString(query.Types, eventType)
+	}
+
+	var events []*TimelineEvent
+
+	if include(TimelineEventEVP) {
+		evps, err := s.evpRepo.GetBySessionID(ctx, sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get EVP recordings: %w", err)
+		}
+		for _, evp := range evps {
+			events = append(events, &TimelineEvent{
+				Type:           TimelineEventEVP,
+				RefID:          evp.ID,
+				InvestigatorID: evp.InvestigatorID,
+				DeviceID:       evp.DeviceID,
+				Timestamp:      evp.Timestamp,
+				Duration:       evp.Duration,
+				Summary:        fmt.Sprintf("EVP recording, %s quality", evp.Quality),
+				Confidence:     confidence(evp.DetectionLevel),
+			})
+		}
+	}
+
+	if include(TimelineEventVOX) {
+		voxEvents, err := s.voxRepo.GetBySessionID(ctx, sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get VOX events: %w", err)
+		}
+		for _, vox := range voxEvents {
+			events = append(events, &TimelineEvent{
+				Type:           TimelineEventVOX,
+				RefID:          vox.ID,
+				InvestigatorID: vox.InvestigatorID,
+				DeviceID:       vox.DeviceID,
+				Timestamp:      vox.Timestamp,
+				Summary:        truncateSummary(fmt.Sprintf("VOX: %q", vox.GeneratedText)),
+				Confidence:     confidence(vox.TriggerStrength),
+			})
+		}
+	}
+
+	if include(TimelineEventRadar) {
+		radarEvents, err := s.radarRepo.GetBySessionID(ctx, sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get radar events: %w", err)
+		}
+		for _, radar := range radarEvents {
+			events = append(events, &TimelineEvent{
+				Type:           TimelineEventRadar,
+				RefID:          radar.ID,
+				InvestigatorID: radar.InvestigatorID,
+				DeviceID:       radar.DeviceID,
+				Timestamp:      radar.Timestamp,
+				Duration:       radar.Duration,
+				Summary: fmt.Sprintf("Radar %s source at (%.1f, %.1f)",
+					radar.SourceType, radar.Position.X, radar.Position.Y),
+				Confidence: confidence(radar.Strength),
+			})
+		}
+	}
+
+	if include(TimelineEventSLS) {
+		slsDetections, err := s.slsRepo.GetBySessionID(ctx, sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get SLS detections: %w", err)
+		}
+		for _, sls := range slsDetections {
+			summary := fmt.Sprintf("SLS figure, %d skeletal points", len(sls.SkeletalPoints))
+			if sls.Movement.Pattern != "" {
+				summary += ", " + sls.Movement.Pattern
+			}
+			events = append(events, &TimelineEvent{
+				Type:           TimelineEventSLS,
+				RefID:          sls.ID,
+				InvestigatorID: sls.InvestigatorID,
+				DeviceID:       sls.DeviceID,
+				Timestamp:      sls.Timestamp,
+				Duration:       sls.Duration,
+				Summary:        summary,
+				Confidence:     confidence(sls.Confidence),
+			})
+		}
+	}
+
+	if include(TimelineEventInteraction) {
+		interactions, err := s.interactionRepo.GetBySessionID(ctx, sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get interactions: %w", err)
+		}
+		for _, interaction := range interactions {
+			events = append(events, &TimelineEvent{
+				Type:           TimelineEventInteraction,
+				RefID:          interaction.ID,
+				InvestigatorID: interaction.InvestigatorID,
+				DeviceID:       interaction.DeviceID,
+				Timestamp:      interaction.Timestamp,
+				Duration:       interaction.ResponseTime,
+				Summary:        truncateSummary(fmt.Sprintf("%s: %s", interaction.Type, interaction.Content)),
+			})
+		}
+	}
+
+	if include(TimelineEventEnvironmental) {
+		// The conditions recorded when the session was created open the timeline
+		env := session.Environmental
+		events = append(events, &TimelineEvent{
+			Type:      TimelineEventEnvironmental,
+			RefID:     session.ID,
+			Timestamp: session.StartTime,
+			Summary: fmt.Sprintf("Baseline conditions: %.1f°C, %.0f%% humidity, EMF %.1f",
+				env.Temperature, env.Humidity, env.EMFLevel),
+		})
+
+		anomalies, err := s.anomalyRepo.GetBySessionID(ctx, sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get environmental anomalies: %w", err)
+		}
+		for _, anomaly := range anomalies {
+			events = append(events, &TimelineEvent{
+				Type:      TimelineEventEnvironmental,
+				RefID:     anomaly.ID,
+				DeviceID:  anomaly.DeviceID,
+				Timestamp: anomaly.Timestamp,
+				Summary: fmt.Sprintf("%s anomaly on %s: %.2f against baseline %.2f (%+.1fσ)",
+					anomaly.Metric, anomaly.DeviceID, anomaly.Value, anomaly.Baseline, anomaly.Deviation),
+			})
+		}
+	}
+
+	events = filterTimelineEvents(events, query)
+
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].Timestamp.Equal(events[j].Timestamp) {
+			return events[i].Timestamp.Before(events[j].Timestamp)
+		}
+		if events[i].Type != events[j].Type {
+			return events[i].Type < events[j].Type
+		}
+		return events[i].RefID < events[j].RefID
+	})
+
+	page := &TimelinePage{
+		SessionID: sessionID,
+		Events:    []*TimelineEvent{},
+		Total:     len(events),
+		Limit:     query.Limit,
+		Offset:    query.Offset,
+	}
+	if query.Offset < len(events) {
+		page.Events = events[query.Offset:min(query.Offset+query.Limit, len(events))]
+	}
+
+	return page, nil
+}
+
+func normalizeTimelineQuery(query TimelineQuery) (TimelineQuery, error) {
+	for _, eventType := range query.Types {
+		if !isTimelineEventType(eventType) {
+			return query, fmt.Errorf("invalid timeline query: unknown event type %s", eventType)
+		}
+	}
+
+	if query.From != nil && query.To != nil && query.To.Before(*query.From) {
+		return query, fmt.Errorf("invalid timeline query: time range ends before it starts")
+	}
+
+	if query.Limit <= 0 {
+		query.Limit = defaultTimelineLimit
+	}
+	query.Limit = min(query.Limit, maxTimelineLimit)
+
+	if query.Offset < 0 {
+		query.Offset = 0
+	}
+
+	return query, nil
+}
+
+// isTimelineEventType reports whether eventType names a kind of timeline event
+func isTimelineEventType(eventType string) bool {
+	switch eventType {
+	case TimelineEventEVP, TimelineEventVOX, TimelineEventRadar, TimelineEventSLS,
+		TimelineEventInteraction, TimelineEventEnvironmental:
+		return true
+	}
+	return false
+}
+
+func filterTimelineEvents(events []*TimelineEvent, query TimelineQuery) []*TimelineEvent {
+	filtered := events[:0]
+	for _, event := range events {
+		if query.From != nil && event.Timestamp.Before(*query.From) {
+			continue
+		}
+		if query.To != nil && event.Timestamp.After(*query.To) {
+			continue
+		}
+		if event.Confidence != nil && *event.Confidence < query.MinConfidence {
+			continue
+		}
+		if query.InvestigatorID != "" && event.InvestigatorID != query.InvestigatorID {
+			continue
+		}
+		if query.DeviceID != "" && event.DeviceID != query.DeviceID {
+			continue
+		}
+		if len(query.EVPIDs) > 0 && event.Type == TimelineEventEVP && !containsString(query.EVPIDs, event.RefID) {
+			continue
+		}
+		filtered = append(filtered, event)
+	}
+	return filtered
+}
+
+func confidence(value float64) *float64 {
+	return &value
+}
+
+func truncateSummary(summary string) string {
+	runes := []rune(summary)
+	if len(runes) <= maxTimelineSummaryRunes {
+		return summary
+	}
+	return string(runes[:maxTimelineSummaryRunes-1]) + "…"
+}
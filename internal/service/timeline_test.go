@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTimelineTestService(session *domain.Session) (*TimelineService, *MockRadarRepository, *MockSLSRepository) {
+	mockSessionRepo := &MockSessionRepository{}
+	mockEVPRepo := &MockEVPRepository{}
+	mockVOXRepo := &MockVOXRepository{}
+	mockRadarRepo := &MockRadarRepository{}
+	mockSLSRepo := &MockSLSRepository{}
+	mockInteractionRepo := &MockInteractionRepository{}
+	mockAnomalyRepo := &MockEnvironmentalAnomalyRepository{}
+
+	mockSessionRepo.On("GetByID", mock.Anything, session.ID).Return(session, nil)
+	mockEVPRepo.On("GetBySessionID", mock.Anything, session.ID).Return([]*domain.EVPRecording{TestEVPRecording()}, nil)
+	mockVOXRepo.On("GetBySessionID", mock.Anything, session.ID).Return([]*domain.VOXEvent{TestVOXEvent()}, nil)
+	mockInteractionRepo.On("GetBySessionID", mock.Anything, session.ID).Return([]*domain.UserInteraction{TestUserInteraction()}, nil)
+	mockAnomalyRepo.On("GetBySessionID", mock.Anything, session.ID).Return([]*domain.EnvironmentalAnomaly{}, nil)
+
+	service := NewTimelineService(mockSessionRepo, mockEVPRepo, mockVOXRepo, mockRadarRepo, mockSLSRepo, mockInteractionRepo, mockAnomalyRepo)
+	return service, mockRadarRepo, mockSLSRepo
+}
+
+func TestTimelineService_GetTimeline_MergesEventsChronologically(t *testing.T) {
+	// Arrange
+	session := TestSession()
+	service, mockRadarRepo, mockSLSRepo := newTimelineTestService(session)
+	mockRadarRepo.On("GetBySessionID", mock.Anything, session.ID).Return([]*domain.RadarEvent{TestRadarEvent()}, nil)
+	mockSLSRepo.On("GetBySessionID", mock.Anything, session.ID).Return([]*domain.SLSDetection{TestSLSDetection()}, nil)
+
+	// Act
+	page, err := service.GetTimeline(context.Background(), session.ID, TimelineQuery{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 6, page.Total)
+	require.Len(t, page.Events, 6)
+	assert.Equal(t, TimelineEventEnvironmental, page.Events[0].Type)
+	assert.Nil(t, page.Events[0].Confidence)
+	for i := 1; i < len(page.Events); i++ {
+		assert.False(t, page.Events[i].Timestamp.Before(page.Events[i-1].Timestamp))
+	}
+
+	radar := findTimelineEvent(page.Events, TimelineEventRadar)
+	require.NotNil(t, radar)
+	assert.Equal(t, "test-radar-012", radar.RefID)
+	assert.Equal(t, 12.5, radar.Duration)
+	require.NotNil(t, radar.Confidence)
+	assert.Equal(t, 0.78, *radar.Confidence)
+}
+
+func TestTimelineService_GetTimeline_FiltersAndPaginates(t *testing.T) {
+	// Arrange
+	session := TestSession()
+	service, mockRadarRepo, mockSLSRepo := newTimelineTestService(session)
+
+	base := time.Now().Add(-time.Hour)
+	var radarEvents []*domain.RadarEvent
+	for i, strength := range []float64{0.9, 0.2, 0.8, 0.7} {
+		event := testRadarEventAt(string(rune('a'+i)), base.Add(time.Duration(i)*time.Minute), 1, 1)
+		event.Strength = strength
+		radarEvents = append(radarEvents, event)
+	}
+	mockRadarRepo.On("GetBySessionID", mock.Anything, session.ID).Return(radarEvents, nil)
+
+	to := base.Add(150 * time.Second)
+
+	// Act
+	page, err := service.GetTimeline(context.Background(), session.ID, TimelineQuery{
+		Types:         []string{TimelineEventRadar},
+		To:            &to,
+		MinConfidence: 0.5,
+		Limit:         1,
+		Offset:        1,
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 2, page.Total) // "b" is too weak and "d" is after the range
+	require.Len(t, page.Events, 1)
+	assert.Equal(t, "c", page.Events[0].RefID)
+	mockSLSRepo.AssertNotCalled(t, "GetBySessionID", mock.Anything, mock.Anything)
+}
+
+func TestTimelineService_GetTimeline_FiltersByInvestigatorAndDevice(t *testing.T) {
+	// Arrange
+	session := TestSession()
+	service, mockRadarRepo, mockSLSRepo := newTimelineTestService(session)
+
+	base := time.Now().Add(-time.Hour)
+	first := testRadarEventAt("a", base, 1, 1)
+	first.InvestigatorID, first.DeviceID = "inv-1", "radar-1"
+	second := testRadarEventAt("b", base.Add(time.Minute), 1, 1)
+	second.InvestigatorID, second.DeviceID = "inv-1", "radar-2"
+	third := testRadarEventAt("c", base.Add(2*time.Minute), 1, 1)
+	third.InvestigatorID, third.DeviceID = "inv-2", "radar-1"
+	mockRadarRepo.On("GetBySessionID", mock.Anything, session.ID).Return([]*domain.RadarEvent{first, second, third}, nil)
+	mockSLSRepo.On("GetBySessionID", mock.Anything, session.ID).Return([]*domain.SLSDetection{}, nil)
+
+	// Act
+	page, err := service.GetTimeline(context.Background(), session.ID, TimelineQuery{
+		InvestigatorID: "inv-1",
+		DeviceID:       "radar-1",
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, page.Events, 1)
+	assert.Equal(t, first.ID, page.Events[0].RefID)
+	assert.Equal(t, "inv-1", page.Events[0].InvestigatorID)
+	assert.Equal(t, "radar-1", page.Events[0].DeviceID)
+}
+
+func TestTimelineService_GetTimeline_UnknownType_ReturnsError(t *testing.T) {
+	// Arrange
+	session := TestSession()
+	service, _, _ := newTimelineTestService(session)
+
+	// Act
+	page, err := service.GetTimeline(context.Background(), session.ID, TimelineQuery{Types: []string{"poltergeist"}})
+
+	// Assert
+	require.Error(t, err)
+	assert.Nil(t, page)
+	assert.Contains(t, err.Error(), "unknown event type")
+}
+
+func findTimelineEvent(events []*TimelineEvent, eventType string) *TimelineEvent {
+	for _, event := range events {
+		if event.Type == eventType {
+			return event
+		}
+	}
+	return nil
+}
@@ -0,0 +1,343 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/myideascope/otherside/pkg/audio"
+)
+
+const (
+	defaultBaselineIterations     = 1000
+	maxBaselineIterations         = 10000
+	defaultRelevanceWindowSeconds = 30.0
+)
+
+// VOXAnalysisService compares a session's VOX output against a Monte-Carlo
+// control built by replaying the VOX generator with the session's own
+// trigger strengths shuffled across its events
+type VOXAnalysisService struct {
+	sessionRepo     domain.SessionRepository
+	voxRepo         domain.VOXRepository
+	interactionRepo domain.InteractionRepository
+	voxGenerator    *audio.VOXGenerator
+}
+
+// NewVOXAnalysisService creates a new VOX analysis service
+func NewVOXAnalysisService(
+	sessionRepo domain.SessionRepository,
+	voxRepo domain.VOXRepository,
+	interactionRepo domain.InteractionRepository,
+	voxGenerator *audio.VOXGenerator,
+) *VOXAnalysisService {
+	return &VOXAnalysisService{
+		sessionRepo:     sessionRepo,
+		voxRepo:         voxRepo,
+		interactionRepo: interactionRepo,
+		voxGenerator:    voxGenerator,
+	}
+}
+
+// VOXBaselineOptions controls the Monte-Carlo control run
+type VOXBaselineOptions struct {
+	Iterations             int     `json:"iterations"`
+	Seed                   int64   `json:"seed"`
+	RelevanceWindowSeconds float64 `json:"relevance_window_seconds"`
+}
+
+// VOXBaselineReport compares observed VOX hits with chance expectation
+type VOXBaselineReport struct {
+	SessionID              string             `json:"session_id"`
+	Iterations             int                `json:"iterations"`
+	Seed                   int64              `json:"seed"`
+	RelevanceWindowSeconds float64            `json:"relevance_window_seconds"`
+	Observed               VOXHitStatistics   `json:"observed"`
+	Expected               VOXHitStatistics   `json:"expected"`
+	HitRatePValue          float64            `json:"hit_rate_p_value"`
+	RelevancePValue        float64            `json:"relevance_p_value"`
+	Words                  []VOXWordFrequency `json:"words"`
+	GeneratedAt            time.Time          `json:"generated_at"`
+}
+
+// VOXHitStatistics summarizes word hits for a set of VOX events. For the
+// expected side every value is the mean over all control iterations.
+type VOXHitStatistics struct {
+	Events         int     `json:"events"`
+	WordHits       float64 `json:"word_hits"`
+	HitRate        float64 `json:"hit_rate"`
+	RelevantHits   float64 `json:"relevant_hits"`
+	RelevanceScore float64 `json:"relevance_score"`
+}
+
+// VOXWordFrequency compares how often a single word was produced
+type VOXWordFrequency struct {
+	Word     string  `json:"word"`
+	Observed int     `json:"observed"`
+	Expected float64 `json:"expected"`
+	PValue   float64 `json:"p_value"`
+}
+
+// voxReplaySlot holds what is needed to replay one observed VOX event
+type voxReplaySlot struct {
+	config         audio.VOXConfig
+	strength       float64
+	words          map[string]bool
+	questionTokens map[string]bool
+}
+
+// voxTally accumulates hit counts for one set of generated texts
+type voxTally struct {
+	wordHits     int
+	relevantHits int
+	words        map[string]int
+}
+
+// AnalyzeChanceBaseline builds a chance baseline for a session's VOX events
+func (s *VOXAnalysisService) AnalyzeChanceBaseline(ctx context.Context, sessionID string, opts VOXBaselineOptions) (*VOXBaselineReport, error) {
+	if _, err := s.sessionRepo.GetByID(ctx, sessionID); err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+
+	opts = normalizeBaselineOptions(opts)
+
+	voxEvents, err := s.voxRepo.GetBySessionID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get VOX events: %w", err)
+	}
+
+	interactions, err := s.interactionRepo.GetBySessionID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get interactions: %w", err)
+	}
+
+	slots := s.buildReplaySlots(voxEvents, interactions, opts.RelevanceWindowSeconds)
+
+	observedTexts := make([]string, len(voxEvents))
+	for i, vox := range voxEvents {
+		observedTexts[i] = vox.GeneratedText
+	}
+	observed := tallyVOXTexts(slots, observedTexts)
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	controlWords := make(map[string][]int)
+	var hitsAtLeast, relevanceAtLeast int
+	var totalHits, totalRelevant int
+
+	for i := 0; i < opts.Iterations; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		control := tallyVOXTexts(slots, s.replayControl(slots, rng))
+
+		totalHits += control.wordHits
+		totalRelevant += control.relevantHits
+		if control.wordHits >= observed.wordHits {
+			hitsAtLeast++
+		}
+		if control.relevantHits >= observed.relevantHits {
+			relevanceAtLeast++
+		}
+
+		for word, count := range control.words {
+			if _, exists := controlWords[word]; !exists {
+				controlWords[word] = make([]int, opts.Iterations)
+			}
+			controlWords[word][i] = count
+		}
+	}
+
+	iterations := float64(opts.Iterations)
+	report := &VOXBaselineReport{
+		SessionID:              sessionID,
+		Iterations:             opts.Iterations,
+		Seed:                   opts.Seed,
+		RelevanceWindowSeconds: opts.RelevanceWindowSeconds,
+		Observed:               newVOXHitStatistics(len(slots), float64(observed.wordHits), float64(observed.relevantHits)),
+		Expected:               newVOXHitStatistics(len(slots), float64(totalHits)/iterations, float64(totalRelevant)/iterations),
+		HitRatePValue:          empiricalPValue(hitsAtLeast, opts.Iterations),
+		RelevancePValue:        empiricalPValue(relevanceAtLeast, opts.Iterations),
+		Words:                  compareWordFrequencies(observed.words, controlWords, opts.Iterations),
+		GeneratedAt:            time.Now(),
+	}
+
+	return report, nil
+}
+
+// buildReplaySlots captures the generator configuration and the words asked
+// about shortly before each observed VOX event
+func (s *VOXAnalysisService) buildReplaySlots(voxEvents []*domain.VOXEvent, interactions []*domain.UserInteraction, windowSeconds float64) []voxReplaySlot {
+	window := time.Duration(windowSeconds * float64(time.Second))
+	slots := make([]voxReplaySlot, len(voxEvents))
+
+	for i, vox := range voxEvents {
+		words := make(map[string]bool)
+		for _, word := range s.voxGenerator.LanguagePack(vox.LanguagePack) {
+			words[word] = true
+		}
+
+		questionTokens := make(map[string]bool)
+		for _, interaction := range interactions {
+			if interaction.Timestamp.After(vox.Timestamp) || vox.Timestamp.Sub(interaction.Timestamp) > window {
+				continue
+			}
+			for _, token := range tokenizeWords(interaction.Content + " " + interaction.Response) {
+				questionTokens[token] = true
+			}
+		}
+
+		slots[i] = voxReplaySlot{
+			config: audio.VOXConfig{
+				DefaultLanguage:  vox.LanguagePack,
+				PhoneticBankSize: phoneticBankSize(vox.PhoneticBank),
+				TriggerThreshold: voxTriggerThreshold,
+			},
+			strength:       vox.TriggerStrength,
+			words:          words,
+			questionTokens: questionTokens,
+		}
+	}
+
+	return slots
+}
+
+// replayControl generates one control session. The trigger strengths
+// observed in the session are permuted across its slots, so the control
+// keeps the session's own distribution of triggers and only breaks their
+// pairing with what was asked before each event.
+func (s *VOXAnalysisService) replayControl(slots []voxReplaySlot, rng *rand.Rand) []string {
+	texts := make([]string, len(slots))
+
+	for i, j := range rng.Perm(len(slots)) {
+		result, err := s.voxGenerator.ComposeTextAt(slots[j].strength, slots[i].config)
+		if err != nil || result == nil {
+			continue
+		}
+		texts[i] = result.GeneratedText
+	}
+
+	return texts
+}
+
+// tallyVOXTexts counts word hits and hits that echo a recent question
+func tallyVOXTexts(slots []voxReplaySlot, texts []string) voxTally {
+	tally := voxTally{words: make(map[string]int)}
+
+	for i, text := range texts {
+		word := strings.ToLower(strings.TrimSpace(text))
+		if word == "" || !slots[i].words[word] {
+			continue
+		}
+
+		tally.wordHits++
+		tally.words[word]++
+		if slots[i].questionTokens[word] {
+			tally.relevantHits++
+		}
+	}
+
+	return tally
+}
+
+func compareWordFrequencies(observed map[string]int, control map[string][]int, iterations int) []VOXWordFrequency {
+	seen := make(map[string]bool)
+	for word := range observed {
+		seen[word] = true
+	}
+	for word := range control {
+		seen[word] = true
+	}
+
+	frequencies := make([]VOXWordFrequency, 0, len(seen))
+	for word := range seen {
+		counts := control[word]
+
+		var total, atLeast int
+		for i := 0; i < iterations; i++ {
+			count := 0
+			if counts != nil {
+				count = counts[i]
+			}
+			total += count
+			if count >= observed[word] {
+				atLeast++
+			}
+		}
+
+		frequencies = append(frequencies, VOXWordFrequency{
+			Word:     word,
+			Observed: observed[word],
+			Expected: float64(total) / float64(iterations),
+			PValue:   empiricalPValue(atLeast, iterations),
+		})
+	}
+
+	sort.Slice(frequencies, func(i, j int) bool {
+		if frequencies[i].Observed != frequencies[j].Observed {
+			return frequencies[i].Observed > frequencies[j].Observed
+		}
+		return frequencies[i].Word < frequencies[j].Word
+	})
+
+	return frequencies
+}
+
+func newVOXHitStatistics(events int, wordHits, relevantHits float64) VOXHitStatistics {
+	stats := VOXHitStatistics{
+		Events:       events,
+		WordHits:     wordHits,
+		RelevantHits: relevantHits,
+	}
+	if events > 0 {
+		stats.HitRate = wordHits / float64(events)
+		stats.RelevanceScore = relevantHits / float64(events)
+	}
+	return stats
+}
+
+// empiricalPValue returns the add-one Monte-Carlo p-value for the number of
+// control runs that matched or exceeded the observed statistic
+func empiricalPValue(atLeast, iterations int) float64 {
+	return float64(atLeast+1) / float64(iterations+1)
+}
+
+func normalizeBaselineOptions(opts VOXBaselineOptions) VOXBaselineOptions {
+	if opts.Iterations <= 0 {
+		opts.Iterations = defaultBaselineIterations
+	}
+	if opts.Iterations > maxBaselineIterations {
+		opts.Iterations = maxBaselineIterations
+	}
+	if opts.RelevanceWindowSeconds <= 0 {
+		opts.RelevanceWindowSeconds = defaultRelevanceWindowSeconds
+	}
+	if opts.Seed == 0 {
+		opts.Seed = time.Now().UnixNano()
+	}
+	return opts
+}
+
+// phoneticBankSize maps a stored phonetic bank name back to a bank size that
+// selects the same bank in the VOX generator
+func phoneticBankSize(bank string) int {
+	switch bank {
+	case "minimal":
+		return 10
+	case "extended":
+		return 40
+	default:
+		return 25
+	}
+}
+
+func tokenizeWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/myideascope/otherside/pkg/audio"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestVOXAnalysisService(session *domain.Session, voxEvents []*domain.VOXEvent, interactions []*domain.UserInteraction) (*VOXAnalysisService, *MockSessionRepository) {
+	mockSessionRepo := &MockSessionRepository{}
+	mockVOXRepo := &MockVOXRepository{}
+	mockInteractionRepo := &MockInteractionRepository{}
+
+	if session != nil {
+		mockSessionRepo.On("GetByID", mock.Anything, session.ID).Return(session, nil)
+		mockVOXRepo.On("GetBySessionID", mock.Anything, session.ID).Return(voxEvents, nil)
+		mockInteractionRepo.On("GetBySessionID", mock.Anything, session.ID).Return(interactions, nil)
+	}
+
+	service := NewVOXAnalysisService(
+		mockSessionRepo, mockVOXRepo, mockInteractionRepo,
+		audio.NewVOXGenerator(TestVOXConfig()),
+	)
+
+	return service, mockSessionRepo
+}
+
+func TestVOXAnalysisService_AnalyzeChanceBaseline_RelevantHits_LowPValue(t *testing.T) {
+	// Arrange
+	session := TestSession()
+	base := time.Now().Add(-time.Hour)
+
+	var voxEvents []*domain.VOXEvent
+	var interactions []*domain.UserInteraction
+	for i := 0; i < 5; i++ {
+		at := base.Add(time.Duration(i) * 5 * time.Minute)
+		interactions = append(interactions, &domain.UserInteraction{
+			ID:        "question",
+			SessionID: session.ID,
+			Timestamp: at.Add(-10 * time.Second),
+			Type:      domain.InteractionTypeVoice,
+			Content:   "Do you need help?",
+		})
+		voxEvents = append(voxEvents, &domain.VOXEvent{
+			ID:              "vox",
+			SessionID:       session.ID,
+			Timestamp:       at,
+			GeneratedText:   "help",
+			PhoneticBank:    "english",
+			TriggerStrength: 0.9,
+			LanguagePack:    "english",
+		})
+	}
+
+	service, _ := newTestVOXAnalysisService(session, voxEvents, interactions)
+
+	// Act
+	report, err := service.AnalyzeChanceBaseline(context.Background(), session.ID, VOXBaselineOptions{
+		Iterations: 200,
+		Seed:       42,
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 200, report.Iterations)
+	assert.Equal(t, int64(42), report.Seed)
+	assert.Equal(t, 5, report.Observed.Events)
+	assert.Equal(t, 5.0, report.Observed.WordHits)
+	assert.Equal(t, 5.0, report.Observed.RelevantHits)
+	assert.Equal(t, 1.0, report.Observed.RelevanceScore)
+	assert.Less(t, report.Expected.RelevantHits, report.Observed.RelevantHits)
+	assert.Less(t, report.RelevancePValue, 0.05)
+
+	require.NotEmpty(t, report.Words)
+	assert.Equal(t, "help", report.Words[0].Word)
+	assert.Equal(t, 5, report.Words[0].Observed)
+	assert.Less(t, report.Words[0].PValue, 0.05)
+}
+
+func TestVOXAnalysisService_AnalyzeChanceBaseline_PhoneticOnly_NoWordHits(t *testing.T) {
+	// Arrange
+	session := TestSession()
+	voxEvents := []*domain.VOXEvent{
+		{ID: "vox-1", SessionID: session.ID, Timestamp: time.Now(), GeneratedText: "ahm", PhoneticBank: "english", LanguagePack: "english"},
+		{ID: "vox-2", SessionID: session.ID, Timestamp: time.Now(), GeneratedText: "sh", PhoneticBank: "english", LanguagePack: "english"},
+	}
+
+	service, _ := newTestVOXAnalysisService(session, voxEvents, nil)
+
+	// Act
+	report, err := service.AnalyzeChanceBaseline(context.Background(), session.ID, VOXBaselineOptions{
+		Iterations: 50,
+		Seed:       7,
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, report.Observed.WordHits)
+	assert.Equal(t, 1.0, report.HitRatePValue)
+	assert.Equal(t, defaultRelevanceWindowSeconds, report.RelevanceWindowSeconds)
+}
+
+func TestVOXAnalysisService_AnalyzeChanceBaseline_ShufflesObservedStrengths(t *testing.T) {
+	// Arrange
+	session := TestSession()
+	base := time.Now().Add(-time.Hour)
+
+	// One strong event, which composes "what", answers a question; three
+	// weak ones only produce phonetics
+	strengths := []float64{0.9, 0.35, 0.36, 0.37}
+	var voxEvents []*domain.VOXEvent
+	for i, strength := range strengths {
+		voxEvents = append(voxEvents, &domain.VOXEvent{
+			ID:              "vox",
+			SessionID:       session.ID,
+			Timestamp:       base.Add(time.Duration(i) * 5 * time.Minute),
+			GeneratedText:   "ah",
+			PhoneticBank:    "english",
+			TriggerStrength: strength,
+			LanguagePack:    "english",
+		})
+	}
+	voxEvents[0].GeneratedText = "what"
+	interactions := []*domain.UserInteraction{{
+		ID:        "question",
+		SessionID: session.ID,
+		Timestamp: base.Add(-10 * time.Second),
+		Type:      domain.InteractionTypeVoice,
+		Content:   "What do you want?",
+	}}
+
+	service, _ := newTestVOXAnalysisService(session, voxEvents, interactions)
+
+	// Act
+	report, err := service.AnalyzeChanceBaseline(context.Background(), session.ID, VOXBaselineOptions{
+		Iterations: 400,
+		Seed:       3,
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, report.Observed.WordHits)
+	assert.Equal(t, 1.0, report.Observed.RelevantHits)
+	assert.Equal(t, 1.0, report.Expected.WordHits)
+	assert.InDelta(t, 0.25, report.Expected.RelevantHits, 0.07)
+	assert.InDelta(t, 0.25, report.RelevancePValue, 0.07)
+}
+
+func TestVOXAnalysisService_AnalyzeChanceBaseline_SameSeed_Reproducible(t *testing.T) {
+	// Arrange
+	session := TestSession()
+	voxEvents := []*domain.VOXEvent{TestVOXEvent()}
+	service, _ := newTestVOXAnalysisService(session, voxEvents, nil)
+	opts := VOXBaselineOptions{Iterations: 100, Seed: 99}
+
+	// Act
+	first, err := service.AnalyzeChanceBaseline(context.Background(), session.ID, opts)
+	require.NoError(t, err)
+	second, err := service.AnalyzeChanceBaseline(context.Background(), session.ID, opts)
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, first.Expected, second.Expected)
+	assert.Equal(t, first.Words, second.Words)
+}
+
+func TestVOXAnalysisService_AnalyzeChanceBaseline_SessionNotFound_ReturnsError(t *testing.T) {
+	// Arrange
+	service, mockSessionRepo := newTestVOXAnalysisService(nil, nil, nil)
+	mockSessionRepo.On("GetByID", mock.Anything, "missing").
+		Return((*domain.Session)(nil), assert.AnError).
+		Once()
+
+	// Act
+	report, err := service.AnalyzeChanceBaseline(context.Background(), "missing", VOXBaselineOptions{})
+
+	// Assert
+	require.Error(t, err)
+	assert.Nil(t, report)
+	assert.Contains(t, err.Error(), "session not found")
+}
+
+func TestEmpiricalPValue(t *testing.T) {
+	assert.InDelta(t, 1.0/101.0, empiricalPValue(0, 100), 1e-9)
+	assert.Equal(t, 1.0, empiricalPValue(100, 100))
+}
@@ -0,0 +1,739 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+)
+
+// Webhook event types
+const (
+	WebhookEventSessionCompleted  = "session.completed"
+	WebhookEventExportReady       = "export.ready"
+	WebhookEventEVPHighConfidence = "evp.high_confidence"
+	WebhookEventAlertFired        = "alert.fired"
+
+	// WebhookEventPing is sent on request to check a subscription
+	WebhookEventPing = "ping"
+)
+
+// Webhook request headers
+const (
+	WebhookHeaderEvent     = "X-OtherSide-Event"
+	WebhookHeaderDelivery  = "X-OtherSide-Delivery"
+	WebhookHeaderSignature = "X-OtherSide-Signature"
+)
+
+const (
+	defaultWebhookBackoff          = 10 * time.Second
+	defaultWebhookMaxBackoff       = time.Hour
+	defaultWebhookMaxAttempts      = 8
+	defaultWebhookEVPMinConfidence = 0.8
+	defaultWebhookDeliveryLimit    = 50
+	maxWebhookDeliveryLimit        = 500
+	maxWebhookDescriptionLength    = 500
+	maxWebhookErrorLength          = 500
+
+	// webhookTimeout bounds one delivery attempt
+	webhookTimeout = 10 * time.Second
+
+	// webhookBatchSize is how many due deliveries are read at a time
+	webhookBatchSize = 50
+)
+
+// WebhookDeliveryConfig configures webhook retries. Zero values fall back
+// to the defaults.
+type WebhookDeliveryConfig struct {
+	// Backoff is the wait after the first failed attempt; it doubles with
+	// each further failure up to MaxBackoff
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// MaxAttempts is how many attempts a delivery gets before it fails
+	MaxAttempts int
+	// EVPMinConfidence is the detection level from which an EVP recording
+	// is sent as evp.high_confidence
+	EVPMinConfidence float64
+	// AllowPrivateAddresses lets webhooks reach loopback, link-local and
+	// private addresses, for receivers on the investigation network
+	AllowPrivateAddresses bool
+}
+
+// WebhookService posts events to the URLs investigators subscribe. Each
+// event is queued as one delivery per subscription before anything is
+// sent, so deliveries survive restarts; failed attempts are retried with
+// exponential backoff and every delivery is kept as a log entry that can
+// be replayed. Payloads are signed with HMAC-SHA256 using the
+// subscription's secret.
+type WebhookService struct {
+	subscriptionRepo domain.WebhookSubscriptionRepository
+	deliveryRepo     domain.WebhookDeliveryRepository
+	accessService    *AccessService
+	config           WebhookDeliveryConfig
+	client           *http.Client
+	now              func() time.Time
+
+	wake    chan struct{}
+	running sync.Mutex
+}
+
+// WebhookSubscriptionRequest describes a webhook subscription. Enabled
+// defaults to true.
+type WebhookSubscriptionRequest struct {
+	URL         string   `json:"url"`
+	EventTypes  []string `json:"event_types"`
+	Description string   `json:"description,omitempty"`
+	Enabled     *bool    `json:"enabled,omitempty"`
+}
+
+// WebhookPayload is the body of every webhook request
+type WebhookPayload struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(
+	subscriptionRepo domain.WebhookSubscriptionRepository,
+	deliveryRepo domain.WebhookDeliveryRepository,
+	config WebhookDeliveryConfig,
+) *WebhookService {
+	if config.Backoff <= 0 {
+		config.Backoff = defaultWebhookBackoff
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = defaultWebhookMaxBackoff
+	}
+	if config.MaxBackoff < config.Backoff {
+		config.MaxBackoff = config.Backoff
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaultWebhookMaxAttempts
+	}
+	if config.EVPMinConfidence <= 0 {
+		config.EVPMinConfidence = defaultWebhookEVPMinConfidence
+	}
+
+	return &WebhookService{
+		subscriptionRepo: subscriptionRepo,
+		deliveryRepo:     deliveryRepo,
+		config:           config,
+		client:           newWebhookClient(config.AllowPrivateAddresses),
+		now:              time.Now,
+		wake:             make(chan struct{}, 1),
+	}
+}
+
+// SetAccessService limits each subscription to events of sessions its
+// author can read
+func (s *WebhookService) SetAccessService(accessService *AccessService) {
+	s.accessService = accessService
+}
+
+// Wake receives a value whenever deliveries are queued, so that the
+// delivery job can send them without waiting for its next run
+func (s *WebhookService) Wake() <-chan struct{} {
+	return s.wake
+}
+
+// CreateSubscription validates and stores a new webhook subscription. The
+// returned subscription carries its signing secret, which is not shown
+// again.
+func (s *WebhookService) CreateSubscription(ctx context.Context, req WebhookSubscriptionRequest) (*domain.WebhookSubscription, error) {
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	now := s.now()
+	subscription := &domain.WebhookSubscription{
+		ID:        generateID(),
+		Secret:    secret,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := applyWebhookSubscriptionRequest(subscription, req); err != nil {
+		return nil, err
+	}
+	if identity := domain.IdentityFromContext(ctx); identity != nil && identity.Kind == domain.PrincipalInvestigator {
+		subscription.CreatedBy = identity.ID
+	}
+
+	if err := s.subscriptionRepo.Create(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+
+	return subscription, nil
+}
+
+// GetSubscription returns a webhook subscription of the caller's, without
+// its secret
+func (s *WebhookService) GetSubscription(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	subscription, err := s.ownSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	subscription.Secret = ""
+	return subscription, nil
+}
+
+// ListSubscriptions returns the caller's webhook subscriptions, without
+// their secrets
+func (s *WebhookService) ListSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	subscriptions, err := s.subscriptionRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+
+	owned := make([]*domain.WebhookSubscription, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		if authorizeWebhookSubscription(ctx, subscription) != nil {
+			continue
+		}
+		subscription.Secret = ""
+		owned = append(owned, subscription)
+	}
+	return owned, nil
+}
+
+// UpdateSubscription replaces the settings of a webhook subscription. Its
+// secret stays.
+func (s *WebhookService) UpdateSubscription(ctx context.Context, id string, req WebhookSubscriptionRequest) (*domain.WebhookSubscription, error) {
+	subscription, err := s.ownSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyWebhookSubscriptionRequest(subscription, req); err != nil {
+		return nil, err
+	}
+	subscription.UpdatedAt = s.now()
+
+	if err := s.subscriptionRepo.Update(ctx, subscription); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("webhook subscription not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to update webhook subscription: %w", err)
+	}
+
+	subscription.Secret = ""
+	return subscription, nil
+}
+
+// DeleteSubscription removes a webhook subscription and its deliveries
+func (s *WebhookService) DeleteSubscription(ctx context.Context, id string) error {
+	if _, err := s.ownSubscription(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.subscriptionRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("webhook subscription not found: %s", id)
+		}
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+	return nil
+}
+
+// ListDeliveries returns up to limit of the latest deliveries of a
+// subscription, optionally only those with a status
+func (s *WebhookService) ListDeliveries(ctx context.Context, subscriptionID string, status domain.WebhookDeliveryStatus, limit int) ([]*domain.WebhookDelivery, error) {
+	switch status {
+	case "", domain.WebhookDeliveryPending, domain.WebhookDeliverySucceeded, domain.WebhookDeliveryFailed:
+	default:
+		return nil, fmt.Errorf("invalid webhook delivery status: %q", status)
+	}
+	if limit <= 0 {
+		limit = defaultWebhookDeliveryLimit
+	}
+	if limit > maxWebhookDeliveryLimit {
+		limit = maxWebhookDeliveryLimit
+	}
+
+	if _, err := s.ownSubscription(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.deliveryRepo.GetBySubscriptionID(ctx, subscriptionID, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// GetDelivery returns a delivery of a subscription
+func (s *WebhookService) GetDelivery(ctx context.Context, subscriptionID, deliveryID string) (*domain.WebhookDelivery, error) {
+	if _, err := s.ownSubscription(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+	return s.subscriptionDelivery(ctx, subscriptionID, deliveryID)
+}
+
+// ReplayDelivery queues the payload of an earlier delivery again, as a new
+// delivery, whatever became of the original
+func (s *WebhookService) ReplayDelivery(ctx context.Context, subscriptionID, deliveryID string) (*domain.WebhookDelivery, error) {
+	if _, err := s.ownSubscription(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+	original, err := s.subscriptionDelivery(ctx, subscriptionID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := s.newDelivery(subscriptionID, original.EventID, original.EventType, original.Payload)
+	delivery.ReplayOf = original.ID
+	if err := s.deliveryRepo.Create(ctx, delivery); err != nil {
+		return nil, fmt.Errorf("failed to queue webhook delivery: %w", err)
+	}
+
+	s.notifyWorker()
+	return delivery, nil
+}
+
+// PingSubscription queues a ping event for one subscription, to check that
+// its endpoint receives and verifies deliveries
+func (s *WebhookService) PingSubscription(ctx context.Context, subscriptionID string) (*domain.WebhookDelivery, error) {
+	subscription, err := s.ownSubscription(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := s.newPayload(WebhookEventPing, map[string]string{"subscription_id": subscription.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := s.newDelivery(subscription.ID, payload.ID, WebhookEventPing, payload.body)
+	if err := s.deliveryRepo.Create(ctx, delivery); err != nil {
+		return nil, fmt.Errorf("failed to queue webhook delivery: %w", err)
+	}
+
+	s.notifyWorker()
+	return delivery, nil
+}
+
+// Notify queues an event for every enabled subscription to its type whose
+// author can read all of sessionIDs. Failures are logged rather than
+// failing the operation that raised the event.
+func (s *WebhookService) Notify(ctx context.Context, eventType string, sessionIDs []string, data interface{}) {
+	subscriptions, err := s.subscriptionRepo.GetAll(ctx)
+	if err != nil {
+		log.Printf("Failed to load webhook subscriptions for %s: %v", eventType, err)
+		return
+	}
+
+	var payload *webhookPayload
+	queued := 0
+	for _, subscription := range subscriptions {
+		if !subscription.Enabled || !containsString(subscription.EventTypes, eventType) {
+			continue
+		}
+		if !s.canSee(ctx, subscription, sessionIDs) {
+			continue
+		}
+
+		if payload == nil {
+			if payload, err = s.newPayload(eventType, data); err != nil {
+				log.Printf("Failed to encode webhook event %s: %v", eventType, err)
+				return
+			}
+		}
+
+		delivery := s.newDelivery(subscription.ID, payload.ID, eventType, payload.body)
+		if err := s.deliveryRepo.Create(ctx, delivery); err != nil {
+			log.Printf("Failed to queue webhook %s for subscription %s: %v", eventType, subscription.ID, err)
+			continue
+		}
+		queued++
+	}
+
+	if queued > 0 {
+		s.notifyWorker()
+	}
+}
+
+// ObserveEvent sends EVP recordings detected with high confidence to the
+// evp.high_confidence subscribers
+func (s *WebhookService) ObserveEvent(ctx context.Context, sessionID, eventType string, data interface{}) {
+	evp, ok := data.(*domain.EVPRecording)
+	if !ok || eventType != LiveEventEVP || evp.DetectionLevel < s.config.EVPMinConfidence {
+		return
+	}
+	s.Notify(ctx, WebhookEventEVPHighConfidence, []string{sessionID}, evp)
+}
+
+// DeliverDue attempts every delivery that is due and returns how many
+// were attempted. Runs do not overlap; a run started while another is in
+// progress returns at once.
+func (s *WebhookService) DeliverDue(ctx context.Context) (int, error) {
+	if !s.running.TryLock() {
+		return 0, nil
+	}
+	defer s.running.Unlock()
+
+	attempted := 0
+	subscriptions := make(map[string]*domain.WebhookSubscription)
+	for {
+		due, err := s.deliveryRepo.GetDue(ctx, s.now(), webhookBatchSize)
+		if err != nil {
+			return attempted, fmt.Errorf("failed to get due webhook deliveries: %w", err)
+		}
+
+		for _, delivery := range due {
+			if ctx.Err() != nil {
+				return attempted, ctx.Err()
+			}
+
+			subscription, cached := subscriptions[delivery.SubscriptionID]
+			if !cached {
+				subscription, err = s.subscriptionRepo.GetByID(ctx, delivery.SubscriptionID)
+				if err != nil && !errors.Is(err, sql.ErrNoRows) {
+					return attempted, fmt.Errorf("failed to get webhook subscription: %w", err)
+				}
+				subscriptions[delivery.SubscriptionID] = subscription
+			}
+
+			s.attempt(ctx, subscription, delivery)
+			if err := s.deliveryRepo.Update(ctx, delivery); err != nil {
+				return attempted, fmt.Errorf("failed to record webhook delivery: %w", err)
+			}
+			attempted++
+		}
+
+		if len(due) < webhookBatchSize {
+			return attempted, nil
+		}
+	}
+}
+
+// PurgeDeliveries removes finished deliveries older than retention from
+// the log and returns how many were removed
+func (s *WebhookService) PurgeDeliveries(ctx context.Context, retention time.Duration) (int64, error) {
+	if retention <= 0 {
+		return 0, nil
+	}
+
+	purged, err := s.deliveryRepo.DeleteFinishedBefore(ctx, s.now().Add(-retention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge webhook deliveries: %w", err)
+	}
+	return purged, nil
+}
+
+// attempt sends a delivery once and records the outcome on it
+func (s *WebhookService) attempt(ctx context.Context, subscription *domain.WebhookSubscription, delivery *domain.WebhookDelivery) {
+	now := s.now()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.ResponseStatus = 0
+	delivery.LastError = ""
+
+	var err error
+	switch {
+	case subscription == nil:
+		err = fmt.Errorf("subscription was deleted")
+	case !subscription.Enabled:
+		err = fmt.Errorf("subscription is disabled")
+	default:
+		delivery.ResponseStatus, err = s.post(ctx, subscription, delivery)
+	}
+
+	if err == nil {
+		delivery.Status = domain.WebhookDeliverySucceeded
+		delivery.NextAttemptAt = nil
+		delivery.DeliveredAt = &now
+		return
+	}
+
+	delivery.LastError = truncateString(err.Error(), maxWebhookErrorLength)
+	if subscription == nil || !subscription.Enabled || delivery.Attempts >= s.config.MaxAttempts {
+		delivery.Status = domain.WebhookDeliveryFailed
+		delivery.NextAttemptAt = nil
+		return
+	}
+
+	next := now.Add(s.backoff(delivery.Attempts))
+	delivery.NextAttemptAt = &next
+}
+
+// post sends a signed delivery and returns the response status
+func (s *WebhookService) post(ctx context.Context, subscription *domain.WebhookSubscription, delivery *domain.WebhookDelivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "OtherSide-Webhooks")
+	req.Header.Set(WebhookHeaderEvent, delivery.EventType)
+	req.Header.Set(WebhookHeaderDelivery, delivery.ID)
+	req.Header.Set(WebhookHeaderSignature, signWebhookPayload(subscription.Secret, s.now().Unix(), delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint answered %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// newWebhookClient returns the client deliveries are posted with. It does
+// not follow redirects and, unless allowPrivate is set, refuses to connect
+// to addresses that are not public, checked after the host is resolved so
+// that a subscription cannot reach the server's own network.
+func newWebhookClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: webhookTimeout}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return fmt.Errorf("webhook address %s is not public; set WEBHOOK_ALLOW_PRIVATE_ADDRESSES=true to reach the local network", host)
+			}
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   webhookTimeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// nonPublicNetworks are reserved ranges the net.IP predicates do not cover
+var nonPublicNetworks = func() []*net.IPNet {
+	var networks []*net.IPNet
+	for _, cidr := range []string{"0.0.0.0/8", "100.64.0.0/10", "192.0.0.0/24", "198.18.0.0/15", "240.0.0.0/4"} {
+		_, network, _ := net.ParseCIDR(cidr)
+		networks = append(networks, network)
+	}
+	return networks
+}()
+
+// isPublicIP reports whether ip is a public unicast address
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return false
+	}
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// backoff returns the wait before the attempt after a number of failed ones
+func (s *WebhookService) backoff(failures int) time.Duration {
+	wait := s.config.Backoff
+	for i := 1; i < failures; i++ {
+		wait *= 2
+		if wait >= s.config.MaxBackoff {
+			return s.config.MaxBackoff
+		}
+	}
+	return wait
+}
+
+// canSee reports whether a subscription's author may read every session
+// an event concerns. Subscriptions made without an investigator see all.
+func (s *WebhookService) canSee(ctx context.Context, subscription *domain.WebhookSubscription, sessionIDs []string) bool {
+	if s.accessService == nil || subscription.CreatedBy == "" {
+		return true
+	}
+
+	author := investigatorContext(ctx, subscription.CreatedBy)
+	for _, sessionID := range sessionIDs {
+		if err := s.accessService.AuthorizeSession(author, sessionID, domain.AccessGuest); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// ownSubscription loads a subscription and checks it belongs to the caller
+func (s *WebhookService) ownSubscription(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	subscription, err := s.subscriptionRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("webhook subscription not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+	if err := authorizeWebhookSubscription(ctx, subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// subscriptionDelivery loads a delivery and checks it belongs to a subscription
+func (s *WebhookService) subscriptionDelivery(ctx context.Context, subscriptionID, deliveryID string) (*domain.WebhookDelivery, error) {
+	delivery, err := s.deliveryRepo.GetByID(ctx, deliveryID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("webhook delivery not found: %s", deliveryID)
+		}
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+	if delivery.SubscriptionID != subscriptionID {
+		return nil, fmt.Errorf("webhook delivery not found: %s", deliveryID)
+	}
+	return delivery, nil
+}
+
+// webhookPayload is an encoded event shared by its deliveries
+type webhookPayload struct {
+	ID   string
+	body json.RawMessage
+}
+
+// newPayload encodes an event as a webhook body
+func (s *WebhookService) newPayload(eventType string, data interface{}) (*webhookPayload, error) {
+	id := generateID()
+	body, err := json.Marshal(WebhookPayload{ID: id, Type: eventType, CreatedAt: s.now(), Data: data})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+	return &webhookPayload{ID: id, body: body}, nil
+}
+
+// newDelivery returns a delivery due now
+func (s *WebhookService) newDelivery(subscriptionID, eventID, eventType string, payload json.RawMessage) *domain.WebhookDelivery {
+	now := s.now()
+	return &domain.WebhookDelivery{
+		ID:             generateID(),
+		SubscriptionID: subscriptionID,
+		EventID:        eventID,
+		EventType:      eventType,
+		Payload:        payload,
+		Status:         domain.WebhookDeliveryPending,
+		NextAttemptAt:  &now,
+		CreatedAt:      now,
+	}
+}
+
+// notifyWorker wakes the delivery job unless a wake-up is already waiting
+func (s *WebhookService) notifyWorker() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// authorizeWebhookSubscription checks that a subscription belongs to the
+// caller. Devices and unauthenticated callers manage every subscription.
+func authorizeWebhookSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	identity := domain.IdentityFromContext(ctx)
+	if identity == nil || identity.Kind == domain.PrincipalDevice {
+		return nil
+	}
+	if subscription.CreatedBy != "" && subscription.CreatedBy != identity.ID {
+		return fmt.Errorf("forbidden: webhook subscription %s belongs to another investigator", subscription.ID)
+	}
+	return nil
+}
+
+// applyWebhookSubscriptionRequest validates a subscription request and
+// copies it onto a subscription
+func applyWebhookSubscriptionRequest(subscription *domain.WebhookSubscription, req WebhookSubscriptionRequest) error {
+	webhookURL := strings.TrimSpace(req.URL)
+	parsed, err := url.Parse(webhookURL)
+	if webhookURL == "" || err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("invalid webhook subscription: url must be an http or https URL")
+	}
+
+	if len(req.EventTypes) == 0 {
+		return fmt.Errorf("invalid webhook subscription: at least one event type is required")
+	}
+	var eventTypes []string
+	for _, eventType := range req.EventTypes {
+		eventType = strings.TrimSpace(eventType)
+		switch eventType {
+		case WebhookEventSessionCompleted, WebhookEventExportReady, WebhookEventEVPHighConfidence, WebhookEventAlertFired:
+		default:
+			return fmt.Errorf("invalid webhook subscription: unknown event type %q", eventType)
+		}
+		if !containsString(eventTypes, eventType) {
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+
+	description := strings.TrimSpace(req.Description)
+	if len(description) > maxWebhookDescriptionLength {
+		return fmt.Errorf("invalid webhook subscription: description is longer than %d characters", maxWebhookDescriptionLength)
+	}
+
+	subscription.URL = webhookURL
+	subscription.EventTypes = eventTypes
+	subscription.Description = description
+	subscription.Enabled = req.Enabled == nil || *req.Enabled
+	return nil
+}
+
+// signWebhookPayload returns the signature header of a payload sent at a
+// time: the Unix timestamp and the hex HMAC-SHA256 of "timestamp.body"
+func signWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// generateWebhookSecret returns a new random signing secret
+func generateWebhookSecret() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(bytes), nil
+}
+
+// investigatorContext returns a copy of ctx acting as an investigator, to
+// check what that investigator may read
+func investigatorContext(ctx context.Context, investigatorID string) context.Context {
+	return domain.ContextWithIdentity(ctx, &domain.Identity{Kind: domain.PrincipalInvestigator, ID: investigatorID})
+}
+
+// truncateString shortens s to at most max bytes
+func truncateString(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max]
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/myideascope/otherside/internal/domain"
+	"github.com/myideascope/otherside/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// webhookStandIn is a local HTTP endpoint recording the webhooks it receives
+type webhookStandIn struct {
+	*httptest.Server
+	status atomic.Int32
+
+	mu       sync.Mutex
+	requests []webhookRequest
+}
+
+type webhookRequest struct {
+	header http.Header
+	body   []byte
+}
+
+func newWebhookStandIn(t *testing.T, status int) *webhookStandIn {
+	standIn := &webhookStandIn{}
+	standIn.status.Store(int32(status))
+	standIn.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		standIn.mu.Lock()
+		standIn.requests = append(standIn.requests, webhookRequest{header: r.Header.Clone(), body: body})
+		standIn.mu.Unlock()
+		w.WriteHeader(int(standIn.status.Load()))
+	}))
+	t.Cleanup(standIn.Close)
+	return standIn
+}
+
+func (s *webhookStandIn) received() []webhookRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]webhookRequest(nil), s.requests...)
+}
+
+// setupWebhooks returns a webhook service over a migrated in-memory
+// database, with the fixed time its clock reads and its delivery repository
+func setupWebhooks(t *testing.T, config WebhookDeliveryConfig) (*WebhookService, *time.Time, *repository.SQLiteWebhookDeliveryRepository) {
+	db := setupLifecycleDB(t)
+	deliveries := repository.NewSQLiteWebhookDeliveryRepository(db)
+	webhooks := NewWebhookService(repository.NewSQLiteWebhookSubscriptionRepository(db), deliveries, config)
+
+	now := time.Now().Truncate(time.Second)
+	webhooks.now = func() time.Time { return now }
+	return webhooks, &now, deliveries
+}
+
+// verifyWebhookSignature checks a signature header against a body
+func verifyWebhookSignature(t *testing.T, secret, header string, body []byte) {
+	parts := strings.Split(header, ",")
+	require.Len(t, parts, 2)
+	timestamp, err := strconv.ParseInt(strings.TrimPrefix(parts[0], "t="), 10, 64)
+	require.NoError(t, err)
+	assert.Equal(t, signWebhookPayload(secret, timestamp, body), header)
+}
+
+func TestWebhookService_DeliverDue_SessionCompleted_PostsSignedPayload(t *testing.T) {
+	// Arrange
+	standIn := newWebhookStandIn(t, http.StatusNoContent)
+	webhooks, _, _ := setupWebhooks(t, WebhookDeliveryConfig{AllowPrivateAddresses: true})
+	ctx := context.Background()
+	subscription, err := webhooks.CreateSubscription(ctx, WebhookSubscriptionRequest{
+		URL: standIn.URL, EventTypes: []string{WebhookEventSessionCompleted},
+	})
+	require.NoError(t, err)
+	_, err = webhooks.CreateSubscription(ctx, WebhookSubscriptionRequest{
+		URL: standIn.URL + "/alerts", EventTypes: []string{WebhookEventAlertFired},
+	})
+	require.NoError(t, err)
+	sm, session := setupLifecycleManager(t)
+	sm.SetWebhookService(webhooks)
+
+	// Act
+	require.NoError(t, sm.CompleteSession(ctx, session.ID))
+	attempted, err := webhooks.DeliverDue(ctx)
+	require.NoError(t, err)
+	deliveries, err := webhooks.ListDeliveries(ctx, subscription.ID, "", 0)
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, 1, attempted)
+	assert.True(t, strings.HasPrefix(subscription.Secret, "whsec_"))
+	requests := standIn.received()
+	require.Len(t, requests, 1)
+	assert.Equal(t, WebhookEventSessionCompleted, requests[0].header.Get(WebhookHeaderEvent))
+	verifyWebhookSignature(t, subscription.Secret, requests[0].header.Get(WebhookHeaderSignature), requests[0].body)
+
+	var payload struct {
+		Type string          `json:"type"`
+		Data *domain.Session `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(requests[0].body, &payload))
+	assert.Equal(t, WebhookEventSessionCompleted, payload.Type)
+	assert.Equal(t, session.ID, payload.Data.ID)
+	assert.Equal(t, domain.SessionStatusComplete, payload.Data.Status)
+
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, requests[0].header.Get(WebhookHeaderDelivery), deliveries[0].ID)
+	assert.Equal(t, domain.WebhookDeliverySucceeded, deliveries[0].Status)
+	assert.Equal(t, http.StatusNoContent, deliveries[0].ResponseStatus)
+	assert.Nil(t, deliveries[0].NextAttemptAt)
+}
+
+func TestWebhookService_DeliverDue_FailingEndpoint_BacksOffUntilFailed(t *testing.T) {
+	// Arrange
+	standIn := newWebhookStandIn(t, http.StatusInternalServerError)
+	webhooks, now, deliveries := setupWebhooks(t, WebhookDeliveryConfig{Backoff: 10 * time.Second, MaxAttempts: 3, AllowPrivateAddresses: true})
+	ctx := context.Background()
+	subscription, err := webhooks.CreateSubscription(ctx, WebhookSubscriptionRequest{
+		URL: standIn.URL, EventTypes: []string{WebhookEventExportReady},
+	})
+	require.NoError(t, err)
+	webhooks.Notify(ctx, WebhookEventExportReady, nil, &ExportResult{Filename: "export.zip"})
+	start := *now
+
+	// Act
+	var waits []time.Duration
+	for attempt := 0; attempt < 3; attempt++ {
+		_, err := webhooks.DeliverDue(ctx)
+		require.NoError(t, err)
+
+		queued, err := deliveries.GetBySubscriptionID(ctx, subscription.ID, "", 1)
+		require.NoError(t, err)
+		if queued[0].NextAttemptAt != nil {
+			waits = append(waits, queued[0].NextAttemptAt.Sub(*now))
+			// Not due yet
+			attempted, err := webhooks.DeliverDue(ctx)
+			require.NoError(t, err)
+			assert.Zero(t, attempted)
+			*now = *queued[0].NextAttemptAt
+		}
+	}
+	failed, err := webhooks.ListDeliveries(ctx, subscription.ID, domain.WebhookDeliveryFailed, 0)
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, []time.Duration{10 * time.Second, 20 * time.Second}, waits)
+	assert.WithinDuration(t, start.Add(30*time.Second), *now, 0)
+	assert.Len(t, standIn.received(), 3)
+	require.Len(t, failed, 1)
+	assert.Equal(t, 3, failed[0].Attempts)
+	assert.Equal(t, http.StatusInternalServerError, failed[0].ResponseStatus)
+	assert.Contains(t, failed[0].LastError, "500")
+}
+
+func TestWebhookService_ReplayDelivery_FailedDelivery_RedeliversSamePayload(t *testing.T) {
+	// Arrange
+	standIn := newWebhookStandIn(t, http.StatusBadGateway)
+	webhooks, _, _ := setupWebhooks(t, WebhookDeliveryConfig{MaxAttempts: 1, AllowPrivateAddresses: true})
+	ctx := context.Background()
+	subscription, err := webhooks.CreateSubscription(ctx, WebhookSubscriptionRequest{
+		URL: standIn.URL, EventTypes: []string{WebhookEventEVPHighConfidence},
+	})
+	require.NoError(t, err)
+	webhooks.ObserveEvent(ctx, "session-1", LiveEventEVP, &domain.EVPRecording{ID: "evp-faint", DetectionLevel: 0.4})
+	webhooks.ObserveEvent(ctx, "session-1", LiveEventEVP, &domain.EVPRecording{ID: "evp-clear", DetectionLevel: 0.9})
+	_, err = webhooks.DeliverDue(ctx)
+	require.NoError(t, err)
+	failed, err := webhooks.ListDeliveries(ctx, subscription.ID, domain.WebhookDeliveryFailed, 0)
+	require.NoError(t, err)
+	require.Len(t, failed, 1)
+	standIn.status.Store(http.StatusOK)
+
+	// Act
+	replay, err := webhooks.ReplayDelivery(ctx, subscription.ID, failed[0].ID)
+	require.NoError(t, err)
+	_, err = webhooks.DeliverDue(ctx)
+	require.NoError(t, err)
+	delivered, err := webhooks.GetDelivery(ctx, subscription.ID, replay.ID)
+	require.NoError(t, err)
+	_, missingErr := webhooks.ReplayDelivery(ctx, "webhook-missing", failed[0].ID)
+
+	// Assert
+	assert.Equal(t, failed[0].ID, delivered.ReplayOf)
+	assert.Equal(t, failed[0].EventID, delivered.EventID)
+	assert.Equal(t, domain.WebhookDeliverySucceeded, delivered.Status)
+	requests := standIn.received()
+	require.Len(t, requests, 2)
+	assert.Equal(t, requests[0].body, requests[1].body)
+	assert.Contains(t, string(requests[1].body), "evp-clear")
+	assert.Contains(t, missingErr.Error(), "not found")
+}
+
+func TestWebhookService_DeliverDue_PrivateAddressOrRedirect_NotFollowed(t *testing.T) {
+	// Arrange
+	target := newWebhookStandIn(t, http.StatusOK)
+	redirector := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusFound))
+	t.Cleanup(redirector.Close)
+	guarded, _, _ := setupWebhooks(t, WebhookDeliveryConfig{MaxAttempts: 1})
+	open, _, _ := setupWebhooks(t, WebhookDeliveryConfig{MaxAttempts: 1, AllowPrivateAddresses: true})
+	ctx := context.Background()
+	loopback, err := guarded.CreateSubscription(ctx, WebhookSubscriptionRequest{
+		URL: target.URL, EventTypes: []string{WebhookEventExportReady},
+	})
+	require.NoError(t, err)
+	redirected, err := open.CreateSubscription(ctx, WebhookSubscriptionRequest{
+		URL: redirector.URL, EventTypes: []string{WebhookEventExportReady},
+	})
+	require.NoError(t, err)
+
+	// Act
+	for _, webhooks := range []*WebhookService{guarded, open} {
+		webhooks.Notify(ctx, WebhookEventExportReady, nil, &ExportResult{Filename: "export.zip"})
+		_, err := webhooks.DeliverDue(ctx)
+		require.NoError(t, err)
+	}
+	refused, err := guarded.ListDeliveries(ctx, loopback.ID, "", 0)
+	require.NoError(t, err)
+	notFollowed, err := open.ListDeliveries(ctx, redirected.ID, "", 0)
+	require.NoError(t, err)
+
+	// Assert
+	assert.Empty(t, target.received())
+	require.Len(t, refused, 1)
+	assert.Equal(t, domain.WebhookDeliveryFailed, refused[0].Status)
+	assert.Zero(t, refused[0].ResponseStatus)
+	assert.Contains(t, refused[0].LastError, "is not public")
+	assert.Contains(t, refused[0].LastError, "WEBHOOK_ALLOW_PRIVATE_ADDRESSES")
+	require.Len(t, notFollowed, 1)
+	assert.Equal(t, domain.WebhookDeliveryFailed, notFollowed[0].Status)
+	assert.Equal(t, http.StatusFound, notFollowed[0].ResponseStatus)
+	assert.False(t, isPublicIP(net.ParseIP("169.254.169.254")))
+	assert.False(t, isPublicIP(net.ParseIP("::ffff:10.0.0.1")))
+	assert.True(t, isPublicIP(net.ParseIP("93.184.216.34")))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math"
 	"math/cmplx"
+	"sort"
 	"time"
 
 	"gonum.org/v1/gonum/dsp/fourier"
@@ -566,9 +567,27 @@ func (v *VOXGenerator) initializeLanguagePacks() {
 
 // GenerateVOX generates VOX communication based on environmental triggers
 func (v *VOXGenerator) GenerateVOX(ctx context.Context, triggerData map[string]float64, config VOXConfig) (*VOXResult, error) {
+	result, err := v.ComposeText(triggerData, config)
+	if err != nil || result == nil {
+		return result, err
+	}
+
+	// Generate frequency modulation data
+	result.FrequencyData = v.generateFrequencyModulation(result.GeneratedText, result.TriggerStrength)
+
+	return result, nil
+}
+
+// ComposeText runs the text stage of GenerateVOX without synthesizing
+// frequency data, so callers can replay a configuration cheaply.
+func (v *VOXGenerator) ComposeText(triggerData map[string]float64, config VOXConfig) (*VOXResult, error) {
 	// Calculate trigger strength from environmental data
-	triggerStrength := v.calculateTriggerStrength(triggerData)
+	return v.ComposeTextAt(v.calculateTriggerStrength(triggerData), config)
+}
 
+// ComposeTextAt runs the text stage of GenerateVOX for a trigger strength
+// that is already known, such as one stored with an earlier VOX event.
+func (v *VOXGenerator) ComposeTextAt(triggerStrength float64, config VOXConfig) (*VOXResult, error) {
 	if triggerStrength < config.TriggerThreshold {
 		return nil, nil // No generation below threshold
 	}
@@ -589,19 +608,23 @@ func (v *VOXGenerator) GenerateVOX(ctx context.Context, triggerData map[string]f
 	// Generate text based on trigger strength and randomness
 	generatedText := v.generateText(phonetics, v.languagePacks[config.DefaultLanguage], triggerStrength)
 
-	// Generate frequency modulation data
-	freqData := v.generateFrequencyModulation(generatedText, triggerStrength)
-
 	return &VOXResult{
 		GeneratedText:   generatedText,
 		PhoneticBank:    bankName,
 		TriggerStrength: triggerStrength,
-		FrequencyData:   freqData,
 		ModulationType:  "amplitude",
 		GeneratedAt:     time.Now(),
 	}, nil
 }
 
+// LanguagePack returns a copy of the word bank for the given language
+func (v *VOXGenerator) LanguagePack(language string) []string {
+	words := v.languagePacks[language]
+	pack := make([]string, len(words))
+	copy(pack, words)
+	return pack
+}
+
 // calculateTriggerStrength calculates trigger strength from environmental data
 func (v *VOXGenerator) calculateTriggerStrength(data map[string]float64) float64 {
 	weights := map[string]float64{
@@ -611,10 +634,17 @@ func (v *VOXGenerator) calculateTriggerStrength(data map[string]float64) float64
 		"interference":  0.2,
 	}
 
+	// Sum in a fixed order so the same data always gives the same strength
+	keys := make([]string, 0, len(weights))
+	for key := range weights {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
 	var totalStrength float64
-	for key, value := range data {
-		if weight, exists := weights[key]; exists {
-			totalStrength += value * weight
+	for _, key := range keys {
+		if value, exists := data[key]; exists {
+			totalStrength += value * weights[key]
 		}
 	}
 
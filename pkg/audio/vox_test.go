@@ -145,7 +145,7 @@ func TestVOXGenerator_calculateTriggerStrength(t *testing.T) {
 			},
 			1.0,
 			func(t *testing.T, strength float64) {
-				assert.Equal(t, 1.0, strength)
+				assert.InDelta(t, 1.0, strength, 1e-9)
 				assert.LessOrEqual(t, strength, 1.0, "Should be normalized to 1.0 maximum")
 			},
 		},
@@ -654,3 +654,55 @@ func BenchmarkVOXGenerator_generateFrequencyModulation(b *testing.B) {
 		vox.generateFrequencyModulation("test", 0.6)
 	}
 }
+
+// TestVOXGenerator_ComposeText_MatchesGenerateVOX checks the text-only replay path
+func TestVOXGenerator_ComposeText_MatchesGenerateVOX(t *testing.T) {
+	vox := NewVOXGenerator(VOXConfig{})
+	config := VOXConfig{
+		DefaultLanguage:  "english",
+		PhoneticBankSize: 25,
+		TriggerThreshold: 0.3,
+	}
+	triggerData := map[string]float64{
+		"emf_anomaly":   0.9,
+		"audio_anomaly": 0.9,
+		"interference":  0.8,
+	}
+
+	composed, err := vox.ComposeText(triggerData, config)
+	require.NoError(t, err)
+	require.NotNil(t, composed)
+
+	generated, err := vox.GenerateVOX(context.Background(), triggerData, config)
+	require.NoError(t, err)
+	require.NotNil(t, generated)
+
+	assert.Equal(t, generated.GeneratedText, composed.GeneratedText)
+	assert.Equal(t, generated.PhoneticBank, composed.PhoneticBank)
+	assert.Equal(t, generated.TriggerStrength, composed.TriggerStrength)
+	assert.Nil(t, composed.FrequencyData)
+	assert.NotEmpty(t, generated.FrequencyData)
+
+	// Below threshold nothing is composed
+	silent, err := vox.ComposeText(map[string]float64{"temperature": 0.1}, config)
+	assert.NoError(t, err)
+	assert.Nil(t, silent)
+
+	// A known strength composes the same text
+	replayed, err := vox.ComposeTextAt(composed.TriggerStrength, config)
+	require.NoError(t, err)
+	require.NotNil(t, replayed)
+	assert.Equal(t, composed.GeneratedText, replayed.GeneratedText)
+}
+
+// TestVOXGenerator_LanguagePack_ReturnsCopy ensures callers cannot mutate the bank
+func TestVOXGenerator_LanguagePack_ReturnsCopy(t *testing.T) {
+	vox := NewVOXGenerator(VOXConfig{})
+
+	pack := vox.LanguagePack("simple")
+	require.NotEmpty(t, pack)
+	pack[0] = "mutated"
+
+	assert.NotEqual(t, "mutated", vox.LanguagePack("simple")[0])
+	assert.Empty(t, vox.LanguagePack("unknown"))
+}
@@ -1,4 +1,16 @@
 // Offline Support and Data Synchronization Module
+
+// generateClientId returns a random UUID for records created offline
+function generateClientId() {
+    if (typeof crypto !== 'undefined' && crypto.randomUUID) {
+        return crypto.randomUUID();
+    }
+    return 'xxxxxxxx-xxxx-4xxx-yxxx-xxxxxxxxxxxx'.replace(/[xy]/g, c => {
+        const r = Math.random() * 16 | 0;
+        return (c === 'x' ? r : (r & 0x3 | 0x8)).toString(16);
+    });
+}
+
 class OfflineManager {
     constructor(app) {
         this.app = app;
@@ -13,6 +25,7 @@ class OfflineManager {
         this.maxRetries = 3;
         this.retryDelay = 5000; // 5 seconds
         this.batchSize = 10;
+        this.syncBatchSize = 500; // operations per /sync/batch request
         
         this.init();
     }
@@ -131,6 +144,9 @@ class OfflineManager {
     
     async saveOfflineSession(sessionData) {
         try {
+            // The server keeps a client-generated id, so queued events
+            // for this session need no remapping once it syncs
+            sessionData.id = sessionData.id || generateClientId();
             await this.offlineStorage.saveSession(sessionData);
             this.queueForSync('session', 'create', sessionData);
             
@@ -143,6 +159,7 @@ class OfflineManager {
     
     async saveOfflineEVP(sessionId, evpData) {
         try {
+            evpData.id = evpData.id || generateClientId();
             await this.offlineStorage.saveEVP(sessionId, evpData);
             this.queueForSync('evp', 'create', { sessionId, evpData });
             
@@ -155,6 +172,7 @@ class OfflineManager {
     
     async saveOfflineVOX(sessionId, voxData) {
         try {
+            voxData.id = voxData.id || generateClientId();
             await this.offlineStorage.saveVOX(sessionId, voxData);
             this.queueForSync('vox', 'create', { sessionId, voxData });
             
@@ -167,6 +185,7 @@ class OfflineManager {
     
     async saveOfflineRadar(sessionId, radarData) {
         try {
+            radarData.id = radarData.id || generateClientId();
             await this.offlineStorage.saveRadar(sessionId, radarData);
             this.queueForSync('radar', 'create', { sessionId, radarData });
             
@@ -179,6 +198,7 @@ class OfflineManager {
     
     async saveOfflineSLS(sessionId, slsData) {
         try {
+            slsData.id = slsData.id || generateClientId();
             await this.offlineStorage.saveSLS(sessionId, slsData);
             this.queueForSync('sls', 'create', { sessionId, slsData });
             
@@ -191,6 +211,7 @@ class OfflineManager {
     
     async saveOfflineInteraction(sessionId, interactionData) {
         try {
+            interactionData.id = interactionData.id || generateClientId();
             await this.offlineStorage.saveInteraction(sessionId, interactionData);
             this.queueForSync('interaction', 'create', { sessionId, interactionData });
             
@@ -204,6 +225,9 @@ class OfflineManager {
     queueForSync(type, action, data) {
         const syncItem = {
             id: Date.now() + Math.random(),
+            // Sent with every attempt so a retry after a lost response is
+            // answered with the original result instead of a duplicate
+            idempotencyKey: generateClientId(),
             type: type,
             action: action,
             data: data,
@@ -262,8 +286,17 @@ class OfflineManager {
             item.status === 'pending' && item.retries < this.maxRetries
         );
         
+        // Sessions and JSON events go to the server in bulk; EVP
+        // recordings carry audio and are uploaded one at a time
+        const bulkItems = pendingItems.filter(item => item.type in this.syncOperationData);
+        const uploadItems = pendingItems.filter(item => !(item.type in this.syncOperationData));
+        
+        for (const chunk of this.chunkArray(bulkItems, this.syncBatchSize)) {
+            await this.syncOperations(chunk);
+        }
+        
         // Process in batches
-        const batches = this.chunkArray(pendingItems, this.batchSize);
+        const batches = this.chunkArray(uploadItems, this.batchSize);
         
         for (const batch of batches) {
             await this.processSyncBatch(batch);
@@ -276,6 +309,80 @@ class OfflineManager {
         this.cleanupSyncQueue();
     }
     
+    // Queue item types sent through /sync/batch, with the key holding
+    // each item's request body
+    get syncOperationData() {
+        return {
+            session: null,
+            vox: 'voxData',
+            radar: 'radarData',
+            sls: 'slsData',
+            interaction: 'interactionData'
+        };
+    }
+    
+    async syncOperations(items) {
+        const operations = items.map(item => {
+            const dataKey = this.syncOperationData[item.type];
+            if (!dataKey) {
+                return { type: item.type, data: item.data };
+            }
+            return {
+                type: item.type,
+                session_id: item.data.sessionId,
+                timestamp: item.timestamp,
+                data: item.data[dataKey]
+            };
+        });
+        
+        items.forEach(item => { item.status = 'syncing'; });
+        this.saveSyncQueue();
+        
+        try {
+            const response = await fetch(`${this.app.apiBaseUrl}/sync/batch`, {
+                method: 'POST',
+                headers: {
+                    'Content-Type': 'application/json'
+                },
+                body: JSON.stringify({ operations })
+            });
+            
+            if (!response.ok) {
+                throw new Error(`Batch sync failed with status ${response.status}`);
+            }
+            
+            const result = await response.json();
+            result.results.forEach(outcome => {
+                const item = items[outcome.index];
+                switch (outcome.status) {
+                    case 'created':
+                    case 'duplicate':
+                        item.status = 'completed';
+                        break;
+                    case 'rejected':
+                        // Sending it again cannot succeed
+                        console.warn(`Server rejected ${item.type}:`, outcome.reason);
+                        item.status = 'completed';
+                        item.lastError = outcome.reason;
+                        break;
+                    default:
+                        item.retries++;
+                        item.status = 'failed';
+                        item.lastError = outcome.reason;
+                }
+            });
+        } catch (error) {
+            console.error('Batch sync error:', error);
+            items.forEach(item => {
+                item.retries++;
+                item.status = 'failed';
+                item.lastError = error.message;
+            });
+        }
+        
+        this.saveSyncQueue();
+    }
+    
     async processSyncBatch(batch) {
         const syncPromises = batch.map(item => this.syncItem(item));
         
@@ -344,7 +451,8 @@ class OfflineManager {
             const response = await fetch(`${this.app.apiBaseUrl}/sessions`, {
                 method: 'POST',
                 headers: {
-                    'Content-Type': 'application/json'
+                    'Content-Type': 'application/json',
+                    'Idempotency-Key': this.idempotencyKey(item)
                 },
                 body: JSON.stringify(item.data)
             });
@@ -368,6 +476,9 @@ class OfflineManager {
             const { sessionId, evpData } = item.data;
             
             const formData = new FormData();
+            if (evpData.id) {
+                formData.append('id', evpData.id);
+            }
             if (evpData.audioBlob) {
                 formData.append('audio', evpData.audioBlob, 'evp-recording.webm');
             }
@@ -377,6 +488,9 @@ class OfflineManager {
                 `${this.app.apiBaseUrl}/sessions/${sessionId}/evp`,
                 {
                     method: 'POST',
+                    headers: {
+                        'Idempotency-Key': this.idempotencyKey(item)
+                    },
                     body: formData
                 }
             );
@@ -397,7 +511,8 @@ class OfflineManager {
                 {
                     method: 'POST',
                     headers: {
-                        'Content-Type': 'application/json'
+                        'Content-Type': 'application/json',
+                        'Idempotency-Key': this.idempotencyKey(item)
                     },
                     body: JSON.stringify(voxData)
                 }
@@ -419,7 +534,8 @@ class OfflineManager {
                 {
                     method: 'POST',
                     headers: {
-                        'Content-Type': 'application/json'
+                        'Content-Type': 'application/json',
+                        'Idempotency-Key': this.idempotencyKey(item)
                     },
                     body: JSON.stringify(radarData)
                 }
@@ -441,7 +557,8 @@ class OfflineManager {
                 {
                     method: 'POST',
                     headers: {
-                        'Content-Type': 'application/json'
+                        'Content-Type': 'application/json',
+                        'Idempotency-Key': this.idempotencyKey(item)
                     },
                     body: JSON.stringify(slsData)
                 }
@@ -463,7 +580,8 @@ class OfflineManager {
                 {
                     method: 'POST',
                     headers: {
-                        'Content-Type': 'application/json'
+                        'Content-Type': 'application/json',
+                        'Idempotency-Key': this.idempotencyKey(item)
                     },
                     body: JSON.stringify(interactionData)
                 }
@@ -476,6 +594,11 @@ class OfflineManager {
         }
     }
     
+    idempotencyKey(item) {
+        // Items queued before keys were added fall back to their queue id
+        return item.idempotencyKey || `sync-${item.id}`;
+    }
+    
     cleanupSyncQueue() {
         const before = this.syncQueue.length;
         